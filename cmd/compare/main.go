@@ -0,0 +1,125 @@
+// compare compares the results files written with --results-file by the
+// loaders and the query runners.
+//
+// Every argument is a results file, optionally prefixed with the name to report
+// it under ('name=file'). By default runs are named after their target. Runs
+// with the same name are repetitions of the same benchmark, their throughput
+// is averaged and tested for significance against the baseline.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
+	"github.com/spf13/pflag"
+)
+
+const (
+	formatTable    = "table"
+	formatMarkdown = "markdown"
+)
+
+// Program option vars:
+var (
+	opts             results.CompareOptions
+	format           string
+	failOnRegression bool
+)
+
+// Parse args:
+func init() {
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [name=]results-file ...\n", os.Args[0])
+		pflag.PrintDefaults()
+	}
+	pflag.String("baseline", "", "Name of the runs to compare the others with (default: the first run)")
+	pflag.String("format", formatTable, "Output format, one of: "+formatTable+", "+formatMarkdown)
+	pflag.Float64("threshold", 5, "Minimum relative change, in percent, to flag as a regression or an improvement")
+	pflag.Float64("alpha", 0.05, "Significance level of the tests of the changes")
+	pflag.Bool("fail-on-regression", false, "Exit with status 1 if any regression is flagged")
+
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	opts.Baseline = viper.GetString("baseline")
+	opts.Threshold = viper.GetFloat64("threshold")
+	opts.Alpha = viper.GetFloat64("alpha")
+	format = viper.GetString("format")
+	failOnRegression = viper.GetBool("fail-on-regression")
+
+	if format != formatTable && format != formatMarkdown {
+		log.Fatalf("unknown format '%s'", format)
+	}
+	if pflag.NArg() == 0 {
+		pflag.Usage()
+		os.Exit(2)
+	}
+}
+
+func main() {
+	runs := make([]results.Run, 0, pflag.NArg())
+	for _, arg := range pflag.Args() {
+		run, err := readRun(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		runs = append(runs, run)
+	}
+
+	comparison, err := results.Compare(runs, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, t := range comparison.Tables {
+		if i > 0 {
+			fmt.Println()
+		}
+		if format == formatMarkdown {
+			err = t.WriteMarkdown(os.Stdout)
+		} else {
+			err = t.WriteText(os.Stdout)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if comparison.Regressions > 0 {
+		fmt.Fprintf(os.Stderr, "%d regression(s) flagged\n", comparison.Regressions)
+		if failOnRegression {
+			os.Exit(1)
+		}
+	}
+}
+
+// readRun reads the results file of an argument. The run is named after the
+// prefix of the argument, else after its target, else after the file.
+func readRun(arg string) (results.Run, error) {
+	var name string
+	fileName := arg
+	if i := strings.Index(arg, "="); i >= 0 {
+		name, fileName = arg[:i], arg[i+1:]
+	}
+	r, err := results.ReadFile(fileName)
+	if err != nil {
+		return results.Run{}, err
+	}
+	if name == "" {
+		name = r.Target
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	}
+	return results.Run{Name: name, Result: r}, nil
+}
@@ -9,6 +9,22 @@
 // devops: scale is the number of hosts to simulate, with log messages
 //         every log-interval seconds.
 // cpu-only: same as `devops` but only generate metrics for CPU
+// devops-generic: hosts with a varying number of generic metric fields, up
+//         to max-metric-count.
+// custom: generators declared by the YAML file given with custom-schema.
+// dataset: the records of the CSV or line protocol file given with dataset,
+//         scale being how many copies of each series are written.
+//
+// With churn-rate set, that fraction of the generators is replaced at every
+// log-interval by new ones with fresh tag values.
+//
+// With real-time set, the timestamps follow the wall clock and every
+// log-interval is written once it is reached, for real-time-duration or, if
+// it is 0, for a year.
+//
+// timestamp-jitter offsets every timestamp by a random duration up to it, and
+// timestamp-precision truncates the timestamps, which the InfluxDB and CnosDB
+// formats then write in its unit.
 package main
 
 import (
@@ -0,0 +1,362 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// timeFmt is the format of the timestamp literals compared to "time", a
+// DateTime64(9) column in the UTC time zone.
+const timeFmt = "2006-01-02 15:04:05.000000000"
+
+// IoT produces ClickHouse-specific queries for all the iot query types.
+//
+// The tags are columns of the readings and diagnostics tables. The latest
+// values of every truck are selected with argMax over a tuple, which unlike
+// the values themselves is never NULL, so that the values all come from the
+// latest row. Runs of consecutive ten minute periods, for driving sessions and
+// breakdowns, are found by sorting the periods of every truck into arrays
+// rather than with window functions or windowFunnel.
+type IoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+// NewIoT makes an IoT object ready to generate Queries.
+func NewIoT(start, end time.Time, scale int, g *BaseGenerator) *IoT {
+	c, err := iot.NewCore(start, end, scale)
+	databases.PanicIfErr(err)
+	return &IoT{
+		Core:          c,
+		BaseGenerator: g,
+	}
+}
+
+func (i *IoT) getTrucksWhereWithNames(names []string) string {
+	nameClauses := []string{}
+	for _, s := range names {
+		nameClauses = append(nameClauses, fmt.Sprintf("'%s'", s))
+	}
+	return fmt.Sprintf("name IN (%s)", strings.Join(nameClauses, ","))
+}
+
+// getTruckWhereString gets multiple random truck names and creates a WHERE SQL statement for these names.
+func (i *IoT) getTruckWhereString(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	databases.PanicIfErr(err)
+	return i.getTrucksWhereWithNames(names)
+}
+
+// getTimeWhereString returns the condition on the time column selecting the
+// whole query interval.
+func (i *IoT) getTimeWhereString() string {
+	return fmt.Sprintf("time >= '%s' AND time < '%s'",
+		i.Interval.Start().UTC().Format(timeFmt),
+		i.Interval.End().UTC().Format(timeFmt))
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *IoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	sql := fmt.Sprintf(`SELECT name, latest.1 AS driver, latest.2 AS longitude, latest.3 AS latitude
+		FROM (
+			SELECT name, argMax((driver, longitude, latitude), time) AS latest
+			FROM readings
+			WHERE %s
+			GROUP BY name)
+		ORDER BY name`,
+		i.getTruckWhereString(nTrucks))
+
+	humanLabel := "ClickHouse last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *IoT) LastLocPerTruck(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, latest.1 AS driver, latest.2 AS longitude, latest.3 AS latitude
+		FROM (
+			SELECT name, argMax((driver, longitude, latitude), time) AS latest
+			FROM readings
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			GROUP BY name)
+		ORDER BY name`,
+		i.GetRandomFleet())
+
+	humanLabel := "ClickHouse last location per truck"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *IoT) TrucksWithLowFuel(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, latest.1 AS driver, latest.2 AS fuel_state
+		FROM (
+			SELECT name, argMax((driver, fuel_state), time) AS latest
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			GROUP BY name)
+		WHERE fuel_state < 0.1
+		ORDER BY name`,
+		i.GetRandomFleet())
+
+	humanLabel := "ClickHouse trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *IoT) TrucksWithHighLoad(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, latest.1 AS driver, latest.2 AS current_load, latest.3 AS load_capacity
+		FROM (
+			SELECT name, argMax((driver, current_load, load_capacity), time) AS latest
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			GROUP BY name)
+		WHERE current_load / load_capacity > 0.9
+		ORDER BY name`,
+		i.GetRandomFleet())
+
+	humanLabel := "ClickHouse trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *IoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM readings
+		WHERE time >= '%s' AND time < '%s'
+		AND name IS NOT NULL
+		AND fleet = '%s'
+		GROUP BY name, driver
+		HAVING avg(velocity) < 1
+		ORDER BY name`,
+		interval.Start().UTC().Format(timeFmt),
+		interval.End().UTC().Format(timeFmt),
+		i.GetRandomFleet())
+
+	humanLabel := "ClickHouse stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, toStartOfInterval(time, INTERVAL 10 minute) AS period, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE time >= '%s' AND time < '%s'
+			AND name IS NOT NULL
+			AND fleet = '%s'
+			GROUP BY name, driver, period)
+		WHERE mean_velocity > 1
+		GROUP BY name, driver
+		HAVING count() > %d
+		ORDER BY name`,
+		interval.Start().UTC().Format(timeFmt),
+		interval.End().UTC().Format(timeFmt),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "ClickHouse trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, toStartOfInterval(time, INTERVAL 10 minute) AS period, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE time >= '%s' AND time < '%s'
+			AND name IS NOT NULL
+			AND fleet = '%s'
+			GROUP BY name, driver, period)
+		WHERE mean_velocity > 1
+		GROUP BY name, driver
+		HAVING count() > %d
+		ORDER BY name`,
+		interval.Start().UTC().Format(timeFmt),
+		interval.End().UTC().Format(timeFmt),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "ClickHouse trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *IoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	sql := `SELECT fleet, avg(fuel_consumption) AS avg_fuel_consumption,
+		avg(nominal_fuel_consumption) AS projected_fuel_consumption
+		FROM readings
+		WHERE velocity > 1
+		AND name IS NOT NULL
+		AND fleet IS NOT NULL
+		GROUP BY fleet
+		ORDER BY fleet`
+
+	humanLabel := "ClickHouse average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *IoT) AvgDailyDrivingDuration(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT toStartOfDay(period) AS day, fleet, name, driver, count() / 6 AS hours_driven
+		FROM (
+			SELECT fleet, name, driver, toStartOfInterval(time, INTERVAL 10 minute) AS period, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE %s
+			AND name IS NOT NULL
+			GROUP BY fleet, name, driver, period)
+		WHERE mean_velocity > 1
+		GROUP BY day, fleet, name, driver
+		ORDER BY day, name`,
+		i.getTimeWhereString())
+
+	humanLabel := "ClickHouse average driver driving duration per day"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+//
+// The ten minute periods of every truck are sorted into an array, where the
+// running count of the periods without driving numbers the driving sessions:
+// the driving periods between two stops share the same number.
+func (i *IoT) AvgDailyDrivingSession(qi query.Query) {
+	sql := fmt.Sprintf(`WITH driver_status
+		AS (
+			SELECT name, toStartOfInterval(time, INTERVAL 10 minute) AS period, ifNull(avg(velocity), 0) > 5 AS driving
+			FROM readings
+			WHERE %s
+			AND name IS NOT NULL
+			GROUP BY name, period
+			), driver_periods
+		AS (
+			SELECT name, status.1 AS period, status.2 AS driving, session
+			FROM (
+				SELECT name, arraySort(groupArray((period, driving))) AS statuses,
+					arrayCumSum(arrayMap(s -> NOT s.2, statuses)) AS sessions
+				FROM driver_status
+				GROUP BY name)
+			ARRAY JOIN statuses AS status, sessions AS session
+			), driver_sessions
+		AS (
+			SELECT name, session, min(period) AS start, count() * 10 AS duration_minutes
+			FROM driver_periods
+			WHERE driving
+			GROUP BY name, session
+			)
+		SELECT name, toStartOfDay(start) AS day, avg(duration_minutes) AS avg_session_minutes
+		FROM driver_sessions
+		GROUP BY name, day
+		ORDER BY name, day`,
+		i.getTimeWhereString())
+
+	humanLabel := "ClickHouse average driver driving session without stopping per day"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *IoT) AvgLoad(qi query.Query) {
+	sql := `SELECT fleet, model, avg(current_load / load_capacity) AS avg_load_percentage
+		FROM diagnostics
+		WHERE name IS NOT NULL
+		GROUP BY fleet, model
+		ORDER BY fleet, model`
+
+	humanLabel := "ClickHouse average load per truck model per fleet"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *IoT) DailyTruckActivity(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT toStartOfDay(period) AS day, fleet, model, count() / 144 AS daily_activity
+		FROM (
+			SELECT fleet, model, name, toStartOfInterval(time, INTERVAL 10 minute) AS period, avg(status) AS mean_status
+			FROM diagnostics
+			WHERE %s
+			AND name IS NOT NULL
+			GROUP BY fleet, model, name, period)
+		WHERE mean_status < 1
+		GROUP BY day, fleet, model
+		ORDER BY day, fleet, model`,
+		i.getTimeWhereString())
+
+	humanLabel := "ClickHouse daily truck activity per fleet per model"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+//
+// A breakdown is a ten minute period with a broken down status following one
+// without, found by comparing the sorted array of the statuses of every truck
+// with itself shifted by one period.
+func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
+	sql := fmt.Sprintf(`WITH breakdown_per_truck_per_ten_minutes
+		AS (
+			SELECT name, model, toStartOfInterval(time, INTERVAL 10 minute) AS period, ifNull(avg(status), 0) >= 1 AS broken_down
+			FROM diagnostics
+			WHERE %s
+			AND name IS NOT NULL
+			GROUP BY name, model, period
+			), breakdowns_per_truck
+		AS (
+			SELECT name, model, arrayMap(s -> s.2, arraySort(groupArray((period, broken_down)))) AS statuses,
+				arrayCount((prev, cur) -> NOT prev AND cur, arrayPopBack(statuses), arrayPopFront(statuses)) AS truck_breakdowns
+			FROM breakdown_per_truck_per_ten_minutes
+			GROUP BY name, model
+			)
+		SELECT model, sum(truck_breakdowns) AS breakdowns
+		FROM breakdowns_per_truck
+		GROUP BY model
+		ORDER BY model`,
+		i.getTimeWhereString())
+
+	humanLabel := "ClickHouse truck breakdown frequency per model"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
@@ -0,0 +1,313 @@
+package clickhouse
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+const (
+	testScale = 10
+)
+
+type testCase struct {
+	desc               string
+	fail               bool
+	failMsg            string
+	input              int
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedTable      string
+	expectedSQLQuery   string
+}
+
+func TestLastLocByTruck(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:    "zero trucks",
+			input:   0,
+			fail:    true,
+			failMsg: "number of trucks cannot be < 1; got 0",
+		},
+		{
+			desc:  "three trucks",
+			input: 3,
+
+			expectedHumanLabel: "ClickHouse last location by specific truck",
+			expectedHumanDesc:  "ClickHouse last location by specific truck: random    3 trucks",
+			expectedTable:      iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT name, latest.1 AS driver, latest.2 AS longitude, latest.3 AS latitude
+		FROM (
+			SELECT name, argMax((driver, longitude, latitude), time) AS latest
+			FROM readings
+			WHERE name IN ('truck_5','truck_9','truck_3')
+			GROUP BY name)
+		ORDER BY name`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.LastLocByTruck(q, c.input)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), cases)
+}
+
+func TestTrucksWithLowFuel(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "ClickHouse trucks with low fuel",
+			expectedHumanDesc:  "ClickHouse trucks with low fuel: under 10 percent",
+			expectedTable:      iot.DiagnosticsTableName,
+			expectedSQLQuery: `SELECT name, latest.1 AS driver, latest.2 AS fuel_state
+		FROM (
+			SELECT name, argMax((driver, fuel_state), time) AS latest
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = 'South'
+			GROUP BY name)
+		WHERE fuel_state < 0.1
+		ORDER BY name`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLowFuel(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), cases)
+}
+
+func TestTrucksWithLongDrivingSessions(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "ClickHouse trucks with longer driving sessions",
+			expectedHumanDesc:  "ClickHouse trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedTable:      iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT name, driver
+		FROM (
+			SELECT name, driver, toStartOfInterval(time, INTERVAL 10 minute) AS period, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE time >= '1970-01-01 00:16:22.646325489' AND time < '1970-01-01 04:16:22.646325489'
+			AND name IS NOT NULL
+			AND fleet = 'West'
+			GROUP BY name, driver, period)
+		WHERE mean_velocity > 1
+		GROUP BY name, driver
+		HAVING count() > 22
+		ORDER BY name`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLongDrivingSessions(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), cases)
+}
+
+func TestAvgDailyDrivingSession(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "ClickHouse average driver driving session without stopping per day",
+			expectedHumanDesc:  "ClickHouse average driver driving session without stopping per day",
+			expectedTable:      iot.ReadingsTableName,
+			expectedSQLQuery: `WITH driver_status
+		AS (
+			SELECT name, toStartOfInterval(time, INTERVAL 10 minute) AS period, ifNull(avg(velocity), 0) > 5 AS driving
+			FROM readings
+			WHERE time >= '1970-01-01 00:00:00.000000000' AND time < '1970-01-01 06:00:00.000000000'
+			AND name IS NOT NULL
+			GROUP BY name, period
+			), driver_periods
+		AS (
+			SELECT name, status.1 AS period, status.2 AS driving, session
+			FROM (
+				SELECT name, arraySort(groupArray((period, driving))) AS statuses,
+					arrayCumSum(arrayMap(s -> NOT s.2, statuses)) AS sessions
+				FROM driver_status
+				GROUP BY name)
+			ARRAY JOIN statuses AS status, sessions AS session
+			), driver_sessions
+		AS (
+			SELECT name, session, min(period) AS start, count() * 10 AS duration_minutes
+			FROM driver_periods
+			WHERE driving
+			GROUP BY name, session
+			)
+		SELECT name, toStartOfDay(start) AS day, avg(duration_minutes) AS avg_session_minutes
+		FROM driver_sessions
+		GROUP BY name, day
+		ORDER BY name, day`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.AvgDailyDrivingSession(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), cases)
+}
+
+func TestTruckBreakdownFrequency(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "ClickHouse truck breakdown frequency per model",
+			expectedHumanDesc:  "ClickHouse truck breakdown frequency per model",
+			expectedTable:      iot.DiagnosticsTableName,
+			expectedSQLQuery: `WITH breakdown_per_truck_per_ten_minutes
+		AS (
+			SELECT name, model, toStartOfInterval(time, INTERVAL 10 minute) AS period, ifNull(avg(status), 0) >= 1 AS broken_down
+			FROM diagnostics
+			WHERE time >= '1970-01-01 00:00:00.000000000' AND time < '1970-01-01 06:00:00.000000000'
+			AND name IS NOT NULL
+			GROUP BY name, model, period
+			), breakdowns_per_truck
+		AS (
+			SELECT name, model, arrayMap(s -> s.2, arraySort(groupArray((period, broken_down)))) AS statuses,
+				arrayCount((prev, cur) -> NOT prev AND cur, arrayPopBack(statuses), arrayPopFront(statuses)) AS truck_breakdowns
+			FROM breakdown_per_truck_per_ten_minutes
+			GROUP BY name, model
+			)
+		SELECT model, sum(truck_breakdowns) AS breakdowns
+		FROM breakdowns_per_truck
+		GROUP BY model
+		ORDER BY model`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TruckBreakdownFrequency(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), cases)
+}
+
+// TestIoTQueryTables checks that every query reads the table it is labeled
+// with and uses the ClickHouse functions for its kind of query.
+func TestIoTQueryTables(t *testing.T) {
+	cases := []struct {
+		fill    func(*IoT, query.Query)
+		table   string
+		keyword string
+	}{
+		{func(i *IoT, q query.Query) { i.LastLocPerTruck(q) }, iot.ReadingsTableName, "argMax((driver, longitude, latitude), time)"},
+		{func(i *IoT, q query.Query) { i.TrucksWithHighLoad(q) }, iot.DiagnosticsTableName, "argMax((driver, current_load, load_capacity), time)"},
+		{func(i *IoT, q query.Query) { i.StationaryTrucks(q) }, iot.ReadingsTableName, "HAVING avg(velocity) < 1"},
+		{func(i *IoT, q query.Query) { i.TrucksWithLongDailySessions(q) }, iot.ReadingsTableName, "HAVING count() > 60"},
+		{func(i *IoT, q query.Query) { i.AvgVsProjectedFuelConsumption(q) }, iot.ReadingsTableName, "GROUP BY fleet"},
+		{func(i *IoT, q query.Query) { i.AvgDailyDrivingDuration(q) }, iot.ReadingsTableName, "toStartOfDay(period)"},
+		{func(i *IoT, q query.Query) { i.AvgLoad(q) }, iot.DiagnosticsTableName, "GROUP BY fleet, model"},
+		{func(i *IoT, q query.Query) { i.DailyTruckActivity(q) }, iot.DiagnosticsTableName, "toStartOfDay(period)"},
+	}
+
+	rand.Seed(123)
+	g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(48*time.Hour), testScale, &BaseGenerator{})
+	for _, c := range cases {
+		q := g.GenerateEmptyQuery()
+		c.fill(g, q)
+		tsq := q.(*query.TimescaleDB)
+		if got := string(tsq.Hypertable); got != c.table {
+			t.Errorf("incorrect table of %s: got %s want %s", tsq.HumanLabel, got, c.table)
+		}
+		sql := string(tsq.SqlQuery)
+		if !strings.Contains(sql, "FROM "+c.table) {
+			t.Errorf("query %s does not read %s:\n%s", tsq.HumanLabel, c.table, sql)
+		}
+		if !strings.Contains(sql, c.keyword) {
+			t.Errorf("query %s does not contain %s:\n%s", tsq.HumanLabel, c.keyword, sql)
+		}
+	}
+}
+
+func TestTenMinutePeriods(t *testing.T) {
+	if got := tenMinutePeriods(5, 4*time.Hour); got != 22 {
+		t.Errorf("incorrect result: got %d want 22", got)
+	}
+	if got := tenMinutePeriods(35, 24*time.Hour); got != 60 {
+		t.Errorf("incorrect result: got %d want 60", got)
+	}
+}
+
+func runTestCases(t *testing.T, testFunc func(*IoT, testCase) query.Query, s time.Time, e time.Time, cases []testCase) {
+	rand.Seed(123) // Setting seed for testing purposes.
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			b := BaseGenerator{}
+			dq, err := b.NewIoT(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating iot generator")
+			}
+			i := dq.(*IoT)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Fatalf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(i, c)
+				}()
+			} else {
+				q := testFunc(i, c)
+
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedTable, c.expectedSQLQuery)
+			}
+		})
+	}
+}
+
+func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, table, sqlQuery string) {
+	tsq, ok := q.(*query.TimescaleDB)
+
+	if !ok {
+		t.Fatal("Filled query is not *query.TimescaleDB type")
+	}
+
+	if got := string(tsq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+
+	if got := string(tsq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+
+	if got := string(tsq.Hypertable); got != table {
+		t.Errorf("incorrect table:\ngot\n%s\nwant\n%s", got, table)
+	}
+
+	if got := string(tsq.SqlQuery); got != sqlQuery {
+		t.Errorf("incorrect SQL query:\ndiff\n%s\ngot\n%s\nwant\n%s", diff.CharacterDiff(got, sqlQuery), got, sqlQuery)
+	}
+}
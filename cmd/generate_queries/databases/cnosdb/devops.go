@@ -0,0 +1,165 @@
+package cnosdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// Devops produces cnosdb-specific queries for all the devops query types.
+type Devops struct {
+	*devops.Core
+	*BaseGenerator
+}
+
+func (d *Devops) getHostWhereWithHostnames(hostnames []string) string {
+	hostnameClauses := []string{}
+	for _, s := range hostnames {
+		hostnameClauses = append(hostnameClauses, fmt.Sprintf("\"hostname\" = '%s'", s))
+	}
+
+	combinedHostnameClause := strings.Join(hostnameClauses, " or ")
+	return "(" + combinedHostnameClause + ")"
+}
+
+func (d *Devops) getHostWhereString(nHosts int) string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return d.getHostWhereWithHostnames(hostnames)
+}
+
+func (d *Devops) getSelectClausesAggMetrics(agg string, metrics []string) []string {
+	selectClauses := make([]string, len(metrics))
+	for i, m := range metrics {
+		selectClauses[i] = fmt.Sprintf("%s(\"%s\") AS \"%s_%s\"", agg, m, agg, m)
+	}
+
+	return selectClauses
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	selectClauses := d.getSelectClausesAggMetrics("max", metrics)
+	whereHosts := d.getHostWhereString(nHosts)
+
+	cnosql := fmt.Sprintf(`SELECT DATE_BIN(INTERVAL '1 minute', time, TIMESTAMP '1970-01-01T00:00:00Z') AS "minute", %s 
+		FROM "cpu" 
+		WHERE %s AND time >= '%s' AND time < '%s' 
+		GROUP BY "minute" 
+		ORDER BY "minute" ASC`,
+		strings.Join(selectClauses, ", "),
+		whereHosts,
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := fmt.Sprintf("cnosdb %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, cnosql)
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	cnosql := fmt.Sprintf(`SELECT DATE_BIN(INTERVAL '1 minute', time, TIMESTAMP '1970-01-01T00:00:00Z') AS "minute", max("usage_user") 
+		FROM "cpu" 
+		WHERE time < '%s' 
+		GROUP BY "minute" 
+		ORDER BY "minute" DESC 
+		LIMIT 5`,
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := "cnosdb max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, cnosql)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+	selectClauses := d.getSelectClausesAggMetrics("avg", metrics)
+
+	cnosql := fmt.Sprintf(`SELECT DATE_BIN(INTERVAL '1 hour', time, TIMESTAMP '1970-01-01T00:00:00Z') AS "hour", "hostname", %s 
+		FROM "cpu" 
+		WHERE time >= '%s' AND time < '%s' 
+		GROUP BY "hour", "hostname" 
+		ORDER BY "hour", "hostname"`,
+		strings.Join(selectClauses, ", "),
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := devops.GetDoubleGroupByLabel("cnosdb", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, cnosql)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	cnosql := `SELECT "cpu".* 
+		FROM "cpu" 
+		INNER JOIN (SELECT "hostname", max(time) AS "max_time" 
+		 FROM "cpu" 
+		 GROUP BY "hostname") AS "last" 
+		ON "cpu"."hostname" = "last"."hostname" AND "cpu".time = "last"."max_time" 
+		ORDER BY "cpu"."hostname"`
+
+	humanLabel := "cnosdb last row per host"
+	humanDesc := humanLabel
+	d.fillInQuery(qi, humanLabel, humanDesc, cnosql)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	var hostWhereClause string
+	if nHosts > 0 {
+		hostWhereClause = fmt.Sprintf("AND %s ", d.getHostWhereString(nHosts))
+	}
+
+	cnosql := fmt.Sprintf(`SELECT * 
+		FROM "cpu" 
+		WHERE "usage_user" > 90.0 AND time >= '%s' AND time < '%s' %s`,
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339),
+		hostWhereClause)
+
+	humanLabel, err := devops.GetHighCPULabel("cnosdb", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, cnosql)
+}
@@ -0,0 +1,244 @@
+package cnosdb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+type DevopsTestCase struct {
+	desc               string
+	input              int
+	fail               bool
+	failMsg            string
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedSQLQuery   string
+}
+
+func TestDevopsGroupByTime(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "zero hosts",
+			input:   0,
+			fail:    true,
+			failMsg: "number of hosts cannot be < 1; got 0",
+		},
+		{
+			desc:    "more hosts than scale",
+			input:   2 * testScale,
+			fail:    true,
+			failMsg: "number of hosts (20) larger than total hosts. See --scale (10)",
+		},
+		{
+			desc:  "two hosts",
+			input: 2,
+
+			expectedHumanLabel: "cnosdb 5 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "cnosdb 5 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T20:16:22Z",
+			expectedSQLQuery: `SELECT DATE_BIN(INTERVAL '1 minute', time, TIMESTAMP '1970-01-01T00:00:00Z') AS "minute", max("usage_user") AS "max_usage_user", max("usage_system") AS "max_usage_system", max("usage_idle") AS "max_usage_idle", max("usage_nice") AS "max_usage_nice", max("usage_iowait") AS "max_usage_iowait" 
+		FROM "cpu" 
+		WHERE ("hostname" = 'host_9' or "hostname" = 'host_3') AND time >= '1970-01-01T20:16:22Z' AND time < '1970-01-01T21:16:22Z' 
+		GROUP BY "minute" 
+		ORDER BY "minute" ASC`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTime(q, c.input, 5, time.Hour)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsGroupByOrderByLimit(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "cnosdb max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "cnosdb max cpu over last 5 min-intervals (random end): 1970-01-01T21:16:22Z",
+			expectedSQLQuery: `SELECT DATE_BIN(INTERVAL '1 minute', time, TIMESTAMP '1970-01-01T00:00:00Z') AS "minute", max("usage_user") 
+		FROM "cpu" 
+		WHERE time < '1970-01-01T21:16:22Z' 
+		GROUP BY "minute" 
+		ORDER BY "minute" DESC 
+		LIMIT 5`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByOrderByLimit(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsGroupByTimeAndPrimaryTag(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "zero metrics",
+			input:   0,
+			fail:    true,
+			failMsg: "cannot get 0 metrics",
+		},
+		{
+			desc:  "one metric",
+			input: 1,
+
+			expectedHumanLabel: "cnosdb mean of 1 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "cnosdb mean of 1 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedSQLQuery: `SELECT DATE_BIN(INTERVAL '1 hour', time, TIMESTAMP '1970-01-01T00:00:00Z') AS "hour", "hostname", avg("usage_user") AS "avg_usage_user" 
+		FROM "cpu" 
+		WHERE time >= '1970-01-01T06:16:22Z' AND time < '1970-01-01T18:16:22Z' 
+		GROUP BY "hour", "hostname" 
+		ORDER BY "hour", "hostname"`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTimeAndPrimaryTag(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsLastPointPerHost(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "cnosdb last row per host",
+			expectedHumanDesc:  "cnosdb last row per host",
+			expectedSQLQuery: `SELECT "cpu".* 
+		FROM "cpu" 
+		INNER JOIN (SELECT "hostname", max(time) AS "max_time" 
+		 FROM "cpu" 
+		 GROUP BY "hostname") AS "last" 
+		ON "cpu"."hostname" = "last"."hostname" AND "cpu".time = "last"."max_time" 
+		ORDER BY "cpu"."hostname"`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.LastPointPerHost(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsHighCPUForHosts(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "negative hosts",
+			input:   -1,
+			fail:    true,
+			failMsg: "nHosts cannot be negative",
+		},
+		{
+			desc:  "all hosts",
+			input: 0,
+
+			expectedHumanLabel: "cnosdb CPU over threshold, all hosts",
+			expectedHumanDesc:  "cnosdb CPU over threshold, all hosts: 1970-01-01T06:16:22Z",
+			expectedSQLQuery: `SELECT * 
+		FROM "cpu" 
+		WHERE "usage_user" > 90.0 AND time >= '1970-01-01T06:16:22Z' AND time < '1970-01-01T18:16:22Z' `,
+		},
+		{
+			desc:  "one host",
+			input: 1,
+
+			expectedHumanLabel: "cnosdb CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "cnosdb CPU over threshold, 1 host(s): 1970-01-01T06:16:22Z",
+			expectedSQLQuery: `SELECT * 
+		FROM "cpu" 
+		WHERE "usage_user" > 90.0 AND time >= '1970-01-01T06:16:22Z' AND time < '1970-01-01T18:16:22Z' AND ("hostname" = 'host_9') `,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.HighCPUForHosts(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func runDevopsTestCases(t *testing.T, testFunc func(*Devops, DevopsTestCase) query.Query, cases []DevopsTestCase) {
+	s := time.Unix(0, 0)
+	e := s.Add(24 * time.Hour)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			dq, err := b.NewDevops(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Fatalf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(d, c)
+				}()
+			} else {
+				q := testFunc(d, c)
+
+				verifySQLQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedSQLQuery)
+			}
+		})
+	}
+}
+
+// verifySQLQuery checks a query sent as the body of a POST to /api/v1/sql.
+func verifySQLQuery(t *testing.T, q query.Query, humanLabel, humanDesc, sql string) {
+	cnosql, ok := q.(*query.HTTP)
+
+	if !ok {
+		t.Fatal("Filled query is not *query.HTTP type")
+	}
+
+	if got := string(cnosql.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+
+	if got := string(cnosql.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+
+	if got := string(cnosql.Method); got != "POST" {
+		t.Errorf("incorrect method:\ngot\n%s\nwant POST", got)
+	}
+
+	if got := string(cnosql.Path); got != "/api/v1/sql" {
+		t.Errorf("incorrect path:\ngot\n%s\nwant /api/v1/sql", got)
+	}
+
+	if got := string(cnosql.Body); got != sql {
+		t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", got, sql)
+	}
+}
@@ -5,13 +5,28 @@ import (
 	"net/url"
 	"time"
 	
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 )
 
+// Query languages the queries can be generated in
+const (
+	QueryLanguageInfluxQL = "influxql"
+	QueryLanguageFlux     = "flux"
+	QueryLanguageSQL      = "sql"
+)
+
+// QueryLanguageChoices are the languages of the InfluxDB 1.x, 2.x and 3.x query APIs.
+var QueryLanguageChoices = []string{QueryLanguageInfluxQL, QueryLanguageFlux, QueryLanguageSQL}
+
 // BaseGenerator contains settings specific for Influx database.
 type BaseGenerator struct {
+	// QueryLanguage is the language of the queries, InfluxQL if empty.
+	QueryLanguage string
+	// Bucket is the bucket the Flux queries read from.
+	Bucket string
 }
 
 // GenerateEmptyQuery returns an empty query.HTTP.
@@ -32,6 +47,33 @@ func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc, influ
 	q.Body = nil
 }
 
+// fillInFluxQuery fills the query struct with a /api/v2/query request posting
+// the Flux script.
+func (g *BaseGenerator) fillInFluxQuery(qi query.Query, humanLabel, humanDesc, flux string) {
+	q := qi.(*query.HTTP)
+	q.HumanLabel = []byte(humanLabel)
+	q.RawQuery = []byte(flux)
+	q.HumanDescription = []byte(humanDesc)
+	q.Method = []byte("POST")
+	q.Path = []byte("/api/v2/query")
+	q.Body = []byte(flux)
+}
+
+// fillInSQLQuery fills the query struct with a /api/v3/query_sql request,
+// answered in CSV to keep the order of the columns.
+func (g *BaseGenerator) fillInSQLQuery(qi query.Query, humanLabel, humanDesc, sql string) {
+	v := url.Values{}
+	v.Set("q", sql)
+	v.Set("format", "csv")
+	q := qi.(*query.HTTP)
+	q.HumanLabel = []byte(humanLabel)
+	q.RawQuery = []byte(sql)
+	q.HumanDescription = []byte(humanDesc)
+	q.Method = []byte("GET")
+	q.Path = []byte(fmt.Sprintf("/api/v3/query_sql?%s", v.Encode()))
+	q.Body = nil
+}
+
 // NewIoT creates a new iot use case query generator.
 func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGenerator, error) {
 	core, err := iot.NewCore(start, end, scale)
@@ -40,10 +82,34 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 		return nil, err
 	}
 	
-	devops := &IoT{
+	switch g.QueryLanguage {
+	case "", QueryLanguageInfluxQL:
+		return &IoT{BaseGenerator: g, Core: core}, nil
+	case QueryLanguageFlux:
+		return &FluxIoT{BaseGenerator: g, Core: core}, nil
+	case QueryLanguageSQL:
+		return &SQLIoT{BaseGenerator: g, Core: core}, nil
+	default:
+		return nil, fmt.Errorf("invalid query language '%s', choices: %v", g.QueryLanguage, QueryLanguageChoices)
+	}
+}
+
+// NewDevops creates a new devops use case query generator.
+// The devops queries are only generated in InfluxQL.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	if g.QueryLanguage != "" && g.QueryLanguage != QueryLanguageInfluxQL {
+		return nil, fmt.Errorf("devops queries are only available in %s, not %s", QueryLanguageInfluxQL, g.QueryLanguage)
+	}
+	core, err := devops.NewCore(start, end, scale)
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	d := &Devops{
 		BaseGenerator: g,
 		Core:          core,
 	}
 	
-	return devops, nil
+	return d, nil
 }
@@ -0,0 +1,217 @@
+package influx
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+type DevopsTestCase struct {
+	desc               string
+	input              int
+	fail               bool
+	failMsg            string
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedQuery      string
+}
+
+func TestDevopsGroupByTime(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "zero hosts",
+			input:   0,
+			fail:    true,
+			failMsg: "number of hosts cannot be < 1; got 0",
+		},
+		{
+			desc:    "more hosts than scale",
+			input:   2 * testScale,
+			fail:    true,
+			failMsg: "number of hosts (20) larger than total hosts. See --scale (10)",
+		},
+		{
+			desc:  "two hosts",
+			input: 2,
+
+			expectedHumanLabel: "Influx 5 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "Influx 5 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T20:16:22Z",
+			expectedQuery: `SELECT max("usage_user"), max("usage_system"), max("usage_idle"), max("usage_nice"), max("usage_iowait") 
+		FROM "cpu" 
+		WHERE ("hostname" = 'host_9' or "hostname" = 'host_3') AND time >= '1970-01-01T20:16:22Z' AND time < '1970-01-01T21:16:22Z' 
+		GROUP BY time(1m)`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTime(q, c.input, 5, time.Hour)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsGroupByOrderByLimit(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "Influx max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "Influx max cpu over last 5 min-intervals (random end): 1970-01-01T21:16:22Z",
+			expectedQuery: `SELECT max("usage_user") 
+		FROM "cpu" 
+		WHERE time < '1970-01-01T21:16:22Z' 
+		GROUP BY time(1m) 
+		ORDER BY time DESC 
+		LIMIT 5`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByOrderByLimit(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsGroupByTimeAndPrimaryTag(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "zero metrics",
+			input:   0,
+			fail:    true,
+			failMsg: "cannot get 0 metrics",
+		},
+		{
+			desc:  "one metric",
+			input: 1,
+
+			expectedHumanLabel: "Influx mean of 1 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "Influx mean of 1 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedQuery: `SELECT mean("usage_user") 
+		FROM "cpu" 
+		WHERE time >= '1970-01-01T06:16:22Z' AND time < '1970-01-01T18:16:22Z' 
+		GROUP BY time(1h), "hostname"`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTimeAndPrimaryTag(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsLastPointPerHost(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "Influx last row per host",
+			expectedHumanDesc:  "Influx last row per host",
+			expectedQuery: `SELECT * 
+		FROM "cpu" 
+		GROUP BY "hostname" 
+		ORDER BY time DESC 
+		LIMIT 1`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.LastPointPerHost(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsHighCPUForHosts(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "negative hosts",
+			input:   -1,
+			fail:    true,
+			failMsg: "nHosts cannot be negative",
+		},
+		{
+			desc:  "all hosts",
+			input: 0,
+
+			expectedHumanLabel: "Influx CPU over threshold, all hosts",
+			expectedHumanDesc:  "Influx CPU over threshold, all hosts: 1970-01-01T06:16:22Z",
+			expectedQuery: `SELECT * 
+		FROM "cpu" 
+		WHERE "usage_user" > 90.0 AND time >= '1970-01-01T06:16:22Z' AND time < '1970-01-01T18:16:22Z' `,
+		},
+		{
+			desc:  "one host",
+			input: 1,
+
+			expectedHumanLabel: "Influx CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "Influx CPU over threshold, 1 host(s): 1970-01-01T06:16:22Z",
+			expectedQuery: `SELECT * 
+		FROM "cpu" 
+		WHERE "usage_user" > 90.0 AND time >= '1970-01-01T06:16:22Z' AND time < '1970-01-01T18:16:22Z' AND ("hostname" = 'host_9') `,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.HighCPUForHosts(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func runDevopsTestCases(t *testing.T, testFunc func(*Devops, DevopsTestCase) query.Query, cases []DevopsTestCase) {
+	s := time.Unix(0, 0)
+	e := s.Add(24 * time.Hour)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			dq, err := b.NewDevops(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Fatalf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(d, c)
+				}()
+			} else {
+				q := testFunc(d, c)
+
+				v := url.Values{}
+				v.Set("q", c.expectedQuery)
+				expectedPath := fmt.Sprintf("/query?%s", v.Encode())
+
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, expectedPath)
+			}
+		})
+	}
+}
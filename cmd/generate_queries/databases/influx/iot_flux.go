@@ -0,0 +1,320 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// FluxIoT produces Flux queries for all the iot query types, run over the
+// /api/v2/query endpoint of InfluxDB 2.x.
+//
+// Every field is a table of its own in Flux: the fields a query compares are
+// pivoted into the columns of a row.
+type FluxIoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+// from returns the start of a Flux query over the given measurement and range,
+// keeping the given fields.
+func (i *FluxIoT) from(measurement, rangeArgs string, fields ...string) string {
+	fieldClauses := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fieldClauses = append(fieldClauses, fmt.Sprintf(`r._field == "%s"`, f))
+	}
+	return fmt.Sprintf(`from(bucket: "%s")
+		|> range(%s)
+		|> filter(fn: (r) => r._measurement == "%s" and (%s))`,
+		i.Bucket,
+		rangeArgs,
+		measurement,
+		strings.Join(fieldClauses, " or "))
+}
+
+// allTime is the range of the queries over all the data.
+const allTime = "start: 0"
+
+// fluxRange returns the range of the queries over the given interval.
+func fluxRange(start, end time.Time) string {
+	return fmt.Sprintf("start: %s, stop: %s", start.UTC().Format(time.RFC3339Nano), end.UTC().Format(time.RFC3339Nano))
+}
+
+// intervalRange returns the range of the queries over the whole interval.
+func (i *FluxIoT) intervalRange() string {
+	return fluxRange(i.Interval.Start(), i.Interval.End())
+}
+
+func (i *FluxIoT) getTruckFilter(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	if err != nil {
+		panic(err.Error())
+	}
+	nameClauses := []string{}
+	for _, s := range names {
+		nameClauses = append(nameClauses, fmt.Sprintf(`r.name == "%s"`, s))
+	}
+	return "(" + strings.Join(nameClauses, " or ") + ")"
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *FluxIoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => %s)
+		|> last()
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> keep(columns: ["name", "driver", "latitude", "longitude"])`,
+		i.from(iot.ReadingsTableName, allTime, "latitude", "longitude"),
+		i.getTruckFilter(nTrucks))
+
+	humanLabel := "Influx Flux last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *FluxIoT) LastLocPerTruck(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => r.fleet == "%s" and exists r.name)
+		|> last()
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> keep(columns: ["name", "driver", "latitude", "longitude"])`,
+		i.from(iot.ReadingsTableName, allTime, "latitude", "longitude"),
+		i.GetRandomFleet())
+
+	humanLabel := "Influx Flux last location per truck"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *FluxIoT) TrucksWithLowFuel(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => r.fleet == "%s" and exists r.name)
+		|> last()
+		|> filter(fn: (r) => r._value <= 0.1)
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> keep(columns: ["name", "driver", "fuel_state"])`,
+		i.from(iot.DiagnosticsTableName, allTime, "fuel_state"),
+		i.GetRandomFleet())
+
+	humanLabel := "Influx Flux trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *FluxIoT) TrucksWithHighLoad(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => r.fleet == "%s" and exists r.name)
+		|> last()
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> filter(fn: (r) => r.current_load >= 0.9 * r.load_capacity)
+		|> keep(columns: ["name", "driver", "current_load", "load_capacity"])`,
+		i.from(iot.DiagnosticsTableName, allTime, "current_load", "load_capacity"),
+		i.GetRandomFleet())
+
+	humanLabel := "Influx Flux trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *FluxIoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => r.fleet == "%s" and exists r.name)
+		|> group(columns: ["name", "driver"])
+		|> mean()
+		|> filter(fn: (r) => r._value < 1.0)
+		|> keep(columns: ["name", "driver"])`,
+		i.from(iot.ReadingsTableName, fluxRange(interval.Start(), interval.End()), "velocity"),
+		i.GetRandomFleet())
+
+	humanLabel := "Influx Flux stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *FluxIoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => r.fleet == "%s" and exists r.name)
+		|> group(columns: ["name", "driver"])
+		|> aggregateWindow(every: 10m, fn: mean, createEmpty: false)
+		|> filter(fn: (r) => r._value > 1.0)
+		|> count()
+		|> filter(fn: (r) => r._value > %d)
+		|> keep(columns: ["name", "driver"])`,
+		i.from(iot.ReadingsTableName, fluxRange(interval.Start(), interval.End()), "velocity"),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "Influx Flux trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *FluxIoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => r.fleet == "%s" and exists r.name)
+		|> group(columns: ["name", "driver"])
+		|> aggregateWindow(every: 10m, fn: mean, createEmpty: false)
+		|> filter(fn: (r) => r._value > 1.0)
+		|> count()
+		|> filter(fn: (r) => r._value > %d)
+		|> keep(columns: ["name", "driver"])`,
+		i.from(iot.ReadingsTableName, fluxRange(interval.Start(), interval.End()), "velocity"),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "Influx Flux trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *FluxIoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> filter(fn: (r) => r.velocity > 1.0 and exists r.fuel_consumption and exists r.nominal_fuel_consumption and exists r.fleet)
+		|> group(columns: ["fleet"])
+		|> reduce(
+			fn: (r, accumulator) => ({
+				count: accumulator.count + 1.0,
+				fuel_consumption: accumulator.fuel_consumption + r.fuel_consumption,
+				nominal_fuel_consumption: accumulator.nominal_fuel_consumption + r.nominal_fuel_consumption,
+			}),
+			identity: {count: 0.0, fuel_consumption: 0.0, nominal_fuel_consumption: 0.0})
+		|> map(fn: (r) => ({
+			fleet: r.fleet,
+			mean_fuel_consumption: r.fuel_consumption / r.count,
+			nominal_fuel_consumption: r.nominal_fuel_consumption / r.count,
+		}))`,
+		i.from(iot.ReadingsTableName, allTime, "velocity", "fuel_consumption", "nominal_fuel_consumption"))
+
+	humanLabel := "Influx Flux average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *FluxIoT) AvgDailyDrivingDuration(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => exists r.name)
+		|> group(columns: ["fleet", "name", "driver"])
+		|> aggregateWindow(every: 10m, fn: mean, createEmpty: false)
+		|> filter(fn: (r) => r._value > 1.0)
+		|> aggregateWindow(every: 1d, fn: count, createEmpty: false)
+		|> map(fn: (r) => ({r with _value: float(v: r._value) / 6.0}))
+		|> rename(columns: {_value: "hours_driven"})
+		|> keep(columns: ["_time", "fleet", "name", "driver", "hours_driven"])`,
+		i.from(iot.ReadingsTableName, i.intervalRange(), "velocity"))
+
+	humanLabel := "Influx Flux average driver driving duration per day"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+//
+// The difference of the driving status of consecutive ten minute periods is 1
+// at the start of a session and -1 at its end, the end of a session follows
+// its start once the periods without a change are left out.
+func (i *FluxIoT) AvgDailyDrivingSession(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => exists r.name)
+		|> group(columns: ["name"])
+		|> aggregateWindow(every: 10m, fn: mean, createEmpty: false)
+		|> map(fn: (r) => ({r with _value: if r._value > 5.0 then 1 else 0}))
+		|> difference()
+		|> filter(fn: (r) => r._value != 0)
+		|> elapsed(unit: 1m)
+		|> filter(fn: (r) => r._value == -1)
+		|> aggregateWindow(every: 1d, fn: mean, column: "elapsed", createEmpty: false)
+		|> rename(columns: {elapsed: "avg_session_minutes"})
+		|> keep(columns: ["_time", "name", "avg_session_minutes"])`,
+		i.from(iot.ReadingsTableName, i.intervalRange(), "velocity"))
+
+	humanLabel := "Influx Flux average driver driving session without stopping per day"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *FluxIoT) AvgLoad(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => exists r.name)
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> map(fn: (r) => ({fleet: r.fleet, model: r.model, _value: r.current_load / r.load_capacity}))
+		|> group(columns: ["fleet", "model"])
+		|> mean()
+		|> rename(columns: {_value: "mean_load_percentage"})`,
+		i.from(iot.DiagnosticsTableName, allTime, "current_load", "load_capacity"))
+
+	humanLabel := "Influx Flux average load per truck model per fleet"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *FluxIoT) DailyTruckActivity(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => exists r.name)
+		|> group(columns: ["fleet", "model", "name"])
+		|> aggregateWindow(every: 10m, fn: mean, createEmpty: false)
+		|> filter(fn: (r) => r._value < 1.0)
+		|> group(columns: ["fleet", "model"])
+		|> aggregateWindow(every: 1d, fn: count, createEmpty: false)
+		|> map(fn: (r) => ({r with _value: float(v: r._value) / 144.0}))
+		|> rename(columns: {_value: "daily_activity"})
+		|> keep(columns: ["_time", "fleet", "model", "daily_activity"])`,
+		i.from(iot.DiagnosticsTableName, i.intervalRange(), "status"))
+
+	humanLabel := "Influx Flux daily truck activity per fleet per model"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+//
+// A breakdown is a ten minute period with a broken down status following one
+// without, where the difference of the status of consecutive periods is 1.
+func (i *FluxIoT) TruckBreakdownFrequency(qi query.Query) {
+	flux := fmt.Sprintf(`%s
+		|> filter(fn: (r) => exists r.name)
+		|> group(columns: ["name", "model"])
+		|> aggregateWindow(every: 10m, fn: mean, createEmpty: false)
+		|> map(fn: (r) => ({r with _value: if r._value >= 1.0 then 1 else 0}))
+		|> difference()
+		|> filter(fn: (r) => r._value == 1)
+		|> group(columns: ["model"])
+		|> count()
+		|> rename(columns: {_value: "breakdowns"})`,
+		i.from(iot.DiagnosticsTableName, i.intervalRange(), "status"))
+
+	humanLabel := "Influx Flux truck breakdown frequency per model"
+	humanDesc := humanLabel
+
+	i.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
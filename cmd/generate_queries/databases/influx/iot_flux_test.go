@@ -0,0 +1,144 @@
+package influx
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+func newFluxIoT(t *testing.T, s, e time.Time) *FluxIoT {
+	rand.Seed(123) // Setting seed for testing purposes.
+	b := &BaseGenerator{QueryLanguage: QueryLanguageFlux, Bucket: "benchmark"}
+	ig, err := b.NewIoT(s, e, testScale)
+	if err != nil {
+		t.Fatalf("Error while creating iot generator: %v", err)
+	}
+	return ig.(*FluxIoT)
+}
+
+func verifyFluxQuery(t *testing.T, q query.Query, humanLabel, humanDesc, flux string) {
+	fq, ok := q.(*query.HTTP)
+	if !ok {
+		t.Fatal("Filled query is not *query.HTTP type")
+	}
+	if got := string(fq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+	if got := string(fq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+	if got := string(fq.Method); got != "POST" {
+		t.Errorf("incorrect method:\ngot\n%s\nwant POST", got)
+	}
+	if got := string(fq.Path); got != "/api/v2/query" {
+		t.Errorf("incorrect path:\ngot\n%s\nwant /api/v2/query", got)
+	}
+	if got := string(fq.Body); got != flux {
+		t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", got, flux)
+	}
+	if got := string(fq.RawQuery); got != flux {
+		t.Errorf("incorrect raw query:\ngot\n%s\nwant\n%s", got, flux)
+	}
+}
+
+func TestFluxLastLocByTruck(t *testing.T) {
+	g := newFluxIoT(t, time.Now(), time.Now())
+	q := g.GenerateEmptyQuery()
+	g.LastLocByTruck(q, 3)
+
+	verifyFluxQuery(t, q,
+		"Influx Flux last location by specific truck",
+		"Influx Flux last location by specific truck: random    3 trucks",
+		`from(bucket: "benchmark")
+		|> range(start: 0)
+		|> filter(fn: (r) => r._measurement == "readings" and (r._field == "latitude" or r._field == "longitude"))
+		|> filter(fn: (r) => (r.name == "truck_5" or r.name == "truck_9" or r.name == "truck_3"))
+		|> last()
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+		|> keep(columns: ["name", "driver", "latitude", "longitude"])`)
+}
+
+func TestFluxStationaryTrucks(t *testing.T) {
+	g := newFluxIoT(t, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour))
+	q := g.GenerateEmptyQuery()
+	g.StationaryTrucks(q)
+
+	verifyFluxQuery(t, q,
+		"Influx Flux stationary trucks",
+		"Influx Flux stationary trucks: with low avg velocity in last 10 minutes",
+		`from(bucket: "benchmark")
+		|> range(start: 1970-01-01T00:36:22.646325489Z, stop: 1970-01-01T00:46:22.646325489Z)
+		|> filter(fn: (r) => r._measurement == "readings" and (r._field == "velocity"))
+		|> filter(fn: (r) => r.fleet == "West" and exists r.name)
+		|> group(columns: ["name", "driver"])
+		|> mean()
+		|> filter(fn: (r) => r._value < 1.0)
+		|> keep(columns: ["name", "driver"])`)
+}
+
+func TestFluxQueriesReadFromBucket(t *testing.T) {
+	g := newFluxIoT(t, time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour))
+	queries := map[string]func(query.Query){
+		"LastLocPerTruck":               g.LastLocPerTruck,
+		"TrucksWithLowFuel":             g.TrucksWithLowFuel,
+		"TrucksWithHighLoad":            g.TrucksWithHighLoad,
+		"TrucksWithLongDrivingSessions": g.TrucksWithLongDrivingSessions,
+		"TrucksWithLongDailySessions":   g.TrucksWithLongDailySessions,
+		"AvgVsProjectedFuelConsumption": g.AvgVsProjectedFuelConsumption,
+		"AvgDailyDrivingDuration":       g.AvgDailyDrivingDuration,
+		"AvgDailyDrivingSession":        g.AvgDailyDrivingSession,
+		"AvgLoad":                       g.AvgLoad,
+		"DailyTruckActivity":            g.DailyTruckActivity,
+		"TruckBreakdownFrequency":       g.TruckBreakdownFrequency,
+	}
+	for name, fn := range queries {
+		q := g.GenerateEmptyQuery()
+		fn(q)
+		fq := q.(*query.HTTP)
+		if !strings.HasPrefix(string(fq.Body), `from(bucket: "benchmark")`) {
+			t.Errorf("%s: query does not read from the bucket:\n%s", name, fq.Body)
+		}
+		if !strings.HasPrefix(string(fq.HumanLabel), "Influx Flux ") {
+			t.Errorf("%s: incorrect human label: %s", name, fq.HumanLabel)
+		}
+	}
+}
+
+func TestNewIoTQueryLanguages(t *testing.T) {
+	cases := []struct {
+		language string
+		want     string
+		fail     bool
+	}{
+		{language: "", want: "*influx.IoT"},
+		{language: QueryLanguageInfluxQL, want: "*influx.IoT"},
+		{language: QueryLanguageFlux, want: "*influx.FluxIoT"},
+		{language: QueryLanguageSQL, want: "*influx.SQLIoT"},
+		{language: "promql", fail: true},
+	}
+	for _, c := range cases {
+		b := &BaseGenerator{QueryLanguage: c.language}
+		g, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), testScale)
+		if c.fail {
+			if err == nil {
+				t.Errorf("language '%s': expected an error", c.language)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("language '%s': unexpected error: %v", c.language, err)
+		}
+		if got := fmt.Sprintf("%T", g); got != c.want {
+			t.Errorf("language '%s': incorrect generator: got %s want %s", c.language, got, c.want)
+		}
+	}
+
+	b := &BaseGenerator{QueryLanguage: QueryLanguageFlux}
+	if _, err := b.NewDevops(time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), testScale); err == nil {
+		t.Errorf("expected an error for devops queries in Flux")
+	}
+}
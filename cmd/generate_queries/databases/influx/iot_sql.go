@@ -0,0 +1,302 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// SQLIoT produces SQL queries for all the iot query types, run over the
+// /api/v3/query_sql endpoint of InfluxDB 3.x.
+//
+// Every measurement is a table of its own with its tags and fields as columns.
+type SQLIoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+func (i *SQLIoT) getTrucksWhereWithNames(names []string) string {
+	nameClauses := []string{}
+	for _, s := range names {
+		nameClauses = append(nameClauses, fmt.Sprintf("name = '%s'", s))
+	}
+
+	combinedHostnameClause := strings.Join(nameClauses, " OR ")
+	return "(" + combinedHostnameClause + ")"
+}
+
+func (i *SQLIoT) getTruckWhereString(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	if err != nil {
+		panic(err.Error())
+	}
+	return i.getTrucksWhereWithNames(names)
+}
+
+// timeWhere returns the condition on the time column of the rows between start and end.
+func timeWhere(start, end time.Time) string {
+	return fmt.Sprintf("time >= '%s' AND time < '%s'", start.UTC().Format(time.RFC3339Nano), end.UTC().Format(time.RFC3339Nano))
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *SQLIoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	sql := fmt.Sprintf(`SELECT name, driver, latitude, longitude
+		FROM (
+			SELECT name, driver, latitude, longitude, row_number() OVER (PARTITION BY name ORDER BY time DESC) AS rn
+			FROM readings
+			WHERE %s
+			) r
+		WHERE rn = 1`,
+		i.getTruckWhereString(nTrucks))
+
+	humanLabel := "Influx SQL last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *SQLIoT) LastLocPerTruck(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, latitude, longitude
+		FROM (
+			SELECT name, driver, latitude, longitude, row_number() OVER (PARTITION BY name ORDER BY time DESC) AS rn
+			FROM readings
+			WHERE fleet = '%s' AND name IS NOT NULL
+			) r
+		WHERE rn = 1`,
+		i.GetRandomFleet())
+
+	humanLabel := "Influx SQL last location per truck"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *SQLIoT) TrucksWithLowFuel(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, fuel_state
+		FROM (
+			SELECT name, driver, fuel_state, row_number() OVER (PARTITION BY name ORDER BY time DESC) AS rn
+			FROM diagnostics
+			WHERE fleet = '%s' AND name IS NOT NULL
+			) d
+		WHERE rn = 1 AND fuel_state <= 0.1`,
+		i.GetRandomFleet())
+
+	humanLabel := "Influx SQL trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *SQLIoT) TrucksWithHighLoad(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, current_load, load_capacity
+		FROM (
+			SELECT name, driver, current_load, load_capacity, row_number() OVER (PARTITION BY name ORDER BY time DESC) AS rn
+			FROM diagnostics
+			WHERE fleet = '%s' AND name IS NOT NULL
+			) d
+		WHERE rn = 1 AND current_load >= 0.9 * load_capacity`,
+		i.GetRandomFleet())
+
+	humanLabel := "Influx SQL trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *SQLIoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM readings
+		WHERE %s AND fleet = '%s' AND name IS NOT NULL
+		GROUP BY name, driver
+		HAVING avg(velocity) < 1`,
+		timeWhere(interval.Start(), interval.End()),
+		i.GetRandomFleet())
+
+	humanLabel := "Influx SQL stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *SQLIoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, date_bin(INTERVAL '10 minutes', time) AS ten_minutes
+			FROM readings
+			WHERE %s AND fleet = '%s' AND name IS NOT NULL
+			GROUP BY name, driver, ten_minutes
+			HAVING avg(velocity) > 1
+			) r
+		GROUP BY name, driver
+		HAVING count(*) > %d`,
+		timeWhere(interval.Start(), interval.End()),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "Influx SQL trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *SQLIoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, date_bin(INTERVAL '10 minutes', time) AS ten_minutes
+			FROM readings
+			WHERE %s AND fleet = '%s' AND name IS NOT NULL
+			GROUP BY name, driver, ten_minutes
+			HAVING avg(velocity) > 1
+			) r
+		GROUP BY name, driver
+		HAVING count(*) > %d`,
+		timeWhere(interval.Start(), interval.End()),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "Influx SQL trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *SQLIoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	sql := `SELECT fleet, avg(fuel_consumption) AS avg_fuel_consumption, avg(nominal_fuel_consumption) AS projected_fuel_consumption
+		FROM readings
+		WHERE velocity > 1 AND fleet IS NOT NULL AND nominal_fuel_consumption IS NOT NULL AND name IS NOT NULL
+		GROUP BY fleet`
+
+	humanLabel := "Influx SQL average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *SQLIoT) AvgDailyDrivingDuration(qi query.Query) {
+	sql := fmt.Sprintf(`WITH ten_minute_driving_sessions
+		AS (
+			SELECT fleet, name, driver, date_bin(INTERVAL '10 minutes', time) AS ten_minutes
+			FROM readings
+			WHERE %s AND name IS NOT NULL
+			GROUP BY fleet, name, driver, ten_minutes
+			HAVING avg(velocity) > 1
+			), daily_total_session
+		AS (
+			SELECT fleet, name, driver, date_trunc('day', ten_minutes) AS day, count(*) / 6.0 AS hours
+			FROM ten_minute_driving_sessions
+			GROUP BY fleet, name, driver, day
+			)
+		SELECT fleet, name, driver, avg(hours) AS avg_daily_hours
+		FROM daily_total_session
+		GROUP BY fleet, name, driver`,
+		timeWhere(i.Interval.Start(), i.Interval.End()))
+
+	humanLabel := "Influx SQL average driver driving duration per day"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+func (i *SQLIoT) AvgDailyDrivingSession(qi query.Query) {
+	sql := fmt.Sprintf(`WITH driver_status
+		AS (
+			SELECT name, date_bin(INTERVAL '10 minutes', time) AS ten_minutes, avg(velocity) > 5 AS driving
+			FROM readings
+			WHERE %s AND name IS NOT NULL
+			GROUP BY name, ten_minutes
+			), driver_status_change
+		AS (
+			SELECT name, ten_minutes AS start, lead(ten_minutes) OVER (PARTITION BY name ORDER BY ten_minutes) AS stop, driving
+			FROM (
+				SELECT name, ten_minutes, driving, lag(driving) OVER (PARTITION BY name ORDER BY ten_minutes) AS prev_driving
+				FROM driver_status
+				) x
+			WHERE x.driving <> x.prev_driving
+			)
+		SELECT name, date_trunc('day', start) AS day, avg((date_part('epoch', stop) - date_part('epoch', start)) / 60) AS avg_session_minutes
+		FROM driver_status_change
+		WHERE driving = true
+		GROUP BY name, day
+		ORDER BY name, day`,
+		timeWhere(i.Interval.Start(), i.Interval.End()))
+
+	humanLabel := "Influx SQL average driver driving session without stopping per day"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *SQLIoT) AvgLoad(qi query.Query) {
+	sql := `SELECT fleet, model, load_capacity, avg(current_load / load_capacity) AS avg_load_percentage
+		FROM diagnostics
+		WHERE name IS NOT NULL
+		GROUP BY fleet, model, load_capacity`
+
+	humanLabel := "Influx SQL average load per truck model per fleet"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *SQLIoT) DailyTruckActivity(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT fleet, model, day, count(*) / 144.0 AS daily_activity
+		FROM (
+			SELECT fleet, model, name, date_trunc('day', time) AS day, date_bin(INTERVAL '10 minutes', time) AS ten_minutes
+			FROM diagnostics
+			WHERE %s AND name IS NOT NULL
+			GROUP BY fleet, model, name, day, ten_minutes
+			HAVING avg(status) < 1
+			) d
+		GROUP BY fleet, model, day
+		ORDER BY day`,
+		timeWhere(i.Interval.Start(), i.Interval.End()))
+
+	humanLabel := "Influx SQL daily truck activity per fleet per model"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+func (i *SQLIoT) TruckBreakdownFrequency(qi query.Query) {
+	sql := fmt.Sprintf(`WITH breakdown_per_truck_per_ten_minutes
+		AS (
+			SELECT name, model, date_bin(INTERVAL '10 minutes', time) AS ten_minutes, avg(status) >= 1 AS broken_down
+			FROM diagnostics
+			WHERE %s AND name IS NOT NULL
+			GROUP BY name, model, ten_minutes
+			), breakdowns_per_truck
+		AS (
+			SELECT model, broken_down, lag(broken_down) OVER (PARTITION BY name ORDER BY ten_minutes) AS prev_broken_down
+			FROM breakdown_per_truck_per_ten_minutes
+			)
+		SELECT model, count(*) AS breakdowns
+		FROM breakdowns_per_truck
+		WHERE broken_down AND NOT prev_broken_down
+		GROUP BY model`,
+		timeWhere(i.Interval.Start(), i.Interval.End()))
+
+	humanLabel := "Influx SQL truck breakdown frequency per model"
+	humanDesc := humanLabel
+
+	i.fillInSQLQuery(qi, humanLabel, humanDesc, sql)
+}
@@ -0,0 +1,101 @@
+package influx
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+func newSQLIoT(t *testing.T, s, e time.Time) *SQLIoT {
+	rand.Seed(123) // Setting seed for testing purposes.
+	b := &BaseGenerator{QueryLanguage: QueryLanguageSQL}
+	ig, err := b.NewIoT(s, e, testScale)
+	if err != nil {
+		t.Fatalf("Error while creating iot generator: %v", err)
+	}
+	return ig.(*SQLIoT)
+}
+
+func verifySQLQuery(t *testing.T, q query.Query, humanLabel, humanDesc, sql string) {
+	sq, ok := q.(*query.HTTP)
+	if !ok {
+		t.Fatal("Filled query is not *query.HTTP type")
+	}
+	if got := string(sq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+	if got := string(sq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+	if got := string(sq.Method); got != "GET" {
+		t.Errorf("incorrect method:\ngot\n%s\nwant GET", got)
+	}
+	v := url.Values{}
+	v.Set("q", sql)
+	v.Set("format", "csv")
+	if got, want := string(sq.Path), fmt.Sprintf("/api/v3/query_sql?%s", v.Encode()); got != want {
+		t.Errorf("incorrect path:\ngot\n%s\nwant\n%s", got, want)
+	}
+	if got := string(sq.RawQuery); got != sql {
+		t.Errorf("incorrect raw query:\ngot\n%s\nwant\n%s", got, sql)
+	}
+	if sq.Body != nil {
+		t.Errorf("body not nil, got %+v", sq.Body)
+	}
+}
+
+func TestSQLLastLocByTruck(t *testing.T) {
+	g := newSQLIoT(t, time.Now(), time.Now())
+	q := g.GenerateEmptyQuery()
+	g.LastLocByTruck(q, 3)
+
+	verifySQLQuery(t, q,
+		"Influx SQL last location by specific truck",
+		"Influx SQL last location by specific truck: random    3 trucks",
+		`SELECT name, driver, latitude, longitude
+		FROM (
+			SELECT name, driver, latitude, longitude, row_number() OVER (PARTITION BY name ORDER BY time DESC) AS rn
+			FROM readings
+			WHERE (name = 'truck_5' OR name = 'truck_9' OR name = 'truck_3')
+			) r
+		WHERE rn = 1`)
+}
+
+func TestSQLStationaryTrucks(t *testing.T) {
+	g := newSQLIoT(t, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour))
+	q := g.GenerateEmptyQuery()
+	g.StationaryTrucks(q)
+
+	verifySQLQuery(t, q,
+		"Influx SQL stationary trucks",
+		"Influx SQL stationary trucks: with low avg velocity in last 10 minutes",
+		`SELECT name, driver
+		FROM readings
+		WHERE time >= '1970-01-01T00:36:22.646325489Z' AND time < '1970-01-01T00:46:22.646325489Z' AND fleet = 'West' AND name IS NOT NULL
+		GROUP BY name, driver
+		HAVING avg(velocity) < 1`)
+}
+
+func TestSQLTrucksWithLongDrivingSessions(t *testing.T) {
+	g := newSQLIoT(t, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour))
+	q := g.GenerateEmptyQuery()
+	g.TrucksWithLongDrivingSessions(q)
+
+	verifySQLQuery(t, q,
+		"Influx SQL trucks with longer driving sessions",
+		"Influx SQL trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+		`SELECT name, driver
+		FROM (
+			SELECT name, driver, date_bin(INTERVAL '10 minutes', time) AS ten_minutes
+			FROM readings
+			WHERE time >= '1970-01-01T00:16:22.646325489Z' AND time < '1970-01-01T04:16:22.646325489Z' AND fleet = 'West' AND name IS NOT NULL
+			GROUP BY name, driver, ten_minutes
+			HAVING avg(velocity) > 1
+			) r
+		GROUP BY name, driver
+		HAVING count(*) > 22`)
+}
@@ -3,23 +3,24 @@ package iotdb
 import (
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 )
 
-// BaseGenerator contains settings specific for TimescaleDB
+// BaseGenerator contains settings specific for IoTDB
 type BaseGenerator struct {
 }
 
-// GenerateEmptyQuery returns an empty query.TDengine.
+// GenerateEmptyQuery returns an empty query.IoTDB.
 func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
-	return query.NewTDengine()
+	return query.NewIoTDB()
 }
 
 // fillInQuery fills the query struct with data.
 func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc, sql string) {
-	q := qi.(*query.TDengine)
+	q := qi.(*query.IoTDB)
 	q.HumanLabel = []byte(humanLabel)
 	q.HumanDescription = []byte(humanDesc)
 	q.SqlQuery = []byte(sql)
@@ -40,3 +41,19 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 
 	return i, nil
 }
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Devops{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return d, nil
+}
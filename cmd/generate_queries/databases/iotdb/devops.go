@@ -0,0 +1,160 @@
+package iotdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// Devops produces IoTDB-specific queries for all the devops query types.
+//
+// The loader writes every series to root.<db>.<measurement>.<tag values>,
+// with the hostname as the first tag value, so host filters are expressed
+// as path prefixes and per-host grouping uses LEVEL = 3.
+type Devops struct {
+	*devops.Core
+	*BaseGenerator
+}
+
+// cpuPath is the path of all the cpu series, whatever the database name is.
+const cpuPath = "root.*." + devops.TableName
+
+func (d *Devops) getHostPaths(nHosts int) string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	paths := make([]string, len(hostnames))
+	for i, h := range hostnames {
+		paths[i] = fmt.Sprintf("%s.%s.**", cpuPath, h)
+	}
+	return strings.Join(paths, ", ")
+}
+
+func (d *Devops) getSelectClausesAggMetrics(agg string, metrics []string) []string {
+	selectClauses := make([]string, len(metrics))
+	for i, m := range metrics {
+		selectClauses[i] = fmt.Sprintf("%s(%s)", agg, m)
+	}
+
+	return selectClauses
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	selectClauses := d.getSelectClausesAggMetrics("MAX_VALUE", metrics)
+
+	sql := fmt.Sprintf(`SELECT %s 
+		FROM %s 
+		GROUP BY ([%d, %d), 1m), LEVEL = 2`,
+		strings.Join(selectClauses, ", "),
+		d.getHostPaths(nHosts),
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel := fmt.Sprintf("IoTDB %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	sql := fmt.Sprintf(`SELECT MAX_VALUE(usage_user) 
+		FROM %s.** 
+		GROUP BY ([%d, %d), 1m), LEVEL = 2 
+		ORDER BY TIME DESC 
+		LIMIT 5`,
+		cpuPath,
+		d.Interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel := "IoTDB max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+	selectClauses := d.getSelectClausesAggMetrics("AVG", metrics)
+
+	sql := fmt.Sprintf(`SELECT %s 
+		FROM %s.** 
+		GROUP BY ([%d, %d), 1h), LEVEL = 3`,
+		strings.Join(selectClauses, ", "),
+		cpuPath,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel := devops.GetDoubleGroupByLabel("IoTDB", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT LAST * 
+		FROM %s.**`,
+		cpuPath)
+
+	humanLabel := "IoTDB last row per host"
+	humanDesc := humanLabel
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	paths := cpuPath + ".**"
+	if nHosts > 0 {
+		paths = d.getHostPaths(nHosts)
+	}
+
+	sql := fmt.Sprintf(`SELECT * 
+		FROM %s 
+		WHERE usage_user > 90.0 AND time >= %d AND time < %d 
+		ALIGN BY DEVICE`,
+		paths,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel, err := devops.GetHighCPULabel("IoTDB", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
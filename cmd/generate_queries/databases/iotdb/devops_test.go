@@ -0,0 +1,207 @@
+package iotdb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+type DevopsTestCase struct {
+	desc               string
+	input              int
+	fail               bool
+	failMsg            string
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedSQLQuery   string
+}
+
+func TestDevopsGroupByTime(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "zero hosts",
+			input:   0,
+			fail:    true,
+			failMsg: "number of hosts cannot be < 1; got 0",
+		},
+		{
+			desc:    "more hosts than scale",
+			input:   2 * testScale,
+			fail:    true,
+			failMsg: "number of hosts (20) larger than total hosts. See --scale (10)",
+		},
+		{
+			desc:  "two hosts",
+			input: 2,
+
+			expectedHumanLabel: "IoTDB 5 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "IoTDB 5 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T20:16:22Z",
+			expectedSQLQuery: `SELECT MAX_VALUE(usage_user), MAX_VALUE(usage_system), MAX_VALUE(usage_idle), MAX_VALUE(usage_nice), MAX_VALUE(usage_iowait) 
+		FROM root.*.cpu.host_9.**, root.*.cpu.host_3.** 
+		GROUP BY ([72982646, 76582646), 1m), LEVEL = 2`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTime(q, c.input, 5, time.Hour)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsGroupByOrderByLimit(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "IoTDB max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "IoTDB max cpu over last 5 min-intervals (random end): 1970-01-01T21:16:22Z",
+			expectedSQLQuery: `SELECT MAX_VALUE(usage_user) 
+		FROM root.*.cpu.** 
+		GROUP BY ([0, 76582646), 1m), LEVEL = 2 
+		ORDER BY TIME DESC 
+		LIMIT 5`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByOrderByLimit(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsGroupByTimeAndPrimaryTag(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "zero metrics",
+			input:   0,
+			fail:    true,
+			failMsg: "cannot get 0 metrics",
+		},
+		{
+			desc:  "one metric",
+			input: 1,
+
+			expectedHumanLabel: "IoTDB mean of 1 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "IoTDB mean of 1 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedSQLQuery: `SELECT AVG(usage_user) 
+		FROM root.*.cpu.** 
+		GROUP BY ([22582646, 65782646), 1h), LEVEL = 3`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTimeAndPrimaryTag(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsLastPointPerHost(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "IoTDB last row per host",
+			expectedHumanDesc:  "IoTDB last row per host",
+			expectedSQLQuery: `SELECT LAST * 
+		FROM root.*.cpu.**`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.LastPointPerHost(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsHighCPUForHosts(t *testing.T) {
+	cases := []DevopsTestCase{
+		{
+			desc:    "negative hosts",
+			input:   -1,
+			fail:    true,
+			failMsg: "nHosts cannot be negative",
+		},
+		{
+			desc:  "all hosts",
+			input: 0,
+
+			expectedHumanLabel: "IoTDB CPU over threshold, all hosts",
+			expectedHumanDesc:  "IoTDB CPU over threshold, all hosts: 1970-01-01T06:16:22Z",
+			expectedSQLQuery: `SELECT * 
+		FROM root.*.cpu.** 
+		WHERE usage_user > 90.0 AND time >= 22582646 AND time < 65782646 
+		ALIGN BY DEVICE`,
+		},
+		{
+			desc:  "one host",
+			input: 1,
+
+			expectedHumanLabel: "IoTDB CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "IoTDB CPU over threshold, 1 host(s): 1970-01-01T06:16:22Z",
+			expectedSQLQuery: `SELECT * 
+		FROM root.*.cpu.host_9.** 
+		WHERE usage_user > 90.0 AND time >= 22582646 AND time < 65782646 
+		ALIGN BY DEVICE`,
+		},
+	}
+
+	testFunc := func(d *Devops, c DevopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.HighCPUForHosts(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func runDevopsTestCases(t *testing.T, testFunc func(*Devops, DevopsTestCase) query.Query, cases []DevopsTestCase) {
+	s := time.Unix(0, 0)
+	e := s.Add(24 * time.Hour)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			dq, err := b.NewDevops(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Fatalf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(d, c)
+				}()
+			} else {
+				q := testFunc(d, c)
+
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedSQLQuery)
+			}
+		})
+	}
+}
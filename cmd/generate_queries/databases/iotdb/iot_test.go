@@ -6,8 +6,6 @@ import (
 	"time"
 
 	"github.com/andreyvit/diff"
-
-	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 )
 
@@ -15,20 +13,18 @@ const (
 	testScale = 10
 )
 
-type testCase struct {
+type IoTTestCase struct {
 	desc               string
+	input              int
 	fail               bool
 	failMsg            string
-	input              int
-	useJSON            bool
 	expectedHumanLabel string
 	expectedHumanDesc  string
-	expectedHypertable string
 	expectedSQLQuery   string
 }
 
 func TestLastLocByTruck(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
 			desc:    "zero trucks",
 			input:   0,
@@ -45,808 +41,348 @@ func TestLastLocByTruck(t *testing.T) {
 			desc:  "one truck",
 			input: 1,
 
-			expectedHumanLabel: "TimescaleDB last location by specific truck",
-			expectedHumanDesc:  "TimescaleDB last location by specific truck: random    1 trucks",
-			expectedHypertable: "readings",
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver, r.*
-		FROM tags t INNER JOIN LATERAL
-			(SELECT longitude, latitude
-			FROM readings r
-			WHERE r.tags_id=t.id
-			ORDER BY time DESC LIMIT 1)  r ON true
-		WHERE t.name IN ('truck_5')`,
-		},
-		{
-			desc:    "one truck use json",
-			input:   1,
-			useJSON: true,
-
-			expectedHumanLabel: "TimescaleDB last location by specific truck",
-			expectedHumanDesc:  "TimescaleDB last location by specific truck: random    1 trucks",
-			expectedHypertable: "readings",
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, r.*
-		FROM tags t INNER JOIN LATERAL
-			(SELECT longitude, latitude
-			FROM readings r
-			WHERE r.tags_id=t.id
-			ORDER BY time DESC LIMIT 1)  r ON true
-		WHERE t.tags_id IN (SELECT id FROM tags WHERE tagset @> '{"name": "truck_9"}')`,
+			expectedHumanLabel: "IoTDB last location by specific truck",
+			expectedHumanDesc:  "IoTDB last location by specific truck: random    1 trucks",
+			expectedSQLQuery: `select name, driver, latitude, longitude from 
+	(select last(*) from readings where name in ("truck_5") group by name,driver )t1;`,
 		},
 		{
-			desc:  "three truck",
+			desc:  "three trucks",
 			input: 3,
 
-			expectedHumanLabel: "TimescaleDB last location by specific truck",
-			expectedHumanDesc:  "TimescaleDB last location by specific truck: random    3 trucks",
-			expectedHypertable: "readings",
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver, r.*
-		FROM tags t INNER JOIN LATERAL
-			(SELECT longitude, latitude
-			FROM readings r
-			WHERE r.tags_id=t.id
-			ORDER BY time DESC LIMIT 1)  r ON true
-		WHERE t.name IN ('truck_3','truck_5','truck_9')`,
+			expectedHumanLabel: "IoTDB last location by specific truck",
+			expectedHumanDesc:  "IoTDB last location by specific truck: random    3 trucks",
+			expectedSQLQuery: `select name, driver, latitude, longitude from 
+	(select last(*) from readings where name in ("truck_5" ,"truck_9" ,"truck_3") group by name,driver )t1;`,
 		},
 	}
 
-	testFunc := func(i *IoT, c testCase) query.Query {
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
 		q := i.GenerateEmptyQuery()
 		i.LastLocByTruck(q, c.input)
 		return q
 	}
 
-	runTestCases(t, testFunc, time.Now(), time.Now(), cases)
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestLastLocPerTruck(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB last location per truck",
-			expectedHumanDesc:  "TimescaleDB last location per truck",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver, r.*
-		FROM tags t INNER JOIN LATERAL
-			(SELECT longitude, latitude
-			FROM readings r
-			WHERE r.tags_id=t.id
-			ORDER BY time DESC LIMIT 1)  r ON true
-		WHERE t.name IS NOT NULL
-		AND t.fleet = 'South'`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB last location per truck",
-			expectedHumanDesc:  "TimescaleDB last location per truck",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, r.*
-		FROM tags t INNER JOIN LATERAL
-			(SELECT longitude, latitude
-			FROM readings r
-			WHERE r.tags_id=t.id
-			ORDER BY time DESC LIMIT 1)  r ON true
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND t.tagset->>'fleet' = 'South'`,
+			expectedHumanLabel: "IoTDB last location per truck",
+			expectedHumanDesc:  "IoTDB last location per truck",
+			expectedSQLQuery: `select name, driver, latitude, longitude from 
+	(select last(*) from readings where fleet="South" group by name,driver )t1;`,
 		},
 	}
 
-	for _, c := range cases {
-		rand.Seed(123)
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Now(), time.Now(), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		g.LastLocPerTruck(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.LastLocPerTruck(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestTrucksWithLowFuel(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB trucks with low fuel",
-			expectedHumanDesc:  "TimescaleDB trucks with low fuel: under 10 percent",
-			expectedHypertable: iot.DiagnosticsTableName,
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver, d.* 
-		FROM tags t INNER JOIN LATERAL 
-			(SELECT fuel_state 
-			FROM diagnostics d 
-			WHERE d.tags_id=t.id 
-			ORDER BY time DESC LIMIT 1) d ON true 
-		WHERE t.name IS NOT NULL
-		AND d.fuel_state < 0.1 
-		AND t.fleet = 'South'`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB trucks with low fuel",
-			expectedHumanDesc:  "TimescaleDB trucks with low fuel: under 10 percent",
-			expectedHypertable: iot.DiagnosticsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, d.* 
-		FROM tags t INNER JOIN LATERAL 
-			(SELECT fuel_state 
-			FROM diagnostics d 
-			WHERE d.tags_id=t.id 
-			ORDER BY time DESC LIMIT 1) d ON true 
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND d.fuel_state < 0.1 
-		AND t.tagset->>'fleet' = 'South'`,
+			expectedHumanLabel: "IoTDB trucks with low fuel",
+			expectedHumanDesc:  "IoTDB trucks with low fuel: under 10 percent",
+			expectedSQLQuery: `select ts,name,driver,fuel_state from 
+		(select last(*) from diagnostics where fuel_state <=0.1
+         and fleet="South" group by name, driver)t1; `,
 		},
 	}
 
-	for _, c := range cases {
-		rand.Seed(123)
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Now(), time.Now(), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		g.TrucksWithLowFuel(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLowFuel(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestTrucksWithHighLoad(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB trucks with high load",
-			expectedHumanDesc:  "TimescaleDB trucks with high load: over 90 percent",
-			expectedHypertable: iot.DiagnosticsTableName,
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver, d.* 
-		FROM tags t INNER JOIN LATERAL 
-			(SELECT current_load 
-			FROM diagnostics d 
-			WHERE d.tags_id=t.id 
-			ORDER BY time DESC LIMIT 1) d ON true 
-		WHERE t.name IS NOT NULL
-		AND d.current_load/t.load_capacity > 0.9 
-		AND t.fleet = 'South'`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB trucks with high load",
-			expectedHumanDesc:  "TimescaleDB trucks with high load: over 90 percent",
-			expectedHypertable: iot.DiagnosticsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, d.* 
-		FROM tags t INNER JOIN LATERAL 
-			(SELECT current_load 
-			FROM diagnostics d 
-			WHERE d.tags_id=t.id 
-			ORDER BY time DESC LIMIT 1) d ON true 
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND d.current_load/t.tagset->>'load_capacity' > 0.9 
-		AND t.tagset->>'fleet' = 'South'`,
+			expectedHumanLabel: "IoTDB trucks with high load",
+			expectedHumanDesc:  "IoTDB trucks with high load: over 90 percent",
+			expectedSQLQuery: `SELECT ts, name, driver, current_load, load_capacity from 
+		(select last(*) from diagnostics where current_load >= 0.9*load_capacity 
+		and fleet="South"
+		group by name, driver, load_capacity) limit 10;`,
 		},
 	}
 
-	for _, c := range cases {
-		rand.Seed(123)
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Now(), time.Now(), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		g.TrucksWithHighLoad(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithHighLoad(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestStationaryTrucks(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB stationary trucks",
-			expectedHumanDesc:  "TimescaleDB stationary trucks: with low avg velocity in last 10 minutes",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver
-		FROM tags t 
-		INNER JOIN readings r ON r.tags_id = t.id 
-		WHERE time >= '1970-01-01 00:36:22.646325 +0000' AND time < '1970-01-01 00:46:22.646325 +0000'
-		AND t.name IS NOT NULL
-		AND t.fleet = 'West' 
-		GROUP BY 1, 2 
-		HAVING avg(r.velocity) < 1`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB stationary trucks",
-			expectedHumanDesc:  "TimescaleDB stationary trucks: with low avg velocity in last 10 minutes",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver
-		FROM tags t 
-		INNER JOIN readings r ON r.tags_id = t.id 
-		WHERE time >= '1970-01-01 00:36:22.646325 +0000' AND time < '1970-01-01 00:46:22.646325 +0000'
-		AND t.tagset->>'name' IS NOT NULL
-		AND t.tagset->>'fleet' = 'West' 
-		GROUP BY 1, 2 
-		HAVING avg(r.velocity) < 1`,
+			expectedHumanLabel: "IoTDB stationary trucks",
+			expectedHumanDesc:  "IoTDB stationary trucks: with low avg velocity in last 10 minutes",
+			expectedSQLQuery: `SELECT avg(velocity) as mean_velocity, name, driver, fleet
+		 FROM readings 
+		 WHERE ts > '1970-01-02T17:46:22Z' AND ts <= '1970-01-02T17:56:22Z' 
+		 AND fleet = 'West' AND mean_velocity < 1
+	     INTERVAL(10m)
+		 GROUP BY name,driver,fleet`,
 		},
 	}
 
-	for _, c := range cases {
-		b := &BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), 10, b)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.StationaryTrucks(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.StationaryTrucks(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestTrucksWithLongDrivingSessions(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB trucks with longer driving sessions",
-			expectedHumanDesc:  "TimescaleDB trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver
-		FROM tags t 
-		INNER JOIN LATERAL 
-			(SELECT  time_bucket('10 minutes', time) AS ten_minutes, tags_id  
-			FROM readings 
-			WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 04:16:22.646325 +0000'
-			GROUP BY ten_minutes, tags_id  
-			HAVING avg(velocity) > 1 
-			ORDER BY ten_minutes, tags_id) AS r ON t.id = r.tags_id 
-		WHERE t.name IS NOT NULL
-		AND t.fleet = 'West'
-		GROUP BY name, driver 
-		HAVING count(r.ten_minutes) > 22`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB trucks with longer driving sessions",
-			expectedHumanDesc:  "TimescaleDB trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver
-		FROM tags t 
-		INNER JOIN LATERAL 
-			(SELECT  time_bucket('10 minutes', time) AS ten_minutes, tags_id  
-			FROM readings 
-			WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 04:16:22.646325 +0000'
-			GROUP BY ten_minutes, tags_id  
-			HAVING avg(velocity) > 1 
-			ORDER BY ten_minutes, tags_id) AS r ON t.id = r.tags_id 
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND t.tagset->>'fleet' = 'West'
-		GROUP BY name, driver 
-		HAVING count(r.ten_minutes) > 22`,
+			expectedHumanLabel: "IoTDB trucks with longer driving sessions",
+			expectedHumanDesc:  "IoTDB trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedSQLQuery: `SELECT name,driver 
+		FROM(SELECT count(*) AS ten_min 
+		 FROM(SELECT avg(velocity) AS mean_velocity 
+		  FROM readings 
+		  WHERE fleet = 'West' AND ts > '1970-01-02T02:16:22Z' AND ts <= '1970-01-02T06:16:22Z'
+          INTERVAL(10m)
+		  GROUP BY name,driver) 
+		 WHERE mean_velocity > 1 
+		 GROUP BY name,driver) 
+		WHERE ten_min_mean_velocity > 22`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.TrucksWithLongDrivingSessions(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLongDrivingSessions(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestTrucksWithLongDailySessions(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB trucks with longer daily sessions",
-			expectedHumanDesc:  "TimescaleDB trucks with longer daily sessions: drove more than 10 hours in the last 24 hours",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver
-		FROM tags t 
-		INNER JOIN LATERAL 
-			(SELECT  time_bucket('10 minutes', time) AS ten_minutes, tags_id  
-			FROM readings 
-			WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-02 00:16:22.646325 +0000'
-			GROUP BY ten_minutes, tags_id  
-			HAVING avg(velocity) > 1 
-			ORDER BY ten_minutes, tags_id) AS r ON t.id = r.tags_id 
-		WHERE t.name IS NOT NULL
-		AND t.fleet = 'West'
-		GROUP BY name, driver 
-		HAVING count(r.ten_minutes) > 60`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB trucks with longer daily sessions",
-			expectedHumanDesc:  "TimescaleDB trucks with longer daily sessions: drove more than 10 hours in the last 24 hours",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver
-		FROM tags t 
-		INNER JOIN LATERAL 
-			(SELECT  time_bucket('10 minutes', time) AS ten_minutes, tags_id  
-			FROM readings 
-			WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-02 00:16:22.646325 +0000'
-			GROUP BY ten_minutes, tags_id  
-			HAVING avg(velocity) > 1 
-			ORDER BY ten_minutes, tags_id) AS r ON t.id = r.tags_id 
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND t.tagset->>'fleet' = 'West'
-		GROUP BY name, driver 
-		HAVING count(r.ten_minutes) > 60`,
+			expectedHumanLabel: "IoTDB trucks with longer daily sessions",
+			expectedHumanDesc:  "IoTDB trucks with longer daily sessions: drove more than 10 hours in the last 24 hours",
+			expectedSQLQuery: `SELECT name,driver 
+		FROM(SELECT count(*) AS ten_min 
+		 FROM(SELECT avg(velocity) AS mean_velocity 
+		  FROM readings 
+		  WHERE fleet = 'West' AND ts > '1970-01-01T18:16:22Z' AND ts <= '1970-01-02T18:16:22Z'
+          INTERVAL(10m)
+		  GROUP BY name,driver) 
+		 WHERE mean_velocity > 1 
+		 GROUP BY name,driver) 
+		WHERE ten_min_mean_velocity > 60`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.TrucksWithLongDailySessions(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLongDailySessions(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestAvgVsProjectedFuelConsumption(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB average vs projected fuel consumption per fleet",
-			expectedHumanDesc:  "TimescaleDB average vs projected fuel consumption per fleet",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.fleet AS fleet, avg(r.fuel_consumption) AS avg_fuel_consumption, 
-		avg(t.nominal_fuel_consumption) AS projected_fuel_consumption
-		FROM tags t
-		INNER JOIN LATERAL(SELECT tags_id, fuel_consumption FROM readings r WHERE r.tags_id = t.id AND velocity > 1) r ON true
-		WHERE t.fleet IS NOT NULL
-		AND t.nominal_fuel_consumption IS NOT NULL 
-		AND t.name IS NOT NULL
-		GROUP BY fleet`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB average vs projected fuel consumption per fleet",
-			expectedHumanDesc:  "TimescaleDB average vs projected fuel consumption per fleet",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'fleet' AS fleet, avg(r.fuel_consumption) AS avg_fuel_consumption, 
-		avg(t.tagset->>'nominal_fuel_consumption') AS projected_fuel_consumption
-		FROM tags t
-		INNER JOIN LATERAL(SELECT tags_id, fuel_consumption FROM readings r WHERE r.tags_id = t.id AND velocity > 1) r ON true
-		WHERE t.tagset->>'fleet' IS NOT NULL
-		AND t.tagset->>'nominal_fuel_consumption' IS NOT NULL 
-		AND t.tagset->>'name' IS NOT NULL
+			expectedHumanLabel: "IoTDB average vs projected fuel consumption per fleet",
+			expectedHumanDesc:  "IoTDB average vs projected fuel consumption per fleet",
+			expectedSQLQuery: `SELECT avg(fuel_consumption) AS mean_fuel_consumption, avg(nominal_fuel_consumption) AS nominal_fuel_consumption 
+		FROM readings 
+		WHERE velocity > 1 
 		GROUP BY fleet`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.AvgVsProjectedFuelConsumption(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.AvgVsProjectedFuelConsumption(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestAvgDailyDrivingDuration(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB average driver driving duration per day",
-			expectedHumanDesc:  "TimescaleDB average driver driving duration per day",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `WITH ten_minute_driving_sessions
-		AS (
-			SELECT time_bucket('10 minutes', TIME) AS ten_minutes, tags_id
-			FROM readings r
-			GROUP BY tags_id, ten_minutes
-			HAVING avg(velocity) > 1
-			), daily_total_session
-		AS (
-			SELECT time_bucket('24 hours', ten_minutes) AS day, tags_id, count(*) / 6 AS hours
-			FROM ten_minute_driving_sessions
-			GROUP BY day, tags_id
-			)
-		SELECT t.fleet AS fleet, t.name AS name, t.driver AS driver, avg(d.hours) AS avg_daily_hours
-		FROM daily_total_session d
-		INNER JOIN tags t ON t.id = d.tags_id
-		GROUP BY fleet, name, driver`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB average driver driving duration per day",
-			expectedHumanDesc:  "TimescaleDB average driver driving duration per day",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `WITH ten_minute_driving_sessions
-		AS (
-			SELECT time_bucket('10 minutes', TIME) AS ten_minutes, tags_id
-			FROM readings r
-			GROUP BY tags_id, ten_minutes
-			HAVING avg(velocity) > 1
-			), daily_total_session
-		AS (
-			SELECT time_bucket('24 hours', ten_minutes) AS day, tags_id, count(*) / 6 AS hours
-			FROM ten_minute_driving_sessions
-			GROUP BY day, tags_id
-			)
-		SELECT t.tagset->>'fleet' AS fleet, t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, avg(d.hours) AS avg_daily_hours
-		FROM daily_total_session d
-		INNER JOIN tags t ON t.id = d.tags_id
-		GROUP BY fleet, name, driver`,
+			expectedHumanLabel: "IoTDB average driver driving duration per day",
+			expectedHumanDesc:  "IoTDB average driver driving duration per day",
+			expectedSQLQuery: `SELECT count(mv)/6 as hours_driven 
+		FROM (SELECT avg(velocity) as mv 
+		 FROM readings 
+		 WHERE ts > '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z'
+		 INTERVAL(10m)
+		 GROUP BY fleet, name, driver) 
+		WHERE ts > '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z' 
+		INTERVAL(1d)`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.AvgDailyDrivingDuration(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.AvgDailyDrivingDuration(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestAvgDailyDrivingSession(t *testing.T) {
-	cases := []testCase{
-		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB average driver driving session without stopping per day",
-			expectedHumanDesc:  "TimescaleDB average driver driving session without stopping per day",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `WITH driver_status
-		AS (
-			SELECT tags_id, time_bucket('10 mins', TIME) AS ten_minutes, avg(velocity) > 5 AS driving
-			FROM readings
-			GROUP BY tags_id, ten_minutes
-			ORDER BY tags_id, ten_minutes
-			), driver_status_change
-		AS (
-			SELECT tags_id, ten_minutes AS start, lead(ten_minutes) OVER (PARTITION BY tags_id ORDER BY ten_minutes) AS stop, driving
-			FROM (
-				SELECT tags_id, ten_minutes, driving, lag(driving) OVER (PARTITION BY tags_id ORDER BY ten_minutes) AS prev_driving
-				FROM driver_status
-				) x
-			WHERE x.driving <> x.prev_driving
-			)
-		SELECT t.name AS name, time_bucket('24 hours', start) AS day, avg(age(stop, start)) AS duration
-		FROM tags t
-		INNER JOIN driver_status_change d ON t.id = d.tags_id
-		WHERE t.name IS NOT NULL
-		AND d.driving = true
-		GROUP BY name, day
-		ORDER BY name, day`,
-		},
-
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB average driver driving session without stopping per day",
-			expectedHumanDesc:  "TimescaleDB average driver driving session without stopping per day",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `WITH driver_status
-		AS (
-			SELECT tags_id, time_bucket('10 mins', TIME) AS ten_minutes, avg(velocity) > 5 AS driving
-			FROM readings
-			GROUP BY tags_id, ten_minutes
-			ORDER BY tags_id, ten_minutes
-			), driver_status_change
-		AS (
-			SELECT tags_id, ten_minutes AS start, lead(ten_minutes) OVER (PARTITION BY tags_id ORDER BY ten_minutes) AS stop, driving
-			FROM (
-				SELECT tags_id, ten_minutes, driving, lag(driving) OVER (PARTITION BY tags_id ORDER BY ten_minutes) AS prev_driving
-				FROM driver_status
-				) x
-			WHERE x.driving <> x.prev_driving
-			)
-		SELECT t.tagset->>'name' AS name, time_bucket('24 hours', start) AS day, avg(age(stop, start)) AS duration
-		FROM tags t
-		INNER JOIN driver_status_change d ON t.id = d.tags_id
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND d.driving = true
-		GROUP BY name, day
-		ORDER BY name, day`,
-		},
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "IoTDB average driver driving session without stopping per day",
+			expectedHumanDesc:  "IoTDB average driver driving session without stopping per day",
+			expectedSQLQuery: `SELECT elapsed 
+		INTO random_measure2_1 
+		FROM (SELECT difference(difka), elapsed(difka, 1m) 
+		 FROM (SELECT difka 
+		  FROM (SELECT difference(mv) AS difka 
+		   FROM (SELECT floor(avg(velocity)/10)/floor(avg(velocity)/10) AS mv 
+		    FROM readings 
+		    WHERE name!='' AND ts > '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z' 
+		    INTERVAL(10m)
+		    GROUP BY name fill(0)) 
+		   GROUP BY name) 
+		  WHERE difka!=0 
+		  GROUP BY name) 
+		 GROUP BY name) 
+		WHERE difference = -2 
+		GROUP BY name; 
+		SELECT avg(elapsed) 
+		FROM random_measure2_1 
+		WHERE ts > '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z' 
+		GROUP BY time(1d),name`,
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.AvgDailyDrivingSession(q)
+		return q
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.AvgDailyDrivingSession(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
-	}
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestAvgLoad(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB average load per truck model per fleet",
-			expectedHumanDesc:  "TimescaleDB average load per truck model per fleet",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.fleet AS fleet, t.model AS model, t.load_capacity AS load_capacity, avg(d.avg_load / t.load_capacity) AS avg_load_percentage
-		FROM tags t
-		INNER JOIN (
-			SELECT tags_id, avg(current_load) AS avg_load
-			FROM diagnostics d
-			GROUP BY tags_id
-			) d ON t.id = d.tags_id
-		WHERE t.name IS NOT NULL
-		GROUP BY fleet, model, load_capacity`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB average load per truck model per fleet",
-			expectedHumanDesc:  "TimescaleDB average load per truck model per fleet",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'fleet' AS fleet, t.tagset->>'model' AS model, t.tagset->>'load_capacity' AS load_capacity, avg(d.avg_load / t.tagset->>'load_capacity') AS avg_load_percentage
-		FROM tags t
-		INNER JOIN (
-			SELECT tags_id, avg(current_load) AS avg_load
-			FROM diagnostics d
-			GROUP BY tags_id
-			) d ON t.id = d.tags_id
-		WHERE t.tagset->>'name' IS NOT NULL
-		GROUP BY fleet, model, load_capacity`,
+			expectedHumanLabel: "IoTDB average load per truck model per fleet",
+			expectedHumanDesc:  "IoTDB average load per truck model per fleet",
+			expectedSQLQuery: `SELECT avg(current_load/load_capacity) AS mean_load_percentage 
+		 FROM diagnostics 
+		 GROUP BY name, fleet, model`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.AvgLoad(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.AvgLoad(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestDailyTruckActivity(t *testing.T) {
-	cases := []testCase{
+	cases := []IoTTestCase{
 		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB daily truck activity per fleet per model",
-			expectedHumanDesc:  "TimescaleDB daily truck activity per fleet per model",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.fleet AS fleet, t.model AS model, y.day, sum(y.ten_mins_per_day) / 144 AS daily_activity
-		FROM tags t
-		INNER JOIN (
-			SELECT time_bucket('24 hours', TIME) AS day, time_bucket('10 minutes', TIME) AS ten_minutes, tags_id, count(*) AS ten_mins_per_day
-			FROM diagnostics
-			GROUP BY day, ten_minutes, tags_id
-			HAVING avg(STATUS) < 1
-			) y ON y.tags_id = t.id
-		WHERE t.name IS NOT NULL
-		GROUP BY fleet, model, y.day
-		ORDER BY y.day`,
-		},
+			desc: "default",
 
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB daily truck activity per fleet per model",
-			expectedHumanDesc:  "TimescaleDB daily truck activity per fleet per model",
-			expectedHypertable: iot.ReadingsTableName,
-			expectedSQLQuery: `SELECT t.tagset->>'fleet' AS fleet, t.tagset->>'model' AS model, y.day, sum(y.ten_mins_per_day) / 144 AS daily_activity
-		FROM tags t
-		INNER JOIN (
-			SELECT time_bucket('24 hours', TIME) AS day, time_bucket('10 minutes', TIME) AS ten_minutes, tags_id, count(*) AS ten_mins_per_day
-			FROM diagnostics
-			GROUP BY day, ten_minutes, tags_id
-			HAVING avg(STATUS) < 1
-			) y ON y.tags_id = t.id
-		WHERE t.tagset->>'name' IS NOT NULL
-		GROUP BY fleet, model, y.day
-		ORDER BY y.day`,
+			expectedHumanLabel: "IoTDB daily truck activity per fleet per model",
+			expectedHumanDesc:  "IoTDB daily truck activity per fleet per model",
+			expectedSQLQuery: `SELECT count(ms)/144 
+		FROM (SELECT avg(status) AS ms 
+		 FROM diagnostics 
+		 WHERE ts >= '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z' 
+         INTERVAL(10m)
+		 GROUP BY model, fleet) 
+		WHERE ts >= '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z' AND ms<1 
+		INTERVAL(1d)
+		GROUP BY  model, fleet`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.DailyTruckActivity(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.DailyTruckActivity(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestTruckBreakdownFrequency(t *testing.T) {
-	cases := []testCase{
-		{
-			desc: "default to using tags",
-
-			expectedHumanLabel: "TimescaleDB truck breakdown frequency per model",
-			expectedHumanDesc:  "TimescaleDB truck breakdown frequency per model",
-			expectedHypertable: iot.DiagnosticsTableName,
-			expectedSQLQuery: `WITH breakdown_per_truck_per_ten_minutes
-		AS (
-			SELECT time_bucket('10 minutes', TIME) AS ten_minutes, tags_id, count(STATUS = 0) / count(*) >= 0.5 AS broken_down
-			FROM diagnostics
-			GROUP BY ten_minutes, tags_id
-			), breakdowns_per_truck
-		AS (
-			SELECT ten_minutes, tags_id, broken_down, lead(broken_down) OVER (
-					PARTITION BY tags_id ORDER BY ten_minutes
-					) AS next_broken_down
-			FROM breakdown_per_truck_per_ten_minutes
-			)
-		SELECT t.model AS model, count(*)
-		FROM tags t
-		INNER JOIN breakdowns_per_truck b ON t.id = b.tags_id
-		WHERE t.name IS NOT NULL
-		AND broken_down = false AND next_broken_down = true
-		GROUP BY model`,
-		},
-
-		{
-			desc: "use JSON",
-
-			useJSON:            true,
-			expectedHumanLabel: "TimescaleDB truck breakdown frequency per model",
-			expectedHumanDesc:  "TimescaleDB truck breakdown frequency per model",
-			expectedHypertable: iot.DiagnosticsTableName,
-			expectedSQLQuery: `WITH breakdown_per_truck_per_ten_minutes
-		AS (
-			SELECT time_bucket('10 minutes', TIME) AS ten_minutes, tags_id, count(STATUS = 0) / count(*) >= 0.5 AS broken_down
-			FROM diagnostics
-			GROUP BY ten_minutes, tags_id
-			), breakdowns_per_truck
-		AS (
-			SELECT ten_minutes, tags_id, broken_down, lead(broken_down) OVER (
-					PARTITION BY tags_id ORDER BY ten_minutes
-					) AS next_broken_down
-			FROM breakdown_per_truck_per_ten_minutes
-			)
-		SELECT t.tagset->>'model' AS model, count(*)
-		FROM tags t
-		INNER JOIN breakdowns_per_truck b ON t.id = b.tags_id
-		WHERE t.tagset->>'name' IS NOT NULL
-		AND broken_down = false AND next_broken_down = true
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "IoTDB truck breakdown frequency per model",
+			expectedHumanDesc:  "IoTDB truck breakdown frequency per model",
+			expectedSQLQuery: `SELECT count(state_changed) 
+		FROM (SELECT difference(broken_down) AS state_changed 
+		 FROM (SELECT floor(2*(sum(nzs)/count(nzs)))/floor(2*(sum(nzs)/count(nzs))) AS broken_down 
+		  FROM (SELECT model, status/status AS nzs 
+		   FROM diagnostics 
+		   WHERE ts >= '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z') 
+		  WHERE ts >= '1970-01-01T00:00:00Z' AND ts < '1970-01-04T00:00:00Z'
+	      INTERVAL(10m)
+		  GROUP BY time(10m),model) 
+		 GROUP BY model) 
+		WHERE state_changed = 1 
 		GROUP BY model`,
 		},
 	}
 
-	for _, c := range cases {
-		b := BaseGenerator{
-			UseJSON: c.useJSON,
-		}
-		ig, err := b.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(25*time.Hour), 10)
-		if err != nil {
-			t.Fatalf("Error while creating iot generator")
-		}
-
-		g := ig.(*IoT)
-
-		q := g.GenerateEmptyQuery()
-		rand.Seed(123)
-		g.TruckBreakdownFrequency(q)
-
-		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TruckBreakdownFrequency(q)
+		return q
 	}
+
+	runIoTTestCases(t, testFunc, cases)
 }
 
 func TestTenMinutePeriods(t *testing.T) {
@@ -892,19 +428,19 @@ func TestTenMinutePeriods(t *testing.T) {
 			t.Errorf("incorrect result for %.2f minutes per hour, duration %s: got %d want %d", c.minutesPerHour, c.duration.String(), got, c.result)
 		}
 	}
-
 }
 
-func runTestCases(t *testing.T, testFunc func(*IoT, testCase) query.Query, s time.Time, e time.Time, cases []testCase) {
-	rand.Seed(123) // Setting seed for testing purposes.
+func runIoTTestCases(t *testing.T, testFunc func(*IoT, IoTTestCase) query.Query, cases []IoTTestCase) {
+	s := time.Unix(0, 0)
+	e := s.Add(72 * time.Hour)
 
 	for _, c := range cases {
 		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
 			b := BaseGenerator{}
-			b.UseJSON = c.useJSON
 			dq, err := b.NewIoT(s, e, testScale)
 			if err != nil {
-				t.Fatalf("Error while creating devops generator")
+				t.Fatalf("Error while creating iot generator")
 			}
 			i := dq.(*IoT)
 
@@ -926,31 +462,28 @@ func runTestCases(t *testing.T, testFunc func(*IoT, testCase) query.Query, s tim
 			} else {
 				q := testFunc(i, c)
 
-				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedSQLQuery)
 			}
 		})
 	}
 }
-func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, hypertable, sqlQuery string) {
-	tsq, ok := q.(*query.TimescaleDB)
+
+func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, sqlQuery string) {
+	sq, ok := q.(*query.IoTDB)
 
 	if !ok {
-		t.Fatal("Filled query is not *query.TimescaleDB type")
+		t.Fatal("Filled query is not *query.IoTDB type")
 	}
 
-	if got := string(tsq.HumanLabel); got != humanLabel {
+	if got := string(sq.HumanLabel); got != humanLabel {
 		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
 	}
 
-	if got := string(tsq.HumanDescription); got != humanDesc {
+	if got := string(sq.HumanDescription); got != humanDesc {
 		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
 	}
 
-	if got := string(tsq.Hypertable); got != hypertable {
-		t.Errorf("incorrect hypertable:\ngot\n%s\nwant\n%s", got, hypertable)
-	}
-
-	if got := string(tsq.SqlQuery); got != sqlQuery {
+	if got := string(sq.SqlQuery); got != sqlQuery {
 		t.Errorf("incorrect SQL query:\ndiff\n%s\ngot\n%s\nwant\n%s", diff.CharacterDiff(got, sqlQuery), got, sqlQuery)
 	}
 }
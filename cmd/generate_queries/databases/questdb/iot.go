@@ -0,0 +1,328 @@
+package questdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// timeFmt is the format of the timestamp literals compared to "timestamp",
+// the designated timestamp of the tables created by line protocol writes.
+const timeFmt = "2006-01-02T15:04:05.000000Z"
+
+// IoT produces QuestDB-specific queries for all the iot query types.
+//
+// The latest reading of every truck uses LATEST ON and the aggregations over
+// time windows SAMPLE BY, both QuestDB extensions of SQL.
+type IoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+// NewIoT makes an IoT object ready to generate Queries.
+func NewIoT(start, end time.Time, scale int, g *BaseGenerator) *IoT {
+	c, err := iot.NewCore(start, end, scale)
+	databases.PanicIfErr(err)
+	return &IoT{
+		Core:          c,
+		BaseGenerator: g,
+	}
+}
+
+func (i *IoT) getTrucksWhereWithNames(names []string) string {
+	nameClauses := []string{}
+	for _, s := range names {
+		nameClauses = append(nameClauses, fmt.Sprintf("'%s'", s))
+	}
+	return fmt.Sprintf("name IN (%s)", strings.Join(nameClauses, ","))
+}
+
+// getTruckWhereString gets multiple random truck names and creates a WHERE SQL statement for these names.
+func (i *IoT) getTruckWhereString(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	databases.PanicIfErr(err)
+	return i.getTrucksWhereWithNames(names)
+}
+
+// getTimeWhereString returns the condition on the designated timestamp
+// selecting the whole query interval.
+func (i *IoT) getTimeWhereString() string {
+	return fmt.Sprintf("timestamp >= '%s' AND timestamp < '%s'",
+		i.Interval.Start().UTC().Format(timeFmt),
+		i.Interval.End().UTC().Format(timeFmt))
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *IoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	sql := fmt.Sprintf(`SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE %s
+		LATEST ON timestamp PARTITION BY name`,
+		i.getTruckWhereString(nTrucks))
+
+	humanLabel := "QuestDB last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *IoT) LastLocPerTruck(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE name IS NOT NULL
+		AND fleet = '%s'
+		LATEST ON timestamp PARTITION BY name`,
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB last location per truck"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *IoT) TrucksWithLowFuel(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, fuel_state
+		FROM (
+			SELECT timestamp, name, driver, fuel_state
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE fuel_state < 0.1`,
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *IoT) TrucksWithHighLoad(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, current_load, load_capacity
+		FROM (
+			SELECT timestamp, name, driver, current_load, load_capacity
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE current_load / load_capacity > 0.9`,
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *IoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE timestamp >= '%s' AND timestamp < '%s'
+			AND name IS NOT NULL
+			AND fleet = '%s')
+		WHERE mean_velocity < 1`,
+		interval.Start().UTC().Format(timeFmt),
+		interval.End().UTC().Format(timeFmt),
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, count() AS ten_minutes
+			FROM (
+				SELECT timestamp, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE timestamp >= '%s' AND timestamp < '%s'
+				AND name IS NOT NULL
+				AND fleet = '%s'
+				SAMPLE BY 10m)
+			WHERE mean_velocity > 1)
+		WHERE ten_minutes > %d`,
+		interval.Start().UTC().Format(timeFmt),
+		interval.End().UTC().Format(timeFmt),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "QuestDB trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, count() AS ten_minutes
+			FROM (
+				SELECT timestamp, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE timestamp >= '%s' AND timestamp < '%s'
+				AND name IS NOT NULL
+				AND fleet = '%s'
+				SAMPLE BY 10m)
+			WHERE mean_velocity > 1)
+		WHERE ten_minutes > %d`,
+		interval.Start().UTC().Format(timeFmt),
+		interval.End().UTC().Format(timeFmt),
+		i.GetRandomFleet(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "QuestDB trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *IoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	sql := `SELECT fleet, avg(fuel_consumption) AS avg_fuel_consumption,
+		avg(nominal_fuel_consumption) AS projected_fuel_consumption
+		FROM readings
+		WHERE velocity > 1
+		AND name IS NOT NULL
+		AND fleet IS NOT NULL`
+
+	humanLabel := "QuestDB average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *IoT) AvgDailyDrivingDuration(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT timestamp, fleet, name, driver, count() / 6.0 AS hours_driven
+		FROM (
+			SELECT timestamp, fleet, name, driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE %s
+			AND name IS NOT NULL
+			SAMPLE BY 10m)
+		WHERE mean_velocity > 1
+		SAMPLE BY 1d`,
+		i.getTimeWhereString())
+
+	humanLabel := "QuestDB average driver driving duration per day"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+func (i *IoT) AvgDailyDrivingSession(qi query.Query) {
+	sql := fmt.Sprintf(`WITH driver_status
+		AS (
+			SELECT timestamp, name, avg(velocity) > 5 AS driving
+			FROM readings
+			WHERE %s
+			AND name IS NOT NULL
+			SAMPLE BY 10m
+			), driver_status_change
+		AS (
+			SELECT name, start, lead(start) OVER (PARTITION BY name ORDER BY start) AS stop, driving
+			FROM (
+				SELECT name, timestamp AS start, driving, lag(driving) OVER (PARTITION BY name ORDER BY timestamp) AS prev_driving
+				FROM driver_status
+				)
+			WHERE driving <> prev_driving
+			)
+		SELECT name, timestamp_floor('d', start) AS day, avg(datediff('m', start, stop)) AS duration_minutes
+		FROM driver_status_change
+		WHERE driving = true
+		ORDER BY name, day`,
+		i.getTimeWhereString())
+
+	humanLabel := "QuestDB average driver driving session without stopping per day"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *IoT) AvgLoad(qi query.Query) {
+	sql := `SELECT fleet, model, avg(current_load / load_capacity) AS avg_load_percentage
+		FROM diagnostics
+		WHERE name IS NOT NULL`
+
+	humanLabel := "QuestDB average load per truck model per fleet"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *IoT) DailyTruckActivity(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT timestamp, fleet, model, count() / 144.0 AS daily_activity
+		FROM (
+			SELECT timestamp, fleet, model, name, avg(status) AS mean_status
+			FROM diagnostics
+			WHERE %s
+			AND name IS NOT NULL
+			SAMPLE BY 10m)
+		WHERE mean_status < 1
+		SAMPLE BY 1d`,
+		i.getTimeWhereString())
+
+	humanLabel := "QuestDB daily truck activity per fleet per model"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
+	sql := fmt.Sprintf(`WITH breakdown_per_truck_per_ten_minutes
+		AS (
+			SELECT timestamp, name, model, avg(status) >= 1 AS broken_down
+			FROM diagnostics
+			WHERE %s
+			AND name IS NOT NULL
+			SAMPLE BY 10m
+			), breakdowns_per_truck
+		AS (
+			SELECT model, broken_down, lead(broken_down) OVER (
+					PARTITION BY name ORDER BY timestamp
+					) AS next_broken_down
+			FROM breakdown_per_truck_per_ten_minutes
+			)
+		SELECT model, count() AS breakdowns
+		FROM breakdowns_per_truck
+		WHERE broken_down = false AND next_broken_down = true`,
+		i.getTimeWhereString())
+
+	humanLabel := "QuestDB truck breakdown frequency per model"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
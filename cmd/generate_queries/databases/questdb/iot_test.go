@@ -0,0 +1,254 @@
+package questdb
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+const (
+	testScale = 10
+)
+
+type testCase struct {
+	desc               string
+	fail               bool
+	failMsg            string
+	input              int
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedTable      string
+	expectedSQLQuery   string
+}
+
+func TestLastLocByTruck(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:    "zero trucks",
+			input:   0,
+			fail:    true,
+			failMsg: "number of trucks cannot be < 1; got 0",
+		},
+		{
+			desc:  "three trucks",
+			input: 3,
+
+			expectedHumanLabel: "QuestDB last location by specific truck",
+			expectedHumanDesc:  "QuestDB last location by specific truck: random    3 trucks",
+			expectedTable:      iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE name IN ('truck_5','truck_9','truck_3')
+		LATEST ON timestamp PARTITION BY name`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.LastLocByTruck(q, c.input)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), cases)
+}
+
+func TestTrucksWithLowFuel(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "QuestDB trucks with low fuel",
+			expectedHumanDesc:  "QuestDB trucks with low fuel: under 10 percent",
+			expectedTable:      iot.DiagnosticsTableName,
+			expectedSQLQuery: `SELECT name, driver, fuel_state
+		FROM (
+			SELECT timestamp, name, driver, fuel_state
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = 'South'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE fuel_state < 0.1`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLowFuel(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), cases)
+}
+
+func TestTrucksWithLongDrivingSessions(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "QuestDB trucks with longer driving sessions",
+			expectedHumanDesc:  "QuestDB trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedTable:      iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT name, driver
+		FROM (
+			SELECT name, driver, count() AS ten_minutes
+			FROM (
+				SELECT timestamp, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE timestamp >= '1970-01-01T00:16:22.646325Z' AND timestamp < '1970-01-01T04:16:22.646325Z'
+				AND name IS NOT NULL
+				AND fleet = 'West'
+				SAMPLE BY 10m)
+			WHERE mean_velocity > 1)
+		WHERE ten_minutes > 22`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLongDrivingSessions(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), cases)
+}
+
+func TestDailyTruckActivity(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "QuestDB daily truck activity per fleet per model",
+			expectedHumanDesc:  "QuestDB daily truck activity per fleet per model",
+			expectedTable:      iot.DiagnosticsTableName,
+			expectedSQLQuery: `SELECT timestamp, fleet, model, count() / 144.0 AS daily_activity
+		FROM (
+			SELECT timestamp, fleet, model, name, avg(status) AS mean_status
+			FROM diagnostics
+			WHERE timestamp >= '1970-01-01T00:00:00.000000Z' AND timestamp < '1970-01-01T06:00:00.000000Z'
+			AND name IS NOT NULL
+			SAMPLE BY 10m)
+		WHERE mean_status < 1
+		SAMPLE BY 1d`,
+		},
+	}
+
+	testFunc := func(i *IoT, c testCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.DailyTruckActivity(q)
+		return q
+	}
+
+	runTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), cases)
+}
+
+// TestIoTQueryTables checks that every query reads the table it is labeled
+// with and uses the QuestDB extensions for its kind of query.
+func TestIoTQueryTables(t *testing.T) {
+	cases := []struct {
+		fill    func(*IoT, query.Query)
+		table   string
+		keyword string
+	}{
+		{func(i *IoT, q query.Query) { i.LastLocPerTruck(q) }, iot.ReadingsTableName, "LATEST ON"},
+		{func(i *IoT, q query.Query) { i.TrucksWithHighLoad(q) }, iot.DiagnosticsTableName, "LATEST ON"},
+		{func(i *IoT, q query.Query) { i.StationaryTrucks(q) }, iot.ReadingsTableName, "avg(velocity)"},
+		{func(i *IoT, q query.Query) { i.TrucksWithLongDailySessions(q) }, iot.ReadingsTableName, "SAMPLE BY 10m"},
+		{func(i *IoT, q query.Query) { i.AvgVsProjectedFuelConsumption(q) }, iot.ReadingsTableName, "avg(nominal_fuel_consumption)"},
+		{func(i *IoT, q query.Query) { i.AvgDailyDrivingDuration(q) }, iot.ReadingsTableName, "SAMPLE BY 1d"},
+		{func(i *IoT, q query.Query) { i.AvgDailyDrivingSession(q) }, iot.ReadingsTableName, "SAMPLE BY 10m"},
+		{func(i *IoT, q query.Query) { i.AvgLoad(q) }, iot.DiagnosticsTableName, "avg(current_load / load_capacity)"},
+		{func(i *IoT, q query.Query) { i.TruckBreakdownFrequency(q) }, iot.DiagnosticsTableName, "SAMPLE BY 10m"},
+	}
+
+	rand.Seed(123)
+	g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(48*time.Hour), testScale, &BaseGenerator{})
+	for _, c := range cases {
+		q := g.GenerateEmptyQuery()
+		c.fill(g, q)
+		tsq := q.(*query.TimescaleDB)
+		if got := string(tsq.Hypertable); got != c.table {
+			t.Errorf("incorrect table of %s: got %s want %s", tsq.HumanLabel, got, c.table)
+		}
+		sql := string(tsq.SqlQuery)
+		if !strings.Contains(sql, "FROM "+c.table) {
+			t.Errorf("query %s does not read %s:\n%s", tsq.HumanLabel, c.table, sql)
+		}
+		if !strings.Contains(sql, c.keyword) {
+			t.Errorf("query %s does not contain %s:\n%s", tsq.HumanLabel, c.keyword, sql)
+		}
+	}
+}
+
+func TestTenMinutePeriods(t *testing.T) {
+	if got := tenMinutePeriods(5, 4*time.Hour); got != 22 {
+		t.Errorf("incorrect result: got %d want 22", got)
+	}
+	if got := tenMinutePeriods(35, 24*time.Hour); got != 60 {
+		t.Errorf("incorrect result: got %d want 60", got)
+	}
+}
+
+func runTestCases(t *testing.T, testFunc func(*IoT, testCase) query.Query, s time.Time, e time.Time, cases []testCase) {
+	rand.Seed(123) // Setting seed for testing purposes.
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			b := BaseGenerator{}
+			dq, err := b.NewIoT(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating iot generator")
+			}
+			i := dq.(*IoT)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Fatalf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(i, c)
+				}()
+			} else {
+				q := testFunc(i, c)
+
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedTable, c.expectedSQLQuery)
+			}
+		})
+	}
+}
+
+func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, table, sqlQuery string) {
+	tsq, ok := q.(*query.TimescaleDB)
+
+	if !ok {
+		t.Fatal("Filled query is not *query.TimescaleDB type")
+	}
+
+	if got := string(tsq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+
+	if got := string(tsq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+
+	if got := string(tsq.Hypertable); got != table {
+		t.Errorf("incorrect table:\ngot\n%s\nwant\n%s", got, table)
+	}
+
+	if got := string(tsq.SqlQuery); got != sqlQuery {
+		t.Errorf("incorrect SQL query:\ndiff\n%s\ngot\n%s\nwant\n%s", diff.CharacterDiff(got, sqlQuery), got, sqlQuery)
+	}
+}
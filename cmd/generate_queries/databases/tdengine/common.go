@@ -3,6 +3,7 @@ package tdengine
 import (
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
@@ -40,3 +41,19 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 
 	return i, nil
 }
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Devops{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return d, nil
+}
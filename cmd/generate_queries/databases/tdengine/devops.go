@@ -0,0 +1,152 @@
+package tdengine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// Devops produces TDengine-specific queries for all the devops query types.
+type Devops struct {
+	*devops.Core
+	*BaseGenerator
+}
+
+func (d *Devops) getHostWhereString(nHosts int) string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	for j := range hostnames {
+		hostnames[j] = fmt.Sprintf(`'%s'`, hostnames[j])
+	}
+	return fmt.Sprintf("hostname in (%s)", strings.Join(hostnames, " ,"))
+}
+
+func (d *Devops) getSelectClausesAggMetrics(agg string, metrics []string) []string {
+	selectClauses := make([]string, len(metrics))
+	for i, m := range metrics {
+		selectClauses[i] = fmt.Sprintf("%s(%s)", agg, m)
+	}
+
+	return selectClauses
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	selectClauses := d.getSelectClausesAggMetrics("max", metrics)
+
+	sql := fmt.Sprintf(`SELECT %s 
+		FROM cpu 
+		WHERE %s AND ts >= '%s' AND ts < '%s' 
+		INTERVAL(1m)`,
+		strings.Join(selectClauses, ", "),
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := fmt.Sprintf("TDengine %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	sql := fmt.Sprintf(`SELECT max(usage_user) 
+		FROM cpu 
+		WHERE ts < '%s' 
+		INTERVAL(1m) 
+		ORDER BY ts DESC 
+		LIMIT 5`,
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := "TDengine max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+	selectClauses := d.getSelectClausesAggMetrics("avg", metrics)
+
+	sql := fmt.Sprintf(`SELECT %s 
+		FROM cpu 
+		WHERE ts >= '%s' AND ts < '%s' 
+		INTERVAL(1h) 
+		GROUP BY hostname`,
+		strings.Join(selectClauses, ", "),
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := devops.GetDoubleGroupByLabel("TDengine", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	sql := `SELECT last_row(*) 
+		FROM cpu 
+		GROUP BY hostname`
+
+	humanLabel := "TDengine last row per host"
+	humanDesc := humanLabel
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	var hostWhereClause string
+	if nHosts > 0 {
+		hostWhereClause = fmt.Sprintf("AND %s ", d.getHostWhereString(nHosts))
+	}
+
+	sql := fmt.Sprintf(`SELECT * 
+		FROM cpu 
+		WHERE usage_user > 90.0 AND ts >= '%s' AND ts < '%s' %s`,
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339),
+		hostWhereClause)
+
+	humanLabel, err := devops.GetHighCPULabel("TDengine", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
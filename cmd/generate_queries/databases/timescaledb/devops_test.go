@@ -0,0 +1,133 @@
+package timescaledb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+type devopsTestCase struct {
+	desc               string
+	input              int
+	useTags            bool
+	useJSON            bool
+	useTimeBucket      bool
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedSQLQuery   string
+}
+
+func TestDevopsGroupByTime(t *testing.T) {
+	cases := []devopsTestCase{
+		{
+			desc:  "plain columns",
+			input: 2,
+
+			expectedHumanLabel: "TimescaleDB 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TimescaleDB 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T20:16:22Z",
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/60)*60) AS minute,
+       max(usage_user) as max_usage_user, max(usage_system) as max_usage_system
+       FROM cpu
+       WHERE hostname IN ('host_9','host_3') AND time >= '1970-01-01 20:16:22.646325 +0000' AND time < '1970-01-01 21:16:22.646325 +0000'
+       GROUP BY minute ORDER BY minute ASC`,
+		},
+		{
+			desc:          "json tags with time_bucket",
+			input:         2,
+			useJSON:       true,
+			useTimeBucket: true,
+
+			expectedHumanLabel: "TimescaleDB 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TimescaleDB 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-01T20:16:22Z",
+			expectedSQLQuery: `SELECT time_bucket('60 seconds', time) AS minute,
+       max(usage_user) as max_usage_user, max(usage_system) as max_usage_system
+       FROM cpu
+       WHERE tags_id IN (SELECT id FROM tags WHERE tagset @> '{"hostname": "host_9"}' OR tagset @> '{"hostname": "host_3"}') AND time >= '1970-01-01 20:16:22.646325 +0000' AND time < '1970-01-01 21:16:22.646325 +0000'
+       GROUP BY minute ORDER BY minute ASC`,
+		},
+	}
+
+	testFunc := func(d *Devops, c devopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.GroupByTime(q, c.input, 2, time.Hour)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsLastPointPerHost(t *testing.T) {
+	cases := []devopsTestCase{
+		{
+			desc:    "tags table",
+			useTags: true,
+
+			expectedHumanLabel: "TimescaleDB last row per host",
+			expectedHumanDesc:  "TimescaleDB last row per host",
+			expectedSQLQuery:   `SELECT DISTINCT ON (t.hostname) * FROM tags t INNER JOIN LATERAL(SELECT * FROM cpu c WHERE c.tags_id = t.id ORDER BY time DESC LIMIT 1) AS b ON true ORDER BY t.hostname, b.time DESC`,
+		},
+		{
+			desc: "plain columns",
+
+			expectedHumanLabel: "TimescaleDB last row per host",
+			expectedHumanDesc:  "TimescaleDB last row per host",
+			expectedSQLQuery:   `SELECT DISTINCT ON (hostname) * FROM cpu ORDER BY hostname, time DESC`,
+		},
+	}
+
+	testFunc := func(d *Devops, c devopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.LastPointPerHost(q)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func TestDevopsHighCPUForHosts(t *testing.T) {
+	cases := []devopsTestCase{
+		{
+			desc:    "one host",
+			input:   1,
+			useTags: true,
+
+			expectedHumanLabel: "TimescaleDB CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "TimescaleDB CPU over threshold, 1 host(s): 1970-01-01T11:54:10Z",
+			expectedSQLQuery:   `SELECT * FROM cpu WHERE usage_user > 90.0 and time >= '1970-01-01 11:54:10.138978 +0000' AND time < '1970-01-01 23:54:10.138978 +0000' AND tags_id IN (SELECT id FROM tags WHERE hostname IN ('host_5'))`,
+		},
+	}
+
+	testFunc := func(d *Devops, c devopsTestCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.HighCPUForHosts(q, c.input)
+		return q
+	}
+
+	runDevopsTestCases(t, testFunc, cases)
+}
+
+func runDevopsTestCases(t *testing.T, testFunc func(*Devops, devopsTestCase) query.Query, cases []devopsTestCase) {
+	s := time.Unix(0, 0)
+	e := s.Add(24 * time.Hour)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{
+				UseTags:       c.useTags,
+				UseJSON:       c.useJSON,
+				UseTimeBucket: c.useTimeBucket,
+			}
+			dq, err := b.NewDevops(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+
+			q := testFunc(d, c)
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, "cpu", c.expectedSQLQuery)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// timeFmt is the format of the evaluation times of the queries, in the
+// millisecond precision of the stored samples.
+const timeFmt = "2006-01-02T15:04:05.999Z07:00"
+
+// BaseGenerator contains settings specific for VictoriaMetrics.
+type BaseGenerator struct {
+}
+
+// GenerateEmptyQuery returns an empty query.HTTP.
+func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
+	return query.NewHTTP()
+}
+
+// fillInQuery fills the query struct with a /api/v1/query_range request
+// evaluating metricsql at every step from start to end. A query evaluated
+// once has the same start and end.
+func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc, metricsql string, start, end time.Time, step time.Duration) {
+	v := url.Values{}
+	v.Set("query", metricsql)
+	v.Set("start", start.UTC().Format(timeFmt))
+	v.Set("end", end.UTC().Format(timeFmt))
+	v.Set("step", promDuration(step))
+	q := qi.(*query.HTTP)
+	q.HumanLabel = []byte(humanLabel)
+	q.RawQuery = []byte(metricsql)
+	q.HumanDescription = []byte(humanDesc)
+	q.Method = []byte("GET")
+	q.Path = []byte(fmt.Sprintf("/api/v1/query_range?%s", v.Encode()))
+	q.Body = nil
+	q.StartTimestamp = start.UnixNano()
+	q.EndTimestamp = end.UnixNano()
+}
+
+// UnsupportedQueryTypes returns the query types of the use case that cannot
+// be expressed in MetricsQL, mapped to the reason why.
+func (g *BaseGenerator) UnsupportedQueryTypes(useCase string) map[string]string {
+	if useCase == common.UseCaseIoT {
+		return unsupportedIoTQueries
+	}
+	return nil
+}
+
+// NewIoT creates a new iot use case query generator.
+func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := iot.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	iot := &IoT{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return iot, nil
+}
+
+// promDuration formats d as a PromQL duration in its largest whole unit,
+// e.g. 10m or 72h.
+func promDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%ds", d/time.Second)
+	default:
+		return fmt.Sprintf("%dms", d/time.Millisecond)
+	}
+}
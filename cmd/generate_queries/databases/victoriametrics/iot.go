@@ -0,0 +1,232 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+const (
+	// instantStep is the step of the queries evaluated once, at the end of
+	// their range.
+	instantStep = time.Second
+	// day is the step of the queries returning one result per day.
+	day = 24 * time.Hour
+)
+
+// IoT produces MetricsQL queries for the iot query types that can be
+// expressed over the series of the readings and diagnostics fields, all but
+// the unsupportedIoTQueries.
+//
+// The fields are imported as one series per truck named
+// <measurement>_<field>, with the tags of the truck as labels. The
+// aggregations over consecutive 10 minute periods are subqueries, the counts
+// of the periods in a state changing between two periods (breakdowns) use
+// increases_over_time and the last readings keep their metric names with
+// keep_metric_names, both MetricsQL extensions of PromQL.
+type IoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+// unsupportedIoTQueries are the iot query types that cannot be expressed in
+// MetricsQL, mapped to the reason why:
+//   - the average fuel consumption only includes the readings with a velocity
+//     over 1, a filter on another series that PromQL can only apply at the
+//     steps of a subquery, not to every reading;
+//   - a driving session is a run of consecutive 10 minute periods of driving,
+//     and PromQL has no function over the runs of a series.
+var unsupportedIoTQueries = map[string]string{
+	iot.LabelAvgVsProjectedFuelConsumption: "cannot be expressed in MetricsQL: the fuel consumption readings are filtered on the velocity of the same reading",
+	iot.LabelAvgDailyDrivingSession:        "cannot be expressed in MetricsQL: the length of the runs of consecutive driving periods is not available",
+}
+
+// NewIoT makes an IoT object ready to generate Queries.
+func NewIoT(start, end time.Time, scale int, g *BaseGenerator) *IoT {
+	c, err := iot.NewCore(start, end, scale)
+	databases.PanicIfErr(err)
+	return &IoT{
+		Core:          c,
+		BaseGenerator: g,
+	}
+}
+
+// getTruckSelector gets multiple random truck names and creates a label
+// matcher for these names.
+func (i *IoT) getTruckSelector(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	databases.PanicIfErr(err)
+	return fmt.Sprintf(`name=~"%s"`, strings.Join(names, "|"))
+}
+
+// getLookback returns the range covering the whole query interval, which
+// the queries over all the readings look back over from its end.
+func (i *IoT) getLookback() string {
+	return promDuration(i.Interval.Duration())
+}
+
+// getDailyRange returns the first and last evaluation time of a query with
+// one result per day: every result covers the day before its time.
+func (i *IoT) getDailyRange() (time.Time, time.Time) {
+	start, end := i.Interval.Start().Add(day), i.Interval.End()
+	if start.After(end) {
+		start = end
+	}
+	return start, end
+}
+
+// fillInInstantQuery fills the query struct with a query evaluated once, at end.
+func (i *IoT) fillInInstantQuery(qi query.Query, humanLabel, humanDesc, metricsql string, end time.Time) {
+	i.fillInQuery(qi, humanLabel, humanDesc, metricsql, end, end, instantStep)
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *IoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	metricsql := fmt.Sprintf(`last_over_time({__name__=~"readings_(latitude|longitude)",%s}[%s]) keep_metric_names`,
+		i.getTruckSelector(nTrucks),
+		i.getLookback())
+
+	humanLabel := "VictoriaMetrics last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *IoT) LastLocPerTruck(qi query.Query) {
+	metricsql := fmt.Sprintf(`last_over_time({__name__=~"readings_(latitude|longitude)",name!="",fleet="%s"}[%s]) keep_metric_names`,
+		i.GetRandomFleet(),
+		i.getLookback())
+
+	humanLabel := "VictoriaMetrics last location per truck"
+	humanDesc := humanLabel
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *IoT) TrucksWithLowFuel(qi query.Query) {
+	metricsql := fmt.Sprintf(`last_over_time(diagnostics_fuel_state{name!="",fleet="%s"}[%s]) < 0.1`,
+		i.GetRandomFleet(),
+		i.getLookback())
+
+	humanLabel := "VictoriaMetrics trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *IoT) TrucksWithHighLoad(qi query.Query) {
+	fleet, lookback := i.GetRandomFleet(), i.getLookback()
+	metricsql := fmt.Sprintf(`last_over_time(diagnostics_current_load{name!="",fleet="%s"}[%s])
+		/ last_over_time(diagnostics_load_capacity{name!="",fleet="%s"}[%s]) >= 0.9`,
+		fleet, lookback, fleet, lookback)
+
+	humanLabel := "VictoriaMetrics trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *IoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	metricsql := fmt.Sprintf(`avg_over_time(readings_velocity{name!="",fleet="%s"}[%s]) < 1`,
+		i.GetRandomFleet(),
+		promDuration(iot.StationaryDuration))
+
+	humanLabel := "VictoriaMetrics stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, interval.End())
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	metricsql := fmt.Sprintf(`count_over_time((avg_over_time(readings_velocity{name!="",fleet="%s"}[10m]) > 1)[%s:10m]) > %d`,
+		i.GetRandomFleet(),
+		promDuration(iot.LongDrivingSessionDuration),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "VictoriaMetrics trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, interval.End())
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	metricsql := fmt.Sprintf(`count_over_time((avg_over_time(readings_velocity{name!="",fleet="%s"}[10m]) > 1)[%s:10m]) > %d`,
+		i.GetRandomFleet(),
+		promDuration(iot.DailyDrivingDuration),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "VictoriaMetrics trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, interval.End())
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *IoT) AvgDailyDrivingDuration(qi query.Query) {
+	metricsql := fmt.Sprintf(`avg_over_time((count_over_time((avg_over_time(readings_velocity{name!=""}[10m]) > 1)[1d:10m]) / 6)[%s:1d])`,
+		i.getLookback())
+
+	humanLabel := "VictoriaMetrics average driver driving duration per day"
+	humanDesc := humanLabel
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *IoT) AvgLoad(qi query.Query) {
+	lookback := i.getLookback()
+	metricsql := fmt.Sprintf(`avg by (fleet, model) (avg_over_time(diagnostics_current_load{name!=""}[%s])
+		/ last_over_time(diagnostics_load_capacity{name!=""}[%s]))`,
+		lookback, lookback)
+
+	humanLabel := "VictoriaMetrics average load per truck model per fleet"
+	humanDesc := humanLabel
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *IoT) DailyTruckActivity(qi query.Query) {
+	metricsql := `sum by (fleet, model) (count_over_time((avg_over_time(diagnostics_status{name!=""}[10m]) < 1)[1d:10m])) / 144`
+
+	humanLabel := "VictoriaMetrics daily truck activity per fleet per model"
+	humanDesc := humanLabel
+
+	start, end := i.getDailyRange()
+	i.fillInQuery(qi, humanLabel, humanDesc, metricsql, start, end, day)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
+	metricsql := fmt.Sprintf(`sum by (model) (increases_over_time((avg_over_time(diagnostics_status{name!=""}[10m]) >= bool 1)[%s:10m]))`,
+		i.getLookback())
+
+	humanLabel := "VictoriaMetrics truck breakdown frequency per model"
+	humanDesc := humanLabel
+
+	i.fillInInstantQuery(qi, humanLabel, humanDesc, metricsql, i.Interval.End())
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
@@ -0,0 +1,305 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/iot"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+const (
+	testScale = 10
+)
+
+type IoTTestCase struct {
+	desc               string
+	input              int
+	fail               bool
+	failMsg            string
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedQuery      string
+	expectedStart      string
+	expectedEnd        string
+	expectedStep       string
+}
+
+func TestLastLocByTruck(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc:    "zero trucks",
+			input:   0,
+			fail:    true,
+			failMsg: "number of trucks cannot be < 1; got 0",
+		},
+		{
+			desc:  "three trucks",
+			input: 3,
+
+			expectedHumanLabel: "VictoriaMetrics last location by specific truck",
+			expectedHumanDesc:  "VictoriaMetrics last location by specific truck: random    3 trucks",
+			expectedQuery:      `last_over_time({__name__=~"readings_(latitude|longitude)",name=~"truck_5|truck_9|truck_3"}[1h]) keep_metric_names`,
+			expectedStart:      "1970-01-01T01:00:00Z",
+			expectedEnd:        "1970-01-01T01:00:00Z",
+			expectedStep:       "1s",
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.LastLocByTruck(q, c.input)
+		return q
+	}
+
+	runIoTTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), cases)
+}
+
+func TestTrucksWithHighLoad(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "VictoriaMetrics trucks with high load",
+			expectedHumanDesc:  "VictoriaMetrics trucks with high load: over 90 percent",
+			expectedQuery: `last_over_time(diagnostics_current_load{name!="",fleet="South"}[1h])
+		/ last_over_time(diagnostics_load_capacity{name!="",fleet="South"}[1h]) >= 0.9`,
+			expectedStart: "1970-01-01T01:00:00Z",
+			expectedEnd:   "1970-01-01T01:00:00Z",
+			expectedStep:  "1s",
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithHighLoad(q)
+		return q
+	}
+
+	runIoTTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), cases)
+}
+
+func TestTrucksWithLongDrivingSessions(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "VictoriaMetrics trucks with longer driving sessions",
+			expectedHumanDesc:  "VictoriaMetrics trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedQuery:      `count_over_time((avg_over_time(readings_velocity{name!="",fleet="West"}[10m]) > 1)[4h:10m]) > 22`,
+			expectedStart:      "1970-01-01T04:16:22.646Z",
+			expectedEnd:        "1970-01-01T04:16:22.646Z",
+			expectedStep:       "1s",
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TrucksWithLongDrivingSessions(q)
+		return q
+	}
+
+	runIoTTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(6*time.Hour), cases)
+}
+
+func TestAvgDailyDrivingDuration(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "VictoriaMetrics average driver driving duration per day",
+			expectedHumanDesc:  "VictoriaMetrics average driver driving duration per day",
+			expectedQuery:      `avg_over_time((count_over_time((avg_over_time(readings_velocity{name!=""}[10m]) > 1)[1d:10m]) / 6)[72h:1d])`,
+			expectedStart:      "1970-01-04T00:00:00Z",
+			expectedEnd:        "1970-01-04T00:00:00Z",
+			expectedStep:       "1s",
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.AvgDailyDrivingDuration(q)
+		return q
+	}
+
+	runIoTTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(72*time.Hour), cases)
+}
+
+func TestDailyTruckActivity(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "VictoriaMetrics daily truck activity per fleet per model",
+			expectedHumanDesc:  "VictoriaMetrics daily truck activity per fleet per model",
+			expectedQuery:      `sum by (fleet, model) (count_over_time((avg_over_time(diagnostics_status{name!=""}[10m]) < 1)[1d:10m])) / 144`,
+			expectedStart:      "1970-01-02T00:00:00Z",
+			expectedEnd:        "1970-01-04T00:00:00Z",
+			expectedStep:       "24h",
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.DailyTruckActivity(q)
+		return q
+	}
+
+	runIoTTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(72*time.Hour), cases)
+}
+
+func TestTruckBreakdownFrequency(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "VictoriaMetrics truck breakdown frequency per model",
+			expectedHumanDesc:  "VictoriaMetrics truck breakdown frequency per model",
+			expectedQuery:      `sum by (model) (increases_over_time((avg_over_time(diagnostics_status{name!=""}[10m]) >= bool 1)[12h:10m]))`,
+			expectedStart:      "1970-01-01T12:00:00Z",
+			expectedEnd:        "1970-01-01T12:00:00Z",
+			expectedStep:       "1s",
+		},
+	}
+
+	testFunc := func(i *IoT, c IoTTestCase) query.Query {
+		q := i.GenerateEmptyQuery()
+		i.TruckBreakdownFrequency(q)
+		return q
+	}
+
+	runIoTTestCases(t, testFunc, time.Unix(0, 0), time.Unix(0, 0).Add(12*time.Hour), cases)
+}
+
+func TestUnsupportedQueryTypes(t *testing.T) {
+	g := &BaseGenerator{}
+	unsupported := g.UnsupportedQueryTypes(common.UseCaseIoT)
+	for _, label := range []string{iot.LabelAvgVsProjectedFuelConsumption, iot.LabelAvgDailyDrivingSession} {
+		if _, ok := unsupported[label]; !ok {
+			t.Errorf("query type %s not listed as unsupported", label)
+		}
+	}
+	if len(unsupported) != 2 {
+		t.Errorf("incorrect number of unsupported query types: got %d want 2", len(unsupported))
+	}
+
+	qg, err := g.NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := qg.(iot.AvgVsProjectedFuelConsumptionFiller); ok {
+		t.Errorf("unsupported query type %s implemented", iot.LabelAvgVsProjectedFuelConsumption)
+	}
+	if _, ok := qg.(iot.AvgDailyDrivingSessionFiller); ok {
+		t.Errorf("unsupported query type %s implemented", iot.LabelAvgDailyDrivingSession)
+	}
+
+	if got := g.UnsupportedQueryTypes(common.UseCaseDevops); len(got) != 0 {
+		t.Errorf("unexpected unsupported query types for devops: %v", got)
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	cases := []struct {
+		in   time.Duration
+		want string
+	}{
+		{in: 72 * time.Hour, want: "72h"},
+		{in: 90 * time.Minute, want: "90m"},
+		{in: 10 * time.Second, want: "10s"},
+		{in: 1500 * time.Millisecond, want: "1500ms"},
+	}
+	for _, c := range cases {
+		if got := promDuration(c.in); got != c.want {
+			t.Errorf("incorrect duration for %v: got %s want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestTenMinutePeriods(t *testing.T) {
+	if got := tenMinutePeriods(5, 4*time.Hour); got != 22 {
+		t.Errorf("incorrect result: got %d want 22", got)
+	}
+	if got := tenMinutePeriods(35, 24*time.Hour); got != 60 {
+		t.Errorf("incorrect result: got %d want 60", got)
+	}
+}
+
+func runIoTTestCases(t *testing.T, testFunc func(*IoT, IoTTestCase) query.Query, s time.Time, e time.Time, cases []IoTTestCase) {
+	rand.Seed(123) // Setting seed for testing purposes.
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			b := BaseGenerator{}
+			dq, err := b.NewIoT(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating iot generator")
+			}
+			i := dq.(*IoT)
+
+			if c.fail {
+				func() {
+					defer func() {
+						r := recover()
+						if r == nil {
+							t.Fatalf("did not panic when should")
+						}
+
+						if r != c.failMsg {
+							t.Fatalf("incorrect fail message: got %s, want %s", r, c.failMsg)
+						}
+					}()
+
+					testFunc(i, c)
+				}()
+			} else {
+				q := testFunc(i, c)
+
+				v := url.Values{}
+				v.Set("query", c.expectedQuery)
+				v.Set("start", c.expectedStart)
+				v.Set("end", c.expectedEnd)
+				v.Set("step", c.expectedStep)
+				expectedPath := fmt.Sprintf("/api/v1/query_range?%s", v.Encode())
+
+				verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery, expectedPath)
+			}
+		})
+	}
+}
+
+func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, metricsql, path string) {
+	hq, ok := q.(*query.HTTP)
+
+	if !ok {
+		t.Fatal("Filled query is not *query.HTTP type")
+	}
+
+	if got := string(hq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+
+	if got := string(hq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+
+	if got := string(hq.Method); got != "GET" {
+		t.Errorf("incorrect method:\ngot\n%s\nwant GET", got)
+	}
+
+	if got := string(hq.RawQuery); got != metricsql {
+		t.Errorf("incorrect query:\ngot\n%s\nwant\n%s", got, metricsql)
+	}
+
+	if got := string(hq.Path); got != path {
+		t.Errorf("incorrect path:\ngot\n%s\nwant\n%s", got, path)
+	}
+
+	if hq.Body != nil {
+		t.Errorf("body not nil, got %+v", hq.Body)
+	}
+}
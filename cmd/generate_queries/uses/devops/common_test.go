@@ -0,0 +1,77 @@
+package devops
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGetCPUMetricsSlice(t *testing.T) {
+	got, err := GetCPUMetricsSlice(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "usage_user" || got[1] != "usage_system" {
+		t.Errorf("incorrect metrics: got %v", got)
+	}
+	if _, err := GetCPUMetricsSlice(0); err == nil || err.Error() != errNoMetrics {
+		t.Errorf("incorrect error for 0 metrics: %v", err)
+	}
+	if _, err := GetCPUMetricsSlice(GetCPUMetricsLen() + 1); err == nil || err.Error() != errTooManyMetrics {
+		t.Errorf("incorrect error for too many metrics: %v", err)
+	}
+}
+
+func TestGetHighCPULabel(t *testing.T) {
+	cases := []struct {
+		nHosts int
+		want   string
+		fail   bool
+	}{
+		{nHosts: 0, want: "Foo CPU over threshold, all hosts"},
+		{nHosts: 5, want: "Foo CPU over threshold, 5 host(s)"},
+		{nHosts: -1, fail: true},
+	}
+	for _, c := range cases {
+		got, err := GetHighCPULabel("Foo", c.nHosts)
+		if c.fail {
+			if err == nil || err.Error() != errNHostsCannotNegative {
+				t.Errorf("%d hosts: incorrect error: %v", c.nHosts, err)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("%d hosts: got %q (%v) want %q", c.nHosts, got, err, c.want)
+		}
+	}
+}
+
+func TestGetDoubleGroupByLabel(t *testing.T) {
+	want := "Foo mean of 5 metrics, all hosts, random 12h0m0s by 1h"
+	if got := GetDoubleGroupByLabel("Foo", 5); got != want {
+		t.Errorf("incorrect label: got %q want %q", got, want)
+	}
+}
+
+func TestGetRandomHosts(t *testing.T) {
+	rand.Seed(123)
+	got, err := getRandomHosts(3, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, h := range got {
+		if seen[h] {
+			t.Errorf("duplicate host %s", h)
+		}
+		seen[h] = true
+	}
+	if len(got) != 3 {
+		t.Errorf("incorrect number of hosts: got %d want 3", len(got))
+	}
+	if _, err := getRandomHosts(0, 10); err == nil {
+		t.Errorf("unexpected lack of error for 0 hosts")
+	}
+	if _, err := getRandomHosts(11, 10); err == nil {
+		t.Errorf("unexpected lack of error for more hosts than scale")
+	}
+}
@@ -0,0 +1,33 @@
+package devops
+
+import (
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/common"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// DoubleGroupby contains info for filling in double groupby queries
+type DoubleGroupby struct {
+	core    utils.QueryGenerator
+	metrics int
+}
+
+// NewGroupBy produces a function that produces a new DoubleGroupby for the given parameters
+func NewGroupBy(numMetrics int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &DoubleGroupby{
+			core:    core,
+			metrics: numMetrics,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details
+func (d *DoubleGroupby) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(DoubleGroupbyFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.GroupByTimeAndPrimaryTag(q, d.metrics)
+	return q
+}
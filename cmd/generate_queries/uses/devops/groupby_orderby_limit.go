@@ -0,0 +1,29 @@
+package devops
+
+import (
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/common"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// GroupByOrderByLimit produces a filler for queries in the devops groupby-orderby-limit case.
+type GroupByOrderByLimit struct {
+	core utils.QueryGenerator
+}
+
+// NewGroupByOrderByLimit returns a new GroupByOrderByLimit for given paremeters
+func NewGroupByOrderByLimit(core utils.QueryGenerator) utils.QueryFiller {
+	return &GroupByOrderByLimit{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details
+func (d *GroupByOrderByLimit) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(GroupbyOrderbyLimitFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.GroupByOrderByLimit(q)
+	return q
+}
@@ -0,0 +1,29 @@
+package devops
+
+import (
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/common"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// LastPointPerHost returns a QueryFiller for the last point per host
+type LastPointPerHost struct {
+	core utils.QueryGenerator
+}
+
+// NewLastPointPerHost produces a new function that produces a new LastPointPerHost
+func NewLastPointPerHost(core utils.QueryGenerator) utils.QueryFiller {
+	return &LastPointPerHost{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details
+func (d *LastPointPerHost) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(LastPointFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.LastPointPerHost(q)
+	return q
+}
@@ -2,6 +2,10 @@ package main
 
 import (
 	"time"
+	
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
 )
 
 type LoadConfig struct {
@@ -24,11 +28,16 @@ type RunnerConfig struct {
 	DoCreateDB      bool          `yaml:"do-create-db" mapstructure:"do-create-db"`
 	DoAbortOnExist  bool          `yaml:"do-abort-on-exist" mapstructure:"do-abort-on-exist"`
 	ReportingPeriod time.Duration `yaml:"reporting-period" mapstructure:"reporting-period"`
+	FlushInterval   time.Duration `yaml:"flush-interval" mapstructure:"flush-interval"`
+	Duration        time.Duration `yaml:"duration"`
+	WarmupDuration  time.Duration `yaml:"warmup-duration" mapstructure:"warmup-duration"`
 	Seed            int64
 	HashWorkers     bool   `yaml:"hash-workers" mapstructure:"hash-workers"`
 	InsertIntervals string `yaml:"insert-intervals" mapstructure:"insert-intervals"`
 	FlowControl     bool   `yaml:"flow-control" mapstructure:"flow-control"`
 	ChannelCapacity uint   `yaml:"channel-capacity" mapstructure:"channel-capacity"`
+	reporter.Config `yaml:",inline" mapstructure:",squash"`
+	load.RetryConfig `yaml:",inline" mapstructure:",squash"`
 }
 
 type DataSourceConfig struct {
@@ -51,4 +60,23 @@ type SimulatorDataSourceConfig struct {
 	Limit                 uint64        `yaml:"max-data-points" mapstructure:"max-data-points"`
 	LogInterval           time.Duration `yaml:"log-interval" mapstructure:"log-interval"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	CustomSchema          string        `yaml:"custom-schema,omitempty" mapstructure:"custom-schema"`
+	ChurnRate             float64       `yaml:"churn-rate,omitempty" mapstructure:"churn-rate"`
+	Quality               string        `yaml:"quality-profile,omitempty" mapstructure:"quality-profile"`
+	common.QualityOverrides `yaml:",inline" mapstructure:",squash"`
+	MaxLateness           time.Duration `yaml:"max-lateness,omitempty" mapstructure:"max-lateness"`
+	Patterns              []string      `yaml:"patterns,omitempty" mapstructure:"patterns"`
+	RealTime              bool          `yaml:"real-time,omitempty" mapstructure:"real-time"`
+	RealTimeDuration      time.Duration `yaml:"real-time-duration,omitempty" mapstructure:"real-time-duration"`
+	TimestampPrecision    string        `yaml:"timestamp-precision,omitempty" mapstructure:"timestamp-precision"`
+	TimestampJitter       time.Duration `yaml:"timestamp-jitter,omitempty" mapstructure:"timestamp-jitter"`
+	Dataset               string        `yaml:"dataset,omitempty" mapstructure:"dataset"`
+	DatasetFormat         string        `yaml:"dataset-format,omitempty" mapstructure:"dataset-format"`
+	DatasetMeasurement    string        `yaml:"dataset-measurement,omitempty" mapstructure:"dataset-measurement"`
+	DatasetMeasurementCol string        `yaml:"dataset-measurement-column,omitempty" mapstructure:"dataset-measurement-column"`
+	DatasetTimeColumn     string        `yaml:"dataset-time-column,omitempty" mapstructure:"dataset-time-column"`
+	DatasetTimeFormat     string        `yaml:"dataset-time-format,omitempty" mapstructure:"dataset-time-format"`
+	DatasetTags           []string      `yaml:"dataset-tags,omitempty" mapstructure:"dataset-tags"`
+	DatasetFields         []string      `yaml:"dataset-fields,omitempty" mapstructure:"dataset-fields"`
+	DatasetShift          bool          `yaml:"dataset-shift,omitempty" mapstructure:"dataset-shift"`
 }
@@ -60,9 +60,9 @@ func getEmptyConfigWithoutDbSpecifics(target, dataSource string) *LoadConfig {
 		loadConfig.DataSource = &DataSourceConfig{
 			Type: source.FileDataSourceType,
 		}
-	case source.SimulatorDataSourceType:
+	case source.SimulatorDataSourceType, source.DatasetDataSourceType:
 		loadConfig.DataSource = &DataSourceConfig{
-			Type: source.SimulatorDataSourceType,
+			Type: dataSource,
 		}
 	}
 	return loadConfig
@@ -116,7 +116,7 @@ func cleanDataSourceFlags(dataSource string, fs *pflag.FlagSet) *pflag.FlagSet {
 	switch dataSource {
 	case source.FileDataSourceType:
 		unwantedPrefix = "data-source.simulator"
-	case source.SimulatorDataSourceType:
+	case source.SimulatorDataSourceType, source.DatasetDataSourceType:
 		unwantedPrefix = "data-source.file"
 	default:
 		panic("unsupported data source type: " + dataSource)
@@ -5,6 +5,8 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
 	"strings"
 	"time"
 )
@@ -34,6 +36,12 @@ func addLoaderRunnerFlags(fs *pflag.FlagSet) {
 		"Whether to abort if a database with the given name already exists.",
 	)
 	fs.Duration("loader.runner.reporting-period", 10*time.Second, "Period to report write stats")
+	fs.Duration(
+		"loader.runner.flush-interval",
+		0,
+		"Period to send the partially filled batches to the workers, so that the items of a data source that blocks "+
+			"are not held back (0 = only full batches, or every second with the real-time simulator)",
+	)
 	fs.Int64("loader.runner.seed", 0, "PRNG seed (default: 0, which uses the current timestamp)")
 	fs.Bool(
 		"loader.runner.do-load",
@@ -47,6 +55,12 @@ func addLoaderRunnerFlags(fs *pflag.FlagSet) {
 	)
 	fs.Uint("loader.runner.workers", 1, "Number of parallel clients inserting")
 	fs.Uint64("loader.runner.limit", 0, "Number of items to insert (0 = all of them).")
+	fs.Duration("loader.runner.duration", 0, "Stop loading after this wall-clock time (0 = no limit)")
+	fs.Duration(
+		"loader.runner.warmup-duration",
+		0,
+		"Time at the start of the run whose inserts are not counted in the summary and results",
+	)
 	fs.String("loader.runner.db-name", "benchmark", "Name of database")
 	fs.Uint(
 		"loader.runner.batch-size",
@@ -67,6 +81,8 @@ func addLoaderRunnerFlags(fs *pflag.FlagSet) {
 			"Default 0 means that:\n\tif hash-workers=false then capacity = 5 * number of workers\n\t"+
 			"if hash-workers=true, then capacity = 5 for each worker",
 	)
+	reporter.Config{}.AddToFlagSetWithPrefix("loader.runner.", fs)
+	load.RetryConfig{}.AddToFlagSetWithPrefix("loader.runner.", fs)
 }
 
 func addDataSourceFlags(fs *pflag.FlagSet) {
@@ -96,6 +112,81 @@ func addDataSourceFlags(fs *pflag.FlagSet) {
 		100,
 		"Max number of metric fields to generate per host. Used only in devops-generic use-case",
 	)
+	fs.String(
+		"data-source.simulator.custom-schema",
+		"",
+		"YAML file declaring the tags, measurements and fields to generate. Used only in custom use-case",
+	)
+	fs.Float64(
+		"data-source.simulator.churn-rate",
+		0,
+		"Fraction of the hosts (or trucks) replaced by new ones with fresh tag values at every log interval, 0 = no churn",
+	)
+	fs.String(
+		"data-source.simulator.quality-profile",
+		common.QualityDefault,
+		"Data quality profile: chances of missing, out-of-order and zero-valued entries. Used only in iot use-case",
+	)
+	common.QualityOverrides{}.AddToFlagSetWithPrefix("data-source.simulator.", fs)
+	fs.Duration(
+		"data-source.simulator.max-lateness",
+		0,
+		"Max time an out-of-order entry can fall behind the newest one, 0 = use the quality profile's value",
+	)
+	fs.StringSlice(
+		"data-source.simulator.patterns",
+		nil,
+		"Value patterns added to the truck readings. Used only in iot use-case",
+	)
+	fs.Bool(
+		"data-source.simulator.real-time",
+		false,
+		"Pace the data with the wall clock: timestamps start at the current log interval and each interval is only released once reached. The timestamp flags are ignored",
+	)
+	fs.Duration(
+		"data-source.simulator.real-time-duration",
+		0,
+		"How long to run in real-time mode, 0 = one year, which is until stopped in practice",
+	)
+	fs.String(
+		"data-source.simulator.timestamp-precision",
+		common.PrecisionNanoseconds,
+		"Precision of the timestamps, which are truncated to it and written in its unit by the line protocol formats (choices: s, ms, us, ns)",
+	)
+	fs.Duration(
+		"data-source.simulator.timestamp-jitter",
+		0,
+		"Max random offset added to the timestamp of each point so they are not aligned on the log interval, 0 = no jitter",
+	)
+	fs.String(
+		"data-source.simulator.dataset",
+		"",
+		"If data-source.type=DATASET, CSV or line protocol file, gzipped if it ends with .gz, whose records are the points",
+	)
+	fs.String("data-source.simulator.dataset-format", "", "Format of the dataset (choices: csv, influx), empty = csv for .csv files, influx otherwise")
+	fs.String("data-source.simulator.dataset-measurement", "dataset", "Measurement of the rows of a CSV dataset")
+	fs.String("data-source.simulator.dataset-measurement-column", "", "CSV column holding the measurement of a row, overrides dataset-measurement")
+	fs.String("data-source.simulator.dataset-time-column", "time", "CSV column holding the timestamp of a row")
+	fs.String(
+		"data-source.simulator.dataset-time-format",
+		"",
+		"Format of the dataset timestamps (choices: rfc3339, s, ms, us, ns), empty = rfc3339 for csv, ns for influx",
+	)
+	fs.StringSlice(
+		"data-source.simulator.dataset-tags",
+		nil,
+		"CSV columns, or line protocol tags, kept as tags. Empty = no CSV tag, all line protocol tags",
+	)
+	fs.StringSlice(
+		"data-source.simulator.dataset-fields",
+		nil,
+		"CSV columns, or line protocol fields, kept as fields. Empty = all the other CSV columns, all line protocol fields",
+	)
+	fs.Bool(
+		"data-source.simulator.dataset-shift",
+		false,
+		"Shift the dataset timestamps so it starts at timestamp-start, dropping the records past timestamp-end",
+	)
 	fs.Uint64(
 		"data-source.simulator.scale",
 		defaultScale,
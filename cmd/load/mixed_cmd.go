@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/initializers"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func initMixedCMD() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:              "mixed",
+		Short:            "Load data into a specified target database while running queries against it",
+		PersistentPreRun: initViperConfig,
+	}
+	mixedCmdFlagSet := mixedCmdFlags()
+	cmd.PersistentFlags().AddFlagSet(mixedCmdFlagSet)
+	err := viper.BindPFlags(cmd.PersistentFlags())
+	// don't bind --config which specifies the file from where to read config
+	cmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config.yaml)")
+
+	if err != nil {
+		return nil, fmt.Errorf("could not bind flags to configuration: %v", err)
+	}
+
+	cmd.AddCommand(initMixedSubCommands()...)
+	return cmd, nil
+}
+
+func mixedCmdFlags() *pflag.FlagSet {
+	fs := loadCmdFlags()
+	addQueryRunnerFlags(fs)
+	fs.Uint64(
+		"mixed.insert-rate",
+		0,
+		"Limit the rate of inserted items (data points) per second, 0 = no limit",
+	)
+	return fs
+}
+
+func addQueryRunnerFlags(fs *pflag.FlagSet) {
+	fs.String("query.file", "", "File name to read queries from")
+	fs.Uint("query.workers", 1, "Number of concurrent query clients")
+	fs.Uint64("query.max-rps", 0, "Limit the rate of queries per second, 0 = no limit")
+	fs.Bool("query.open-loop", false, "Send queries at a constant rate of query.max-rps queries per second and measure latencies from their intended send times")
+	fs.Uint64("query.max-queries", 0, "Limit the number of queries to send, 0 = no limit")
+	fs.Duration("query.duration", 0, "Stop sending queries after this wall-clock time, 0 = until loading completes")
+	fs.Bool("query.loop", false, "Read the query file again from the start when it ends, until loading completes or query.duration or query.max-queries is reached")
+	fs.Uint64("query.burn-in", 0, "Number of queries to ignore before collecting statistics.")
+	fs.Duration("query.warmup-duration", 0, "Time at the start of the run whose queries are not counted in the statistics")
+	fs.Bool("query.prewarm-queries", false, "Run each query twice in a row so the warm query is guaranteed to be a cache hit")
+	fs.Bool("query.print-responses", false, "Pretty print response bodies for correctness checking")
+	fs.Int("query.debug", 0, "Whether to print debug messages.")
+	fs.String("query.hdr-latencies", "", "Write the High Dynamic Range (HDR) Histogram of Response Latencies to this file.")
+	fs.String("query.results-file", "", "Write the query results summary json to this file")
+	fs.String("query.on-error", query.OnErrorAbort, "What to do when a query fails, one of: "+query.OnErrorAbort+", "+query.OnErrorSkip+", "+query.OnErrorRetry)
+	fs.Uint("query.max-retries", 3, "Number of times to retry a failed query with --query.on-error="+query.OnErrorRetry)
+}
+
+// initMixedSubCommands creates a sub-command for each target, those that cannot
+// also run queries fail with an error
+func initMixedSubCommands() []*cobra.Command {
+	var commands []*cobra.Command
+	for _, format := range constants.SupportedFormats() {
+		target := initializers.GetTarget(format)
+		short := "Load data into " + format + " while running queries against it"
+		run := createRunMixedUnsupported(target)
+		if queryTarget, ok := target.(targets.QueryTarget); ok {
+			run = createRunMixed(target, queryTarget)
+		} else {
+			short = "Not supported: " + format + " cannot run queries"
+		}
+		cmd := &cobra.Command{
+			Use:   format,
+			Short: short,
+			Run:   run,
+		}
+
+		target.TargetSpecificFlags("loader.db-specific.", cmd.PersistentFlags())
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+func createRunMixed(target targets.ImplementedTarget, queryTarget targets.QueryTarget) cmdRunner {
+	return func(cmd *cobra.Command, args []string) {
+		if err := viper.BindPFlags(cmd.PersistentFlags()); err != nil {
+			panic(fmt.Errorf("could not bind db-specific flags for %s: %v", target.TargetName(), err))
+		}
+		runner, bench, queryPool, createFn, err := parseMixedConfig(target, queryTarget, viper.GetViper())
+		if err != nil {
+			panic(err)
+		}
+		runner.run(bench, queryPool, createFn)
+	}
+}
+
+// createRunMixedUnsupported returns the runner of a target that cannot run its
+// queries while it is loaded.
+func createRunMixedUnsupported(target targets.ImplementedTarget) cmdRunner {
+	return func(cmd *cobra.Command, args []string) {
+		panic(fmt.Errorf("%s does not support mixed workloads: the target cannot run queries", target.TargetName()))
+	}
+}
+
+func parseMixedConfig(target targets.ImplementedTarget, queryTarget targets.QueryTarget, v *viper.Viper) (
+	*mixedRunner, targets.Benchmark, *sync.Pool, query.ProcessorCreate, error,
+) {
+	bench, loaderConfig, err := parseBenchmarkConfig(target, v)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if insertRate := v.GetUint64("mixed.insert-rate"); insertRate > 0 {
+		bench = newRateLimitedBenchmark(bench, insertRate, loaderConfig.BatchSize)
+	}
+
+	queryViper := v.Sub("query")
+	if queryViper == nil {
+		return nil, nil, nil, nil, fmt.Errorf("config file didn't have a top-level 'query' object")
+	}
+	var queryConfig query.BenchmarkRunnerConfig
+	if err := queryViper.Unmarshal(&queryConfig); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	// queries run against the database being loaded and their latencies are
+	// reported together with the insert rates
+	queryConfig.DBName = loaderConfig.DBName
+	queryConfig.PrintInterval = 0
+	queryConfig.Target = target.TargetName()
+	queryConfig.LoopUntilStopped = true
+	queries := query.NewBenchmarkRunner(queryConfig)
+
+	createFn, err := queryTarget.QueryProcessor(queries, v.Sub("loader").Sub("db-specific"))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	// the loader reports nothing itself, the mixed runner reports both sides
+	period := loaderConfig.ReportingPeriod
+	loaderConfig.ReportingPeriod = 0
+	metricsReporter, err := reporter.New(loaderConfig.Config)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	loaderConfig.Config = reporter.Config{}
+	runner := &mixedRunner{
+		loader:   load.GetBenchmarkRunner(*loaderConfig),
+		queries:  queries,
+		period:   period,
+		reporter: metricsReporter,
+	}
+	return runner, bench, queryTarget.QueryPool(), createFn, nil
+}
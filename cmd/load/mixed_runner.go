@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"golang.org/x/time/rate"
+)
+
+// dataPollInterval is how often the mixed runner checks whether the loader has
+// written its first batch, before starting the queries
+const dataPollInterval = 10 * time.Millisecond
+
+// mixedRunner loads a target database and runs queries against it at the same
+// time. Insert throughput and query latencies are reported over the same windows.
+type mixedRunner struct {
+	loader   load.BenchmarkRunner
+	queries  *query.BenchmarkRunner
+	period   time.Duration
+	reporter reporter.Reporter
+}
+
+// run loads the data of bench and, once the first batch is written, starts
+// running queries. The queries stop when loading completes.
+func (m *mixedRunner) run(bench targets.Benchmark, queryPool *sync.Pool, createFn query.ProcessorCreate) {
+	loadDone := make(chan struct{})
+	queriesDone := make(chan struct{})
+	go func() {
+		// the loader (re)creates the database before writing, queries can
+		// only run after that
+		if m.waitForData(loadDone) {
+			m.queries.Run(queryPool, createFn)
+		}
+		close(queriesDone)
+	}()
+
+	stopReport := make(chan struct{})
+	reportDone := make(chan struct{})
+	if m.period > 0 {
+		go m.report(stopReport, reportDone)
+	} else {
+		close(reportDone)
+	}
+
+	m.loader.RunBenchmark(bench)
+	close(loadDone)
+	m.queries.Stop()
+	<-queriesDone
+	close(stopReport)
+	<-reportDone
+	if err := m.reporter.Close(); err != nil {
+		log.Printf("could not close metrics reporter: %v", err)
+	}
+}
+
+// waitForData blocks until the loader has written some data, it returns false
+// if loading finished without writing anything.
+func (m *mixedRunner) waitForData(loadDone <-chan struct{}) bool {
+	ticker := time.NewTicker(dataPollInterval)
+	defer ticker.Stop()
+	for {
+		if metrics, _ := m.loader.Progress(); metrics > 0 {
+			return true
+		}
+		select {
+		case <-loadDone:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// report prints the insert rates and the latencies of the queries completed in
+// each period, until stop is closed
+func (m *mixedRunner) report(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(m.period)
+	defer ticker.Stop()
+
+	prevTime := time.Now()
+	prevMetricCount, prevRowCount := m.loader.Progress()
+	// discard queries completed before the first window
+	m.queries.IntervalQuantiles()
+
+	fmt.Printf("time,per. metric/s,per. row/s,queries,per. query/s,min ms,med ms,p95 ms,p99 ms,max ms\n")
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			metricCount, rowCount := m.loader.Progress()
+			queryCount, quantiles := m.queries.IntervalQuantiles()
+
+			took := now.Sub(prevTime).Seconds()
+			metricRate := float64(metricCount-prevMetricCount) / took
+			rowRate := float64(rowCount-prevRowCount) / took
+			queryRate := float64(queryCount) / took
+			fmt.Printf("%d,%0.2f,%0.2f,%d,%0.2f,%0.2f,%0.2f,%0.2f,%0.2f,%0.2f\n",
+				now.Unix(), metricRate, rowRate, queryCount, queryRate,
+				quantiles["q0"], quantiles["q50"], quantiles["q95"], quantiles["q99"], quantiles["q100"])
+
+			m.sendReports(now, metricRate, rowRate, queryCount, queryRate, quantiles)
+
+			prevMetricCount = metricCount
+			prevRowCount = rowCount
+			prevTime = now
+		}
+	}
+}
+
+// sendReports sends the stats of a window to the metrics sinks, one report for
+// each side of the workload
+func (m *mixedRunner) sendReports(now time.Time, metricRate, rowRate float64, queryCount int64, queryRate float64, quantiles map[string]float64) {
+	loadReport := &reporter.Report{Time: now, Subsystem: reporter.SubsystemLoad}
+	loadReport.Add("metric_rate", "", metricRate)
+	loadReport.Add("row_rate", "", rowRate)
+
+	queryReport := &reporter.Report{Time: now, Subsystem: reporter.SubsystemQuery}
+	queryReport.Add("interval_queries", "", float64(queryCount))
+	queryReport.Add("interval_query_rate", "", queryRate)
+	for _, q := range []string{"q0", "q50", "q95", "q99", "q100"} {
+		queryReport.Add("interval_latency_"+q+"_ms", "", quantiles[q])
+	}
+
+	for _, r := range []*reporter.Report{loadReport, queryReport} {
+		if err := m.reporter.Report(r); err != nil {
+			log.Printf("could not send report: %v", err)
+		}
+	}
+}
+
+// rateLimitedBenchmark is a targets.Benchmark whose data source returns items
+// no faster than a fixed rate
+type rateLimitedBenchmark struct {
+	targets.Benchmark
+	ds targets.DataSource
+}
+
+// newRateLimitedBenchmark limits the items read from the data source of b to
+// itemsPerSecond. Up to burst items can be read at once.
+func newRateLimitedBenchmark(b targets.Benchmark, itemsPerSecond uint64, burst uint) targets.Benchmark {
+	if burst == 0 {
+		burst = 1
+	}
+	return &rateLimitedBenchmark{
+		Benchmark: b,
+		ds: &rateLimitedDataSource{
+			DataSource: b.GetDataSource(),
+			limiter:    rate.NewLimiter(rate.Limit(itemsPerSecond), int(burst)),
+		},
+	}
+}
+
+func (b *rateLimitedBenchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+type rateLimitedDataSource struct {
+	targets.DataSource
+	limiter *rate.Limiter
+}
+
+func (d *rateLimitedDataSource) NextItem() data.LoadedPoint {
+	time.Sleep(d.limiter.Reserve().Delay())
+	return d.DataSource.NextItem()
+}
@@ -11,6 +11,16 @@ import (
 )
 
 func parseConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benchmark, load.BenchmarkRunner, error) {
+	benchmark, loaderConfig, err := parseBenchmarkConfig(target, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return benchmark, load.GetBenchmarkRunner(*loaderConfig), nil
+}
+
+// parseBenchmarkConfig creates the Benchmark for target and the configuration
+// of the runner that should run it.
+func parseBenchmarkConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benchmark, *load.BenchmarkRunnerConfig, error) {
 	dataSourceViper := v.Sub("data-source")
 	if dataSourceViper == nil {
 		return nil, nil, fmt.Errorf("config file didn't have a top-level 'data-source' object")
@@ -37,6 +47,8 @@ func parseConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benc
 	}
 	
 	loaderConfigInternal := convertRunnerConfigToInternalRep(loaderConfig)
+	loaderConfigInternal.Target = target.TargetName()
+	loaderConfigInternal.RealTime = dataSourceInternal.Simulator != nil && dataSourceInternal.Simulator.RealTime
 	
 	dbSpecificViper := loaderViper.Sub("db-specific")
 	if dbSpecificViper == nil {
@@ -48,7 +60,7 @@ func parseConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benc
 		return nil, nil, err
 	}
 	
-	return benchmark, load.GetBenchmarkRunner(*loaderConfigInternal), nil
+	return benchmark, loaderConfigInternal, nil
 }
 
 func parseRunnerConfig(v *viper.Viper) (*RunnerConfig, error) {
@@ -69,11 +81,16 @@ func convertRunnerConfigToInternalRep(r *RunnerConfig) *load.BenchmarkRunnerConf
 		DoCreateDB:      r.DoCreateDB,
 		DoAbortOnExist:  r.DoAbortOnExist,
 		ReportingPeriod: r.ReportingPeriod,
+		FlushInterval:   r.FlushInterval,
 		Seed:            r.Seed,
 		HashWorkers:     r.HashWorkers,
 		InsertIntervals: r.InsertIntervals,
 		NoFlowControl:   !r.FlowControl,
 		ChannelCapacity: r.ChannelCapacity,
+		Duration:        r.Duration,
+		WarmupDuration:  r.WarmupDuration,
+		Config:          r.Config,
+		RetryConfig:     r.RetryConfig,
 	}
 }
 
@@ -109,7 +126,7 @@ func parseDataSourceConfig(v *viper.Viper) (*DataSourceConfig, error) {
 	if conf.Simulator == nil {
 		errStr := fmt.Sprintf(
 			"specified type %s, but no simulator data source config provided",
-			source.SimulatorDataSourceType,
+			conf.Type,
 		)
 		return nil, errors.New(errStr)
 	}
@@ -137,8 +154,30 @@ func convertDataSourceConfigToInternalRepresentation(format string, d *DataSourc
 			Limit:                 d.Simulator.Limit,
 			LogInterval:           d.Simulator.LogInterval,
 			MaxMetricCountPerHost: d.Simulator.MaxMetricCountPerHost,
+			CustomSchema:          d.Simulator.CustomSchema,
+			ChurnRate:             d.Simulator.ChurnRate,
+			Quality:               d.Simulator.Quality,
+			QualityOverrides:      d.Simulator.QualityOverrides,
+			MaxLateness:           d.Simulator.MaxLateness,
+			Patterns:              d.Simulator.Patterns,
+			RealTime:              d.Simulator.RealTime,
+			RealTimeDuration:      d.Simulator.RealTimeDuration,
+			TimestampPrecision:    d.Simulator.TimestampPrecision,
+			TimestampJitter:       d.Simulator.TimestampJitter,
+			Dataset:               d.Simulator.Dataset,
+			DatasetFormat:         d.Simulator.DatasetFormat,
+			DatasetMeasurement:    d.Simulator.DatasetMeasurement,
+			DatasetMeasurementCol: d.Simulator.DatasetMeasurementCol,
+			DatasetTimeColumn:     d.Simulator.DatasetTimeColumn,
+			DatasetTimeFormat:     d.Simulator.DatasetTimeFormat,
+			DatasetTags:           d.Simulator.DatasetTags,
+			DatasetFields:         d.Simulator.DatasetFields,
+			DatasetShift:          d.Simulator.DatasetShift,
 			InterleavedNumGroups:  1,
 		}
+		if d.Type == source.DatasetDataSourceType {
+			simulator.Use = common.UseCaseDataset
+		}
 	}
 	return &source.DataSourceConfig{
 		Type: d.Type,
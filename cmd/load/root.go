@@ -18,6 +18,11 @@ func init() {
 		panic(err)
 	}
 	rootCmd.AddCommand(loadCmd)
+	mixedCmd, err := initMixedCMD()
+	if err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(mixedCmd)
 	configCmd := initConfigCMD()
 	rootCmd.AddCommand(configCmd)
 }
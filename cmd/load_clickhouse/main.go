@@ -0,0 +1,77 @@
+// load_clickhouse loads a ClickHouse instance with data from stdin, inserted
+// over the HTTP interface.
+//
+// If the database exists beforehand, it will be *DROPPED*.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/clickhouse"
+	"github.com/spf13/pflag"
+)
+
+// Parse args:
+func initProgramOptions() (*clickhouse.LoadingOptions, load.BenchmarkRunner, *load.BenchmarkRunnerConfig, *source.DataSourceConfig) {
+	target := clickhouse.NewTarget()
+	loaderConf := load.BenchmarkRunnerConfig{}
+	loaderConf.AddToFlagSet(pflag.CommandLine)
+	target.TargetSpecificFlags("", pflag.CommandLine)
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&loaderConf); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+	opts := clickhouse.LoadingOptions{}
+	opts.Host = viper.GetString("host")
+	opts.Port = viper.GetString("port")
+	opts.User = viper.GetString("user")
+	opts.Password = viper.GetString("password")
+	opts.OrderBy = viper.GetString("order-by")
+	opts.PartitionBy = viper.GetString("partition-by")
+	opts.InsertFormat = viper.GetString("insert-format")
+	opts.LogBatches = viper.GetBool("log-batches")
+
+	if !utils.IsIn(opts.InsertFormat, clickhouse.InsertFormatChoices) {
+		log.Fatalf("invalid insert format: %s", opts.InsertFormat)
+	}
+
+	simulatorConf := common.DataGeneratorConfig{}
+	if err := viper.Unmarshal(&simulatorConf.BaseConfig); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	if err := viper.Unmarshal(&simulatorConf); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	dataSourceConfig, err := loaderConf.DataSourceConfig(target.TargetName(), &simulatorConf)
+	if err != nil {
+		panic(err)
+	}
+
+	loaderConf.Target = target.TargetName()
+	loader := load.GetBenchmarkRunner(loaderConf)
+	return &opts, loader, &loaderConf, dataSourceConfig
+}
+
+func main() {
+	opts, loader, loaderConf, dataSourceConfig := initProgramOptions()
+
+	benchmark, err := clickhouse.NewBenchmark(loaderConf.DBName, opts, dataSourceConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	loader.RunBenchmark(benchmark)
+}
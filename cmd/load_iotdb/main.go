@@ -10,12 +10,13 @@ import (
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/iotdb"
 	"github.com/spf13/pflag"
 )
 
 // Parse args:
-func initProgramOptions() (*iotdb.LoadingOptions, load.BenchmarkRunner, *load.BenchmarkRunnerConfig) {
+func initProgramOptions() (*iotdb.LoadingOptions, load.BenchmarkRunner, *load.BenchmarkRunnerConfig, *source.DataSourceConfig) {
 	target := iotdb.NewTarget()
 	loaderConf := load.BenchmarkRunnerConfig{}
 	loaderConf.AddToFlagSet(pflag.CommandLine)
@@ -41,22 +42,32 @@ func initProgramOptions() (*iotdb.LoadingOptions, load.BenchmarkRunner, *load.Be
 	opts.LogBatches = viper.GetBool("log-batches")
 	opts.ProfileFile = viper.GetString("write-profile")
 
+	simulatorConf := common.DataGeneratorConfig{}
+	if err := viper.Unmarshal(&simulatorConf.BaseConfig); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	if err := viper.Unmarshal(&simulatorConf); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	dataSourceConfig, err := loaderConf.DataSourceConfig(target.TargetName(), &simulatorConf)
+	if err != nil {
+		panic(err)
+	}
+
+	loaderConf.Target = target.TargetName()
 	loader := load.GetBenchmarkRunner(loaderConf)
-	return &opts, loader, &loaderConf
+	return &opts, loader, &loaderConf, dataSourceConfig
 }
 
 func main() {
-	opts, loader, loaderConf := initProgramOptions()
+	opts, loader, loaderConf, dataSourceConfig := initProgramOptions()
 
 	// If specified, generate a performance profile
 	if len(opts.ProfileFile) > 0 {
 		go profileCPUAndMem(opts.ProfileFile)
 	}
 
-	benchmark, err := iotdb.NewBenchmark(loaderConf.DBName, opts, &source.DataSourceConfig{
-		Type: source.FileDataSourceType,
-		File: &source.FileDataSourceConfig{Location: loaderConf.FileName},
-	})
+	benchmark, err := iotdb.NewBenchmark(loaderConf.DBName, opts, dataSourceConfig)
 	if err != nil {
 		panic(err)
 	}
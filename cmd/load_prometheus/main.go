@@ -0,0 +1,71 @@
+// load_prometheus loads any receiver of the Prometheus remote_write protocol
+// with data from stdin, written as snappy-compressed WriteRequests.
+//
+// Series of earlier runs are not deleted, remote_write cannot delete them.
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/prometheus"
+	"github.com/spf13/pflag"
+)
+
+// Parse args:
+func initProgramOptions() (*prometheus.LoadingOptions, load.BenchmarkRunner, *source.DataSourceConfig) {
+	target := prometheus.NewTarget()
+	loaderConf := load.BenchmarkRunnerConfig{}
+	loaderConf.AddToFlagSet(pflag.CommandLine)
+	target.TargetSpecificFlags("", pflag.CommandLine)
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&loaderConf); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+	opts := prometheus.LoadingOptions{}
+	opts.WriteURL = viper.GetString("write-url")
+
+	if len(strings.TrimSpace(opts.WriteURL)) == 0 {
+		log.Fatal("missing 'write-url' flag")
+	}
+
+	simulatorConf := common.DataGeneratorConfig{}
+	if err := viper.Unmarshal(&simulatorConf.BaseConfig); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	if err := viper.Unmarshal(&simulatorConf); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	dataSourceConfig, err := loaderConf.DataSourceConfig(target.TargetName(), &simulatorConf)
+	if err != nil {
+		panic(err)
+	}
+
+	loaderConf.Target = target.TargetName()
+	loader := load.GetBenchmarkRunner(loaderConf)
+	return &opts, loader, dataSourceConfig
+}
+
+func main() {
+	opts, loader, dataSourceConfig := initProgramOptions()
+
+	benchmark, err := prometheus.NewBenchmark(opts, dataSourceConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	loader.RunBenchmark(benchmark)
+}
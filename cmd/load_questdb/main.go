@@ -0,0 +1,77 @@
+// load_questdb loads a QuestDB instance with data from stdin, written in
+// InfluxDB line protocol over http or tcp.
+//
+// If the tables of the simulated data exist beforehand, they will be *DROPPED*.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/questdb"
+	"github.com/spf13/pflag"
+)
+
+// Parse args:
+func initProgramOptions() (*questdb.LoadingOptions, load.BenchmarkRunner, *source.DataSourceConfig) {
+	target := questdb.NewTarget()
+	loaderConf := load.BenchmarkRunnerConfig{}
+	loaderConf.AddToFlagSet(pflag.CommandLine)
+	target.TargetSpecificFlags("", pflag.CommandLine)
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&loaderConf); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+	opts := questdb.LoadingOptions{}
+	opts.Host = viper.GetString("host")
+	opts.Protocol = viper.GetString("protocol")
+	opts.HTTPPort = viper.GetString("http-port")
+	opts.ILPPort = viper.GetString("ilp-port")
+	opts.PGPort = viper.GetString("pg-port")
+	opts.User = viper.GetString("user")
+	opts.Pass = viper.GetString("pass")
+
+	if !utils.IsIn(opts.Protocol, questdb.ProtocolChoices) {
+		log.Fatalf("invalid protocol: %s", opts.Protocol)
+	}
+
+	simulatorConf := common.DataGeneratorConfig{}
+	if err := viper.Unmarshal(&simulatorConf.BaseConfig); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	if err := viper.Unmarshal(&simulatorConf); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	dataSourceConfig, err := loaderConf.DataSourceConfig(target.TargetName(), &simulatorConf)
+	if err != nil {
+		panic(err)
+	}
+
+	loaderConf.HashWorkers = false
+	loaderConf.Target = target.TargetName()
+	loader := load.GetBenchmarkRunner(loaderConf)
+	return &opts, loader, dataSourceConfig
+}
+
+func main() {
+	opts, loader, dataSourceConfig := initProgramOptions()
+
+	benchmark, err := questdb.NewBenchmark(opts, dataSourceConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	loader.RunBenchmark(benchmark)
+}
@@ -12,11 +12,12 @@ import (
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/timescaledb"
 )
 
 // Parse args:
-func initProgramOptions() (*timescaledb.LoadingOptions, load.BenchmarkRunner, *load.BenchmarkRunnerConfig) {
+func initProgramOptions() (*timescaledb.LoadingOptions, load.BenchmarkRunner, *load.BenchmarkRunnerConfig, *source.DataSourceConfig) {
 	target := timescaledb.NewTarget()
 	loaderConf := load.BenchmarkRunnerConfig{}
 	loaderConf.AddToFlagSet(pflag.CommandLine)
@@ -73,12 +74,25 @@ func initProgramOptions() (*timescaledb.LoadingOptions, load.BenchmarkRunner, *l
 	opts.ForceTextFormat = viper.GetBool("force-text-format")
 	opts.UseInsert = viper.GetBool("use-insert")
 	
+	simulatorConf := common.DataGeneratorConfig{}
+	if err := viper.Unmarshal(&simulatorConf.BaseConfig); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	if err := viper.Unmarshal(&simulatorConf); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	dataSourceConfig, err := loaderConf.DataSourceConfig(target.TargetName(), &simulatorConf)
+	if err != nil {
+		panic(err)
+	}
+	
+	loaderConf.Target = target.TargetName()
 	loader := load.GetBenchmarkRunner(loaderConf)
-	return &opts, loader, &loaderConf
+	return &opts, loader, &loaderConf, dataSourceConfig
 }
 
 func main() {
-	opts, loader, loaderConf := initProgramOptions()
+	opts, loader, loaderConf, dataSourceConfig := initProgramOptions()
 	
 	// If specified, generate a performance profile
 	if len(opts.ProfileFile) > 0 {
@@ -92,10 +106,7 @@ func main() {
 		)
 	}
 	
-	benchmark, err := timescaledb.NewBenchmark(loaderConf.DBName, opts, &source.DataSourceConfig{
-		Type: source.FileDataSourceType,
-		File: &source.FileDataSourceConfig{Location: loaderConf.FileName},
-	})
+	benchmark, err := timescaledb.NewBenchmark(loaderConf.DBName, opts, dataSourceConfig)
 	if err != nil {
 		panic(err)
 	}
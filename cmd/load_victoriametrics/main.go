@@ -0,0 +1,76 @@
+// load_victoriametrics loads a VictoriaMetrics instance with data from stdin,
+// imported as InfluxDB line protocol or JSON lines.
+//
+// If the series of the simulated data exist beforehand, they will be *DELETED*.
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/victoriametrics"
+	"github.com/spf13/pflag"
+)
+
+// Parse args:
+func initProgramOptions() (*victoriametrics.LoadingOptions, load.BenchmarkRunner, *source.DataSourceConfig) {
+	target := victoriametrics.NewTarget()
+	loaderConf := load.BenchmarkRunnerConfig{}
+	loaderConf.AddToFlagSet(pflag.CommandLine)
+	target.TargetSpecificFlags("", pflag.CommandLine)
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&loaderConf); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+	opts := victoriametrics.LoadingOptions{}
+	opts.URLs = viper.GetString("urls")
+	opts.ImportFormat = viper.GetString("import-format")
+
+	if len(strings.TrimSpace(opts.URLs)) == 0 {
+		log.Fatal("missing 'urls' flag")
+	}
+	if !utils.IsIn(opts.ImportFormat, victoriametrics.ImportFormatChoices) {
+		log.Fatalf("invalid import format: %s", opts.ImportFormat)
+	}
+
+	simulatorConf := common.DataGeneratorConfig{}
+	if err := viper.Unmarshal(&simulatorConf.BaseConfig); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	if err := viper.Unmarshal(&simulatorConf); err != nil {
+		panic(fmt.Errorf("unable to decode simulator config: %s", err))
+	}
+	dataSourceConfig, err := loaderConf.DataSourceConfig(target.TargetName(), &simulatorConf)
+	if err != nil {
+		panic(err)
+	}
+
+	loaderConf.HashWorkers = false
+	loaderConf.Target = target.TargetName()
+	loader := load.GetBenchmarkRunner(loaderConf)
+	return &opts, loader, dataSourceConfig
+}
+
+func main() {
+	opts, loader, dataSourceConfig := initProgramOptions()
+
+	benchmark, err := victoriametrics.NewBenchmark(opts, dataSourceConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	loader.RunBenchmark(benchmark)
+}
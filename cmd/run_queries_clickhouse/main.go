@@ -0,0 +1,69 @@
+// run_queries_clickhouse speed tests ClickHouse using requests from stdin or file
+//
+// It reads encoded Query objects from stdin or file, and makes concurrent requests
+// to the HTTP interface of the provided ClickHouse endpoint. The summary includes
+// the rows read by the queries, as reported by ClickHouse.
+// This program has no knowledge of the internals of the endpoint.
+package main
+
+import (
+	"fmt"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/clickhouse"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/spf13/pflag"
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+	opts   *clickhouse.QueryOptions
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("host", "localhost", "Hostname of the ClickHouse instance")
+	pflag.String("port", "8123", "Port of the HTTP interface")
+	pflag.String("user", "default", "User to connect to ClickHouse as")
+	pflag.String("password", "", "Password for the user connecting to ClickHouse")
+
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	config.Target = constants.FormatClickHouse
+	runner = query.NewBenchmarkRunner(config)
+
+	loadingOptions := &clickhouse.LoadingOptions{
+		Host: viper.GetString("host"),
+		Port: viper.GetString("port"),
+	}
+	opts = &clickhouse.QueryOptions{
+		URL:            loadingOptions.URL(),
+		DBName:         runner.DatabaseName(),
+		User:           viper.GetString("user"),
+		Password:       viper.GetString("password"),
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	}
+}
+
+func main() {
+	runner.Run(&query.TimescaleDBPool, newProcessor)
+}
+
+func newProcessor() query.Processor { return clickhouse.NewQueryProcessor(opts) }
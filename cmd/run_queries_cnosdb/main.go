@@ -1,4 +1,4 @@
-// run_queries_cnosdb speed tests InfluxDB using requests from stdin.
+// run_queries_cnosdb speed tests CnosDB using requests from stdin.
 //
 // It reads encoded Query objects from stdin, and makes concurrent requests
 // to the provided HTTP endpoint. This program has no knowledge of the
@@ -14,13 +14,14 @@ import (
 	"github.com/blagojts/viper"
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/cnosdb"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/spf13/pflag"
 )
 
 // Program option vars:
 var (
 	daemonUrls []string
-	chunkSize  uint64
 	basicAuth  string
 )
 
@@ -53,17 +54,13 @@ func init() {
 	}
 
 	csvDaemonUrls = viper.GetString("urls")
-	chunkSize = viper.GetUint64("chunk-response-size")
 
 	daemonUrls = strings.Split(csvDaemonUrls, ",")
 	if len(daemonUrls) == 0 {
 		log.Fatal("missing 'urls' flag")
 	}
-	for i, url := range daemonUrls {
-		u := strings.TrimSuffix(url, "/")
-		daemonUrls[i] = u + "/api/v1/sql?tenant=cnosdb&db="
-	}
 
+	config.Target = constants.FormatCnosDB
 	runner = query.NewBenchmarkRunner(config)
 }
 
@@ -77,30 +74,12 @@ func main() {
 	runner.Run(&query.HTTPPool, newProcessor)
 }
 
-type processor struct {
-	w    *HTTPClient
-	opts *HTTPClientDoOptions
-}
-
-func newProcessor() query.Processor { return &processor{} }
-
-func (p *processor) Init(workerNumber int) {
-	p.opts = &HTTPClientDoOptions{
-		debug:                runner.DebugLevel(),
-		prettyPrintResponses: runner.DoPrintResponses(),
-		database:             runner.DatabaseName(),
-	}
-	url := daemonUrls[workerNumber%len(daemonUrls)]
-	p.w = NewHTTPClient(url)
-}
-
-func (p *processor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
-	hq := q.(*query.HTTP)
-	lag, err := p.w.Do(hq, p.opts)
-	if err != nil {
-		return nil, err
-	}
-	stat := query.GetStat()
-	stat.Init(q.HumanLabelName(), lag)
-	return []*query.Stat{stat}, nil
+func newProcessor() query.Processor {
+	return cnosdb.NewQueryProcessor(&cnosdb.QueryOptions{
+		URLs:           daemonUrls,
+		Database:       runner.DatabaseName(),
+		BasicAuth:      basicAuth,
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	})
 }
@@ -14,12 +14,17 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/influx"
 )
 
 // Program option vars:
 var (
 	daemonUrls []string
 	chunkSize  uint64
+	apiVersion int
+	org        string
+	token      string
 )
 
 // Global vars:
@@ -35,6 +40,9 @@ func init() {
 	
 	pflag.String("urls", "http://localhost:8086", "Daemon URLs, comma-separated. Will be used in a round-robin fashion.")
 	pflag.Uint64("chunk-response-size", 0, "Number of series to chunk results into. 0 means no chunking.")
+	pflag.Int("api-version", 1, "HTTP API version of the server: 1, 2 or 3. Only sets how to authenticate, the endpoints are those of the queries.")
+	pflag.String("org", "", "Organization the Flux queries run in, required by Flux queries.")
+	pflag.String("token", "", "API token to authenticate with API versions 2 and 3.")
 	
 	pflag.Parse()
 	
@@ -50,12 +58,19 @@ func init() {
 	
 	csvDaemonUrls = viper.GetString("urls")
 	chunkSize = viper.GetUint64("chunk-response-size")
+	apiVersion = viper.GetInt("api-version")
+	org = viper.GetString("org")
+	token = viper.GetString("token")
 	
 	daemonUrls = strings.Split(csvDaemonUrls, ",")
 	if len(daemonUrls) == 0 {
 		log.Fatal("missing 'urls' flag")
 	}
+	if apiVersion < 1 || apiVersion > 3 {
+		log.Fatalf("invalid api version: %d", apiVersion)
+	}
 	
+	config.Target = constants.FormatInflux
 	runner = query.NewBenchmarkRunner(config)
 }
 
@@ -63,31 +78,14 @@ func main() {
 	runner.Run(&query.HTTPPool, newProcessor)
 }
 
-type processor struct {
-	w    *HTTPClient
-	opts *HTTPClientDoOptions
-}
-
-func newProcessor() query.Processor { return &processor{} }
-
-func (p *processor) Init(workerNumber int) {
-	p.opts = &HTTPClientDoOptions{
-		Debug:                runner.DebugLevel(),
-		PrettyPrintResponses: runner.DoPrintResponses(),
-		chunkSize:            chunkSize,
-		database:             runner.DatabaseName(),
-	}
-	url := daemonUrls[workerNumber%len(daemonUrls)]
-	p.w = NewHTTPClient(url)
-}
-
-func (p *processor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
-	hq := q.(*query.HTTP)
-	lag, err := p.w.Do(hq, p.opts)
-	if err != nil {
-		return nil, err
-	}
-	stat := query.GetStat()
-	stat.Init(q.HumanLabelName(), lag)
-	return []*query.Stat{stat}, nil
+func newProcessor() query.Processor {
+	return influx.NewQueryProcessor(&influx.QueryOptions{
+		URLs:           daemonUrls,
+		Database:       runner.DatabaseName(),
+		ChunkSize:      chunkSize,
+		Org:            org,
+		Authorization:  influx.Authorization(apiVersion, token),
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	})
 }
@@ -0,0 +1,69 @@
+// run_queries_iotdb speed tests IoTDB using requests from stdin or file
+//
+// It reads encoded Query objects from stdin or file, and makes concurrent requests
+// to the provided IoTDB endpoint using one session per worker.
+// This program has no knowledge of the internals of the endpoint.
+package main
+
+import (
+	"fmt"
+
+	"github.com/apache/iotdb-client-go/client"
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/iotdb"
+	"github.com/spf13/pflag"
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+	opts   *iotdb.QueryOptions
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("host", "localhost", "IoTDB host")
+	pflag.String("port", "6667", "Which port to connect to on the IoTDB host")
+	pflag.String("user", "root", "User to connect to IoTDB as")
+	pflag.String("password", "root", "Password for the user connecting to IoTDB")
+	pflag.Int32("fetch-size", client.DefaultFetchSize, "Number of rows fetched from IoTDB per round trip")
+	pflag.Duration("timeout", 0, "Timeout for a single query, 0 = no timeout")
+
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	config.Target = constants.FormatIOTDB
+	runner = query.NewBenchmarkRunner(config)
+
+	opts = &iotdb.QueryOptions{
+		Host:           viper.GetString("host"),
+		Port:           viper.GetString("port"),
+		User:           viper.GetString("user"),
+		Pass:           viper.GetString("password"),
+		FetchSize:      viper.GetInt32("fetch-size"),
+		Timeout:        viper.GetDuration("timeout"),
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	}
+}
+
+func main() {
+	runner.Run(&query.IoTDBPool, newProcessor)
+}
+
+func newProcessor() query.Processor { return iotdb.NewQueryProcessor(opts) }
@@ -0,0 +1,75 @@
+// run_queries_questdb speed tests QuestDB using requests from stdin or file
+//
+// It reads encoded Query objects from stdin or file, and makes concurrent requests
+// to the PostgreSQL wire protocol endpoint of QuestDB, using the query processors
+// of TimescaleDB.
+// This program has no knowledge of the internals of the endpoint.
+package main
+
+import (
+	"fmt"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/questdb"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/timescaledb"
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/spf13/pflag"
+)
+
+const pgxDriver = "pgx"
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+	opts   *timescaledb.QueryOptions
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("host", "localhost", "Hostname of the QuestDB instance")
+	pflag.String("pg-port", "8812", "Port of the PostgreSQL wire protocol endpoint")
+	pflag.String("user", "admin", "User to connect to QuestDB as")
+	pflag.String("pass", "quest", "Password for the user connecting to QuestDB")
+
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	config.Target = constants.FormatQuestDB
+	runner = query.NewBenchmarkRunner(config)
+
+	// QuestDB has a single database, the benchmark database name is not used
+	loadingOptions := &questdb.LoadingOptions{
+		Host:   viper.GetString("host"),
+		PGPort: viper.GetString("pg-port"),
+		User:   viper.GetString("user"),
+		Pass:   viper.GetString("pass"),
+	}
+	connectString := loadingOptions.GetConnectString()
+	opts = &timescaledb.QueryOptions{
+		Driver:         pgxDriver,
+		ConnectString:  func(int) string { return connectString },
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	}
+}
+
+func main() {
+	runner.Run(&query.TimescaleDBPool, newProcessor)
+}
+
+func newProcessor() query.Processor { return timescaledb.NewQueryProcessor(opts) }
@@ -6,6 +6,7 @@ import (
 	"github.com/blagojts/viper"
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 	"github.com/taosdata/driver-go/v2/af"
@@ -49,6 +50,16 @@ func (p *processor) Init(workerNum int) {
 }
 
 func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	stats, _, err := p.execute(q, false)
+	return stats, err
+}
+
+func (p *processor) ProcessQueryWithResult(q query.Query, isWarm bool) ([]*query.Stat, *query.ResultSet, error) {
+	return p.execute(q, true)
+}
+
+// execute runs the query and, if collect is set, returns all the fetched rows.
+func (p *processor) execute(q query.Query, collect bool) ([]*query.Stat, *query.ResultSet, error) {
 	tq := q.(*query.TDengine)
 	start := time.Now()
 	qry := string(tq.SqlQuery)
@@ -58,26 +69,31 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	rows, err := p.conn.Query(qry)
 	if err != nil {
 		logrus.WithField("query", qry).Debug(err)
-		return nil, err
+		return nil, nil, err
 	}
 	cols := rows.Columns()
 	length := len(cols)
 	cache := make([]driver.Value, length)
-	var rowsPrint [][]driver.Value
-	if p.printResponse {
+	var rs *query.ResultSet
+	if collect || p.printResponse {
+		rs = query.NewResultSet(cols...)
 		for {
 			err = rows.Next(cache)
 			if err == io.EOF {
 				break
 			}
-			temp := make([]driver.Value, length)
-			copy(temp, cache)
-			rowsPrint = append(rowsPrint, temp)
+			temp := make([]interface{}, length)
+			for i, v := range cache {
+				temp[i] = v
+			}
+			rs.AppendRow(temp...)
+		}
+		if p.printResponse {
+			logrus.WithFields(logrus.Fields{
+				"columns": cols,
+				"rows":    rs.Rows,
+			}).Info()
 		}
-		logrus.WithFields(logrus.Fields{
-			"columns": cols,
-			"rows":    rowsPrint,
-		}).Info()
 	} else {
 		for {
 			err = rows.Next(cache)
@@ -87,13 +103,13 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 		}
 	}
 	if err = rows.Close(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	took := float64(time.Since(start).Nanoseconds()) / 1e6
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
-	return []*query.Stat{stat}, nil
+	return []*query.Stat{stat}, rs, nil
 }
 
 func init() {
@@ -120,6 +136,7 @@ func init() {
 	password = viper.GetString("password")
 	port = viper.GetInt("port")
 
+	config.Target = constants.FormatTDEngine
 	runner = query.NewBenchmarkRunner(config)
 }
 
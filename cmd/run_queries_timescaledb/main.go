@@ -6,20 +6,18 @@
 package main
 
 import (
-	"database/sql"
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
 	
 	"github.com/blagojts/viper"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	_ "github.com/lib/pq"
-	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/timescaledb"
 )
 
 const pgxDriver = "pgx" // default driver
@@ -77,6 +75,7 @@ func init() {
 	showExplain = viper.GetBool("show-explain")
 	forceTextFormat = viper.GetBool("force-text-format")
 	
+	config.Target = constants.FormatTimescaleDB
 	runner = query.NewBenchmarkRunner(config)
 	
 	if showExplain {
@@ -99,6 +98,16 @@ func main() {
 	runner.Run(&query.TimescaleDBPool, newProcessor)
 }
 
+func newProcessor() query.Processor {
+	return timescaledb.NewQueryProcessor(&timescaledb.QueryOptions{
+		Driver:         driver,
+		ConnectString:  getConnectString,
+		ShowExplain:    showExplain,
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	})
+}
+
 // Get the connection string for a connection to PostgreSQL.
 
 // If we're running queries against multiple nodes we need to balance the queries
@@ -127,115 +136,3 @@ func getConnectString(workerNumber int) string {
 	
 	return connectString
 }
-
-// prettyPrintResponse prints a Query and its response in JSON format with two
-// keys: 'query' which has a value of the SQL used to generate the second key
-// 'results' which is an array of each row in the return set.
-func prettyPrintResponse(rows *sql.Rows, q *query.TimescaleDB) {
-	resp := make(map[string]interface{})
-	resp["query"] = string(q.SqlQuery)
-	resp["results"] = mapRows(rows)
-	
-	line, err := json.MarshalIndent(resp, "", "  ")
-	if err != nil {
-		panic(err)
-	}
-	
-	fmt.Println(string(line) + "\n")
-}
-
-func mapRows(r *sql.Rows) []map[string]interface{} {
-	rows := []map[string]interface{}{}
-	cols, _ := r.Columns()
-	for r.Next() {
-		row := make(map[string]interface{})
-		values := make([]interface{}, len(cols))
-		for i := range values {
-			values[i] = new(interface{})
-		}
-		
-		err := r.Scan(values...)
-		if err != nil {
-			panic(errors.Wrap(err, "error while reading values"))
-		}
-		
-		for i, column := range cols {
-			row[column] = *values[i].(*interface{})
-		}
-		rows = append(rows, row)
-	}
-	return rows
-}
-
-type queryExecutorOptions struct {
-	showExplain   bool
-	debug         bool
-	printResponse bool
-}
-
-type processor struct {
-	db   *sql.DB
-	opts *queryExecutorOptions
-}
-
-func newProcessor() query.Processor { return &processor{} }
-
-func (p *processor) Init(workerNumber int) {
-	db, err := sql.Open(driver, getConnectString(workerNumber))
-	if err != nil {
-		panic(err)
-	}
-	p.db = db
-	p.opts = &queryExecutorOptions{
-		showExplain:   showExplain,
-		debug:         runner.DebugLevel() > 0,
-		printResponse: runner.DoPrintResponses(),
-	}
-}
-
-func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
-	// No need to run again for EXPLAIN
-	if isWarm && p.opts.showExplain {
-		return nil, nil
-	}
-	tq := q.(*query.TimescaleDB)
-	
-	start := time.Now()
-	qry := string(tq.SqlQuery)
-	if showExplain {
-		qry = "EXPLAIN ANALYZE " + qry
-	}
-	rows, err := p.db.Query(qry)
-	if err != nil {
-		return nil, err
-	}
-	
-	if p.opts.debug {
-		fmt.Println(qry)
-	}
-	if showExplain {
-		text := ""
-		for rows.Next() {
-			var s string
-			if err2 := rows.Scan(&s); err2 != nil {
-				panic(err2)
-			}
-			text += s + "\n"
-		}
-		fmt.Printf("%s\n\n%s\n-----\n\n", qry, text)
-	} else if p.opts.printResponse {
-		prettyPrintResponse(rows, tq)
-	}
-	// Fetching all the rows to confirm that the query is fully completed.
-	for rows.Next() {
-	}
-	rows.Close()
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	took := float64(time.Since(start).Nanoseconds()) / 1e6
-	stat := query.GetStat()
-	stat.Init(q.HumanLabelName(), took)
-	
-	return []*query.Stat{stat}, err
-}
@@ -0,0 +1,65 @@
+// run_queries_victoriametrics speed tests VictoriaMetrics using requests from stdin or file
+//
+// It reads encoded Query objects from stdin or file, and makes concurrent
+// requests to the /api/v1/query_range endpoint of the provided URLs.
+// This program has no knowledge of the internals of the endpoint.
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/victoriametrics"
+	"github.com/spf13/pflag"
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+	opts   *victoriametrics.QueryOptions
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("urls", "http://localhost:8428", "VictoriaMetrics URLs, comma-separated. Will be used in a round-robin fashion.")
+
+	pflag.Parse()
+
+	err := utils.SetupConfigFile()
+
+	if err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	csvURLs := viper.GetString("urls")
+	if len(strings.TrimSpace(csvURLs)) == 0 {
+		log.Fatal("missing 'urls' flag")
+	}
+
+	config.Target = constants.FormatVictoriaMetrics
+	runner = query.NewBenchmarkRunner(config)
+
+	opts = &victoriametrics.QueryOptions{
+		URLs:           strings.Split(csvURLs, ","),
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	}
+}
+
+func main() {
+	runner.Run(&query.HTTPPool, newProcessor)
+}
+
+func newProcessor() query.Processor { return victoriametrics.NewQueryProcessor(opts) }
@@ -13,6 +13,7 @@ require (
 	github.com/google/go-cmp v0.5.7
 	github.com/jackc/pgx/v4 v4.8.0
 	github.com/jmoiron/sqlx v1.2.1-0.20190826204134-d7d95172beb5
+	github.com/klauspost/compress v1.10.10
 	github.com/lib/pq v1.3.0
 	github.com/mattn/go-sqlite3 v1.11.0 // indirect
 	github.com/mitchellh/mapstructure v1.2.2 // indirect
@@ -20,6 +20,9 @@ import (
 const (
 	ErrNoConfig          = "no GeneratorConfig provided"
 	ErrInvalidDataConfig = "invalid config: DataGenerator needs a DataGeneratorConfig"
+
+	errCouldNotDataSummaryFmt = "could not output data summary: %v"
+	errNanosecondsOnlyFmt     = "format %s writes nanosecond timestamps, timestamp precision '%s' is not supported"
 )
 
 // DataGenerator is a type of Generator for creating data that will be consumed
@@ -30,6 +33,9 @@ type DataGenerator struct {
 	// os.Stdout unless File is specified in the GeneratorConfig passed to
 	// Generate.
 	Out io.Writer
+	// DebugOut is where the generation summary should be written. If nil, it
+	// will be os.Stderr.
+	DebugOut io.Writer
 
 	config *common.DataGeneratorConfig
 
@@ -57,6 +63,9 @@ func (g *DataGenerator) init(config common.GeneratorConfig) error {
 	if g.Out == nil {
 		g.Out = os.Stdout
 	}
+	if g.DebugOut == nil {
+		g.DebugOut = os.Stderr
+	}
 	g.bufOut, err = getBufferedWriter(g.config.File, g.Out)
 	if err != nil {
 		return err
@@ -124,6 +133,32 @@ func (g *DataGenerator) runSimulator(sim common.Simulator, serializer serialize.
 
 		currGroupID = (currGroupID + 1) % dgc.InterleavedNumGroups
 	}
+
+	if summarizer, ok := sim.(common.Summarizer); ok {
+		return g.writeSummary(summarizer.Summary())
+	}
+	return nil
+}
+
+// writeSummary writes the counters reported by the simulator, such as the
+// number of entries it dropped or sent out of order, sorted by name.
+func (g *DataGenerator) writeSummary(summary map[string]uint64) error {
+	out := g.DebugOut
+	if out == nil {
+		out = os.Stderr
+	}
+
+	keys := make([]string, 0, len(summary))
+	for k := range summary {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, err := fmt.Fprintf(out, "%s: %d\n", k, summary[k])
+		if err != nil {
+			return fmt.Errorf(errCouldNotDataSummaryFmt, err)
+		}
+	}
 	return nil
 }
 
@@ -137,8 +172,21 @@ func (g *DataGenerator) getSerializer(sim common.Simulator, target targets.Imple
 
 	case constants.FormatIOTDB:
 		g.writeHeader(sim.Headers())
+
+	case constants.FormatClickHouse:
+		g.writeHeader(sim.Headers())
+
+	case constants.FormatCnosDB, constants.FormatInflux:
+		// the loaders send the precision of the timestamps along with them
+		g.bufOut.WriteString(common.PrecisionHeader(g.config.TimestampPrecision))
+
+	case constants.FormatPrometheus, constants.FormatVictoriaMetrics:
+		// the loaders convert the timestamps as nanoseconds
+		if p := g.config.TimestampPrecision; p != "" && p != common.PrecisionNanoseconds {
+			return nil, fmt.Errorf(errNanosecondsOnlyFmt, target.TargetName(), p)
+		}
 	}
-	return target.Serializer(), nil
+	return serialize.WithTimestampPrecision(target.Serializer(), common.PrecisionDuration(g.config.TimestampPrecision)), nil
 }
 
 // TODO should be implemented in targets package
@@ -162,9 +210,15 @@ func (g *DataGenerator) writeHeader(headers *common.GeneratedDataHeaders) {
 	sort.Strings(keys)
 	for _, measurementName := range keys {
 		g.bufOut.WriteString(measurementName)
-		for _, field := range fields[measurementName] {
+		// fields are float64 unless followed by their type
+		fieldTypes := headers.FieldTypesOf(measurementName)
+		for i, field := range fields[measurementName] {
 			g.bufOut.WriteString(",")
 			g.bufOut.Write([]byte(field))
+			if fieldTypes[i] != common.FieldTypeFloat64 {
+				g.bufOut.WriteString(" ")
+				g.bufOut.WriteString(fieldTypes[i])
+			}
 		}
 		g.bufOut.WriteString("\n")
 	}
@@ -286,6 +286,42 @@ func TestRunSimulator(t *testing.T) {
 	}
 }
 
+type testSummarizedSimulator struct {
+	testSimulator
+}
+
+func (s *testSummarizedSimulator) Summary() map[string]uint64 {
+	return map[string]uint64{"zero tags": 2, "missing entries": s.iteration}
+}
+
+func TestRunSimulatorSummary(t *testing.T) {
+	var buf, debug bytes.Buffer
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Scale: 1,
+		},
+		Limit:                3,
+		InitialScale:         1,
+		LogInterval:          defaultLogInterval,
+		InterleavedNumGroups: 1,
+	}
+	g := &DataGenerator{
+		DebugOut: &debug,
+		config:   dgc,
+		bufOut:   bufio.NewWriter(&buf),
+	}
+	sim := &testSummarizedSimulator{testSimulator{limit: 3, shouldWriteLimit: 3}}
+	
+	err := g.runSimulator(sim, &testSerializer{}, dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: got %v", err)
+	}
+	want := "missing entries: 3\nzero tags: 2\n"
+	if got := debug.String(); got != want {
+		t.Errorf("incorrect summary: got\n%s\nwant\n%s", got, want)
+	}
+}
+
 func TestGetSerializer(t *testing.T) {
 	dgc := &common.DataGeneratorConfig{
 		BaseConfig: common.BaseConfig{
@@ -332,6 +368,39 @@ func TestGetSerializer(t *testing.T) {
 	
 	checkWriteHeader(constants.FormatInflux, false)
 	checkWriteHeader(constants.FormatTimescaleDB, true)
+	checkWriteHeader(constants.FormatPrometheus, false)
+
+	dgc.TimestampPrecision = common.PrecisionMilliseconds
+	for _, format := range []string{constants.FormatPrometheus, constants.FormatVictoriaMetrics} {
+		target := &mockTarget{name: format, serializer: &mockSerializer{}}
+		if _, err := g.getSerializer(sim, target); err == nil {
+			t.Errorf("no error for millisecond timestamps with format %s", format)
+		}
+	}
+}
+
+func TestWriteHeader(t *testing.T) {
+	var buf bytes.Buffer
+	g := &DataGenerator{bufOut: bufio.NewWriter(&buf)}
+	g.writeHeader(&common.GeneratedDataHeaders{
+		TagTypes: []string{"string", "float32"},
+		TagKeys:  []string{"name", "capacity"},
+		FieldKeys: map[string][]string{
+			"readings": {"velocity", "status", "online", "mode"},
+			"cpu":      {"usage"},
+		},
+		FieldTypes: map[string][]string{
+			"readings": {"float64", "int64", "bool", "string"},
+		},
+	})
+	g.bufOut.Flush()
+	
+	want := "tags,name string,capacity float32\n" +
+		"cpu,usage\n" +
+		"readings,velocity,status int64,online bool,mode string\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("incorrect header:\ngot\n%s\nwant\n%s", got, want)
+	}
 }
 
 type mockSerializer struct {
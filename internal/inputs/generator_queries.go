@@ -3,6 +3,7 @@ package inputs
 import (
 	"bufio"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -28,13 +29,32 @@ const (
 	errUnknownUseCaseFmt        = "use case '%s' is undefined"
 	errCannotParseTimeFmt       = "cannot parse time from string '%s': %v"
 	errBadUseFmt                = "invalid use case specified: '%v'"
+	errUnsupportedQueryTypeFmt  = "%w: '%s' for format '%s': %s"
 )
 
+// ErrUnsupportedQueryType is wrapped by the error returned when the format
+// cannot express the requested query type, so that callers iterating over
+// the query types can skip it.
+var ErrUnsupportedQueryType = errors.New("unsupported query type")
+
+// DevopsGeneratorMaker creates a query generator for devops use case
+type DevopsGeneratorMaker interface {
+	NewDevops(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
+}
+
 // IoTGeneratorMaker creates a quert generator for iot use case
 type IoTGeneratorMaker interface {
 	NewIoT(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
 }
 
+// UnsupportedQueryTypesLister is implemented by the query generator factories
+// of the databases that cannot express some query types of a use case.
+type UnsupportedQueryTypesLister interface {
+	// UnsupportedQueryTypes returns the query types of the use case that
+	// cannot be generated, mapped to the reason why.
+	UnsupportedQueryTypes(useCase string) map[string]string
+}
+
 // QueryGenerator is a type of Generator for creating queries to test against a
 // database. The output is specific to the type of database (due to each using
 // different querying techniques, e.g. SQL or REST), but is consumed by TSBS
@@ -115,6 +135,12 @@ func (g *QueryGenerator) init(conf common.GeneratorConfig) error {
 		return fmt.Errorf(errBadQueryTypeFmt, g.conf.Use, g.conf.QueryType)
 	}
 	
+	if lister, ok := g.factories[g.conf.Format].(UnsupportedQueryTypesLister); ok {
+		if reason, ok := lister.UnsupportedQueryTypes(g.conf.Use)[g.conf.QueryType]; ok {
+			return fmt.Errorf(errUnsupportedQueryTypeFmt, ErrUnsupportedQueryType, g.conf.QueryType, g.conf.Format, reason)
+		}
+	}
+	
 	g.tsStart, err = internalUtils.ParseUTCTime(g.conf.TimeStart)
 	if err != nil {
 		return fmt.Errorf(errCannotParseTimeFmt, g.conf.TimeStart, err)
@@ -153,7 +179,7 @@ func (g *QueryGenerator) addFactory(database string, factory interface{}) error
 	validFactory := false
 	
 	switch factory.(type) {
-	case IoTGeneratorMaker:
+	case DevopsGeneratorMaker, IoTGeneratorMaker:
 		validFactory = true
 	}
 	
@@ -176,6 +202,14 @@ func (g *QueryGenerator) getUseCaseGenerator(c *config.QueryGeneratorConfig) (qu
 	}
 	
 	switch c.Use {
+	case common.UseCaseDevops, common.UseCaseCPUOnly:
+		devopsFactory, ok := factory.(DevopsGeneratorMaker)
+		
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+		
+		return devopsFactory.NewDevops(g.tsStart, g.tsEnd, scale)
 	case common.UseCaseIoT:
 		iotFactory, ok := factory.(IoTGeneratorMaker)
 		
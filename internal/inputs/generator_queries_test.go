@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/influx"
 	
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/timescaledb"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/uses/devops"
 	queryUtils "github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/utils"
 	internalUtils "github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
@@ -105,7 +107,7 @@ func TestQueryGeneratorInit(t *testing.T) {
 	const okQueryType = "single-groupby-1-1-1"
 	g := &QueryGenerator{
 		useCaseMatrix: map[string]map[string]queryUtils.QueryFillerMaker{
-			common.UseCaseIoT: {
+			common.UseCaseDevops: {
 				okQueryType: nil,
 			},
 		},
@@ -151,11 +153,11 @@ func TestQueryGeneratorInit(t *testing.T) {
 	} else if got := err.Error(); got != want {
 		t.Errorf("incorrect error for bad use case:\ngot\n%s\nwant\n%s", got, want)
 	}
-	c.Use = common.UseCaseIoT
+	c.Use = common.UseCaseDevops
 	
 	// Test unknown query type
 	err = g.init(c)
-	want = fmt.Sprintf(errBadQueryTypeFmt, common.UseCaseIoT, "unknown query type")
+	want = fmt.Sprintf(errBadQueryTypeFmt, common.UseCaseDevops, "unknown query type")
 	if err == nil {
 		t.Errorf("unexpected lack of error with bad query type")
 	} else if got := err.Error(); got != want {
@@ -212,6 +214,48 @@ func TestQueryGeneratorInit(t *testing.T) {
 	}
 }
 
+func TestQueryGeneratorInitUnsupportedQueryType(t *testing.T) {
+	const queryType = "avg-daily-driving-session"
+	g := &QueryGenerator{
+		useCaseMatrix: map[string]map[string]queryUtils.QueryFillerMaker{
+			common.UseCaseIoT: {
+				queryType: nil,
+			},
+		},
+		factories: make(map[string]interface{}),
+	}
+	var buf bytes.Buffer
+	g.Out = &buf
+	c := &config.QueryGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Format:    constants.FormatVictoriaMetrics,
+			Use:       common.UseCaseIoT,
+			Scale:     1,
+			TimeStart: defaultTimeStart,
+			TimeEnd:   defaultTimeEnd,
+		},
+		QueryType:            queryType,
+		InterleavedNumGroups: 1,
+	}
+	
+	err := g.init(c)
+	if err == nil {
+		t.Fatalf("unexpected lack of error with unsupported query type")
+	}
+	if !errors.Is(err, ErrUnsupportedQueryType) {
+		t.Errorf("incorrect error for unsupported query type: got %v", err)
+	}
+	if g.bufOut != nil {
+		t.Errorf("output opened for unsupported query type")
+	}
+	
+	// the other query types of the format are still generated
+	c.Format = constants.FormatTimescaleDB
+	if err := g.init(c); err != nil {
+		t.Errorf("unexpected error for supported query type: %v", err)
+	}
+}
+
 func TestGetUseCaseGenerator(t *testing.T) {
 	var useCaseMatrix = map[string]map[string]queryUtils.QueryFillerMaker{
 		"devops": {
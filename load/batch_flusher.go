@@ -0,0 +1,66 @@
+package load
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// batchFlusher sends the partially filled batches of a scanner to the workers
+// every interval, so that a data source that blocks, like a real-time
+// simulator, does not hold back the items already read until the batches are
+// full. The lock guards the state of the scanner: the scanner holds it, except
+// while it waits for the next item of the data source.
+type batchFlusher struct {
+	sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newBatchFlusher returns a locked batchFlusher calling flush every interval,
+// or nil if interval is 0.
+func newBatchFlusher(interval time.Duration, flush func()) *batchFlusher {
+	if interval <= 0 {
+		return nil
+	}
+	f := &batchFlusher{stop: make(chan struct{}), done: make(chan struct{})}
+	f.Lock()
+	go func() {
+		defer close(f.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-ticker.C:
+				f.Lock()
+				flush()
+				f.Unlock()
+			}
+		}
+	}()
+	return f
+}
+
+// nextItem returns the next item of ds, the batches may be flushed meanwhile.
+func (f *batchFlusher) nextItem(ds targets.DataSource) data.LoadedPoint {
+	if f == nil {
+		return ds.NextItem()
+	}
+	f.Unlock()
+	defer f.Lock()
+	return ds.NextItem()
+}
+
+// close stops flushing the batches.
+func (f *batchFlusher) close() {
+	if f == nil {
+		return
+	}
+	close(f.stop)
+	f.Unlock()
+	<-f.done
+}
@@ -0,0 +1,103 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// blockingDataSource returns its items, then blocks until release is closed
+// before ending.
+type blockingDataSource struct {
+	items   []byte
+	release chan struct{}
+}
+
+func (d *blockingDataSource) NextItem() data.LoadedPoint {
+	if len(d.items) == 0 {
+		<-d.release
+		return data.LoadedPoint{}
+	}
+	item := d.items[0]
+	d.items = d.items[1:]
+	return data.NewLoadedPoint(item)
+}
+
+func (d *blockingDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
+
+func TestScanFlushesPartialBatches(t *testing.T) {
+	const flushInterval = 10 * time.Millisecond
+	scanners := map[string]func(ds targets.DataSource, batches chan<- uint) uint64{
+		"with flow control": func(ds targets.DataSource, batches chan<- uint) uint64 {
+			ch := newDuplexChannel(1)
+			go func() {
+				for b := range ch.toWorker {
+					batches <- b.Len()
+					ch.sendToScanner()
+				}
+			}()
+			defer ch.close()
+			return scanWithFlowControl([]*duplexChannel{ch}, 10, 0, flushInterval, ds, &testFactory{}, &targets.ConstantIndexer{})
+		},
+		"without flow control": func(ds targets.DataSource, batches chan<- uint) uint64 {
+			ch := make(chan targets.Batch, 1)
+			go func() {
+				for b := range ch {
+					batches <- b.Len()
+				}
+			}()
+			defer close(ch)
+			return scanWithoutFlowControl(ds, &targets.ConstantIndexer{}, &testFactory{}, []chan targets.Batch{ch}, 10, 0, flushInterval)
+		},
+	}
+	for desc, scan := range scanners {
+		ds := &blockingDataSource{items: []byte{0x00, 0x01, 0x02}, release: make(chan struct{})}
+		batches := make(chan uint, 10)
+		read := make(chan uint64)
+		go func() {
+			read <- scan(ds, batches)
+		}()
+
+		select {
+		case got := <-batches:
+			if got != 3 {
+				t.Errorf("%s: incorrect flushed batch length: got %d want %d", desc, got, 3)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: partial batch not flushed while the data source blocks", desc)
+		}
+		close(ds.release)
+		if got := <-read; got != 3 {
+			t.Errorf("%s: read incorrect: got %d want %d", desc, got, 3)
+		}
+		if len(batches) != 0 {
+			t.Errorf("%s: incorrect number of batches after the flush: got %d want 0", desc, len(batches))
+		}
+	}
+}
+
+func TestFlushInterval(t *testing.T) {
+	cases := []struct {
+		desc string
+		conf BenchmarkRunnerConfig
+		want time.Duration
+	}{
+		{desc: "file", conf: BenchmarkRunnerConfig{DataSource: "FILE"}, want: 0},
+		{desc: "file with the real-time flag", conf: BenchmarkRunnerConfig{DataSource: "FILE", RealTime: true}, want: 0},
+		{desc: "simulator", conf: BenchmarkRunnerConfig{DataSource: "SIMULATOR"}, want: 0},
+		{desc: "real-time simulator", conf: BenchmarkRunnerConfig{DataSource: "SIMULATOR", RealTime: true}, want: realTimeFlushInterval},
+		{desc: "set interval", conf: BenchmarkRunnerConfig{DataSource: "FILE", FlushInterval: time.Minute}, want: time.Minute},
+		{desc: "set interval with the real-time simulator",
+			conf: BenchmarkRunnerConfig{DataSource: "SIMULATOR", RealTime: true, FlushInterval: time.Minute}, want: time.Minute},
+	}
+	for _, c := range cases {
+		if got := c.conf.flushInterval(); got != c.want {
+			t.Errorf("%s: incorrect flush interval: got %v want %v", c.desc, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package load
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/spf13/pflag"
+)
+
+// addSimulatorFlags adds the flags of a common.DataGeneratorConfig, which
+// configure the simulator of --data-source=SIMULATOR, except those the loader
+// already has (its seed also seeds the simulator) and the format, which is the
+// one of the loaded database.
+func addSimulatorFlags(fs *pflag.FlagSet) {
+	simulatorFlags := pflag.NewFlagSet("simulator", pflag.ContinueOnError)
+	(&common.DataGeneratorConfig{}).AddToFlagSet(simulatorFlags)
+	simulatorFlags.VisitAll(func(f *pflag.Flag) {
+		if f.Name != "format" && fs.Lookup(f.Name) == nil {
+			fs.AddFlag(f)
+		}
+	})
+}
+
+// DataSourceConfig returns the configuration of the data source of the load_*
+// commands: the file FileName (STDIN if empty) or, with DataSource SIMULATOR,
+// the points of the simulator configured by sim, in the given format. DATASET
+// is the simulator of the dataset use case.
+func (c BenchmarkRunnerConfig) DataSourceConfig(format string, sim *common.DataGeneratorConfig) (*source.DataSourceConfig, error) {
+	switch c.DataSource {
+	case "", source.FileDataSourceType:
+		return &source.DataSourceConfig{
+			Type: source.FileDataSourceType,
+			File: &source.FileDataSourceConfig{Location: c.FileName},
+		}, nil
+	case source.SimulatorDataSourceType, source.DatasetDataSourceType:
+		simulator := *sim
+		simulator.Format = format
+		if c.DataSource == source.DatasetDataSourceType {
+			simulator.Use = common.UseCaseDataset
+		}
+		// the file flag is the input of the FILE data source, the simulator
+		// would write its output to it
+		simulator.File = ""
+		if simulator.InterleavedNumGroups == 0 {
+			simulator.InterleavedNumGroups = 1
+		}
+		return &source.DataSourceConfig{
+			Type:      c.DataSource,
+			Simulator: &simulator,
+		}, nil
+	default:
+		return nil, fmt.Errorf("data source type '%s' unrecognized; allowed: %v", c.DataSource, source.ValidDataSourceTypes)
+	}
+}
+
+// serializedDataSource is a DataSource of the points of a simulator serialized
+// by a target, each item holding one serialized point without its trailing
+// newline, like the lines of a file written by generate_data.
+type serializedDataSource struct {
+	simulator  common.Simulator
+	serializer serialize.PointSerializer
+	point      *data.Point
+	buf        bytes.Buffer
+}
+
+// NewSerializedDataSource returns a DataSource for the loaders that read the
+// lines of a data file as items, with the points of sim serialized by serializer
+// instead.
+func NewSerializedDataSource(sim common.Simulator, serializer serialize.PointSerializer) targets.DataSource {
+	return &serializedDataSource{simulator: sim, serializer: serializer, point: data.NewPoint()}
+}
+
+// NextItem returns the next serialized point, or an empty item once the simulation is finished.
+func (d *serializedDataSource) NextItem() data.LoadedPoint {
+	for !d.simulator.Finished() {
+		write := d.simulator.Next(d.point)
+		if !write {
+			d.point.Reset()
+			continue
+		}
+		d.buf.Reset()
+		err := d.serializer.Serialize(d.point, &d.buf)
+		d.point.Reset()
+		if err != nil {
+			fatal("can not serialize point: %s", err)
+			return data.LoadedPoint{}
+		}
+		// items are copied into the batches, the buffer can be reused
+		return data.NewLoadedPoint(bytes.TrimSuffix(d.buf.Bytes(), []byte("\n")))
+	}
+	return data.LoadedPoint{}
+}
+
+// Headers returns the headers of the simulated data.
+func (d *serializedDataSource) Headers() *common.GeneratedDataHeaders {
+	return d.simulator.Headers()
+}
@@ -0,0 +1,124 @@
+package load
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/spf13/pflag"
+)
+
+func TestAddSimulatorFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	(&BenchmarkRunnerConfig{}).AddToFlagSet(fs)
+	for _, name := range []string{"use-case", "scale", "timestamp-start", "timestamp-end", "log-interval", "data-source"} {
+		if fs.Lookup(name) == nil {
+			t.Errorf("flag %s not added", name)
+		}
+	}
+	if fs.Lookup("format") != nil {
+		t.Errorf("format flag added")
+	}
+	if err := fs.Parse([]string{"--data-source=SIMULATOR", "--use-case=cpu-only", "--scale=10"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDataSourceConfig(t *testing.T) {
+	sim := &common.DataGeneratorConfig{}
+	sim.File = "/tmp/data"
+	sim.Scale = 10
+
+	c := BenchmarkRunnerConfig{FileName: "/tmp/data"}
+	got, err := c.DataSourceConfig("influx", sim)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Type != source.FileDataSourceType || got.File.Location != "/tmp/data" {
+		t.Errorf("unexpected file data source config: %+v", got)
+	}
+
+	c.DataSource = source.SimulatorDataSourceType
+	got, err = c.DataSourceConfig("influx", sim)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Type != source.SimulatorDataSourceType {
+		t.Fatalf("wrong type: got %s", got.Type)
+	}
+	if got.Simulator.Format != "influx" || got.Simulator.File != "" || got.Simulator.Scale != 10 || got.Simulator.InterleavedNumGroups != 1 {
+		t.Errorf("unexpected simulator config: %+v", got.Simulator)
+	}
+	if sim.File != "/tmp/data" {
+		t.Errorf("simulator config modified")
+	}
+
+	sim.Dataset = "/tmp/dataset.csv"
+	c.DataSource = source.DatasetDataSourceType
+	got, err = c.DataSourceConfig("influx", sim)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Type != source.DatasetDataSourceType {
+		t.Fatalf("wrong type: got %s", got.Type)
+	}
+	if got.Simulator.Use != common.UseCaseDataset || got.Simulator.Dataset != sim.Dataset || got.Simulator.Format != "influx" {
+		t.Errorf("unexpected dataset simulator config: %+v", got.Simulator)
+	}
+
+	c.DataSource = "KAFKA"
+	if _, err = c.DataSourceConfig("influx", sim); err == nil {
+		t.Errorf("expected error for unknown data source")
+	}
+}
+
+type testSimulator struct {
+	made, max int
+}
+
+func (s *testSimulator) Finished() bool { return s.made >= s.max }
+
+func (s *testSimulator) Next(p *data.Point) bool {
+	s.made++
+	p.SetMeasurementName([]byte("cpu"))
+	p.AppendField([]byte("usage"), s.made)
+	// every other point is skipped, as the simulators do for the points of
+	// the other interleaved groups
+	return s.made%2 == 1
+}
+
+func (s *testSimulator) Fields() map[string][]string { return nil }
+
+func (s *testSimulator) TagKeys() []string { return nil }
+
+func (s *testSimulator) TagTypes() []string { return nil }
+
+func (s *testSimulator) Headers() *common.GeneratedDataHeaders {
+	return &common.GeneratedDataHeaders{TagKeys: []string{"hostname"}}
+}
+
+type testSerializer struct{}
+
+func (testSerializer) Serialize(p *data.Point, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s usage=%v\n", p.MeasurementName(), p.GetFieldValue([]byte("usage")))
+	return err
+}
+
+func TestSerializedDataSource(t *testing.T) {
+	ds := NewSerializedDataSource(&testSimulator{max: 4}, testSerializer{})
+	if got := ds.Headers().TagKeys; len(got) != 1 || got[0] != "hostname" {
+		t.Errorf("wrong headers: %v", got)
+	}
+	for _, want := range []string{"cpu usage=1", "cpu usage=3"} {
+		item := ds.NextItem()
+		if got := string(item.Data.([]byte)); got != want {
+			t.Errorf("wrong item: got %q want %q", got, want)
+		}
+	}
+	if item := ds.NextItem(); item.Data != nil {
+		t.Errorf("expected empty item once the simulation is finished, got %v", item.Data)
+	}
+}
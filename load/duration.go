@@ -0,0 +1,79 @@
+package load
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// deadlineDataSource is a DataSource that runs out of data at a deadline, so
+// that loading stops cleanly after a wall-clock time: the batches already read
+// are still written.
+type deadlineDataSource struct {
+	targets.DataSource
+	deadline time.Time
+}
+
+// NextItem returns the next item of the wrapped DataSource, or an empty item once the deadline has passed.
+func (d *deadlineDataSource) NextItem() data.LoadedPoint {
+	if !time.Now().Before(d.deadline) {
+		return data.LoadedPoint{}
+	}
+	return d.DataSource.NextItem()
+}
+
+// warmup records the counts of a load benchmark at the end of its warmup, so
+// that only what is loaded afterwards is reported.
+type warmup struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  bool
+	end   time.Time
+
+	metricCnt       uint64
+	rowCnt          uint64
+	failedMetricCnt uint64
+	failedRowCnt    uint64
+}
+
+// startWarmup starts the warmup of l, which ends after l.WarmupDuration.
+func (l *CommonBenchmarkRunner) startWarmup() {
+	l.warmup = &warmup{}
+	l.warmup.timer = time.AfterFunc(l.WarmupDuration, l.endWarmup)
+}
+
+// endWarmup records the counts at the end of the warmup
+func (l *CommonBenchmarkRunner) endWarmup() {
+	w := l.warmup
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+	w.end = time.Now()
+	w.metricCnt = atomic.LoadUint64(&l.metricCnt)
+	w.rowCnt = atomic.LoadUint64(&l.rowCnt)
+	w.failedMetricCnt = atomic.LoadUint64(&l.failedMetricCnt)
+	w.failedRowCnt = atomic.LoadUint64(&l.failedRowCnt)
+	printFn("warmup complete after %v with %d metrics loaded\n", l.WarmupDuration, w.metricCnt)
+}
+
+// excludeWarmup removes what was loaded during the warmup from the counts of
+// a finished benchmark and returns the start of the reported window. If the
+// benchmark finished during the warmup, it is reported entirely.
+func (l *CommonBenchmarkRunner) excludeWarmup(start time.Time) time.Time {
+	w := l.warmup
+	w.timer.Stop()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.done {
+		printFn("loading finished before the warmup was complete, reporting the whole run\n")
+		return start
+	}
+	l.metricCnt -= w.metricCnt
+	l.rowCnt -= w.rowCnt
+	l.failedMetricCnt -= w.failedMetricCnt
+	l.failedRowCnt -= w.failedRowCnt
+	return w.end
+}
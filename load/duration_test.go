@@ -0,0 +1,67 @@
+package load
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+type countingDataSource struct {
+	targets.DataSource
+	items int
+}
+
+func (d *countingDataSource) NextItem() data.LoadedPoint {
+	d.items++
+	return data.NewLoadedPoint(d.items)
+}
+
+func TestDeadlineDataSource(t *testing.T) {
+	ds := &deadlineDataSource{DataSource: &countingDataSource{}, deadline: time.Now().Add(time.Hour)}
+	if item := ds.NextItem(); item.Data == nil {
+		t.Errorf("no data before the deadline")
+	}
+	ds.deadline = time.Now()
+	if item := ds.NextItem(); item.Data != nil {
+		t.Errorf("got data after the deadline: %v", item.Data)
+	}
+}
+
+func TestExcludeWarmup(t *testing.T) {
+	printFn = func(s string, args ...interface{}) (n int, err error) { return 0, nil }
+	l := &CommonBenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{WarmupDuration: 10 * time.Millisecond}}
+	start := time.Now()
+	l.metricCnt, l.rowCnt, l.failedMetricCnt = 100, 10, 1
+	l.startWarmup()
+	time.Sleep(50 * time.Millisecond)
+	atomic.StoreUint64(&l.metricCnt, 250)
+	atomic.StoreUint64(&l.rowCnt, 25)
+	atomic.StoreUint64(&l.failedMetricCnt, 3)
+
+	windowStart := l.excludeWarmup(start)
+	if !windowStart.After(start) {
+		t.Errorf("reported window starts before the end of the warmup")
+	}
+	if l.metricCnt != 150 || l.rowCnt != 15 || l.failedMetricCnt != 2 {
+		t.Errorf("wrong counts after the warmup: got %d metrics, %d rows, %d failed metrics want 150, 15, 2",
+			l.metricCnt, l.rowCnt, l.failedMetricCnt)
+	}
+}
+
+func TestExcludeWarmupNotComplete(t *testing.T) {
+	printFn = func(s string, args ...interface{}) (n int, err error) { return 0, nil }
+	l := &CommonBenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{WarmupDuration: time.Hour}}
+	start := time.Now()
+	l.startWarmup()
+	l.metricCnt = 100
+
+	if windowStart := l.excludeWarmup(start); windowStart != start {
+		t.Errorf("run that ended during the warmup not reported entirely")
+	}
+	if l.metricCnt != 100 {
+		t.Errorf("wrong count: got %d want %d", l.metricCnt, 100)
+	}
+}
@@ -3,7 +3,6 @@ package load
 import (
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
@@ -27,7 +26,7 @@ func (l *noFlowBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
 		go l.work(b, wg, channels[i%numChannels], i)
 	}
 	// Start scan process - actual data read process
-	scanWithoutFlowControl(b.GetDataSource(), b.GetPointIndexer(numChannels), b.GetBatchFactory(), channels, l.BatchSize, l.Limit)
+	scanWithoutFlowControl(l.dataSource(b, *start), b.GetPointIndexer(numChannels), b.GetBatchFactory(), channels, l.BatchSize, l.Limit, l.flushInterval())
 	for _, c := range channels {
 		close(c)
 	}
@@ -48,15 +47,12 @@ func (l *noFlowBenchmarkRunner) createChannels(numChannels, capacity uint) []cha
 // work is the processing function for each worker in the loader
 func (l *noFlowBenchmarkRunner) work(b targets.Benchmark, wg *sync.WaitGroup, c <-chan targets.Batch, workerNum uint) {
 	// Prepare processor
-	proc := b.GetProcessor()
-	proc.Init(int(workerNum), l.DoLoad, l.HashWorkers)
+	proc := l.initProcessor(b, workerNum)
 
 	// Process batches coming from the incoming queue (c)
 	for batch := range c {
 		startedWorkAt := time.Now()
-		metricCnt, rowCnt := proc.ProcessBatch(batch, l.DoLoad)
-		atomic.AddUint64(&l.metricCnt, metricCnt)
-		atomic.AddUint64(&l.rowCnt, rowCnt)
+		l.processBatch(proc, batch)
 		l.timeToSleep(workerNum, startedWorkAt)
 	}
 
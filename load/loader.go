@@ -1,15 +1,17 @@
 package load
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"math/rand"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 
 	"github.com/cnosdb/tsdb-comparisons/load/insertstrategy"
@@ -19,6 +21,8 @@ import (
 const (
 	// defaultBatchSize - default size of batches to be inserted
 	defaultBatchSize                = 10000
+	// realTimeFlushInterval - period to send the partially filled batches at with the real-time simulator
+	realTimeFlushInterval           = time.Second
 	DefaultChannelCapacityFlagVal   = 0
 	defaultChannelCapacityPerWorker = 5
 	errDBExistsFmt                  = "database \"%s\" exists: aborting."
@@ -40,14 +44,33 @@ type BenchmarkRunnerConfig struct {
 	DoCreateDB      bool          `yaml:"do-create-db" mapstructure:"do-create-db" json:"do-create-db"`
 	DoAbortOnExist  bool          `yaml:"do-abort-on-exist" mapstructure:"do-abort-on-exist" json:"do-abort-on-exist"`
 	ReportingPeriod time.Duration `yaml:"reporting-period" mapstructure:"reporting-period" json:"reporting-period"`
+	// FlushInterval is the period the partially filled batches are sent at, for
+	// the data sources that block. It defaults to realTimeFlushInterval with the
+	// RealTime simulator, whose flag is shared with the simulator configuration
+	FlushInterval time.Duration `yaml:"flush-interval" mapstructure:"flush-interval" json:"flush-interval"`
+	RealTime      bool          `yaml:"real-time" mapstructure:"real-time" json:"real-time"`
 	HashWorkers     bool          `yaml:"hash-workers" mapstructure:"hash-workers" json:"hash-workers"`
 	NoFlowControl   bool          `yaml:"no-flow-control" mapstructure:"no-flow-control" json:"no-flow-control"`
 	ChannelCapacity uint          `yaml:"channel-capacity" mapstructure:"channel-capacity" json:"channel-capacity"`
 	InsertIntervals string        `yaml:"insert-intervals" mapstructure:"insert-intervals" json:"insert-intervals"`
 	ResultsFile     string        `yaml:"results-file" mapstructure:"results-file" json:"results-file"`
+	// Duration stops loading after a wall-clock time, WarmupDuration leaves
+	// what is loaded at the start of the run out of the summary and results
+	Duration       time.Duration `yaml:"duration" mapstructure:"duration" json:"duration"`
+	WarmupDuration time.Duration `yaml:"warmup-duration" mapstructure:"warmup-duration" json:"warmup-duration"`
 	// deprecated, should not be used in other places other than tsbs_load_xx commands
 	FileName string `yaml:"file" mapstructure:"file" json:"file"`
+	// DataSource is where the load_xx commands read the data from: FILE (FileName)
+	// or SIMULATOR, see DataSourceConfig
+	DataSource string `yaml:"data-source" mapstructure:"data-source" json:"data-source"`
 	Seed     int64  `yaml:"seed" mapstructure:"seed" json:"seed"`
+	// Reporter configures the metrics sinks that receive the periodic reports
+	reporter.Config `yaml:",inline" mapstructure:",squash"`
+	// RetryConfig configures how processors retry failed writes
+	RetryConfig `yaml:",inline" mapstructure:",squash"`
+	// Target is the name of the database being loaded, recorded in the results file.
+	// It is set by the load commands, not by flags.
+	Target string `yaml:"-" mapstructure:"-" json:"-"`
 }
 
 // AddToFlagSet adds command line flags needed by the BenchmarkRunnerConfig to the flag set.
@@ -56,30 +79,50 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Uint("batch-size", defaultBatchSize, "Number of items to batch together in a single insert")
 	fs.Uint("workers", 1, "Number of parallel clients inserting")
 	fs.Uint64("limit", 0, "Number of items to insert (0 = all of them).")
+	fs.Duration("duration", 0, "Stop loading after this wall-clock time (0 = no limit)")
+	fs.Duration("warmup-duration", 0, "Time at the start of the run whose inserts are not counted in the summary and results")
 	fs.Bool("do-load", true, "Whether to write data. Set this flag to false to check input read speed.")
 	fs.Bool("do-create-db", false, "Whether to create the database. Disable on all but one client if running on a multi client setup.")
 	fs.Bool("do-abort-on-exist", false, "Whether to abort if a database with the given name already exists.")
 	fs.Duration("reporting-period", 10*time.Second, "Period to report write stats")
+	fs.Duration("flush-interval", 0, "Period to send the partially filled batches to the workers, so that the items of a data source "+
+		"that blocks are not held back (0 = only full batches, or every second with the real-time simulator)")
 	fs.String("file", "", "File name to read data from")
+	fs.String("data-source", source.FileDataSourceType, "Where to load the data from. Valid: "+strings.Join(source.ValidDataSourceTypes, ", ")+
+		". SIMULATOR generates the data while loading it, as configured by the same flags as generate_data (use-case, scale, ...), "+
+		"DATASET reads the external dataset of the dataset flags (dataset, dataset-tags, ...)")
 	fs.Int64("seed", 0, "PRNG seed (default: 0, which uses the current timestamp)")
 	fs.String("insert-intervals", "", "Time to wait between each insert, default '' => all workers insert ASAP. '1,2' = worker 1 waits 1s between inserts, worker 2 and others wait 2s")
 	fs.Bool("hash-workers", false, "Whether to consistently hash insert data to the same workers (i.e., the data for a particular host always goes to the same worker)")
 	fs.String("results-file", "", "Write the test results summary json to this file")
+	c.Config.AddToFlagSet(fs)
+	c.RetryConfig.AddToFlagSet(fs)
+	addSimulatorFlags(fs)
 }
 
 type BenchmarkRunner interface {
 	DatabaseName() string
 	RunBenchmark(b targets.Benchmark)
+	// Progress returns the number of metrics and rows loaded so far, it is safe
+	// to call while RunBenchmark is running
+	Progress() (metricCount, rowCount uint64)
 }
 
 // CommonBenchmarkRunner is responsible for initializing and storing common
 // flags across all database systems and ultimately running a supplied Benchmark
 type CommonBenchmarkRunner struct {
 	BenchmarkRunnerConfig
-	metricCnt      uint64
-	rowCnt         uint64
-	initialRand    *rand.Rand
-	sleepRegulator insertstrategy.SleepRegulator
+	metricCnt       uint64
+	rowCnt          uint64
+	failedMetricCnt uint64 // failedMetricCnt is the number of metrics that could not be written, even after retrying
+	failedRowCnt    uint64
+	initialRand     *rand.Rand
+	sleepRegulator  insertstrategy.SleepRegulator
+	retryPolicy     *RetryPolicy
+	reporter        reporter.Reporter
+	reportStop      chan struct{} // reportStop is closed to stop the periodic reports
+	reportDone      chan struct{}
+	warmup          *warmup // warmup tracks the warmup, if WarmupDuration is set
 }
 
 // GetBenchmarkRunnerWithBatchSize returns the singleton CommonBenchmarkRunner for use in a benchmark program
@@ -93,6 +136,9 @@ func GetBenchmarkRunner(c BenchmarkRunnerConfig) BenchmarkRunner {
 	}
 
 	loader.initialRand = rand.New(rand.NewSource(loader.Seed))
+	if c.Duration > 0 && c.WarmupDuration >= c.Duration {
+		panic("warmup duration is not shorter than duration")
+	}
 
 	var err error
 	if c.InsertIntervals == "" {
@@ -103,6 +149,11 @@ func GetBenchmarkRunner(c BenchmarkRunnerConfig) BenchmarkRunner {
 			panic(fmt.Sprintf("could not initialize BenchmarkRunner: %v", err))
 		}
 	}
+	loader.retryPolicy = NewRetryPolicy(c.RetryConfig)
+	loader.reporter, err = reporter.New(c.Config)
+	if err != nil {
+		panic(fmt.Sprintf("could not initialize BenchmarkRunner: %v", err))
+	}
 	if !c.NoFlowControl {
 		return &loader
 	}
@@ -123,6 +174,11 @@ func (l *CommonBenchmarkRunner) DatabaseName() string {
 	return l.DBName
 }
 
+// Progress returns the number of metrics and rows loaded so far
+func (l *CommonBenchmarkRunner) Progress() (uint64, uint64) {
+	return atomic.LoadUint64(&l.metricCnt), atomic.LoadUint64(&l.rowCnt)
+}
+
 func (l *CommonBenchmarkRunner) preRun(b targets.Benchmark) (*sync.WaitGroup, *time.Time) {
 	// Create required DB
 	if b.GetDBCreator() != nil {
@@ -131,20 +187,57 @@ func (l *CommonBenchmarkRunner) preRun(b targets.Benchmark) (*sync.WaitGroup, *t
 	}
 
 	if l.ReportingPeriod.Nanoseconds() > 0 {
-		go l.report(l.ReportingPeriod)
+		l.reportStop = make(chan struct{})
+		l.reportDone = make(chan struct{})
+		go func() {
+			l.report(l.ReportingPeriod)
+			close(l.reportDone)
+		}()
 	}
 	wg := &sync.WaitGroup{}
 	wg.Add(int(l.Workers))
 	start := time.Now()
+	if l.WarmupDuration > 0 {
+		l.startWarmup()
+	}
 	return wg, &start
 }
 
+// flushInterval returns the period to send the partially filled batches at,
+// 0 to only send the full ones.
+func (c BenchmarkRunnerConfig) flushInterval() time.Duration {
+	if c.FlushInterval == 0 && c.RealTime && c.DataSource != source.FileDataSourceType {
+		return realTimeFlushInterval
+	}
+	return c.FlushInterval
+}
+
+// dataSource returns the DataSource of b, ending at the configured duration
+// after start
+func (l *CommonBenchmarkRunner) dataSource(b targets.Benchmark, start time.Time) targets.DataSource {
+	ds := b.GetDataSource()
+	if l.Duration > 0 {
+		ds = &deadlineDataSource{DataSource: ds, deadline: start.Add(l.Duration)}
+	}
+	return ds
+}
+
 func (l *CommonBenchmarkRunner) postRun(wg *sync.WaitGroup, start *time.Time) {
 	// Wait for all workers to finish
 	wg.Wait()
 	end := time.Now()
 	took := end.Sub(*start)
+	if l.reportStop != nil {
+		close(l.reportStop)
+		<-l.reportDone
+	}
+	if l.warmup != nil {
+		// only the window after the warmup is reported
+		*start = l.excludeWarmup(*start)
+		took = end.Sub(*start)
+	}
 	l.summary(took)
+	l.reportSummary(end, took)
 	if l.BenchmarkRunnerConfig.ResultsFile != "" {
 		metricRate := float64(l.metricCnt) / took.Seconds()
 		rowRate := float64(l.rowCnt) / took.Seconds()
@@ -153,29 +246,27 @@ func (l *CommonBenchmarkRunner) postRun(wg *sync.WaitGroup, start *time.Time) {
 }
 
 func (l *CommonBenchmarkRunner) saveTestResult(took time.Duration, start time.Time, end time.Time, metricRate, rowRate float64) {
-	totals := make(map[string]interface{})
-	totals["metricRate"] = metricRate
-	if l.rowCnt > 0 {
-		totals["rowRate"] = rowRate
-	}
-
-	testResult := LoaderTestResult{
-		ResultFormatVersion: LoaderTestResultVersion,
+	testResult := results.Result{
+		ResultFormatVersion: results.FormatVersion,
+		Kind:                results.KindLoad,
+		Target:              l.Target,
+		DBName:              l.DBName,
 		RunnerConfig:        l.BenchmarkRunnerConfig,
-		StartTime:           start.Unix(),
-		EndTime:             end.Unix(),
+		StartTime:           start.UnixNano() / int64(time.Millisecond),
+		EndTime:             end.UnixNano() / int64(time.Millisecond),
 		DurationMillis:      took.Milliseconds(),
-		Totals:              totals,
+		Load: &results.LoadTotals{
+			Metrics:       l.metricCnt,
+			Rows:          l.rowCnt,
+			MetricRate:    metricRate,
+			RowRate:       rowRate,
+			FailedMetrics: l.failedMetricCnt,
+			FailedRows:    l.failedRowCnt,
+		},
 	}
 
 	_, _ = fmt.Printf("Saving results json file to %s\n", l.BenchmarkRunnerConfig.ResultsFile)
-	file, err := json.MarshalIndent(testResult, "", " ")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	err = ioutil.WriteFile(l.BenchmarkRunnerConfig.ResultsFile, file, 0644)
-	if err != nil {
+	if err := testResult.WriteFile(l.BenchmarkRunnerConfig.ResultsFile); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -200,7 +291,7 @@ func (l *CommonBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
 	}
 
 	// Start scan process - actual data read process
-	scanWithFlowControl(channels, l.BatchSize, l.Limit, b.GetDataSource(), b.GetBatchFactory(), b.GetPointIndexer(uint(len(channels))))
+	scanWithFlowControl(channels, l.BatchSize, l.Limit, l.flushInterval(), l.dataSource(b, *start), b.GetBatchFactory(), b.GetPointIndexer(uint(len(channels))))
 	// After scan process completed (no more data to come) - begin shutdown process
 
 	// Close all communication channels to/from workers
@@ -277,16 +368,13 @@ func (l *CommonBenchmarkRunner) createChannels(numChannels, capacity uint) []*du
 func (l *CommonBenchmarkRunner) work(b targets.Benchmark, wg *sync.WaitGroup, c *duplexChannel, workerNum uint) {
 
 	// Prepare processor
-	proc := b.GetProcessor()
-	proc.Init(int(workerNum), l.DoLoad, l.HashWorkers)
+	proc := l.initProcessor(b, workerNum)
 
 	// Process batches coming from duplexChannel.toWorker queue
 	// and send ACKs into duplexChannel.toScanner queue
 	for batch := range c.toWorker {
 		startedWorkAt := time.Now()
-		metricCnt, rowCnt := proc.ProcessBatch(batch, l.DoLoad)
-		atomic.AddUint64(&l.metricCnt, metricCnt)
-		atomic.AddUint64(&l.rowCnt, rowCnt)
+		l.processBatch(proc, batch)
 		c.sendToScanner()
 		l.timeToSleep(workerNum, startedWorkAt)
 	}
@@ -300,6 +388,34 @@ func (l *CommonBenchmarkRunner) work(b targets.Benchmark, wg *sync.WaitGroup, c
 	wg.Done()
 }
 
+// initProcessor gets the processor of a worker from b and initializes it
+func (l *CommonBenchmarkRunner) initProcessor(b targets.Benchmark, workerNum uint) targets.Processor {
+	proc := b.GetProcessor()
+	switch p := proc.(type) {
+	case RetryingProcessor:
+		p.SetRetryPolicy(l.retryPolicy)
+	}
+	proc.Init(int(workerNum), l.DoLoad, l.HashWorkers)
+	return proc
+}
+
+// processBatch processes a batch with proc and counts the metrics and rows
+// written, and those that failed if proc tells them apart
+func (l *CommonBenchmarkRunner) processBatch(proc targets.Processor, batch targets.Batch) {
+	switch p := proc.(type) {
+	case targets.ProcessorWithFailures:
+		metricCnt, rowCnt, failedMetricCnt, failedRowCnt := p.ProcessBatchWithFailures(batch, l.DoLoad)
+		atomic.AddUint64(&l.metricCnt, metricCnt)
+		atomic.AddUint64(&l.rowCnt, rowCnt)
+		atomic.AddUint64(&l.failedMetricCnt, failedMetricCnt)
+		atomic.AddUint64(&l.failedRowCnt, failedRowCnt)
+	default:
+		metricCnt, rowCnt := proc.ProcessBatch(batch, l.DoLoad)
+		atomic.AddUint64(&l.metricCnt, metricCnt)
+		atomic.AddUint64(&l.rowCnt, rowCnt)
+	}
+}
+
 func (l *CommonBenchmarkRunner) timeToSleep(workerNum uint, startedWorkAt time.Time) {
 	if l.sleepRegulator != nil {
 		l.sleepRegulator.Sleep(int(workerNum), startedWorkAt)
@@ -315,6 +431,9 @@ func (l *CommonBenchmarkRunner) summary(took time.Duration) {
 		rowRate := float64(l.rowCnt) / float64(took.Seconds())
 		printFn("loaded %d rows in %0.3fsec with %d workers (mean rate %0.2f rows/sec)\n", l.rowCnt, took.Seconds(), l.Workers, rowRate)
 	}
+	if l.failedMetricCnt > 0 || l.failedRowCnt > 0 {
+		printFn("failed to load %d metrics and %d rows\n", l.failedMetricCnt, l.failedRowCnt)
+	}
 }
 
 // report handles periodic reporting of loading stats
@@ -325,7 +444,15 @@ func (l *CommonBenchmarkRunner) report(period time.Duration) {
 	prevRowCount := uint64(0)
 
 	printFn("time,per. metric/s,metric total,overall metric/s,per. row/s,row total,overall row/s\n")
-	for now := range time.NewTicker(period).C {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		var now time.Time
+		select {
+		case <-l.reportStop:
+			return
+		case now = <-ticker.C:
+		}
 		cCount := atomic.LoadUint64(&l.metricCnt)
 		rCount := atomic.LoadUint64(&l.rowCnt)
 
@@ -333,16 +460,58 @@ func (l *CommonBenchmarkRunner) report(period time.Duration) {
 		took := now.Sub(prevTime)
 		colrate := float64(cCount-prevColCount) / float64(took.Seconds())
 		overallColRate := float64(cCount) / float64(sinceStart.Seconds())
+		r := &reporter.Report{Time: now, Subsystem: reporter.SubsystemLoad}
+		r.Add("metric_rate", "", colrate)
+		r.Add("metric_total", "", float64(cCount))
+		r.Add("overall_metric_rate", "", overallColRate)
+		r.Add("failed_metric_total", "", float64(atomic.LoadUint64(&l.failedMetricCnt)))
+		r.Add("failed_row_total", "", float64(atomic.LoadUint64(&l.failedRowCnt)))
 		if rCount > 0 {
 			rowrate := float64(rCount-prevRowCount) / float64(took.Seconds())
 			overallRowRate := float64(rCount) / float64(sinceStart.Seconds())
 			printFn("%d,%0.2f,%E,%0.2f,%0.2f,%E,%0.2f\n", now.Unix(), colrate, float64(cCount), overallColRate, rowrate, float64(rCount), overallRowRate)
+			r.Add("row_rate", "", rowrate)
+			r.Add("row_total", "", float64(rCount))
+			r.Add("overall_row_rate", "", overallRowRate)
 		} else {
 			printFn("%d,%0.2f,%E,%0.2f,-,-,-\n", now.Unix(), colrate, float64(cCount), overallColRate)
 		}
+		l.sendReport(r)
 
 		prevColCount = cCount
 		prevRowCount = rCount
 		prevTime = now
 	}
 }
+
+// reportSummary sends the final totals of the benchmark to the metrics sinks
+// and closes them
+func (l *CommonBenchmarkRunner) reportSummary(end time.Time, took time.Duration) {
+	if l.reporter == nil {
+		return
+	}
+	r := &reporter.Report{Time: end, Subsystem: reporter.SubsystemLoad}
+	r.Add("metric_total", "", float64(l.metricCnt))
+	r.Add("overall_metric_rate", "", float64(l.metricCnt)/took.Seconds())
+	if l.rowCnt > 0 {
+		r.Add("row_total", "", float64(l.rowCnt))
+		r.Add("overall_row_rate", "", float64(l.rowCnt)/took.Seconds())
+	}
+	r.Add("failed_metric_total", "", float64(l.failedMetricCnt))
+	r.Add("failed_row_total", "", float64(l.failedRowCnt))
+	r.Add("duration_seconds", "", took.Seconds())
+	l.sendReport(r)
+	if err := l.reporter.Close(); err != nil {
+		log.Printf("could not close metrics reporter: %v", err)
+	}
+}
+
+// sendReport sends r to the metrics sinks, errors are logged but do not stop the benchmark
+func (l *CommonBenchmarkRunner) sendReport(r *reporter.Report) {
+	if l.reporter == nil {
+		return
+	}
+	if err := l.reporter.Report(r); err != nil {
+		log.Printf("could not send report: %v", err)
+	}
+}
@@ -336,6 +336,10 @@ func TestWork(t *testing.T) {
 		t.Errorf("TestWork: invalid metric count: got %d want %d", got, 2)
 	}
 	
+	if got, _ := br.Progress(); got != 2 {
+		t.Errorf("TestWork: invalid progress metric count: got %d want %d", got, 2)
+	}
+	
 	if !b.processors[0].closed {
 		t.Errorf("TestWork: processor 0 not closed")
 	}
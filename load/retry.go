@@ -0,0 +1,169 @@
+package load
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// RetryConfig holds the settings of the RetryPolicy of a load benchmark.
+type RetryConfig struct {
+	MaxAttempts    uint          `yaml:"retry-max-attempts" mapstructure:"retry-max-attempts" json:"retry-max-attempts"`
+	InitialBackoff time.Duration `yaml:"retry-initial-backoff" mapstructure:"retry-initial-backoff" json:"retry-initial-backoff"`
+	MaxBackoff     time.Duration `yaml:"retry-max-backoff" mapstructure:"retry-max-backoff" json:"retry-max-backoff"`
+}
+
+// AddToFlagSet adds command line flags needed by the RetryConfig to the flag set.
+func (c RetryConfig) AddToFlagSet(fs *pflag.FlagSet) {
+	c.AddToFlagSetWithPrefix("", fs)
+}
+
+// AddToFlagSetWithPrefix adds the flags of the RetryConfig to the flag set, with
+// their names prefixed by prefix.
+func (c RetryConfig) AddToFlagSetWithPrefix(prefix string, fs *pflag.FlagSet) {
+	fs.Uint(prefix+"retry-max-attempts", defaultRetryMaxAttempts, "Number of attempts to write a batch before counting it as failed (1 = no retries)")
+	fs.Duration(prefix+"retry-initial-backoff", defaultRetryInitialBackoff, "Time to wait before the first retry of a failed write, doubled on every further retry")
+	fs.Duration(prefix+"retry-max-backoff", defaultRetryMaxBackoff, "Maximum time to wait between two attempts to write a batch")
+}
+
+// RetryPolicy retries failed operations with a jittered exponential backoff:
+// the n-th retry waits a random time between half and all of
+// InitialBackoff * 2^(n-1), capped at MaxBackoff. Only errors classified as
+// retryable by IsRetryable are retried.
+//
+// A nil *RetryPolicy runs operations once.
+type RetryPolicy struct {
+	RetryConfig
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	// sleep allows for testing
+	sleep func(time.Duration)
+}
+
+// NewRetryPolicy returns the RetryPolicy configured by c.
+func NewRetryPolicy(c RetryConfig) *RetryPolicy {
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 1
+	}
+	return &RetryPolicy{
+		RetryConfig: c,
+		rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		sleep:       time.Sleep,
+	}
+}
+
+// Do runs op until it succeeds, returns an error that is not retryable or the
+// maximum number of attempts is reached. It returns the error of the last attempt.
+func (p *RetryPolicy) Do(op func() error) error {
+	err := op()
+	if p == nil {
+		return err
+	}
+	for attempt := uint(1); err != nil && attempt < p.MaxAttempts && IsRetryable(err); attempt++ {
+		p.sleep(p.backoff(attempt))
+		err = op()
+	}
+	return err
+}
+
+// backoff returns the time to wait before the retry-th retry
+func (p *RetryPolicy) backoff(retry uint) time.Duration {
+	d := p.InitialBackoff
+	for i := uint(1); i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	p.mu.Lock()
+	jitter := time.Duration(p.rand.Int63n(int64(d)/2 + 1))
+	p.mu.Unlock()
+	return d/2 + jitter
+}
+
+// RetryingProcessor is a targets.Processor that retries failed writes. The
+// loader sets its RetryPolicy before calling Init.
+type RetryingProcessor interface {
+	targets.Processor
+	SetRetryPolicy(p *RetryPolicy)
+}
+
+// retryableError marks an error as retryable
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// permanentError marks an error as not retryable
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Retryable marks err as retryable, e.g. a write rejected because the database is overloaded.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+// Permanent marks err as not retryable, e.g. a write rejected because of invalid data.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsRetryable tells whether an operation that failed with err may succeed if
+// tried again. Errors marked with Retryable or Permanent are classified as
+// marked, network errors and closed connections are retryable and all other
+// errors are not.
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	var retryable *retryableError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &permanent):
+		return false
+	case errors.As(err, &retryable):
+		return true
+	case errors.As(err, &netErr):
+		return true
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.EPIPE):
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryableHTTPStatus tells whether a write rejected with an HTTP status code
+// may succeed if tried again: on 429 (Too Many Requests) and on server errors.
+func IsRetryableHTTPStatus(statusCode int) bool {
+	return statusCode == 429 || statusCode >= 500
+}
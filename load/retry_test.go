@@ -0,0 +1,155 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+func testRetryPolicy(maxAttempts uint, sleeps *[]time.Duration) *RetryPolicy {
+	p := NewRetryPolicy(RetryConfig{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	})
+	p.sleep = func(d time.Duration) {
+		*sleeps = append(*sleeps, d)
+	}
+	return p
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	errRetryable := Retryable(errors.New("overloaded"))
+	errInvalid := errors.New("invalid data")
+	cases := []struct {
+		desc         string
+		maxAttempts  uint
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{desc: "success", maxAttempts: 3, errs: []error{nil}, wantAttempts: 1},
+		{desc: "success after retry", maxAttempts: 3, errs: []error{errRetryable, nil}, wantAttempts: 2},
+		{desc: "attempts exhausted", maxAttempts: 3, errs: []error{errRetryable, errRetryable, errRetryable}, wantAttempts: 3, wantErr: errRetryable},
+		{desc: "not retryable", maxAttempts: 3, errs: []error{errInvalid}, wantAttempts: 1, wantErr: errInvalid},
+		{desc: "no retries", maxAttempts: 1, errs: []error{errRetryable}, wantAttempts: 1, wantErr: errRetryable},
+		{desc: "zero attempts", maxAttempts: 0, errs: []error{errRetryable}, wantAttempts: 1, wantErr: errRetryable},
+	}
+	for _, c := range cases {
+		var sleeps []time.Duration
+		p := testRetryPolicy(c.maxAttempts, &sleeps)
+		attempts := 0
+		err := p.Do(func() error {
+			attempts++
+			return c.errs[attempts-1]
+		})
+		if err != c.wantErr {
+			t.Errorf("%s: wrong error: got %v want %v", c.desc, err, c.wantErr)
+		}
+		if attempts != c.wantAttempts {
+			t.Errorf("%s: wrong number of attempts: got %d want %d", c.desc, attempts, c.wantAttempts)
+		}
+		if len(sleeps) != attempts-1 {
+			t.Errorf("%s: wrong number of backoffs: got %d want %d", c.desc, len(sleeps), attempts-1)
+		}
+	}
+}
+
+func TestRetryPolicyDoNil(t *testing.T) {
+	var p *RetryPolicy
+	attempts := 0
+	err := p.Do(func() error {
+		attempts++
+		return Retryable(errors.New("overloaded"))
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("nil policy should run once: got %d attempts, error %v", attempts, err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	var sleeps []time.Duration
+	p := testRetryPolicy(10, &sleeps)
+	// 100ms, 200ms, 400ms, 800ms, then capped at 1s
+	wantMax := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, max := range wantMax {
+		max *= time.Millisecond
+		for j := 0; j < 100; j++ {
+			d := p.backoff(uint(i + 1))
+			if d < max/2 || d > max {
+				t.Fatalf("backoff of retry %d out of bounds: got %v want between %v and %v", i+1, d, max/2, max)
+			}
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{desc: "nil", err: nil, want: false},
+		{desc: "plain error", err: errors.New("invalid data"), want: false},
+		{desc: "marked retryable", err: Retryable(errors.New("overloaded")), want: true},
+		{desc: "wrapped retryable", err: fmt.Errorf("batch 1: %w", Retryable(errors.New("overloaded"))), want: true},
+		{desc: "permanent network error", err: Permanent(&net.OpError{Op: "dial", Err: errors.New("refused")}), want: false},
+		{desc: "network error", err: &net.OpError{Op: "dial", Err: errors.New("refused")}, want: true},
+		{desc: "closed connection", err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), want: true},
+	}
+	for _, c := range cases {
+		if got := IsRetryable(c.err); got != c.want {
+			t.Errorf("%s: got %v want %v", c.desc, got, c.want)
+		}
+	}
+	if !IsRetryableHTTPStatus(503) || !IsRetryableHTTPStatus(429) || IsRetryableHTTPStatus(400) {
+		t.Errorf("wrong classification of HTTP status codes")
+	}
+}
+
+type testFailingProcessor struct {
+	testProcessor
+	policy *RetryPolicy
+}
+
+func (p *testFailingProcessor) SetRetryPolicy(policy *RetryPolicy) {
+	p.policy = policy
+}
+
+func (p *testFailingProcessor) ProcessBatchWithFailures(targets.Batch, bool) (uint64, uint64, uint64, uint64) {
+	return 1, 1, 2, 3
+}
+
+type testFailingBenchmark struct {
+	testBenchmark
+	proc *testFailingProcessor
+}
+
+func (b *testFailingBenchmark) GetProcessor() targets.Processor {
+	return b.proc
+}
+
+func TestProcessBatchWithFailures(t *testing.T) {
+	br := &CommonBenchmarkRunner{retryPolicy: NewRetryPolicy(RetryConfig{MaxAttempts: 2})}
+	p := &testFailingProcessor{}
+	proc := br.initProcessor(&testFailingBenchmark{proc: p}, 3)
+	if p.policy != br.retryPolicy {
+		t.Errorf("retry policy not set on the processor")
+	}
+	if p.worker != 3 {
+		t.Errorf("processor has wrong worker id: got %d want %d", p.worker, 3)
+	}
+	br.processBatch(proc, &testBatch{})
+	br.processBatch(proc, &testBatch{})
+	if br.metricCnt != 2 || br.rowCnt != 2 {
+		t.Errorf("wrong loaded counts: got %d metrics and %d rows", br.metricCnt, br.rowCnt)
+	}
+	if br.failedMetricCnt != 4 || br.failedRowCnt != 6 {
+		t.Errorf("wrong failed counts: got %d metrics and %d rows", br.failedMetricCnt, br.failedRowCnt)
+	}
+}
@@ -1,6 +1,8 @@
 package load
 
 import (
+	"time"
+	
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 )
 
@@ -10,9 +12,10 @@ import (
 // readDs does no flow control, if the capacity of a channel is reached, scanning stops for all
 // workers. (should only happen if channel-capacity is low and one worker is unreasonable slower than the rest)
 // in that case just set hash-workers to false and use 1 channel for all workers.
+// With a flushInterval, the partially filled batches are also sent every flushInterval.
 func scanWithoutFlowControl(
 	ds targets.DataSource, indexer targets.PointIndexer, factory targets.BatchFactory, channels []chan targets.Batch,
-	batchSize uint, limit uint64,
+	batchSize uint, limit uint64, flushInterval time.Duration,
 ) uint64 {
 	if batchSize == 0 {
 		panic("batch size can't be 0")
@@ -22,12 +25,20 @@ func scanWithoutFlowControl(
 	for i := 0; i < numChannels; i++ {
 		batches[i] = factory.New()
 	}
+	flusher := newBatchFlusher(flushInterval, func() {
+		for idx, b := range batches {
+			if b.Len() > 0 {
+				channels[idx] <- b
+				batches[idx] = factory.New()
+			}
+		}
+	})
 	var itemsRead uint64
 	for {
 		if limit > 0 && itemsRead >= limit {
 			break
 		}
-		item := ds.NextItem()
+		item := flusher.nextItem(ds)
 		if item.Data == nil {
 			// Nothing to scan any more - input is empty or failed
 			// Time to exit
@@ -43,6 +54,7 @@ func scanWithoutFlowControl(
 			batches[idx] = factory.New()
 		}
 	}
+	flusher.close()
 	
 	for idx, unfilledBatch := range batches {
 		if unfilledBatch.Len() > 0 {
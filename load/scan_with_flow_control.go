@@ -2,6 +2,7 @@ package load
 
 import (
 	"reflect"
+	"time"
 	
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 )
@@ -42,8 +43,10 @@ func sendOrQueueBatch(ch *duplexChannel, count *int, batch targets.Batch, unsent
 // which are then dispatched to workers (duplexChannel chosen by PointIndexer).
 // Scan does flow control to make sure workers are not left idle for too long
 // and also that the scanning process does not starve them of CPU.
+// With a flushInterval, the partially filled batches are also sent every flushInterval,
+// in case the DataSource blocks.
 func scanWithFlowControl(
-	channels []*duplexChannel, batchSize uint, limit uint64,
+	channels []*duplexChannel, batchSize uint, limit uint64, flushInterval time.Duration,
 	ds targets.DataSource, factory targets.BatchFactory, indexer targets.PointIndexer,
 ) uint64 {
 	var itemsRead uint64
@@ -92,6 +95,24 @@ func scanWithFlowControl(
 	// so we don't go over a limit (olimit), in order to slow down the scanner so it doesn't starve the workers
 	ocnt := 0
 	olimit := numChannels * cap(channels[0].toWorker) * 3
+	
+	// The flusher handles the acknowledgements which arrived while the DataSource
+	// blocks, then sends the partial batches, replacing them with new empty ones
+	flusher := newBatchFlusher(flushInterval, func() {
+		for {
+			chosen, _, ok := reflect.Select(cases)
+			if !ok {
+				break
+			}
+			unsentBatches[chosen] = ackAndMaybeSend(channels[chosen], &ocnt, unsentBatches[chosen])
+		}
+		for idx, b := range fillingBatches {
+			if b.Len() > 0 {
+				unsentBatches[idx] = sendOrQueueBatch(channels[idx], &ocnt, b, unsentBatches[idx])
+				fillingBatches[idx] = factory.New()
+			}
+		}
+	})
 	for {
 		
 		// Check whether incoming items limit reached.
@@ -113,7 +134,7 @@ func scanWithFlowControl(
 		}
 		
 		// Prepare new batch - decode new item and append it to batch
-		item := ds.NextItem()
+		item := flusher.nextItem(ds)
 		if item.Data == nil {
 			// Nothing to scan any more - input is empty or failed
 			// Time to exit
@@ -134,6 +155,8 @@ func scanWithFlowControl(
 		}
 	}
 	
+	flusher.close()
+	
 	// Finished reading input - no more items to come
 	// Make sure last batch goes out - it may be smaller than batchSize requested - there is not more items
 	for idx, b := range fillingBatches {
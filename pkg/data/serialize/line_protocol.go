@@ -0,0 +1,83 @@
+package serialize
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SplitLineProtocol splits a line of line protocol at the sep bytes that are
+// neither escaped with a backslash nor within a string field value.
+func SplitLineProtocol(s []byte, sep byte) [][]byte {
+	var parts [][]byte
+	start := 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// UnescapeLineProtocol removes the backslashes escaping the commas, spaces
+// and equal signs of a measurement, a tag key or value, or a field key. Other
+// backslashes are kept.
+func UnescapeLineProtocol(s []byte) string {
+	return unescapeBefore(s, ", =")
+}
+
+// SplitLineProtocolKeyValue splits a tag or field at its first unescaped '='
+// and returns its unescaped key and its raw value.
+func SplitLineProtocolKeyValue(kv []byte) (string, []byte, error) {
+	parts := SplitLineProtocol(kv, '=')
+	if len(parts) < 2 || len(parts[0]) == 0 {
+		return "", nil, fmt.Errorf("invalid key=value pair '%s'", kv)
+	}
+	return UnescapeLineProtocol(parts[0]), kv[len(parts[0])+1:], nil
+}
+
+// ParseLineProtocolFieldValue returns the value of a field of line protocol:
+// a bool, a string, an int64 for an 'i' suffix, a uint64 for a 'u' suffix,
+// or else a float64.
+func ParseLineProtocolFieldValue(s []byte) (interface{}, error) {
+	switch string(s) {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	n := len(s)
+	switch {
+	case n >= 2 && s[0] == '"' && s[n-1] == '"':
+		return unescapeBefore(s[1:n-1], `"\`), nil
+	case n > 0 && s[n-1] == 'i':
+		return strconv.ParseInt(string(s[:n-1]), 10, 64)
+	case n > 0 && s[n-1] == 'u':
+		return strconv.ParseUint(string(s[:n-1]), 10, 64)
+	default:
+		return strconv.ParseFloat(string(s), 64)
+	}
+}
+
+// unescapeBefore removes the backslashes escaping one of the specials.
+func unescapeBefore(s []byte, specials string) string {
+	if bytes.IndexByte(s, '\\') < 0 {
+		return string(s)
+	}
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(specials, s[i+1]) >= 0 {
+			i++
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
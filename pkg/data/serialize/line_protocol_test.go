@@ -0,0 +1,95 @@
+package serialize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitLineProtocol(t *testing.T) {
+	cases := []struct {
+		desc  string
+		input string
+		sep   byte
+		want  []string
+	}{
+		{
+			desc:  "sections",
+			input: `cpu,host=a usage=1.5 1640995200000000000`,
+			sep:   ' ',
+			want:  []string{"cpu,host=a", "usage=1.5", "1640995200000000000"},
+		},
+		{
+			desc:  "escaped separators",
+			input: `cpu,zone=z\ 1,rack=a\,b usage=1`,
+			sep:   ',',
+			want:  []string{"cpu", `zone=z\ 1`, `rack=a\,b usage=1`},
+		},
+		{
+			desc:  "separators of string fields",
+			input: `msg="hi, \"there\"",count=3i`,
+			sep:   ',',
+			want:  []string{`msg="hi, \"there\""`, "count=3i"},
+		},
+	}
+	for _, c := range cases {
+		var got []string
+		for _, part := range SplitLineProtocol([]byte(c.input), c.sep) {
+			got = append(got, string(part))
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %q want %q", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestUnescapeLineProtocol(t *testing.T) {
+	if got, want := UnescapeLineProtocol([]byte(`z\ 1\,2\=3\x`)), `z 1,2=3\x`; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestSplitLineProtocolKeyValue(t *testing.T) {
+	key, value, err := SplitLineProtocolKeyValue([]byte(`a\=b=c=d`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != "a=b" || string(value) != "c=d" {
+		t.Errorf("got %q, %q", key, value)
+	}
+	for _, kv := range []string{"host", "=a"} {
+		if _, _, err := SplitLineProtocolKeyValue([]byte(kv)); err == nil {
+			t.Errorf("no error for '%s'", kv)
+		}
+	}
+}
+
+func TestParseLineProtocolFieldValue(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    interface{}
+		wantErr bool
+	}{
+		{input: "1.5", want: 1.5},
+		{input: "3i", want: int64(3)},
+		{input: "4u", want: uint64(4)},
+		{input: "t", want: true},
+		{input: "FALSE", want: false},
+		{input: `"a \"b\" \\c"`, want: `a "b" \c`},
+		{input: "x", wantErr: true},
+		{input: "1.5i", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := ParseLineProtocolFieldValue([]byte(c.input))
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: no error", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.input, err)
+		} else if got != c.want {
+			t.Errorf("%s: got %#v want %#v", c.input, got, c.want)
+		}
+	}
+}
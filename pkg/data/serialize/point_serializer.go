@@ -3,9 +3,25 @@ package serialize
 import (
 	"github.com/cnosdb/tsdb-comparisons/pkg/data"
 	"io"
+	"time"
 )
 
 // PointSerializer serializes a Point for writing
 type PointSerializer interface {
 	Serialize(p *data.Point, w io.Writer) error
 }
+
+// TimestampPrecisionSetter is implemented by PointSerializers that can write
+// the timestamps in a unit other than nanoseconds.
+type TimestampPrecisionSetter interface {
+	SetTimestampPrecision(unit time.Duration)
+}
+
+// WithTimestampPrecision sets the unit the timestamps are written in, when s
+// supports it, and returns s.
+func WithTimestampPrecision(s PointSerializer, unit time.Duration) PointSerializer {
+	if setter, ok := s.(TimestampPrecisionSetter); ok {
+		setter.SetTimestampPrecision(unit)
+	}
+	return s
+}
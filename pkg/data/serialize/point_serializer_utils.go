@@ -16,12 +16,18 @@ var (
 	TestColFloat    = []byte("usage_guest_nice")
 	TestColInt      = []byte("usage_guest")
 	TestColInt64    = []byte("big_usage_guest")
+	TestColUint64   = []byte("bytes_sent")
+	TestColBool     = []byte("online")
+	TestColString   = []byte("state")
 )
 
 const (
 	TestFloat             = float64(38.24311829)
 	TestInt               = 38
 	TestInt64             = int64(5000000000)
+	TestUint64            = uint64(18446744073709551615)
+	TestBool              = true
+	TestString            = "running"
 	ErrWriterAlwaysErr    = "bad write: I always error"
 	ErrWriterSometimesErr = "bad write: I sometimes error"
 )
@@ -71,6 +77,19 @@ func TestPointInt() *data.Point {
 		[][]byte{TestColInt}, []interface{}{TestInt})
 }
 
+func TestPointTyped() *data.Point {
+	return generateTestPoint(TestMeasurement, TestTagKeys, TestTagVals,
+		&TestNow, [][]byte{TestColUint64, TestColBool, TestColString, TestColFloat},
+		[]interface{}{TestUint64, TestBool, TestString, TestFloat})
+}
+
+// TestPointEscaped is a point whose measurement, tags and field keys contain
+// characters that are special in line protocol.
+func TestPointEscaped() *data.Point {
+	return generateTestPoint([]byte("cpu load"), [][]byte{[]byte("host name")}, []interface{}{"host,0=a"}, &TestNow,
+		[][]byte{[]byte("usage=guest")}, []interface{}{TestFloat})
+}
+
 func TestPointNoTags() *data.Point {
 	return generateTestPoint(TestMeasurement, [][]byte{}, []interface{}{}, &TestNow,
 		[][]byte{TestColFloat}, []interface{}{TestFloat})
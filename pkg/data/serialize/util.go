@@ -3,8 +3,19 @@ package serialize
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// UnixIn returns t as the number of units elapsed since the Unix epoch,
+// truncated. A unit of 0 means nanoseconds.
+func UnixIn(t time.Time, unit time.Duration) int64 {
+	if unit <= time.Nanosecond {
+		return t.UnixNano()
+	}
+	return t.UnixNano() / int64(unit)
+}
+
 // Utility function for appending various data types to a byte string
 func FastFormatAppend(v interface{}, buf []byte) []byte {
 	switch v.(type) {
@@ -12,6 +23,10 @@ func FastFormatAppend(v interface{}, buf []byte) []byte {
 		return strconv.AppendInt(buf, int64(v.(int)), 10)
 	case int64:
 		return strconv.AppendInt(buf, v.(int64), 10)
+	case uint:
+		return strconv.AppendUint(buf, uint64(v.(uint)), 10)
+	case uint64:
+		return strconv.AppendUint(buf, v.(uint64), 10)
 	case float64:
 		// Why -1 ?
 		// From Golang source on genericFtoa (called by AppendFloat): 'Negative precision means "only as much as needed to be exact."'
@@ -35,3 +50,63 @@ func FastFormatAppend(v interface{}, buf []byte) []byte {
 		panic(fmt.Sprintf("unknown field type for %#v", v))
 	}
 }
+
+// CSVFormatAppend appends v like FastFormatAppend for the CSV formats of
+// TimescaleDB, TDengine, IoTDB and ClickHouse: the backslashes, commas and
+// newlines of string values are escaped with a backslash, so that they do not
+// split the line. SplitCSV reads the values back.
+func CSVFormatAppend(v interface{}, buf []byte) []byte {
+	switch v := v.(type) {
+	case []byte:
+		return appendCSVEscaped(buf, string(v))
+	case string:
+		return appendCSVEscaped(buf, v)
+	default:
+		return FastFormatAppend(v, buf)
+	}
+}
+
+func appendCSVEscaped(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\', ',':
+			buf = append(buf, '\\', c)
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+// SplitCSV splits a line written with CSVFormatAppend at the unescaped commas
+// and unescapes the values. Like strings.SplitN, it returns at most n values
+// when n > 0, the last one being the rest of the line, left escaped.
+func SplitCSV(s string, n int) []string {
+	if n == 0 || n == 1 || strings.IndexByte(s, '\\') < 0 {
+		return strings.SplitN(s, ",", n)
+	}
+	values := make([]string, 0, strings.Count(s, ",")+1)
+	value := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			if s[i] == 'n' {
+				value = append(value, '\n')
+			} else {
+				value = append(value, s[i])
+			}
+		case c == ',':
+			values = append(values, string(value))
+			value = value[:0]
+			if n > 0 && len(values) == n-1 {
+				return append(values, s[i+1:])
+			}
+		default:
+			value = append(value, c)
+		}
+	}
+	return append(values, string(value))
+}
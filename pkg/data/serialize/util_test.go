@@ -1,7 +1,9 @@
 package serialize
 
 import (
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestFastFormatAppend(t *testing.T) {
@@ -40,6 +42,13 @@ func TestFastFormatAppend(t *testing.T) {
 			output:      []byte("values,5000000000"),
 			shouldPanic: false,
 		},
+		{
+			desc:        "fastFormatAppend should properly append a uint64 to a given byte string",
+			inputString: []byte("values,"),
+			input:       uint64(18446744073709551615),
+			output:      []byte("values,18446744073709551615"),
+			shouldPanic: false,
+		},
 		{
 			desc:        "fastFormatAppend should properly append a byte string to a given byte string",
 			inputString: []byte("values,"),
@@ -90,3 +99,66 @@ func TestFastFormatAppend(t *testing.T) {
 		}
 	}
 }
+
+func TestUnixIn(t *testing.T) {
+	ts := time.Date(2016, 1, 1, 0, 0, 1, 234567891, time.UTC)
+	cases := []struct {
+		unit time.Duration
+		want int64
+	}{
+		{unit: 0, want: 1451606401234567891},
+		{unit: time.Nanosecond, want: 1451606401234567891},
+		{unit: time.Microsecond, want: 1451606401234567},
+		{unit: time.Millisecond, want: 1451606401234},
+		{unit: time.Second, want: 1451606401},
+	}
+	for _, c := range cases {
+		if got := UnixIn(ts, c.unit); got != c.want {
+			t.Errorf("unit %v: got %d want %d", c.unit, got, c.want)
+		}
+	}
+}
+
+func TestCSVFormatAppend(t *testing.T) {
+	cases := []struct {
+		desc   string
+		input  interface{}
+		output string
+	}{
+		{desc: "numbers are not escaped", input: float64(29.37), output: "values,29.37"},
+		{desc: "plain strings are not escaped", input: "idle", output: "values,idle"},
+		{desc: "commas are escaped", input: "a,b", output: `values,a\,b`},
+		{desc: "quotes are kept", input: []byte("it's"), output: "values,it's"},
+		{desc: "backslashes and newlines are escaped", input: "a\\b\nc", output: `values,a\\b\nc`},
+	}
+	for _, c := range cases {
+		got := CSVFormatAppend(c.input, []byte("values,"))
+		if string(got) != c.output {
+			t.Errorf("%s: got %s want %s", c.desc, got, c.output)
+		}
+	}
+}
+
+func TestSplitCSV(t *testing.T) {
+	value := "a,b'c\\d\ne"
+	line := string(CSVFormatAppend(value, []byte("1640995200000000000,")))
+	line = string(CSVFormatAppend(1.5, append([]byte(line), ',')))
+
+	got := SplitCSV(line, -1)
+	want := []string{"1640995200000000000", value, "1.5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	got = SplitCSV(line, 2)
+	want = []string{"1640995200000000000", line[len("1640995200000000000,"):]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("with n = 2: got %q want %q", got, want)
+	}
+
+	got = SplitCSV("tags,hostname=host_0", -1)
+	want = []string{"tags", "hostname=host_0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without escapes: got %q want %q", got, want)
+	}
+}
@@ -7,10 +7,14 @@ import (
 const (
 	FileDataSourceType      = "FILE"
 	SimulatorDataSourceType = "SIMULATOR"
+	// DatasetDataSourceType reads the points of an external CSV or line
+	// protocol dataset, configured by the dataset options of the Simulator
+	// config.
+	DatasetDataSourceType = "DATASET"
 )
 
 var (
-	ValidDataSourceTypes = []string{FileDataSourceType, SimulatorDataSourceType}
+	ValidDataSourceTypes = []string{FileDataSourceType, SimulatorDataSourceType, DatasetDataSourceType}
 )
 
 type DataSourceConfig struct {
@@ -12,6 +12,16 @@ const (
 	errInvalidGroupsFmt = "incorrect interleaved groups configuration: id %d >= total groups %d"
 	errTotalGroupsZero  = "incorrect interleaved groups configuration: total groups = 0"
 	errLogIntervalZero  = "cannot have log interval of 0"
+	errMaxMetricCount   = "max metric count per host has to be greater than 0"
+	errNoCustomSchema   = "custom use case needs a schema file, see --custom-schema"
+	errNoDataset        = "dataset use case needs a dataset file, see --dataset"
+	errChurnRate        = "churn rate has to be between 0 and 1"
+	errBadQualityFmt    = "invalid quality profile specified: '%v'"
+	errMaxLateness      = "max lateness cannot be negative"
+	errBadPatternFmt    = "invalid pattern specified: '%v'"
+	errRealTimeDuration = "real-time duration cannot be negative"
+	errBadPrecisionFmt  = "invalid timestamp precision specified: '%v'"
+	errTimestampJitter  = "timestamp jitter cannot be negative"
 )
 
 func TestDataGeneratorConfigValidate(t *testing.T) {
@@ -70,6 +80,190 @@ func TestDataGeneratorConfigValidate(t *testing.T) {
 	}
 	c.LogInterval = time.Second
 	
+	// Test MaxMetricCountPerHost validation
+	c.Use = common.UseCaseDevopsGeneric
+	c.MaxMetricCountPerHost = 0
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for 0 max metric count")
+	} else if got := err.Error(); got != errMaxMetricCount {
+		t.Errorf("incorrect error for 0 max metric count: got\n%s\nwant\n%s", got, errMaxMetricCount)
+	}
+	c.MaxMetricCountPerHost = 100
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for max metric count of 100: %v", err)
+	}
+	
+	// Test CustomSchema validation
+	c.Use = common.UseCaseCustom
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for custom use case without schema")
+	} else if got := err.Error(); got != errNoCustomSchema {
+		t.Errorf("incorrect error for custom use case without schema: got\n%s\nwant\n%s", got, errNoCustomSchema)
+	}
+	c.CustomSchema = "schema.yaml"
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for custom use case with schema: %v", err)
+	}
+	
+	// Test dataset validation
+	c.Use = common.UseCaseDataset
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for dataset use case without dataset")
+	} else if got := err.Error(); got != errNoDataset {
+		t.Errorf("incorrect error for dataset use case without dataset: got\n%s\nwant\n%s", got, errNoDataset)
+	}
+	c.Dataset = "export.csv"
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for dataset use case with dataset: %v", err)
+	}
+	c.Use = common.UseCaseIoT
+	
+	// Test ChurnRate validation
+	for _, rate := range []float64{-0.1, 1.5} {
+		c.ChurnRate = rate
+		err = c.Validate()
+		if err == nil {
+			t.Errorf("unexpected lack of error for churn rate %v", rate)
+		} else if got := err.Error(); got != errChurnRate {
+			t.Errorf("incorrect error for churn rate %v: got\n%s\nwant\n%s", rate, got, errChurnRate)
+		}
+	}
+	c.ChurnRate = 0.5
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for churn rate of 0.5: %v", err)
+	}
+	c.ChurnRate = 0
+	
+	// Test Quality validation
+	c.Quality = ""
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for empty quality profile: %v", err)
+	} else if got := c.Quality; got != common.QualityDefault {
+		t.Errorf("incorrect quality profile for empty one: got %s want %s", got, common.QualityDefault)
+	}
+	c.Quality = "bad"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for bad quality profile")
+	} else if got, want := err.Error(), fmt.Sprintf(errBadQualityFmt, "bad"); got != want {
+		t.Errorf("incorrect error for bad quality profile: got\n%s\nwant\n%s", got, want)
+	}
+	c.Quality = common.QualityChaotic
+	c.MaxLateness = -time.Second
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for negative max lateness")
+	} else if got := err.Error(); got != errMaxLateness {
+		t.Errorf("incorrect error for negative max lateness: got\n%s\nwant\n%s", got, errMaxLateness)
+	}
+	c.MaxLateness = time.Minute
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for max lateness of 1m: %v", err)
+	}
+	if got := c.QualityProfile().MaxLateness; got != time.Minute {
+		t.Errorf("incorrect max lateness of the quality profile: got %v want %v", got, time.Minute)
+	}
+	c.MaxLateness = 0
+	if got, want := c.QualityProfile().MaxLateness, common.QualityProfiles[common.QualityChaotic].MaxLateness; got != want {
+		t.Errorf("incorrect max lateness of the chaotic profile: got %v want %v", got, want)
+	}
+	
+	// Test QualityOverrides validation and that they replace the profile's values
+	tooLikely, missing, unset, batchSize := 1.5, 0.0, -1.0, 5
+	c.ZeroFieldChance = &tooLikely
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for chance greater than 1")
+	} else if got, want := err.Error(), "quality-zero-field-chance cannot be greater than 1: 1.5"; got != want {
+		t.Errorf("incorrect error for chance greater than 1: got\n%s\nwant\n%s", got, want)
+	}
+	c.ZeroFieldChance = &unset
+	c.EntryMissingChance = &missing
+	c.BatchSize = &batchSize
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for quality overrides: %v", err)
+	}
+	want := common.QualityProfiles[common.QualityChaotic]
+	want.EntryMissingChance = 0
+	want.BatchSize = 5
+	if got := c.QualityProfile(); got != want {
+		t.Errorf("incorrect quality profile with overrides: got\n%+v\nwant\n%+v", got, want)
+	}
+	c.QualityOverrides = common.QualityOverrides{}
+	c.Quality = common.QualityDefault
+	
+	// Test Patterns validation
+	c.Patterns = []string{common.PatternSeasonal, "noise"}
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for bad pattern")
+	} else if got, want := err.Error(), fmt.Sprintf(errBadPatternFmt, "noise"); got != want {
+		t.Errorf("incorrect error for bad pattern: got\n%s\nwant\n%s", got, want)
+	}
+	c.Patterns = common.PatternChoices
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for all patterns: %v", err)
+	}
+	c.Patterns = nil
+	
+	// Test real-time duration validation
+	c.RealTime = true
+	c.RealTimeDuration = -time.Minute
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for negative real-time duration")
+	} else if got := err.Error(); got != errRealTimeDuration {
+		t.Errorf("incorrect error for negative real-time duration: got\n%s\nwant\n%s", got, errRealTimeDuration)
+	}
+	c.RealTimeDuration = 0
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for real-time mode without duration: %v", err)
+	}
+	c.RealTime = false
+	
+	// Test timestamp precision and jitter validation
+	c.TimestampPrecision = ""
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for empty timestamp precision: %v", err)
+	} else if got := c.TimestampPrecision; got != common.PrecisionNanoseconds {
+		t.Errorf("incorrect default timestamp precision: got %s want %s", got, common.PrecisionNanoseconds)
+	}
+	c.TimestampPrecision = "m"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for bad timestamp precision")
+	} else if got, want := err.Error(), fmt.Sprintf(errBadPrecisionFmt, "m"); got != want {
+		t.Errorf("incorrect error for bad timestamp precision: got\n%s\nwant\n%s", got, want)
+	}
+	c.TimestampPrecision = common.PrecisionMilliseconds
+	c.TimestampJitter = -time.Second
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for negative timestamp jitter")
+	} else if got := err.Error(); got != errTimestampJitter {
+		t.Errorf("incorrect error for negative timestamp jitter: got\n%s\nwant\n%s", got, errTimestampJitter)
+	}
+	c.TimestampJitter = time.Second
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for timestamp jitter of 1s: %v", err)
+	}
+	c.TimestampPrecision = common.PrecisionNanoseconds
+	c.TimestampJitter = 0
+	
 	// Test groups validation
 	c.InterleavedNumGroups = 0
 	err = c.Validate()
@@ -19,9 +19,34 @@ func RandomInt64SliceChoice(s []int64) int64 {
 
 const (
 	// Use case choices (make sure to update TestGetConfig if adding a new one)
-	UseCaseIoT = "iot"
+	UseCaseIoT           = "iot"
+	UseCaseDevops        = "devops"
+	UseCaseCPUOnly       = "cpu-only"
+	UseCaseDevopsGeneric = "devops-generic"
+	UseCaseCustom        = "custom"
+	UseCaseDataset       = "dataset"
 )
 
 var UseCaseChoices = []string{
 	UseCaseIoT,
+	UseCaseDevops,
+	UseCaseCPUOnly,
+	UseCaseDevopsGeneric,
+	UseCaseCustom,
+	UseCaseDataset,
+}
+
+const (
+	// Value pattern choices, added to the simulated values on demand
+	PatternSeasonal  = "seasonal"
+	PatternSpikes    = "spikes"
+	PatternSteps     = "steps"
+	PatternAnomalies = "anomalies"
+)
+
+var PatternChoices = []string{
+	PatternSeasonal,
+	PatternSpikes,
+	PatternSteps,
+	PatternAnomalies,
 }
@@ -3,6 +3,7 @@ package common
 import (
 	"math"
 	"math/rand"
+	"time"
 )
 
 // Distribution provides an interface to model a statistical distribution.
@@ -11,6 +12,21 @@ type Distribution interface {
 	Get() float64 // should be idempotent
 }
 
+// TimedDistribution is a Distribution whose value also depends on the
+// simulated time, e.g. on the time of day. SubsystemMeasurement sets the time
+// before every Advance; distributions wrapping others pass it on.
+type TimedDistribution interface {
+	Distribution
+	SetTime(t time.Time)
+}
+
+// SetDistributionTime sets the simulated time of d, if it depends on it.
+func SetDistributionTime(d Distribution, t time.Time) {
+	if td, ok := d.(TimedDistribution); ok {
+		td.SetTime(t)
+	}
+}
+
 // NormalDistribution models a normal distribution (stateless).
 type NormalDistribution struct {
 	Mean   float64
@@ -187,6 +203,11 @@ func (f *FloatPrecision) Advance() {
 	f.step.Advance()
 }
 
+// SetTime passes the simulated time to the underlying distribution.
+func (f *FloatPrecision) SetTime(t time.Time) {
+	SetDistributionTime(f.step, t)
+}
+
 // Get returns the value from the underlying distribution with adjusted float value precision.
 func (f *FloatPrecision) Get() float64 {
 	return float64(int(f.step.Get()*f.precision)) / f.precision
@@ -235,7 +256,222 @@ func (d *LazyDistribution) Advance() {
 	d.step.Advance()
 }
 
+// SetTime passes the simulated time to the underlying distributions.
+func (d *LazyDistribution) SetTime(t time.Time) {
+	SetDistributionTime(d.motive, t)
+	SetDistributionTime(d.step, t)
+}
+
 // Get returns the last computed value for this distribution.
 func (d *LazyDistribution) Get() float64 {
 	return d.step.Get()
 }
+
+// SeasonalDistribution scales an underlying distribution by a sinusoidal
+// cycle of the simulated time, e.g. a daily cycle for a Period of 24h. The
+// value swings between 1-Amplitude and 1+Amplitude times the underlying one,
+// peaking at Phase into every Period.
+type SeasonalDistribution struct {
+	Base      Distribution
+	Amplitude float64
+	Period    time.Duration
+	Phase     time.Duration
+
+	now time.Time
+}
+
+// SD creates a new SeasonalDistribution over base with the given relative
+// amplitude, period and phase of the peak.
+func SD(base Distribution, amplitude float64, period, phase time.Duration) *SeasonalDistribution {
+	return &SeasonalDistribution{
+		Base:      base,
+		Amplitude: amplitude,
+		Period:    period,
+		Phase:     phase,
+	}
+}
+
+// Advance advances the underlying distribution.
+func (d *SeasonalDistribution) Advance() {
+	d.Base.Advance()
+}
+
+// SetTime sets the simulated time the cycle is computed for.
+func (d *SeasonalDistribution) SetTime(t time.Time) {
+	d.now = t
+	SetDistributionTime(d.Base, t)
+}
+
+// Get returns the underlying value scaled by the cycle at the simulated time.
+func (d *SeasonalDistribution) Get() float64 {
+	if d.Period <= 0 {
+		return d.Base.Get()
+	}
+	offset := (d.now.UnixNano() - int64(d.Phase)) % int64(d.Period)
+	angle := 2 * math.Pi * float64(offset) / float64(d.Period)
+	return d.Base.Get() * (1 + d.Amplitude*math.Cos(angle))
+}
+
+// SpikeDistribution adds spikes to an underlying distribution. The number of
+// spikes at every Advance follows a Poisson distribution of mean Rate, and
+// each spike adds a value of the Magnitude distribution for that Advance only.
+type SpikeDistribution struct {
+	Base      Distribution
+	Rate      float64
+	Magnitude Distribution
+
+	spike float64
+}
+
+// SPD creates a new SpikeDistribution over base with the given mean number of
+// spikes per Advance and distribution of their magnitude.
+func SPD(base Distribution, rate float64, magnitude Distribution) *SpikeDistribution {
+	return &SpikeDistribution{
+		Base:      base,
+		Rate:      rate,
+		Magnitude: magnitude,
+	}
+}
+
+// Advance advances the underlying distribution and draws the new spikes.
+func (d *SpikeDistribution) Advance() {
+	d.Base.Advance()
+	d.spike = 0
+	for n := poisson(d.Rate); n > 0; n-- {
+		d.Magnitude.Advance()
+		d.spike += d.Magnitude.Get()
+	}
+}
+
+// SetTime passes the simulated time to the underlying distributions.
+func (d *SpikeDistribution) SetTime(t time.Time) {
+	SetDistributionTime(d.Base, t)
+	SetDistributionTime(d.Magnitude, t)
+}
+
+// Get returns the underlying value plus the spikes of the last Advance.
+func (d *SpikeDistribution) Get() float64 {
+	return d.Base.Get() + d.spike
+}
+
+// poisson returns a random number of events of a Poisson distribution of the
+// given mean (Knuth's algorithm, fine for the small means of spikes).
+func poisson(mean float64) int {
+	if mean <= 0 {
+		return 0
+	}
+	l := math.Exp(-mean)
+	n := 0
+	for p := rand.Float64(); p > l; p *= rand.Float64() {
+		n++
+	}
+	return n
+}
+
+// StepChangeDistribution adds a piecewise constant level to an underlying
+// distribution, to model regime changes. At every Advance the level changes
+// with the given Chance to a new value of the Level distribution.
+type StepChangeDistribution struct {
+	Base   Distribution
+	Level  Distribution
+	Chance float64
+
+	level float64
+}
+
+// SCD creates a new StepChangeDistribution over base with the given
+// distribution of levels and chance of a change per Advance. The level
+// starts at 0, that is at the underlying value.
+func SCD(base, level Distribution, chance float64) *StepChangeDistribution {
+	return &StepChangeDistribution{
+		Base:   base,
+		Level:  level,
+		Chance: chance,
+	}
+}
+
+// Advance advances the underlying distribution and maybe changes the level.
+func (d *StepChangeDistribution) Advance() {
+	d.Base.Advance()
+	if rand.Float64() < d.Chance {
+		d.Level.Advance()
+		d.level = d.Level.Get()
+	}
+}
+
+// SetTime passes the simulated time to the underlying distributions.
+func (d *StepChangeDistribution) SetTime(t time.Time) {
+	SetDistributionTime(d.Base, t)
+	SetDistributionTime(d.Level, t)
+}
+
+// Get returns the underlying value plus the current level.
+func (d *StepChangeDistribution) Get() float64 {
+	return d.Base.Get() + d.level
+}
+
+// AnomalyWindow is a labelled period of simulated time during which a value
+// is replaced by Factor times itself plus Offset.
+type AnomalyWindow struct {
+	Label  string
+	Start  time.Time
+	End    time.Time
+	Factor float64
+	Offset float64
+}
+
+// Contains tells whether t is within the window.
+func (w *AnomalyWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// AnomalyDistribution alters an underlying distribution during the given
+// anomaly windows, and reports which one is active so that the anomalies can
+// be labelled in the data.
+type AnomalyDistribution struct {
+	Base    Distribution
+	Windows []AnomalyWindow
+
+	active *AnomalyWindow
+}
+
+// AD creates a new AnomalyDistribution over base with the given windows.
+func AD(base Distribution, windows []AnomalyWindow) *AnomalyDistribution {
+	return &AnomalyDistribution{
+		Base:    base,
+		Windows: windows,
+	}
+}
+
+// Advance advances the underlying distribution.
+func (d *AnomalyDistribution) Advance() {
+	d.Base.Advance()
+}
+
+// SetTime sets the simulated time the active window is looked up for.
+func (d *AnomalyDistribution) SetTime(t time.Time) {
+	d.active = nil
+	for i := range d.Windows {
+		if d.Windows[i].Contains(t) {
+			d.active = &d.Windows[i]
+			break
+		}
+	}
+	SetDistributionTime(d.Base, t)
+}
+
+// Label returns the label of the active anomaly window, or "" outside them.
+func (d *AnomalyDistribution) Label() string {
+	if d.active == nil {
+		return ""
+	}
+	return d.active.Label
+}
+
+// Get returns the underlying value, altered by the active anomaly window.
+func (d *AnomalyDistribution) Get() float64 {
+	if d.active == nil {
+		return d.Base.Get()
+	}
+	return d.Base.Get()*d.active.Factor + d.active.Offset
+}
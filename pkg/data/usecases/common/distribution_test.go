@@ -2,7 +2,9 @@ package common
 
 import (
 	"math"
+	"math/rand"
 	"testing"
+	"time"
 )
 
 type mockDistribution struct {
@@ -158,3 +160,107 @@ func TestLazyDistribution(t *testing.T) {
 		})
 	}
 }
+
+func TestSeasonalDistribution(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc   string
+		now    time.Time
+		expect float64
+	}{
+		{desc: "at the peak", now: start.Add(6 * time.Hour), expect: 15},
+		{desc: "a day after the peak", now: start.Add(30 * time.Hour), expect: 15},
+		{desc: "half a period after the peak", now: start.Add(18 * time.Hour), expect: 5},
+		{desc: "a quarter period after the peak", now: start.Add(12 * time.Hour), expect: 10},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			base := &mockDistribution{ReturnValue: 10}
+			sd := FP(SD(base, 0.5, 24*time.Hour, 6*time.Hour), 3)
+			SetDistributionTime(sd, testCase.now)
+			sd.Advance()
+			if !base.AdvanceCalled {
+				t.Errorf("advance not called on base distribution")
+			}
+			if got := sd.Get(); got != testCase.expect {
+				t.Errorf("expected: %f, got %f", testCase.expect, got)
+			}
+		})
+	}
+}
+
+func TestSpikeDistribution(t *testing.T) {
+	rand.Seed(123)
+	base := &mockDistribution{ReturnValue: 10}
+	spd := SPD(base, 0.1, &ConstantDistribution{State: 100})
+	spikes := 0
+	for i := 0; i < 10000; i++ {
+		spd.Advance()
+		if got := math.Mod(spd.Get()-10, 100); got != 0 {
+			t.Fatalf("value is not the base plus spikes: got %f", spd.Get())
+		}
+		spikes += int(spd.Get()-10) / 100
+	}
+	// 1000 spikes are expected on average.
+	if spikes < 900 || spikes > 1100 {
+		t.Errorf("unexpected number of spikes: got %d want about %d", spikes, 1000)
+	}
+
+	spd.Rate = 0
+	spd.Advance()
+	if got := spd.Get(); got != 10 {
+		t.Errorf("expected no spike with a rate of 0, got %f", got)
+	}
+}
+
+func TestStepChangeDistribution(t *testing.T) {
+	base := &mockDistribution{ReturnValue: 10}
+	level := &mockDistribution{ReturnValue: 5}
+	scd := SCD(base, level, 0)
+	scd.Advance()
+	if level.AdvanceCalled {
+		t.Errorf("level changed with a chance of 0")
+	}
+	if got := scd.Get(); got != 10 {
+		t.Errorf("expected the base value before any change: got %f", got)
+	}
+
+	scd.Chance = 1
+	scd.Advance()
+	if !level.AdvanceCalled {
+		t.Errorf("level not changed with a chance of 1")
+	}
+	level.ReturnValue = -3
+	if got := scd.Get(); got != 15 {
+		t.Errorf("expected the level to stay until the next change: got %f", got)
+	}
+}
+
+func TestAnomalyDistribution(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	ad := AD(&mockDistribution{ReturnValue: 10}, []AnomalyWindow{
+		{Label: "surge", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), Factor: 2},
+		{Label: "drift", Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour), Factor: 1, Offset: -1},
+	})
+	testCases := []struct {
+		now    time.Time
+		label  string
+		expect float64
+	}{
+		{now: start, label: "", expect: 10},
+		{now: start.Add(time.Hour), label: "surge", expect: 20},
+		{now: start.Add(2 * time.Hour), label: "", expect: 10},
+		{now: start.Add(210 * time.Minute), label: "drift", expect: 9},
+	}
+
+	for _, testCase := range testCases {
+		ad.SetTime(testCase.now)
+		if got := ad.Label(); got != testCase.label {
+			t.Errorf("%v: incorrect label: got %q want %q", testCase.now, got, testCase.label)
+		}
+		if got := ad.Get(); got != testCase.expect {
+			t.Errorf("%v: expected: %f, got %f", testCase.now, testCase.expect, got)
+		}
+	}
+}
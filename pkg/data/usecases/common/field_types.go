@@ -0,0 +1,48 @@
+package common
+
+// Field types of the generated data, named after the Go types of the values.
+// Fields without a declared type are float64.
+const (
+	FieldTypeFloat64 = "float64"
+	FieldTypeFloat32 = "float32"
+	FieldTypeInt64   = "int64"
+	FieldTypeUint64  = "uint64"
+	FieldTypeBool    = "bool"
+	FieldTypeString  = "string"
+)
+
+// FieldType returns the field type of a field value. nil values, e.g. of
+// fields not reported by an entry, are taken as float64.
+func FieldType(v interface{}) string {
+	switch v.(type) {
+	case float32:
+		return FieldTypeFloat32
+	case int, int64:
+		return FieldTypeInt64
+	case uint, uint64:
+		return FieldTypeUint64
+	case bool:
+		return FieldTypeBool
+	case string, []byte:
+		return FieldTypeString
+	default:
+		return FieldTypeFloat64
+	}
+}
+
+// FieldTypesOf returns the types of the fields of the given measurement, in
+// the order of FieldKeys. Types that are not known, e.g. when they were read
+// from a file generated before fields had types, are float64.
+func (h *GeneratedDataHeaders) FieldTypesOf(measurement string) []string {
+	keys := h.FieldKeys[measurement]
+	known := h.FieldTypes[measurement]
+	types := make([]string, len(keys))
+	for i := range keys {
+		if i < len(known) && known[i] != "" {
+			types[i] = known[i]
+		} else {
+			types[i] = FieldTypeFloat64
+		}
+	}
+	return types
+}
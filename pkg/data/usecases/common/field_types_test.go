@@ -0,0 +1,48 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldType(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: 1.5, want: FieldTypeFloat64},
+		{value: float32(1.5), want: FieldTypeFloat32},
+		{value: 3, want: FieldTypeInt64},
+		{value: int64(3), want: FieldTypeInt64},
+		{value: uint64(3), want: FieldTypeUint64},
+		{value: true, want: FieldTypeBool},
+		{value: "on", want: FieldTypeString},
+		{value: []byte("on"), want: FieldTypeString},
+		{value: nil, want: FieldTypeFloat64},
+	}
+	for _, c := range cases {
+		if got := FieldType(c.value); got != c.want {
+			t.Errorf("incorrect type for %#v: got %s want %s", c.value, got, c.want)
+		}
+	}
+}
+
+func TestGeneratedDataHeadersFieldTypesOf(t *testing.T) {
+	h := &GeneratedDataHeaders{
+		FieldKeys: map[string][]string{
+			"cpu":  {"usage", "count", "state"},
+			"disk": {"free"},
+		},
+		FieldTypes: map[string][]string{
+			"cpu": {"", FieldTypeInt64},
+		},
+	}
+	want := []string{FieldTypeFloat64, FieldTypeInt64, FieldTypeFloat64}
+	if got := h.FieldTypesOf("cpu"); !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect cpu types: got %v want %v", got, want)
+	}
+	want = []string{FieldTypeFloat64}
+	if got := h.FieldTypesOf("disk"); !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect disk types: got %v want %v", got, want)
+	}
+}
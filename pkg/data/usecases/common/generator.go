@@ -25,6 +25,16 @@ type Tag struct {
 const (
 	errMaxMetricCountValue = "max metric count per host has to be greater than 0"
 	errLogIntervalZero     = "cannot have log interval of 0"
+	errNoCustomSchema      = "custom use case needs a schema file, see --custom-schema"
+	errNoDataset           = "dataset use case needs a dataset file, see --dataset"
+	errChurnRateValue      = "churn rate has to be between 0 and 1"
+	errBadQualityFmt       = "invalid quality profile specified: '%v'"
+	errQualityChanceFmt    = "%s cannot be greater than 1: %v"
+	errMaxLatenessNegative = "max lateness cannot be negative"
+	errBadPatternFmt       = "invalid pattern specified: '%v'"
+	errRealTimeDuration    = "real-time duration cannot be negative"
+	errBadPrecisionFmt     = "invalid timestamp precision specified: '%v'"
+	errTimestampJitter     = "timestamp jitter cannot be negative"
 	defaultLogInterval     = 10 * time.Second
 )
 
@@ -40,6 +50,25 @@ type DataGeneratorConfig struct {
 	InterleavedGroupID    uint          `yaml:"interleaved-generation-group-id" mapstructure:"interleaved-generation-group-id"`
 	InterleavedNumGroups  uint          `yaml:"interleaved-generation-groups" mapstructure:"interleaved-generation-groups"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	CustomSchema          string        `yaml:"custom-schema" mapstructure:"custom-schema"`
+	ChurnRate             float64       `yaml:"churn-rate" mapstructure:"churn-rate"`
+	Quality               string        `yaml:"quality-profile" mapstructure:"quality-profile"`
+	QualityOverrides      `yaml:",inline" mapstructure:",squash"`
+	MaxLateness           time.Duration `yaml:"max-lateness" mapstructure:"max-lateness"`
+	Patterns              []string      `yaml:"patterns" mapstructure:"patterns"`
+	RealTime              bool          `yaml:"real-time" mapstructure:"real-time"`
+	RealTimeDuration      time.Duration `yaml:"real-time-duration" mapstructure:"real-time-duration"`
+	TimestampPrecision    string        `yaml:"timestamp-precision" mapstructure:"timestamp-precision"`
+	TimestampJitter       time.Duration `yaml:"timestamp-jitter" mapstructure:"timestamp-jitter"`
+	Dataset               string        `yaml:"dataset" mapstructure:"dataset"`
+	DatasetFormat         string        `yaml:"dataset-format" mapstructure:"dataset-format"`
+	DatasetMeasurement    string        `yaml:"dataset-measurement" mapstructure:"dataset-measurement"`
+	DatasetMeasurementCol string        `yaml:"dataset-measurement-column" mapstructure:"dataset-measurement-column"`
+	DatasetTimeColumn     string        `yaml:"dataset-time-column" mapstructure:"dataset-time-column"`
+	DatasetTimeFormat     string        `yaml:"dataset-time-format" mapstructure:"dataset-time-format"`
+	DatasetTags           []string      `yaml:"dataset-tags" mapstructure:"dataset-tags"`
+	DatasetFields         []string      `yaml:"dataset-fields" mapstructure:"dataset-fields"`
+	DatasetShift          bool          `yaml:"dataset-shift" mapstructure:"dataset-shift"`
 }
 
 // Validate checks that the values of the DataGeneratorConfig are reasonable.
@@ -57,6 +86,60 @@ func (c *DataGeneratorConfig) Validate() error {
 		return fmt.Errorf(errLogIntervalZero)
 	}
 	
+	if c.Use == UseCaseDevopsGeneric && c.MaxMetricCountPerHost < 1 {
+		return fmt.Errorf(errMaxMetricCountValue)
+	}
+	
+	if c.Use == UseCaseCustom && c.CustomSchema == "" {
+		return fmt.Errorf(errNoCustomSchema)
+	}
+	
+	if c.Use == UseCaseDataset && c.Dataset == "" {
+		return fmt.Errorf(errNoDataset)
+	}
+	
+	if c.ChurnRate < 0 || c.ChurnRate > 1 {
+		return fmt.Errorf(errChurnRateValue)
+	}
+	
+	if c.Quality == "" {
+		c.Quality = QualityDefault
+	}
+	
+	if !utils.IsIn(c.Quality, QualityChoices) {
+		return fmt.Errorf(errBadQualityFmt, c.Quality)
+	}
+	
+	if err := c.QualityOverrides.validate(); err != nil {
+		return err
+	}
+	
+	if c.MaxLateness < 0 {
+		return fmt.Errorf(errMaxLatenessNegative)
+	}
+	
+	for _, pattern := range c.Patterns {
+		if !utils.IsIn(pattern, PatternChoices) {
+			return fmt.Errorf(errBadPatternFmt, pattern)
+		}
+	}
+	
+	if c.RealTimeDuration < 0 {
+		return fmt.Errorf(errRealTimeDuration)
+	}
+	
+	if c.TimestampPrecision == "" {
+		c.TimestampPrecision = PrecisionNanoseconds
+	}
+	
+	if !utils.IsIn(c.TimestampPrecision, PrecisionChoices) {
+		return fmt.Errorf(errBadPrecisionFmt, c.TimestampPrecision)
+	}
+	
+	if c.TimestampJitter < 0 {
+		return fmt.Errorf(errTimestampJitter)
+	}
+	
 	err = utils.ValidateGroups(c.InterleavedGroupID, c.InterleavedNumGroups)
 	
 	return err
@@ -73,6 +156,47 @@ func (c *DataGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Uint("interleaved-generation-groups", 1,
 		"The number of round-robin serialization groups. Use this to scale up data generation to multiple processes.")
 	fs.Uint64("max-metric-count", 100, "Max number of metric fields to generate per host. Used only in devops-generic use-case")
+	fs.String("custom-schema", "", "YAML file declaring the tags, measurements and fields to generate. Used only in custom use-case")
+	fs.Float64("churn-rate", 0, "Fraction of the hosts (or trucks) replaced by new ones with fresh tag values at every log interval, 0 = no churn")
+	fs.String("quality-profile", QualityDefault,
+		fmt.Sprintf("Data quality profile: chances of missing, out-of-order and zero-valued entries. Used only in iot use-case (choices: %s)", strings.Join(QualityChoices, ", ")))
+	c.QualityOverrides.AddToFlagSetWithPrefix("", fs)
+	fs.Duration("max-lateness", 0, "Max time an out-of-order entry can fall behind the newest one, 0 = use the quality profile's value")
+	fs.StringSlice("patterns", nil,
+		fmt.Sprintf("Value patterns added to the truck readings. Used only in iot use-case (choices: %s)", strings.Join(PatternChoices, ", ")))
+	fs.Bool("real-time", false,
+		"Pace the data with the wall clock: timestamps start at the current log interval and each interval is only released once reached. The timestamp flags are ignored")
+	fs.Duration("real-time-duration", 0, "How long to run in real-time mode, 0 = one year, which is until stopped in practice")
+	fs.String("timestamp-precision", PrecisionNanoseconds,
+		fmt.Sprintf("Precision of the timestamps, which are truncated to it and written in its unit by the line protocol formats (choices: %s)", strings.Join(PrecisionChoices, ", ")))
+	fs.Duration("timestamp-jitter", 0, "Max random offset added to the timestamp of each point so they are not aligned on the log interval, 0 = no jitter")
+	fs.String("dataset", "", "CSV or line protocol file, gzipped if it ends with .gz, whose records are the points. Used only in dataset use-case")
+	fs.String("dataset-format", "", "Format of the dataset (choices: csv, influx), empty = csv for .csv files, influx otherwise")
+	fs.String("dataset-measurement", "dataset", "Measurement of the rows of a CSV dataset")
+	fs.String("dataset-measurement-column", "", "CSV column holding the measurement of a row, overrides dataset-measurement")
+	fs.String("dataset-time-column", "time", "CSV column holding the timestamp of a row")
+	fs.String("dataset-time-format", "", "Format of the dataset timestamps (choices: rfc3339, s, ms, us, ns), empty = rfc3339 for csv, ns for influx")
+	fs.StringSlice("dataset-tags", nil, "CSV columns, or line protocol tags, kept as tags. Empty = no CSV tag, all line protocol tags")
+	fs.StringSlice("dataset-fields", nil, "CSV columns, or line protocol fields, kept as fields. Empty = all the other CSV columns, all line protocol fields")
+	fs.Bool("dataset-shift", false, "Shift the dataset timestamps so it starts at timestamp-start, dropping the records past timestamp-end")
+}
+
+// QualityProfile returns the data quality profile selected by the config,
+// with the values of its QualityOverrides and its max lateness, when
+// MaxLateness is set, replacing the profile's ones.
+func (c *DataGeneratorConfig) QualityProfile() QualityProfile {
+	name := c.Quality
+	if name == "" {
+		name = QualityDefault
+	}
+	
+	profile := QualityProfiles[name]
+	c.QualityOverrides.apply(&profile)
+	if c.MaxLateness > 0 {
+		profile.MaxLateness = c.MaxLateness
+	}
+	
+	return profile
 }
 
 const defaultTimeStart = "2016-01-01T00:00:00Z"
@@ -25,14 +25,17 @@ func NewSubsystemMeasurementWithDistributionMakers(start time.Time, makers []Lab
 	m := NewSubsystemMeasurement(start, len(makers))
 	for i := 0; i < len(makers); i++ {
 		m.Distributions[i] = makers[i].DistributionMaker()
+		SetDistributionTime(m.Distributions[i], start)
 	}
 	return m
 }
 
-// Tick advances all the distributions for the SubsystemMeasurement.
+// Tick advances all the distributions for the SubsystemMeasurement, after
+// moving the time-dependent ones to the new timestamp.
 func (m *SubsystemMeasurement) Tick(d time.Duration) {
 	m.Timestamp = m.Timestamp.Add(d)
 	for i := range m.Distributions {
+		SetDistributionTime(m.Distributions[i], m.Timestamp)
 		m.Distributions[i].Advance()
 	}
 }
@@ -101,6 +101,25 @@ func TestSubsytemMeasurementTick(t *testing.T) {
 	}
 }
 
+func TestSubsytemMeasurementTickTimedDistribution(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := AnomalyWindow{Label: "anomaly", Start: start.Add(time.Minute), End: start.Add(2 * time.Minute), Factor: 2}
+	ad := AD(&monotonicDistribution{state: 1}, []AnomalyWindow{window})
+	m := NewSubsystemMeasurementWithDistributionMakers(start, []LabeledDistributionMaker{
+		{Label: []byte("foo"), DistributionMaker: func() Distribution { return FP(ad, 0) }},
+	})
+	if got := ad.Label(); got != "" {
+		t.Errorf("anomaly active at start: got %q", got)
+	}
+	m.Tick(time.Minute)
+	if got := ad.Label(); got != window.Label {
+		t.Errorf("tick did not set the time of the distribution: got label %q want %q", got, window.Label)
+	}
+	if got := m.Distributions[0].Get(); got != 4 {
+		t.Errorf("tick did not advance distro: got %f want %f", got, 4.0)
+	}
+}
+
 const (
 	toPointState      = 0.5
 	toPointLabel      = "foo"
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// Timestamp precision choices
+	PrecisionSeconds      = "s"
+	PrecisionMilliseconds = "ms"
+	PrecisionMicroseconds = "us"
+	PrecisionNanoseconds  = "ns"
+)
+
+var PrecisionChoices = []string{
+	PrecisionSeconds,
+	PrecisionMilliseconds,
+	PrecisionMicroseconds,
+	PrecisionNanoseconds,
+}
+
+// precisionDurations are the units of the timestamp precisions.
+var precisionDurations = map[string]time.Duration{
+	PrecisionSeconds:      time.Second,
+	PrecisionMilliseconds: time.Millisecond,
+	PrecisionMicroseconds: time.Microsecond,
+	PrecisionNanoseconds:  time.Nanosecond,
+}
+
+// PrecisionDuration returns the unit of a timestamp precision, nanoseconds
+// when it is empty or not known.
+func PrecisionDuration(precision string) time.Duration {
+	if d, ok := precisionDurations[precision]; ok {
+		return d
+	}
+	return time.Nanosecond
+}
+
+// precisionHeaderPrefix starts the header of the line protocol data files
+// whose timestamps are not in nanoseconds, e.g. "# precision=ms". Line
+// protocol takes the lines starting with # as comments.
+const precisionHeaderPrefix = "# precision="
+
+// PrecisionHeader returns the header line of a line protocol data file whose
+// timestamps have the given precision, empty for nanoseconds, the precision
+// of the files without header.
+func PrecisionHeader(precision string) string {
+	if precision == "" || precision == PrecisionNanoseconds {
+		return ""
+	}
+	return precisionHeaderPrefix + precision + "\n"
+}
+
+// ReadPrecisionHeader reads the header written by PrecisionHeader at the start
+// of r, if any, and returns the precision of the timestamps of the file.
+func ReadPrecisionHeader(r *bufio.Reader) (string, error) {
+	prefix, err := r.Peek(len(precisionHeaderPrefix))
+	if err != nil || string(prefix) != precisionHeaderPrefix {
+		// shorter files have no header
+		return PrecisionNanoseconds, nil
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	precision := strings.TrimSpace(strings.TrimPrefix(line, precisionHeaderPrefix))
+	if _, ok := precisionDurations[precision]; !ok {
+		return "", fmt.Errorf(errBadPrecisionFmt, precision)
+	}
+	return precision, nil
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestPrecisionHeader(t *testing.T) {
+	cases := []struct {
+		desc      string
+		input     string
+		precision string
+		rest      string
+		fail      bool
+	}{
+		{
+			desc:      "header of a precision",
+			input:     PrecisionHeader(PrecisionMilliseconds) + "cpu usage=1 1451606400000\n",
+			precision: PrecisionMilliseconds,
+			rest:      "cpu usage=1 1451606400000\n",
+		},
+		{
+			desc:      "nanoseconds have no header",
+			input:     PrecisionHeader(PrecisionNanoseconds) + "cpu usage=1 1451606400000000000\n",
+			precision: PrecisionNanoseconds,
+			rest:      "cpu usage=1 1451606400000000000\n",
+		},
+		{
+			desc:      "file shorter than the header",
+			input:     "cpu",
+			precision: PrecisionNanoseconds,
+			rest:      "cpu",
+		},
+		{
+			desc:  "unknown precision",
+			input: "# precision=h\n",
+			fail:  true,
+		},
+	}
+	for _, c := range cases {
+		r := bufio.NewReader(strings.NewReader(c.input))
+		precision, err := ReadPrecisionHeader(r)
+		if c.fail {
+			if err == nil {
+				t.Errorf("%s: expected an error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+			continue
+		}
+		if precision != c.precision {
+			t.Errorf("%s: incorrect precision: got %s want %s", c.desc, precision, c.precision)
+		}
+		rest, _ := r.ReadString(0)
+		if rest != c.rest {
+			t.Errorf("%s: incorrect rest of the file: got %q want %q", c.desc, rest, c.rest)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// Data quality profile choices
+	QualityClean   = "clean"
+	QualityDefault = "default"
+	QualityChaotic = "chaotic"
+)
+
+var QualityChoices = []string{
+	QualityClean,
+	QualityDefault,
+	QualityChaotic,
+}
+
+// QualityProfile describes how dirty the simulated data is: the chances of
+// missing, out-of-order and zero-valued entries, which the IoT use case
+// applies to batches of BatchSize entries.
+type QualityProfile struct {
+	// BatchSize is the number of entries the chances are drawn for at once.
+	// 0 disables all of them and the entries are written as simulated.
+	BatchSize uint
+
+	// Batch chances.
+	BatchMissingChance        float64
+	BatchOutOfOrderChance     float64
+	BatchInsertPreviousChance float64
+
+	// Entry chances.
+	EntryMissingChance        float64
+	EntryOutOfOrderChance     float64
+	EntryInsertPreviousChance float64
+
+	// Zero values.
+	ZeroTagChance   float64
+	ZeroFieldChance float64
+
+	// MaxLateness is how far behind the newest simulated entry an out-of-order
+	// batch or entry can fall before it is written anyway. It is checked
+	// between batches, so entries can be up to a batch later. 0 means no limit.
+	MaxLateness time.Duration
+}
+
+// QualityProfiles are the preset profiles, by name.
+var QualityProfiles = map[string]QualityProfile{
+	QualityClean: {},
+	QualityDefault: {
+		BatchSize:                 10,
+		BatchMissingChance:        0.01,
+		BatchOutOfOrderChance:     0.05,
+		BatchInsertPreviousChance: 0.5,
+		EntryMissingChance:        0.1,
+		EntryOutOfOrderChance:     0.3,
+		EntryInsertPreviousChance: 0.5,
+		ZeroTagChance:             0.01,
+		ZeroFieldChance:           0.1,
+	},
+	QualityChaotic: {
+		BatchSize:                 20,
+		BatchMissingChance:        0.05,
+		BatchOutOfOrderChance:     0.2,
+		BatchInsertPreviousChance: 0.2,
+		EntryMissingChance:        0.2,
+		EntryOutOfOrderChance:     0.5,
+		EntryInsertPreviousChance: 0.2,
+		ZeroTagChance:             0.05,
+		ZeroFieldChance:           0.3,
+		MaxLateness:               time.Hour,
+	},
+}
+
+// QualityOverrides replace values of the selected quality profile, which are
+// the starting values. A nil or negative value keeps the profile's value.
+type QualityOverrides struct {
+	BatchSize                 *int     `yaml:"quality-batch-size,omitempty" mapstructure:"quality-batch-size"`
+	BatchMissingChance        *float64 `yaml:"quality-batch-missing-chance,omitempty" mapstructure:"quality-batch-missing-chance"`
+	BatchOutOfOrderChance     *float64 `yaml:"quality-batch-out-of-order-chance,omitempty" mapstructure:"quality-batch-out-of-order-chance"`
+	BatchInsertPreviousChance *float64 `yaml:"quality-batch-insert-previous-chance,omitempty" mapstructure:"quality-batch-insert-previous-chance"`
+	EntryMissingChance        *float64 `yaml:"quality-entry-missing-chance,omitempty" mapstructure:"quality-entry-missing-chance"`
+	EntryOutOfOrderChance     *float64 `yaml:"quality-entry-out-of-order-chance,omitempty" mapstructure:"quality-entry-out-of-order-chance"`
+	EntryInsertPreviousChance *float64 `yaml:"quality-entry-insert-previous-chance,omitempty" mapstructure:"quality-entry-insert-previous-chance"`
+	ZeroTagChance             *float64 `yaml:"quality-zero-tag-chance,omitempty" mapstructure:"quality-zero-tag-chance"`
+	ZeroFieldChance           *float64 `yaml:"quality-zero-field-chance,omitempty" mapstructure:"quality-zero-field-chance"`
+}
+
+// AddToFlagSetWithPrefix adds the flags of the QualityOverrides to the flag
+// set, with their names prefixed by prefix.
+func (o QualityOverrides) AddToFlagSetWithPrefix(prefix string, fs *pflag.FlagSet) {
+	fs.Int(prefix+"quality-batch-size", -1, "Number of entries the quality chances are drawn for at once, 0 = none of them, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-batch-missing-chance", -1, "Chance of a missing batch, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-batch-out-of-order-chance", -1, "Chance of an out-of-order batch, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-batch-insert-previous-chance", -1, "Chance an out-of-order batch is written with the next one, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-entry-missing-chance", -1, "Chance of a missing entry in a batch, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-entry-out-of-order-chance", -1, "Chance of an out-of-order entry in a batch, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-entry-insert-previous-chance", -1, "Chance an out-of-order entry is written with the next one, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-zero-tag-chance", -1, "Chance of an empty tag, negative = the quality profile's value")
+	fs.Float64(prefix+"quality-zero-field-chance", -1, "Chance of an empty field, negative = the quality profile's value")
+}
+
+// chances returns the chance overrides by flag name.
+func (o QualityOverrides) chances() map[string]*float64 {
+	return map[string]*float64{
+		"quality-batch-missing-chance":         o.BatchMissingChance,
+		"quality-batch-out-of-order-chance":    o.BatchOutOfOrderChance,
+		"quality-batch-insert-previous-chance": o.BatchInsertPreviousChance,
+		"quality-entry-missing-chance":         o.EntryMissingChance,
+		"quality-entry-out-of-order-chance":    o.EntryOutOfOrderChance,
+		"quality-entry-insert-previous-chance": o.EntryInsertPreviousChance,
+		"quality-zero-tag-chance":              o.ZeroTagChance,
+		"quality-zero-field-chance":            o.ZeroFieldChance,
+	}
+}
+
+// validate checks that the chances are at most 1.
+func (o QualityOverrides) validate() error {
+	for name, chance := range o.chances() {
+		if chance != nil && *chance > 1 {
+			return fmt.Errorf(errQualityChanceFmt, name, *chance)
+		}
+	}
+	return nil
+}
+
+// apply replaces the values of the profile p that are overridden.
+func (o QualityOverrides) apply(p *QualityProfile) {
+	if o.BatchSize != nil && *o.BatchSize >= 0 {
+		p.BatchSize = uint(*o.BatchSize)
+	}
+	overrides := []struct {
+		override *float64
+		value    *float64
+	}{
+		{o.BatchMissingChance, &p.BatchMissingChance},
+		{o.BatchOutOfOrderChance, &p.BatchOutOfOrderChance},
+		{o.BatchInsertPreviousChance, &p.BatchInsertPreviousChance},
+		{o.EntryMissingChance, &p.EntryMissingChance},
+		{o.EntryOutOfOrderChance, &p.EntryOutOfOrderChance},
+		{o.EntryInsertPreviousChance, &p.EntryInsertPreviousChance},
+		{o.ZeroTagChance, &p.ZeroTagChance},
+		{o.ZeroFieldChance, &p.ZeroFieldChance},
+	}
+	for _, v := range overrides {
+		if v.override != nil && *v.override >= 0 {
+			*v.value = *v.override
+		}
+	}
+}
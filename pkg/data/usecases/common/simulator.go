@@ -2,6 +2,7 @@ package common
 
 import (
 	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"math/rand"
 	"reflect"
 	"time"
 )
@@ -23,8 +24,26 @@ type BaseSimulatorConfig struct {
 	GeneratorScale uint64
 	// GeneratorConstructor is the function used to create a new Generator given an id number and start time
 	GeneratorConstructor func(i int, start time.Time) Generator
+	// ChurnRate is the fraction of the Generators retired at every epoch and replaced by new ones,
+	// with fresh ids and tag values, so the number of series keeps growing while the active set
+	// stays the same size. 0 disables churn.
+	ChurnRate float64
+	// RealTime paces the Simulator with the wall clock: the points of every reporting period are
+	// only released once the wall clock reaches their timestamp.
+	RealTime bool
+	// TimestampJitter is the max random offset added to the timestamp of every point. 0 disables it.
+	TimestampJitter time.Duration
+	// TimestampPrecision is the unit the timestamps of the points are truncated to.
+	// 0 or a nanosecond leaves them as they are.
+	TimestampPrecision time.Duration
 }
 
+// allows for testing
+var (
+	timeNow = time.Now
+	sleep   = time.Sleep
+)
+
 func calculateEpochs(duration time.Duration, interval time.Duration) uint64 {
 	return uint64(duration.Nanoseconds() / interval.Nanoseconds())
 }
@@ -58,6 +77,15 @@ func (sc *BaseSimulatorConfig) NewSimulator(interval time.Duration, limit uint64
 		interval:        interval,
 		
 		simulatedMeasurementIndex: 0,
+		
+		generatorConstructor: sc.GeneratorConstructor,
+		churnRate:            sc.ChurnRate,
+		nextGeneratorID:      len(generators),
+		
+		realTime: sc.RealTime,
+		
+		timestampJitter:    sc.TimestampJitter,
+		timestampPrecision: sc.TimestampPrecision,
 	}
 	
 	return sim
@@ -67,6 +95,8 @@ type GeneratedDataHeaders struct {
 	TagTypes  []string
 	TagKeys   []string
 	FieldKeys map[string][]string
+	// FieldTypes holds the type of each field of FieldKeys, see FieldType.
+	FieldTypes map[string][]string
 }
 
 // Simulator simulates a use case.
@@ -79,6 +109,12 @@ type Simulator interface {
 	Headers() *GeneratedDataHeaders
 }
 
+// Summarizer is implemented by Simulators that alter the simulated data, e.g.
+// by dropping or reordering entries, to report how many times they did so.
+type Summarizer interface {
+	Summary() map[string]uint64
+}
+
 // BaseSimulator generates data similar to truck readings.
 type BaseSimulator struct {
 	madePoints uint64
@@ -97,6 +133,17 @@ type BaseSimulator struct {
 	interval       time.Duration
 	
 	simulatedMeasurementIndex int
+	
+	generatorConstructor func(i int, start time.Time) Generator
+	churnRate            float64
+	churnDebt            float64
+	churnIndex           int
+	nextGeneratorID      int
+	
+	realTime bool
+	
+	timestampJitter    time.Duration
+	timestampPrecision time.Duration
 }
 
 // Finished tells whether we have simulated all the necessary points.
@@ -119,6 +166,11 @@ func (s *BaseSimulator) Next(p *data.Point) bool {
 		}
 		
 		s.adjustNumHostsForEpoch()
+		s.churnGenerators()
+	}
+	
+	if s.realTime && s.generatorIndex == 0 && s.simulatedMeasurementIndex == 0 {
+		s.waitForEpoch()
 	}
 	
 	generator := s.generators[s.generatorIndex]
@@ -131,12 +183,34 @@ func (s *BaseSimulator) Next(p *data.Point) bool {
 	// Populate measurement-specific tags and fields:
 	generator.Measurements()[s.simulatedMeasurementIndex].ToPoint(p)
 	
+	if s.timestampJitter > 0 || s.timestampPrecision > time.Nanosecond {
+		s.adjustTimestamp(p)
+	}
+	
 	ret := s.generatorIndex < s.epochGenerators
 	s.madePoints++
 	s.generatorIndex++
 	return ret
 }
 
+// adjustTimestamp offsets the timestamp of the point by a random jitter and
+// truncates it to the timestamp precision. The measurements keep their own
+// time, the point gets a copy.
+func (s *BaseSimulator) adjustTimestamp(p *data.Point) {
+	if p.Timestamp() == nil {
+		return
+	}
+	
+	ts := *p.Timestamp()
+	if s.timestampJitter > 0 {
+		ts = ts.Add(time.Duration(rand.Int63n(int64(s.timestampJitter))))
+	}
+	if s.timestampPrecision > time.Nanosecond {
+		ts = ts.Truncate(s.timestampPrecision)
+	}
+	p.SetTimestamp(&ts)
+}
+
 // Fields returns all the simulated measurements for the device.
 func (s *BaseSimulator) Fields() map[string][]string {
 	if len(s.generators) <= 0 {
@@ -188,11 +262,34 @@ func (s *BaseSimulator) TagTypes() []string {
 	return types
 }
 
+// FieldTypes returns the type of each field of every measurement, extracted
+// from the generated values.
+func (s *BaseSimulator) FieldTypes() map[string][]string {
+	if len(s.generators) <= 0 {
+		panic("cannot get field types because no Generators added")
+	}
+	
+	toReturn := make(map[string][]string, len(s.generators))
+	for _, sm := range s.generators[0].Measurements() {
+		point := data.NewPoint()
+		sm.ToPoint(point)
+		fieldValues := point.FieldValues()
+		types := make([]string, len(fieldValues))
+		for i, v := range fieldValues {
+			types[i] = FieldType(v)
+		}
+		toReturn[string(point.MeasurementName())] = types
+	}
+	
+	return toReturn
+}
+
 func (s *BaseSimulator) Headers() *GeneratedDataHeaders {
 	return &GeneratedDataHeaders{
-		TagTypes:  s.TagTypes(),
-		TagKeys:   s.TagKeys(),
-		FieldKeys: s.Fields(),
+		TagTypes:   s.TagTypes(),
+		TagKeys:    s.TagKeys(),
+		FieldKeys:  s.Fields(),
+		FieldTypes: s.FieldTypes(),
 	}
 }
 
@@ -211,6 +308,36 @@ func (s *BaseSimulator) adjustNumHostsForEpoch() {
 	s.epochGenerators = s.initGenerators + uint64(missingScale*float64(s.epoch)/float64(s.epochs-1))
 }
 
+// churnGenerators retires churnRate of the Generators, oldest first, and
+// replaces them with new ones starting at the current epoch. Fractions of a
+// Generator are carried over to the next epochs, so low rates still churn.
+func (s *BaseSimulator) churnGenerators() {
+	if s.churnRate <= 0 || len(s.generators) == 0 {
+		return
+	}
+	
+	s.churnDebt += s.churnRate * float64(len(s.generators))
+	start := s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	for ; s.churnDebt >= 1; s.churnDebt-- {
+		s.generators[s.churnIndex] = s.generatorConstructor(s.nextGeneratorID, start)
+		s.nextGeneratorID++
+		s.churnIndex = (s.churnIndex + 1) % len(s.generators)
+	}
+}
+
+// waitForEpoch blocks until the wall clock reaches the timestamp of the current epoch.
+func (s *BaseSimulator) waitForEpoch() {
+	at := s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	if d := at.Sub(timeNow()); d > 0 {
+		sleep(d)
+	}
+}
+
+// ChurnedGenerators returns how many Generators have been retired and replaced so far.
+func (s *BaseSimulator) ChurnedGenerators() uint64 {
+	return uint64(s.nextGeneratorID - len(s.generators))
+}
+
 // SimulatedMeasurement simulates one measurement (e.g. Redis for DevOps).
 type SimulatedMeasurement interface {
 	Tick(time.Duration)
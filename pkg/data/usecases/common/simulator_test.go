@@ -192,6 +192,24 @@ func TestBaseSimulatorFieldsPanic(t *testing.T) {
 	t.Fatalf("test should have stopped at this point")
 }
 
+func TestBaseSimulatorFieldTypes(t *testing.T) {
+	s := testBaseConf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	
+	types := s.FieldTypes()
+	got, ok := types[string(dummyMeasurementName)]
+	if !ok {
+		t.Fatalf("field types not set, want %s", string(dummyMeasurementName))
+	}
+	if len(got) != 1 || got[0] != FieldTypeString {
+		t.Errorf("unexpected field types, got %v want [%s]", got, FieldTypeString)
+	}
+	
+	headers := s.Headers()
+	if len(headers.FieldTypes) != len(headers.FieldKeys) {
+		t.Errorf("headers field types incorrect, got %v", headers.FieldTypes)
+	}
+}
+
 func TestBaseSimulatorConfigNewSimulator(t *testing.T) {
 	duration := time.Second
 	start := time.Now()
@@ -249,3 +267,161 @@ func TestBaseSimulatorConfigNewSimulator(t *testing.T) {
 	}
 
 }
+
+type idGenerator struct {
+	dummyGenerator
+	id    int
+	start time.Time
+}
+
+func (g idGenerator) Tags() []Tag {
+	return []Tag{{Key: []byte("name"), Value: fmt.Sprintf("gen_%d", g.id)}}
+}
+
+func TestBaseSimulatorChurn(t *testing.T) {
+	start := time.Unix(0, 0)
+	conf := &BaseSimulatorConfig{
+		Start:              start,
+		End:                start.Add(4 * time.Second),
+		InitGeneratorScale: 10,
+		GeneratorScale:     10,
+		GeneratorConstructor: func(i int, start time.Time) Generator {
+			return &idGenerator{id: i, start: start}
+		},
+		ChurnRate: 0.25,
+	}
+	s := conf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	p := data.NewPoint()
+	perEpoch := 10 * dummyGeneratorMeasurementCount
+	
+	// 2.5 generators are replaced at every epoch, the remainder is carried
+	// over, so 2, 3 and 2 generators are churned at the next three epochs.
+	wantChurned := []uint64{0, 2, 5, 7}
+	for epoch, want := range wantChurned {
+		for i := 0; i < perEpoch; i++ {
+			if !s.Next(p) {
+				t.Fatalf("epoch %d: unexpected point not written at i = %d", epoch, i)
+			}
+		}
+		if got := s.ChurnedGenerators(); got != want {
+			t.Errorf("epoch %d: incorrect churned generators: got %d want %d", epoch, got, want)
+		}
+		if got := len(s.generators); got != 10 {
+			t.Errorf("epoch %d: incorrect active generators: got %d want %d", epoch, got, 10)
+		}
+	
+		// The oldest generators are replaced first, by new ones starting at
+		// the current epoch.
+		for i, g := range s.generators {
+			ig := g.(*idGenerator)
+			wantID := i
+			if uint64(i) < want {
+				wantID = 10 + i
+			}
+			if ig.id != wantID {
+				t.Errorf("epoch %d: incorrect id for generator %d: got %d want %d", epoch, i, ig.id, wantID)
+			}
+			if wantID >= 10 && ig.start.Before(start.Add(time.Second)) {
+				t.Errorf("epoch %d: churned generator %d starts too early: %v", epoch, i, ig.start)
+			}
+		}
+	}
+}
+
+func TestBaseSimulatorNoChurn(t *testing.T) {
+	s := testBaseConf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	p := data.NewPoint()
+	for i := 0; i < 3*testGeneratorScale*dummyGeneratorMeasurementCount; i++ {
+		s.Next(p)
+	}
+	if got := s.ChurnedGenerators(); got != 0 {
+		t.Errorf("incorrect churned generators without churn rate: got %d want %d", got, 0)
+	}
+}
+
+func TestBaseSimulatorRealTime(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	var sleeps []time.Duration
+	oldNow, oldSleep := timeNow, sleep
+	defer func() {
+		timeNow, sleep = oldNow, oldSleep
+	}()
+	timeNow = func() time.Time {
+		return now
+	}
+	sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+	}
+	
+	conf := &BaseSimulatorConfig{
+		Start:                now,
+		End:                  now.Add(3 * time.Second),
+		InitGeneratorScale:   2,
+		GeneratorScale:       2,
+		GeneratorConstructor: dummyGeneratorConstructor,
+		RealTime:             true,
+	}
+	s := conf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	
+	// the first epoch starts now, the next two are waited for
+	p := data.NewPoint()
+	for !s.Finished() {
+		s.Next(p)
+		p.Reset()
+	}
+	
+	want := []time.Duration{time.Second, time.Second}
+	if len(sleeps) != len(want) || sleeps[0] != want[0] || sleeps[1] != want[1] {
+		t.Errorf("incorrect waits: got %v want %v", sleeps, want)
+	}
+	
+	// a slow consumer is not held back
+	sleeps = nil
+	conf.Start = now.Add(-time.Hour)
+	conf.End = conf.Start.Add(3 * time.Second)
+	s = conf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	for !s.Finished() {
+		s.Next(p)
+		p.Reset()
+	}
+	if len(sleeps) != 0 {
+		t.Errorf("unexpected waits for past epochs: got %v", sleeps)
+	}
+}
+
+func TestBaseSimulatorAdjustTimestamp(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &BaseSimulator{timestampJitter: 500 * time.Millisecond, timestampPrecision: time.Millisecond}
+	p := data.NewPoint()
+	
+	seen := make(map[time.Time]bool)
+	for i := 0; i < 100; i++ {
+		ts := start
+		p.SetTimestamp(&ts)
+		s.adjustTimestamp(p)
+		got := *p.Timestamp()
+		if ts != start {
+			t.Fatalf("timestamp of the measurement changed: got %v want %v", ts, start)
+		}
+		if got.Before(start) || !got.Before(start.Add(s.timestampJitter)) {
+			t.Errorf("timestamp out of the jitter range: got %v", got)
+		}
+		if got != got.Truncate(time.Millisecond) {
+			t.Errorf("timestamp not truncated to the precision: got %v", got)
+		}
+		seen[got] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("timestamps are not jittered: got %d distinct timestamps", len(seen))
+	}
+	
+	// without jitter the timestamps are only truncated
+	s = &BaseSimulator{timestampPrecision: time.Second}
+	ts := start.Add(1500 * time.Millisecond)
+	p.SetTimestamp(&ts)
+	s.adjustTimestamp(p)
+	if got, want := *p.Timestamp(), start.Add(time.Second); got != want {
+		t.Errorf("incorrect truncated timestamp: got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package custom
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// Distribution types of a DistributionSpec, named after the constructors of
+// the common package.
+const (
+	distND    = "ND"
+	distUD    = "UD"
+	distWD    = "WD"
+	distCWD   = "CWD"
+	distMWD   = "MWD"
+	distConst = "CONST"
+	distFP    = "FP"
+	distLD    = "LD"
+	distSD    = "SD"
+	distSPD   = "SPD"
+	distSCD   = "SCD"
+)
+
+var distChoices = []string{distND, distUD, distWD, distCWD, distMWD, distConst, distFP, distLD, distSD, distSPD, distSCD}
+
+// DistributionSpec declares a common.Distribution. Which of its settings are
+// used depends on Type:
+//
+//	ND:    mean, stddev
+//	UD:    low, high
+//	WD:    step, state
+//	CWD:   step, min, max, state (random within [min, max] when unset)
+//	MWD:   step, state
+//	CONST: value
+//	FP:    step, precision
+//	LD:    motive, step, threshold
+//	SD:    step, amplitude, period, phase (daily cycle peaking at 00:00 when unset)
+//	SPD:   step, rate, magnitude
+//	SCD:   step, level, chance
+type DistributionSpec struct {
+	Type      string            `yaml:"type"`
+	Mean      float64           `yaml:"mean,omitempty"`
+	StdDev    float64           `yaml:"stddev,omitempty"`
+	Low       float64           `yaml:"low,omitempty"`
+	High      float64           `yaml:"high,omitempty"`
+	Min       float64           `yaml:"min,omitempty"`
+	Max       float64           `yaml:"max,omitempty"`
+	State     *float64          `yaml:"state,omitempty"`
+	Value     float64           `yaml:"value,omitempty"`
+	Precision int               `yaml:"precision,omitempty"`
+	Threshold float64           `yaml:"threshold,omitempty"`
+	Amplitude float64           `yaml:"amplitude,omitempty"`
+	Period    string            `yaml:"period,omitempty"`
+	Phase     string            `yaml:"phase,omitempty"`
+	Rate      float64           `yaml:"rate,omitempty"`
+	Chance    float64           `yaml:"chance,omitempty"`
+	Step      *DistributionSpec `yaml:"step,omitempty"`
+	Motive    *DistributionSpec `yaml:"motive,omitempty"`
+	Magnitude *DistributionSpec `yaml:"magnitude,omitempty"`
+	Level     *DistributionSpec `yaml:"level,omitempty"`
+
+	period time.Duration
+	phase  time.Duration
+}
+
+func (d *DistributionSpec) validate() error {
+	needs := func(name string, sub *DistributionSpec) error {
+		if sub == nil {
+			return fmt.Errorf("%s distribution needs a %s", d.Type, name)
+		}
+		return sub.validate()
+	}
+
+	switch d.Type {
+	case distND:
+		if d.StdDev < 0 {
+			return fmt.Errorf("ND stddev cannot be negative")
+		}
+	case distUD:
+		if d.High < d.Low {
+			return fmt.Errorf("UD high cannot be lower than low")
+		}
+	case distWD, distMWD, distFP:
+		return needs("step", d.Step)
+	case distCWD:
+		if d.Max < d.Min {
+			return fmt.Errorf("CWD max cannot be lower than min")
+		}
+		return needs("step", d.Step)
+	case distConst:
+	case distLD:
+		if err := needs("motive", d.Motive); err != nil {
+			return err
+		}
+		return needs("step", d.Step)
+	case distSD:
+		var err error
+		d.period = 24 * time.Hour
+		if d.Period != "" {
+			if d.period, err = time.ParseDuration(d.Period); err != nil || d.period <= 0 {
+				return fmt.Errorf("SD period has to be a positive duration, got '%s'", d.Period)
+			}
+		}
+		if d.Phase != "" {
+			if d.phase, err = time.ParseDuration(d.Phase); err != nil {
+				return fmt.Errorf("SD phase has to be a duration, got '%s'", d.Phase)
+			}
+		}
+		return needs("step", d.Step)
+	case distSPD:
+		if d.Rate < 0 {
+			return fmt.Errorf("SPD rate cannot be negative")
+		}
+		if err := needs("magnitude", d.Magnitude); err != nil {
+			return err
+		}
+		return needs("step", d.Step)
+	case distSCD:
+		if d.Chance < 0 || d.Chance > 1 {
+			return fmt.Errorf("SCD chance has to be between 0 and 1")
+		}
+		if err := needs("level", d.Level); err != nil {
+			return err
+		}
+		return needs("step", d.Step)
+	default:
+		return fmt.Errorf("unknown distribution type '%s' (choices: %s)", d.Type, strings.Join(distChoices, ", "))
+	}
+	return nil
+}
+
+// New creates a new common.Distribution as declared. The spec has to be
+// valid. Every call draws its own random initial states, so each generator
+// gets independent distributions.
+func (d *DistributionSpec) New() common.Distribution {
+	switch d.Type {
+	case distND:
+		return common.ND(d.Mean, d.StdDev)
+	case distUD:
+		return common.UD(d.Low, d.High)
+	case distWD:
+		return common.WD(d.Step.New(), d.state(0))
+	case distCWD:
+		return common.CWD(d.Step.New(), d.Min, d.Max, d.state(d.Min+rand.Float64()*(d.Max-d.Min)))
+	case distMWD:
+		return common.MWD(d.Step.New(), d.state(0))
+	case distConst:
+		return &common.ConstantDistribution{State: d.Value}
+	case distFP:
+		return common.FP(d.Step.New(), d.Precision)
+	case distLD:
+		return common.LD(d.Motive.New(), d.Step.New(), d.Threshold)
+	case distSD:
+		return common.SD(d.Step.New(), d.Amplitude, d.period, d.phase)
+	case distSPD:
+		return common.SPD(d.Step.New(), d.Rate, d.Magnitude.New())
+	case distSCD:
+		return common.SCD(d.Step.New(), d.Level.New(), d.Chance)
+	default:
+		panic(fmt.Sprintf("unknown distribution type '%s'", d.Type))
+	}
+}
+
+func (d *DistributionSpec) state(def float64) float64 {
+	if d.State != nil {
+		return *d.State
+	}
+	return def
+}
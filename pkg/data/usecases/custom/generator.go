@@ -0,0 +1,131 @@
+package custom
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// Generator is a simulated entity of a custom use case, e.g. a sensor, with
+// the tags and measurements declared by a Schema.
+type Generator struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+}
+
+// TickAll advances all Distributions of a Generator.
+func (g *Generator) TickAll(d time.Duration) {
+	for i := range g.simulatedMeasurements {
+		g.simulatedMeasurements[i].Tick(d)
+	}
+}
+
+// Measurements returns the generator measurements.
+func (g Generator) Measurements() []common.SimulatedMeasurement {
+	return g.simulatedMeasurements
+}
+
+// Tags returns the generator tags.
+func (g Generator) Tags() []common.Tag {
+	return g.tags
+}
+
+// NewGeneratorConstructor returns the function creating the generators of the
+// given schema, to be used as a common.BaseSimulatorConfig constructor.
+func NewGeneratorConstructor(s *Schema) func(i int, start time.Time) common.Generator {
+	return func(i int, start time.Time) common.Generator {
+		tags := make([]common.Tag, len(s.Tags))
+		for j, t := range s.Tags {
+			tags[j] = common.Tag{Key: []byte(t.Key), Value: t.value(i)}
+		}
+
+		sm := make([]common.SimulatedMeasurement, len(s.Measurements))
+		for j := range s.Measurements {
+			sm[j] = newMeasurement(start, &s.Measurements[j])
+		}
+
+		return &Generator{
+			simulatedMeasurements: sm,
+			tags:                  tags,
+		}
+	}
+}
+
+// value picks the value of the tag for the generator with id i.
+func (t *TagSpec) value(i int) string {
+	switch {
+	case t.Format != "":
+		return fmt.Sprintf(t.Format, i)
+	case t.Cardinality > 0:
+		return fmt.Sprintf("%s_%d", t.Key, rand.Intn(t.Cardinality))
+	default:
+		return common.RandomStringSliceChoice(t.Values)
+	}
+}
+
+// measurement simulates a measurement declared by a MeasurementSpec.
+type measurement struct {
+	*common.SubsystemMeasurement
+	name    []byte
+	labels  [][]byte
+	types   []string
+	choices [][]string
+}
+
+func newMeasurement(start time.Time, spec *MeasurementSpec) *measurement {
+	m := &measurement{
+		SubsystemMeasurement: common.NewSubsystemMeasurement(start, len(spec.Fields)),
+		name:                 []byte(spec.Name),
+		labels:               make([][]byte, len(spec.Fields)),
+		types:                make([]string, len(spec.Fields)),
+		choices:              make([][]string, len(spec.Fields)),
+	}
+	for i := range spec.Fields {
+		f := &spec.Fields[i]
+		m.Distributions[i] = f.Distribution.New()
+		common.SetDistributionTime(m.Distributions[i], start)
+		m.labels[i] = []byte(f.Name)
+		m.types[i] = f.Type
+		m.choices[i] = f.choices()
+	}
+	return m
+}
+
+// choices returns the values a string field can take.
+func (f *FieldSpec) choices() []string {
+	if f.Type != fieldTypeString || len(f.Values) > 0 {
+		return f.Values
+	}
+	choices := make([]string, f.Cardinality)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("%s_%d", f.Name, i)
+	}
+	return choices
+}
+
+// ToPoint serializes the measurement to data.Point.
+func (m *measurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(m.name)
+	p.SetTimestamp(&m.Timestamp)
+
+	for i, d := range m.Distributions {
+		v := d.Get()
+		switch m.types[i] {
+		case fieldTypeInt:
+			p.AppendField(m.labels[i], int64(v))
+		case fieldTypeUint:
+			p.AppendField(m.labels[i], uint64(math.Max(v, 0)))
+		case fieldTypeBool:
+			p.AppendField(m.labels[i], v > 0)
+		case fieldTypeString:
+			n := int64(len(m.choices[i]))
+			p.AppendField(m.labels[i], m.choices[i][(int64(math.Floor(v))%n+n)%n])
+		default:
+			p.AppendField(m.labels[i], v)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package custom
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+func TestNewGeneratorConstructor(t *testing.T) {
+	rand.Seed(123)
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	g := NewGeneratorConstructor(s)(7, now).(*Generator)
+
+	tags := g.Tags()
+	if got := len(tags); got != 3 {
+		t.Fatalf("incorrect number of tags: got %d want %d", got, 3)
+	}
+	if got := tags[0].Value; got != "sensor_7" {
+		t.Errorf("incorrect formatted tag: got %v want sensor_7", got)
+	}
+	if got := tags[1].Value; got != "a" && got != "b" {
+		t.Errorf("tag value not from the pool: got %v", got)
+	}
+	switch tags[2].Value {
+	case "line_0", "line_1", "line_2":
+	default:
+		t.Errorf("tag value not within the cardinality: got %v", tags[2].Value)
+	}
+
+	ms := g.Measurements()
+	if got := len(ms); got != 1 {
+		t.Fatalf("incorrect number of measurements: got %d want %d", got, 1)
+	}
+	p := data.NewPoint()
+	ms[0].ToPoint(p)
+	if got := string(p.MeasurementName()); got != "environment" {
+		t.Errorf("incorrect measurement name: got %s want environment", got)
+	}
+	values := p.FieldValues()
+	if got, ok := values[0].(float64); !ok || got != 20 {
+		t.Errorf("incorrect float field: got %#v want 20.0", values[0])
+	}
+	if got, ok := values[1].(int64); !ok || got != 0 {
+		t.Errorf("incorrect int field: got %#v want int64(0)", values[1])
+	}
+	if got, ok := values[2].(uint64); !ok || got != 0 {
+		t.Errorf("incorrect uint field: got %#v want uint64(0)", values[2])
+	}
+	if got, ok := values[3].(bool); !ok || !got {
+		t.Errorf("incorrect bool field: got %#v want true", values[3])
+	}
+	if got, ok := values[4].(string); !ok || got != "idle" {
+		t.Errorf("incorrect string field: got %#v want idle", values[4])
+	}
+
+	g.TickAll(time.Second)
+	p.Reset()
+	ms[0].ToPoint(p)
+	if got := *p.Timestamp(); !got.Equal(now.Add(time.Second)) {
+		t.Errorf("measurement not ticked: got %v want %v", got, now.Add(time.Second))
+	}
+	if got := p.FieldValues()[1]; got != int64(2) {
+		t.Errorf("incorrect int field after tick: got %#v want int64(2)", got)
+	}
+	if got := p.FieldValues()[4]; got != "busy" {
+		t.Errorf("incorrect string field after tick: got %#v want busy", got)
+	}
+}
+
+func TestFieldSpecChoices(t *testing.T) {
+	f := &FieldSpec{Name: "mode", Type: fieldTypeString, Cardinality: 3}
+	got := f.choices()
+	if len(got) != 3 || got[0] != "mode_0" || got[2] != "mode_2" {
+		t.Errorf("incorrect choices for a cardinality: got %v", got)
+	}
+	f = &FieldSpec{Name: "mode", Type: fieldTypeString, Values: []string{"on", "off"}}
+	if got := f.choices(); len(got) != 2 || got[1] != "off" {
+		t.Errorf("incorrect choices for values: got %v", got)
+	}
+}
+
+func TestDistributionSpecNew(t *testing.T) {
+	state := 5.0
+	cases := []struct {
+		spec DistributionSpec
+		want interface{}
+	}{
+		{DistributionSpec{Type: distND}, &common.NormalDistribution{}},
+		{DistributionSpec{Type: distUD, High: 1}, &common.UniformDistribution{}},
+		{DistributionSpec{Type: distWD, Step: &DistributionSpec{Type: distND}}, &common.RandomWalkDistribution{}},
+		{DistributionSpec{Type: distCWD, Max: 1, Step: &DistributionSpec{Type: distND}}, &common.ClampedRandomWalkDistribution{}},
+		{DistributionSpec{Type: distMWD, State: &state, Step: &DistributionSpec{Type: distND}}, &common.MonotonicRandomWalkDistribution{}},
+		{DistributionSpec{Type: distConst, Value: 3}, &common.ConstantDistribution{}},
+		{DistributionSpec{Type: distFP, Precision: 2, Step: &DistributionSpec{Type: distND}}, &common.FloatPrecision{}},
+		{DistributionSpec{Type: distLD, Motive: &DistributionSpec{Type: distND}, Step: &DistributionSpec{Type: distND}}, &common.LazyDistribution{}},
+		{DistributionSpec{Type: distSD, Amplitude: 0.5, Step: &DistributionSpec{Type: distND}}, &common.SeasonalDistribution{}},
+		{DistributionSpec{Type: distSPD, Rate: 0.1, Magnitude: &DistributionSpec{Type: distND}, Step: &DistributionSpec{Type: distND}}, &common.SpikeDistribution{}},
+		{DistributionSpec{Type: distSCD, Chance: 0.1, Level: &DistributionSpec{Type: distND}, Step: &DistributionSpec{Type: distND}}, &common.StepChangeDistribution{}},
+	}
+
+	for _, c := range cases {
+		if err := c.spec.validate(); err != nil {
+			t.Errorf("%s: unexpected validation error: %v", c.spec.Type, err)
+			continue
+		}
+		got := c.spec.New()
+		if gotType, wantType := typeName(got), typeName(c.want); gotType != wantType {
+			t.Errorf("%s: incorrect distribution: got %s want %s", c.spec.Type, gotType, wantType)
+		}
+	}
+}
+
+func TestSeasonalDistributionSpec(t *testing.T) {
+	s, err := ParseSchema([]byte("measurements: [{name: m, fields: [{name: f, distribution: {type: SD, amplitude: 0.2, period: 12h, phase: 6h, step: {type: CONST, value: 10}}}]}]"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sd, ok := s.Measurements[0].Fields[0].Distribution.New().(*common.SeasonalDistribution)
+	if !ok {
+		t.Fatalf("incorrect distribution: got %s", typeName(sd))
+	}
+	if sd.Period != 12*time.Hour || sd.Phase != 6*time.Hour || sd.Amplitude != 0.2 {
+		t.Errorf("incorrect seasonal distribution: got period %v, phase %v, amplitude %v", sd.Period, sd.Phase, sd.Amplitude)
+	}
+
+	start := time.Date(2022, 1, 1, 6, 0, 0, 0, time.UTC)
+	g := NewGeneratorConstructor(s)(0, start)
+	p := data.NewPoint()
+	g.Measurements()[0].ToPoint(p)
+	if got := p.FieldValues()[0]; got != 12.0 {
+		t.Errorf("incorrect value at the peak: got %v want %v", got, 12.0)
+	}
+}
+
+func TestSimulatorConfigInterval(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	sc := &SimulatorConfig{
+		BaseSimulatorConfig: &common.BaseSimulatorConfig{
+			Start:              now,
+			End:                now.Add(time.Minute),
+			InitGeneratorScale: 2,
+			GeneratorScale:     2,
+		},
+		Schema: s,
+	}
+	// the 30s interval of the schema overrides the given one
+	sim := sc.NewSimulator(time.Second, 0)
+	points := 0
+	p := data.NewPoint()
+	for !sim.Finished() {
+		sim.Next(p)
+		p.Reset()
+		points++
+	}
+	if want := 2 * 2; points != want {
+		t.Errorf("incorrect number of points: got %d want %d", points, want)
+	}
+	if got := sim.Fields()["environment"]; len(got) != 5 || got[0] != "temperature" || got[1] != "count" {
+		t.Errorf("incorrect fields: got %v", got)
+	}
+	want := []string{"float64", "int64", "uint64", "bool", "string"}
+	if got := sim.Headers().FieldTypes["environment"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect field types: got %v want %v", got, want)
+	}
+}
+
+func typeName(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,209 @@
+package custom
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	fieldTypeFloat  = "float"
+	fieldTypeInt    = "int"
+	fieldTypeUint   = "uint"
+	fieldTypeBool   = "bool"
+	fieldTypeString = "string"
+
+	errNoSchemaFile        = "custom use case needs a schema file, see --custom-schema"
+	errNoMeasurements      = "schema declares no measurements"
+	errNoTagKey            = "tag %d has no key"
+	errDuplicateTagKeyFmt  = "tag '%s' declared more than once"
+	errTagValuesFmt        = "tag '%s' needs exactly one of values, cardinality or format"
+	errNoMeasurementName   = "measurement %d has no name"
+	errDuplicateMeasureFmt = "measurement '%s' declared more than once"
+	errNoFieldsFmt         = "measurement '%s' declares no fields"
+	errNoFieldNameFmt      = "field %d of measurement '%s' has no name"
+	errDuplicateFieldFmt   = "field '%s' of measurement '%s' declared more than once"
+	errFieldTypeFmt        = "field '%s' of measurement '%s' has an unknown type '%s' (choices: float, int, uint, bool, string)"
+	errFieldValuesFmt      = "string field '%s' of measurement '%s' needs exactly one of values or cardinality"
+	errFieldNotStringFmt   = "field '%s' of measurement '%s' is not a string, it cannot have values or a cardinality"
+	errFieldDistFmt        = "field '%s' of measurement '%s': %v"
+	errBadIntervalFmt      = "cannot parse interval '%s': %v"
+)
+
+// Schema declares a custom use case: the tags of every simulated generator
+// (e.g. a sensor), the measurements each one of them reports and how the
+// values of their fields evolve.
+//
+// e.g.
+//
+//	interval: 10s
+//	tags:
+//	  - key: sensor_id
+//	    format: sensor_%d
+//	  - key: site
+//	    values: [north, south]
+//	  - key: rack
+//	    cardinality: 20
+//	measurements:
+//	  - name: environment
+//	    fields:
+//	      - name: temperature
+//	        distribution:
+//	          type: CWD
+//	          step: {type: ND, mean: 0, stddev: 0.5}
+//	          min: -20
+//	          max: 50
+type Schema struct {
+	// Interval is the time between two readings of a generator. It
+	// overrides --log-interval when set.
+	Interval     string            `yaml:"interval,omitempty"`
+	Tags         []TagSpec         `yaml:"tags"`
+	Measurements []MeasurementSpec `yaml:"measurements"`
+	interval     time.Duration
+}
+
+// TagSpec declares a tag of every generator. Exactly one of Values,
+// Cardinality and Format sets how the generators get their value.
+type TagSpec struct {
+	Key string `yaml:"key"`
+	// Values is a pool the value of each generator is randomly chosen from.
+	Values []string `yaml:"values,omitempty"`
+	// Cardinality is the number of distinct values, named <key>_<n>, the
+	// value of each generator is randomly chosen from.
+	Cardinality int `yaml:"cardinality,omitempty"`
+	// Format is formatted with the id of the generator, e.g. "sensor_%d",
+	// so every generator has its own value.
+	Format string `yaml:"format,omitempty"`
+}
+
+// MeasurementSpec declares a measurement reported by every generator.
+type MeasurementSpec struct {
+	Name   string      `yaml:"name"`
+	Fields []FieldSpec `yaml:"fields"`
+}
+
+// FieldSpec declares a field of a measurement.
+type FieldSpec struct {
+	Name string `yaml:"name"`
+	// Type is the type of the reported values: float (default), int, uint,
+	// bool or string. The values are converted from the distribution: uint
+	// values are clamped to 0, bool values are true when positive and string
+	// values are one of Values, or of <name>_<n> for Cardinality, picked by
+	// the integer part of the value modulo the number of choices.
+	Type         string           `yaml:"type,omitempty"`
+	Values       []string         `yaml:"values,omitempty"`
+	Cardinality  int              `yaml:"cardinality,omitempty"`
+	Distribution DistributionSpec `yaml:"distribution"`
+}
+
+// LoadSchema reads and validates the schema in the YAML file at path.
+func LoadSchema(path string) (*Schema, error) {
+	if path == "" {
+		return nil, fmt.Errorf(errNoSchemaFile)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read schema file: %v", err)
+	}
+	return ParseSchema(b)
+}
+
+// ParseSchema parses and validates a YAML schema.
+func ParseSchema(b []byte) (*Schema, error) {
+	s := &Schema{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, fmt.Errorf("cannot parse schema: %v", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks that the schema is complete and consistent.
+func (s *Schema) Validate() error {
+	if s.Interval != "" {
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil || d <= 0 {
+			if err == nil {
+				err = fmt.Errorf("has to be positive")
+			}
+			return fmt.Errorf(errBadIntervalFmt, s.Interval, err)
+		}
+		s.interval = d
+	}
+
+	keys := make(map[string]bool)
+	for i, t := range s.Tags {
+		if t.Key == "" {
+			return fmt.Errorf(errNoTagKey, i)
+		}
+		if keys[t.Key] {
+			return fmt.Errorf(errDuplicateTagKeyFmt, t.Key)
+		}
+		keys[t.Key] = true
+
+		set := 0
+		if len(t.Values) > 0 {
+			set++
+		}
+		if t.Cardinality > 0 {
+			set++
+		}
+		if t.Format != "" {
+			set++
+		}
+		if set != 1 || t.Cardinality < 0 {
+			return fmt.Errorf(errTagValuesFmt, t.Key)
+		}
+	}
+
+	if len(s.Measurements) == 0 {
+		return fmt.Errorf(errNoMeasurements)
+	}
+	names := make(map[string]bool)
+	for i, m := range s.Measurements {
+		if m.Name == "" {
+			return fmt.Errorf(errNoMeasurementName, i)
+		}
+		if names[m.Name] {
+			return fmt.Errorf(errDuplicateMeasureFmt, m.Name)
+		}
+		names[m.Name] = true
+
+		if len(m.Fields) == 0 {
+			return fmt.Errorf(errNoFieldsFmt, m.Name)
+		}
+		fields := make(map[string]bool)
+		for j := range m.Fields {
+			f := &m.Fields[j]
+			if f.Name == "" {
+				return fmt.Errorf(errNoFieldNameFmt, j, m.Name)
+			}
+			if fields[f.Name] {
+				return fmt.Errorf(errDuplicateFieldFmt, f.Name, m.Name)
+			}
+			fields[f.Name] = true
+
+			switch f.Type {
+			case "", fieldTypeFloat, fieldTypeInt, fieldTypeUint, fieldTypeBool:
+				if len(f.Values) > 0 || f.Cardinality != 0 {
+					return fmt.Errorf(errFieldNotStringFmt, f.Name, m.Name)
+				}
+			case fieldTypeString:
+				if (len(f.Values) > 0) == (f.Cardinality > 0) || f.Cardinality < 0 {
+					return fmt.Errorf(errFieldValuesFmt, f.Name, m.Name)
+				}
+			default:
+				return fmt.Errorf(errFieldTypeFmt, f.Name, m.Name, f.Type)
+			}
+			if err := f.Distribution.validate(); err != nil {
+				return fmt.Errorf(errFieldDistFmt, f.Name, m.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSchema = `
+interval: 30s
+tags:
+  - key: sensor_id
+    format: sensor_%d
+  - key: site
+    values: [a, b]
+  - key: line
+    cardinality: 3
+measurements:
+  - name: environment
+    fields:
+      - name: temperature
+        distribution:
+          type: CWD
+          step: {type: ND, mean: 0, stddev: 1}
+          min: -10
+          max: 40
+          state: 20
+      - name: count
+        type: int
+        distribution:
+          type: MWD
+          step: {type: CONST, value: 2}
+      - name: errors
+        type: uint
+        distribution: {type: CONST, value: -3}
+      - name: online
+        type: bool
+        distribution: {type: CONST, value: 1}
+      - name: mode
+        type: string
+        values: [idle, busy]
+        distribution:
+          type: MWD
+          step: {type: CONST, value: 1}
+`
+
+func TestParseSchema(t *testing.T) {
+	s, err := ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.interval != 30*time.Second {
+		t.Errorf("incorrect interval: got %v want %v", s.interval, 30*time.Second)
+	}
+	if got := len(s.Tags); got != 3 {
+		t.Errorf("incorrect number of tags: got %d want %d", got, 3)
+	}
+	if got := len(s.Measurements); got != 1 {
+		t.Fatalf("incorrect number of measurements: got %d want %d", got, 1)
+	}
+	fields := s.Measurements[0].Fields
+	if got := fields[0].Distribution.Step.StdDev; got != 1 {
+		t.Errorf("incorrect nested distribution stddev: got %v want %v", got, 1)
+	}
+	if got := fields[1].Type; got != fieldTypeInt {
+		t.Errorf("incorrect field type: got %s want %s", got, fieldTypeInt)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	cases := []struct {
+		desc    string
+		schema  string
+		wantErr string
+	}{
+		{
+			desc:    "unknown key",
+			schema:  "measurement: []",
+			wantErr: "cannot parse schema",
+		},
+		{
+			desc:    "bad interval",
+			schema:  "interval: soon\nmeasurements: [{name: m, fields: [{name: f, distribution: {type: ND}}]}]",
+			wantErr: "cannot parse interval 'soon'",
+		},
+		{
+			desc:    "no measurements",
+			schema:  "tags: [{key: a, values: [x]}]",
+			wantErr: errNoMeasurements,
+		},
+		{
+			desc:    "tag without values",
+			schema:  "tags: [{key: a}]\nmeasurements: [{name: m, fields: [{name: f, distribution: {type: ND}}]}]",
+			wantErr: "tag 'a' needs exactly one of values, cardinality or format",
+		},
+		{
+			desc:    "tag with two value sources",
+			schema:  "tags: [{key: a, values: [x], cardinality: 2}]\nmeasurements: [{name: m, fields: [{name: f, distribution: {type: ND}}]}]",
+			wantErr: "tag 'a' needs exactly one of values, cardinality or format",
+		},
+		{
+			desc:    "duplicate tag",
+			schema:  "tags: [{key: a, values: [x]}, {key: a, values: [y]}]\nmeasurements: [{name: m, fields: [{name: f, distribution: {type: ND}}]}]",
+			wantErr: "tag 'a' declared more than once",
+		},
+		{
+			desc:    "measurement without fields",
+			schema:  "measurements: [{name: m}]",
+			wantErr: "measurement 'm' declares no fields",
+		},
+		{
+			desc:    "duplicate field",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: ND}}, {name: f, distribution: {type: ND}}]}]",
+			wantErr: "field 'f' of measurement 'm' declared more than once",
+		},
+		{
+			desc:    "unknown field type",
+			schema:  "measurements: [{name: m, fields: [{name: f, type: text, distribution: {type: ND}}]}]",
+			wantErr: "field 'f' of measurement 'm' has an unknown type 'text'",
+		},
+		{
+			desc:    "string field without values",
+			schema:  "measurements: [{name: m, fields: [{name: f, type: string, distribution: {type: ND}}]}]",
+			wantErr: "string field 'f' of measurement 'm' needs exactly one of values or cardinality",
+		},
+		{
+			desc:    "string field with two value sources",
+			schema:  "measurements: [{name: m, fields: [{name: f, type: string, values: [x], cardinality: 2, distribution: {type: ND}}]}]",
+			wantErr: "string field 'f' of measurement 'm' needs exactly one of values or cardinality",
+		},
+		{
+			desc:    "values of a non string field",
+			schema:  "measurements: [{name: m, fields: [{name: f, type: int, values: [x], distribution: {type: ND}}]}]",
+			wantErr: "field 'f' of measurement 'm' is not a string, it cannot have values or a cardinality",
+		},
+		{
+			desc:    "unknown distribution",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: XD}}]}]",
+			wantErr: "unknown distribution type 'XD'",
+		},
+		{
+			desc:    "missing step",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: CWD, max: 1}}]}]",
+			wantErr: "CWD distribution needs a step",
+		},
+		{
+			desc:    "bad nested distribution",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: FP, step: {type: UD, low: 2, high: 1}}}]}]",
+			wantErr: "UD high cannot be lower than low",
+		},
+		{
+			desc:    "bad seasonal period",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: SD, period: -1h, step: {type: ND}}}]}]",
+			wantErr: "SD period has to be a positive duration, got '-1h'",
+		},
+		{
+			desc:    "spikes without magnitude",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: SPD, rate: 0.1, step: {type: ND}}}]}]",
+			wantErr: "SPD distribution needs a magnitude",
+		},
+		{
+			desc:    "bad step change chance",
+			schema:  "measurements: [{name: m, fields: [{name: f, distribution: {type: SCD, chance: 2, level: {type: ND}, step: {type: ND}}}]}]",
+			wantErr: "SCD chance has to be between 0 and 1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := ParseSchema([]byte(c.schema))
+			if err == nil {
+				t.Fatalf("unexpected lack of error")
+			}
+			if !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("incorrect error: got\n%s\nwant it to contain\n%s", err.Error(), c.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	if _, err := LoadSchema(""); err == nil || err.Error() != errNoSchemaFile {
+		t.Errorf("incorrect error for no schema file: got %v want %s", err, errNoSchemaFile)
+	}
+	if _, err := LoadSchema("/does/not/exist.yaml"); err == nil {
+		t.Errorf("unexpected lack of error for missing schema file")
+	}
+	if _, err := LoadSchema("../../../../docs/sample-configs/custom-sensor-fleet.yaml"); err != nil {
+		t.Errorf("unexpected error for the sample schema: %v", err)
+	}
+}
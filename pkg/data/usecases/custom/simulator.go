@@ -0,0 +1,26 @@
+package custom
+
+import (
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a Simulator for a custom use case.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	*common.BaseSimulatorConfig
+	Schema *Schema
+}
+
+// NewSimulator produces a Simulator of the schema generators over the
+// specified interval and points limit. The interval of the schema, when set,
+// takes precedence over the given one.
+func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	if sc.Schema.interval > 0 {
+		interval = sc.Schema.interval
+	}
+	c := *sc.BaseSimulatorConfig
+	c.GeneratorConstructor = NewGeneratorConstructor(sc.Schema)
+	return c.NewSimulator(interval, limit)
+}
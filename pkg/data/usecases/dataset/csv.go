@@ -0,0 +1,155 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// csvReader reads the rows of a CSV dataset whose first row names the
+// columns.
+type csvReader struct {
+	r       *csv.Reader
+	m       *Mapping
+	line    int
+	columns int
+
+	timeIndex        int
+	measurementIndex int
+	tagIndexes       []int
+	fieldKeys        []string
+	fieldIndexes     []int
+}
+
+func newCSVReader(r io.Reader, m *Mapping) (*csvReader, error) {
+	cr := &csvReader{
+		r:                csv.NewReader(r),
+		m:                m,
+		measurementIndex: -1,
+	}
+	cr.r.FieldsPerRecord = -1
+	cr.r.TrimLeadingSpace = true
+
+	header, err := cr.r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf(errNoRecords)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read dataset: %v", err)
+	}
+	cr.line = 1
+	cr.columns = len(header)
+
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		indexes[name] = i
+	}
+	index := func(name string) (int, error) {
+		i, ok := indexes[name]
+		if !ok {
+			return 0, fmt.Errorf(errNoColumnFmt, name)
+		}
+		return i, nil
+	}
+
+	if cr.timeIndex, err = index(m.TimeColumn); err != nil {
+		return nil, err
+	}
+	used := map[string]bool{m.TimeColumn: true}
+	if m.MeasurementColumn != "" {
+		if cr.measurementIndex, err = index(m.MeasurementColumn); err != nil {
+			return nil, err
+		}
+		used[m.MeasurementColumn] = true
+	}
+	for _, tag := range m.Tags {
+		i, err := index(tag)
+		if err != nil {
+			return nil, err
+		}
+		cr.tagIndexes = append(cr.tagIndexes, i)
+		used[tag] = true
+	}
+
+	cr.fieldKeys = m.Fields
+	if len(cr.fieldKeys) == 0 {
+		for _, name := range header {
+			if !used[name] {
+				cr.fieldKeys = append(cr.fieldKeys, name)
+			}
+		}
+	}
+	for _, field := range cr.fieldKeys {
+		i, err := index(field)
+		if err != nil {
+			return nil, err
+		}
+		cr.fieldIndexes = append(cr.fieldIndexes, i)
+	}
+	return cr, nil
+}
+
+// next returns the next row with at least one field value. Empty cells are
+// missing tags or fields.
+func (cr *csvReader) next() (*record, error) {
+	for {
+		row, err := cr.r.Read()
+		if err == io.EOF {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read dataset: %v", err)
+		}
+		cr.line++
+		if len(row) != cr.columns {
+			return nil, fmt.Errorf(errRowColumnCountFmt, cr.line, len(row), cr.columns)
+		}
+
+		rec := &record{
+			measurement: cr.m.Measurement,
+			tags:        make(map[string]string, len(cr.tagIndexes)),
+			fields:      make(map[string]value, len(cr.fieldIndexes)),
+		}
+		rec.timestamp, err = parseTime(row[cr.timeIndex], cr.m.TimeFormat)
+		if err != nil {
+			return nil, fmt.Errorf(errBadTimestampFmt, cr.line, row[cr.timeIndex], err)
+		}
+		if cr.measurementIndex >= 0 && row[cr.measurementIndex] != "" {
+			rec.measurement = row[cr.measurementIndex]
+		}
+		for i, index := range cr.tagIndexes {
+			if row[index] == "" {
+				continue
+			}
+			rec.tagKeys = append(rec.tagKeys, cr.m.Tags[i])
+			rec.tags[cr.m.Tags[i]] = row[index]
+		}
+		for i, index := range cr.fieldIndexes {
+			if row[index] == "" {
+				continue
+			}
+			rec.fieldKeys = append(rec.fieldKeys, cr.fieldKeys[i])
+			rec.fields[cr.fieldKeys[i]] = value{raw: row[index], kind: csvKind(row[index])}
+		}
+		if len(rec.fieldKeys) > 0 {
+			return rec, nil
+		}
+	}
+}
+
+// csvKind tells the type of a CSV value by the most specific one it parses as.
+func csvKind(raw string) string {
+	if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return common.FieldTypeInt64
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return common.FieldTypeFloat64
+	}
+	if _, err := parseBool(raw); err == nil {
+		return common.FieldTypeBool
+	}
+	return common.FieldTypeString
+}
@@ -0,0 +1,343 @@
+package dataset
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const (
+	// Dataset format choices
+	FormatCSV    = "csv"
+	FormatInflux = "influx"
+
+	// TimeFormatRFC3339 is the time format of RFC3339 timestamps, the other
+	// time formats are Unix times in one of the common.PrecisionChoices units.
+	TimeFormatRFC3339 = "rfc3339"
+
+	defaultMeasurement = "dataset"
+	defaultTimeColumn  = "time"
+
+	errNoDatasetFile     = "dataset use case needs a dataset file, see --dataset"
+	errBadFormatFmt      = "invalid dataset format specified: '%v' (choices: csv, influx)"
+	errBadTimeFormatFmt  = "invalid dataset time format specified: '%v' (choices: rfc3339, s, ms, us, ns)"
+	errNoRecords         = "dataset has no records with fields"
+	errNoColumnFmt       = "dataset has no column '%s'"
+	errCopiesNeedTag     = "dataset series can only be multiplied when they have a tag, see --dataset-tags"
+	errBadTimestampFmt   = "line %d: cannot parse timestamp '%s': %v"
+	errNoTimestampFmt    = "line %d: no timestamp"
+	errBadLineFmt        = "line %d: %s"
+	errRowColumnCountFmt = "line %d: got %d columns, the header has %d"
+)
+
+var FormatChoices = []string{
+	FormatCSV,
+	FormatInflux,
+}
+
+// Mapping tells how the records of a dataset map to points.
+type Mapping struct {
+	// Format of the dataset, FormatCSV or FormatInflux (line protocol). When
+	// empty, it is told by the file extension: CSV for .csv, line protocol
+	// otherwise.
+	Format string
+	// Measurement is the measurement of the CSV rows, unless
+	// MeasurementColumn is set.
+	Measurement string
+	// MeasurementColumn is the CSV column holding the measurement of a row.
+	MeasurementColumn string
+	// TimeColumn is the CSV column holding the timestamp of a row.
+	TimeColumn string
+	// TimeFormat is how the timestamps are written, TimeFormatRFC3339 or the
+	// unit of a Unix time. Defaults to RFC3339 for CSV and nanoseconds for
+	// line protocol.
+	TimeFormat string
+	// Tags are the CSV columns, or line protocol tags, kept as tags. All
+	// line protocol tags are kept when empty.
+	Tags []string
+	// Fields are the CSV columns, or line protocol fields, kept as fields.
+	// When empty, every CSV column that is not the measurement, the time or a
+	// tag is a field, and all line protocol fields are kept.
+	Fields []string
+}
+
+// value is a field value as it is written in the dataset, with the type told
+// by its own syntax.
+type value struct {
+	raw  string
+	kind string
+}
+
+// record is a row of a CSV file or a line of line protocol. The keys of its
+// tags and fields are kept in the order they are written in.
+type record struct {
+	measurement string
+	tagKeys     []string
+	tags        map[string]string
+	fieldKeys   []string
+	fields      map[string]value
+	timestamp   time.Time
+}
+
+// recordReader reads the records of a dataset one by one, returning io.EOF
+// after the last one.
+type recordReader interface {
+	next() (*record, error)
+}
+
+type opener func() (io.ReadCloser, error)
+
+// Dataset is an external dataset, e.g. an export of a production database,
+// whose records are turned into points. Opening it reads it once to learn its
+// tags, measurements, fields and their types, and its first timestamp.
+type Dataset struct {
+	mapping Mapping
+	open    opener
+
+	tagKeys    []string
+	fieldKeys  map[string][]string
+	fieldTypes map[string][]string
+	start      time.Time
+	records    uint64
+}
+
+// Open reads the dataset at path, gzipped when it ends with .gz, according to
+// the mapping m.
+func Open(path string, m Mapping) (*Dataset, error) {
+	if path == "" {
+		return nil, fmt.Errorf(errNoDatasetFile)
+	}
+	if m.Format == "" {
+		m.Format = FormatInflux
+		if filepath.Ext(strings.TrimSuffix(path, ".gz")) == ".csv" {
+			m.Format = FormatCSV
+		}
+	}
+	return newDataset(m, fileOpener(path))
+}
+
+func fileOpener(path string) opener {
+	return func() (io.ReadCloser, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open dataset: %v", err)
+		}
+		if !strings.HasSuffix(path, ".gz") {
+			return f, nil
+		}
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("cannot open dataset: %v", err)
+		}
+		return &gzipFile{Reader: gz, file: f}, nil
+	}
+}
+
+// gzipFile closes both the gzip reader and its file.
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *gzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+func newDataset(m Mapping, open opener) (*Dataset, error) {
+	if !utils.IsIn(m.Format, FormatChoices) {
+		return nil, fmt.Errorf(errBadFormatFmt, m.Format)
+	}
+	if m.Measurement == "" {
+		m.Measurement = defaultMeasurement
+	}
+	if m.TimeColumn == "" {
+		m.TimeColumn = defaultTimeColumn
+	}
+	if m.TimeFormat == "" {
+		m.TimeFormat = TimeFormatRFC3339
+		if m.Format == FormatInflux {
+			m.TimeFormat = common.PrecisionNanoseconds
+		}
+	}
+	if m.TimeFormat != TimeFormatRFC3339 && !utils.IsIn(m.TimeFormat, common.PrecisionChoices) {
+		return nil, fmt.Errorf(errBadTimeFormatFmt, m.TimeFormat)
+	}
+
+	d := &Dataset{
+		mapping:    m,
+		open:       open,
+		tagKeys:    append([]string(nil), m.Tags...),
+		fieldKeys:  make(map[string][]string),
+		fieldTypes: make(map[string][]string),
+	}
+	if err := d.scan(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// scan reads the whole dataset to learn its schema and first timestamp.
+func (d *Dataset) scan() error {
+	rc, r, err := d.reader()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	knownTags := make(map[string]bool)
+	for _, k := range d.tagKeys {
+		knownTags[k] = true
+	}
+	fieldIndexes := make(map[string]map[string]int)
+	for {
+		rec, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, k := range rec.tagKeys {
+			if !knownTags[k] {
+				knownTags[k] = true
+				d.tagKeys = append(d.tagKeys, k)
+			}
+		}
+
+		indexes, ok := fieldIndexes[rec.measurement]
+		if !ok {
+			indexes = make(map[string]int)
+			fieldIndexes[rec.measurement] = indexes
+		}
+		for _, k := range rec.fieldKeys {
+			v := rec.fields[k]
+			i, ok := indexes[k]
+			if !ok {
+				i = len(d.fieldKeys[rec.measurement])
+				indexes[k] = i
+				d.fieldKeys[rec.measurement] = append(d.fieldKeys[rec.measurement], k)
+				d.fieldTypes[rec.measurement] = append(d.fieldTypes[rec.measurement], "")
+			}
+			d.fieldTypes[rec.measurement][i] = mergeKinds(d.fieldTypes[rec.measurement][i], v.kind)
+		}
+
+		if d.records == 0 || rec.timestamp.Before(d.start) {
+			d.start = rec.timestamp
+		}
+		d.records++
+	}
+
+	if d.records == 0 {
+		return fmt.Errorf(errNoRecords)
+	}
+	return nil
+}
+
+// reader opens the dataset and returns a reader of its records.
+func (d *Dataset) reader() (io.Closer, recordReader, error) {
+	rc, err := d.open()
+	if err != nil {
+		return nil, nil, err
+	}
+	var r recordReader
+	switch d.mapping.Format {
+	case FormatCSV:
+		r, err = newCSVReader(rc, &d.mapping)
+	default:
+		r = newLineProtocolReader(rc, &d.mapping)
+	}
+	if err != nil {
+		rc.Close()
+		return nil, nil, err
+	}
+	return rc, r, nil
+}
+
+// Headers returns the tags, fields and field types of the dataset.
+func (d *Dataset) Headers() *common.GeneratedDataHeaders {
+	tagTypes := make([]string, len(d.tagKeys))
+	for i := range tagTypes {
+		tagTypes[i] = "string"
+	}
+	return &common.GeneratedDataHeaders{
+		TagTypes:   tagTypes,
+		TagKeys:    d.tagKeys,
+		FieldKeys:  d.fieldKeys,
+		FieldTypes: d.fieldTypes,
+	}
+}
+
+// parseTime parses a timestamp written in the given time format.
+func parseTime(s, format string) (time.Time, error) {
+	if format == TimeFormatRFC3339 {
+		return time.Parse(time.RFC3339Nano, s)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, n*int64(common.PrecisionDuration(format))).UTC(), nil
+}
+
+// mergeKinds returns the type of a field whose values have the types a and b.
+// Mixed numbers are float64, any other mix is string.
+func mergeKinds(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case isNumeric(a) && isNumeric(b):
+		return common.FieldTypeFloat64
+	default:
+		return common.FieldTypeString
+	}
+}
+
+func isNumeric(kind string) bool {
+	return kind == common.FieldTypeFloat64 || kind == common.FieldTypeInt64 || kind == common.FieldTypeUint64
+}
+
+// convert returns the raw value of a field as a value of the field type, or
+// nil when it does not parse.
+func convert(raw, fieldType string) interface{} {
+	var v interface{}
+	var err error
+	switch fieldType {
+	case common.FieldTypeInt64:
+		v, err = strconv.ParseInt(raw, 10, 64)
+	case common.FieldTypeUint64:
+		v, err = strconv.ParseUint(raw, 10, 64)
+	case common.FieldTypeBool:
+		v, err = parseBool(raw)
+	case common.FieldTypeString:
+		v = raw
+	default:
+		v, err = strconv.ParseFloat(raw, 64)
+	}
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// parseBool parses the booleans of line protocol, which are a superset of
+// the ones of CSV.
+func parseBool(s string) (bool, error) {
+	switch s {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean '%s'", s)
+}
@@ -0,0 +1,357 @@
+package dataset
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const testCSV = `time,host,region,kind,temp,status,state
+2021-06-01T00:00:10Z,a,eu,env,21.5,1,run
+2021-06-01T00:00:00Z,b,,env,22,2,idle
+2021-06-01T00:00:20Z,a,eu,power,,3,run
+2021-06-01T00:00:30Z,b,us,env,,,
+`
+
+const testLineProtocol = `# exported from production
+cpu,host=a,region=eu usage=1.5,count=3i,msg="hi, \"there\"" 1622505600000000000
+mem,host=b free=10u,ok=t 1622505610000000000
+
+cpu,host=b,zone=z\ 1 usage=2,count=4.5 1622505620000000000
+`
+
+func stringOpener(s string) opener {
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+func TestNewDatasetCSV(t *testing.T) {
+	d, err := newDataset(Mapping{
+		Format:            FormatCSV,
+		MeasurementColumn: "kind",
+		Tags:              []string{"host", "region"},
+	}, stringOpener(testCSV))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h := d.Headers()
+	if want := []string{"host", "region"}; !reflect.DeepEqual(h.TagKeys, want) {
+		t.Errorf("incorrect tag keys: got %v want %v", h.TagKeys, want)
+	}
+	if want := []string{"string", "string"}; !reflect.DeepEqual(h.TagTypes, want) {
+		t.Errorf("incorrect tag types: got %v want %v", h.TagTypes, want)
+	}
+	wantFields := map[string][]string{
+		"env":   {"temp", "status", "state"},
+		"power": {"status", "state"},
+	}
+	if !reflect.DeepEqual(h.FieldKeys, wantFields) {
+		t.Errorf("incorrect field keys: got %v want %v", h.FieldKeys, wantFields)
+	}
+	wantTypes := map[string][]string{
+		"env":   {common.FieldTypeFloat64, common.FieldTypeInt64, common.FieldTypeString},
+		"power": {common.FieldTypeInt64, common.FieldTypeString},
+	}
+	if !reflect.DeepEqual(h.FieldTypes, wantTypes) {
+		t.Errorf("incorrect field types: got %v want %v", h.FieldTypes, wantTypes)
+	}
+	if want := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC); !d.start.Equal(want) {
+		t.Errorf("incorrect start: got %v want %v", d.start, want)
+	}
+	// the last row has no field values
+	if d.records != 3 {
+		t.Errorf("incorrect number of records: got %d want 3", d.records)
+	}
+}
+
+func TestNewDatasetCSVFields(t *testing.T) {
+	d, err := newDataset(Mapping{
+		Format:      FormatCSV,
+		Measurement: "sensors",
+		Fields:      []string{"status", "temp"},
+	}, stringOpener(testCSV))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(d.tagKeys) != 0 {
+		t.Errorf("unexpected tag keys: %v", d.tagKeys)
+	}
+	want := map[string][]string{"sensors": {"status", "temp"}}
+	if !reflect.DeepEqual(d.fieldKeys, want) {
+		t.Errorf("incorrect field keys: got %v want %v", d.fieldKeys, want)
+	}
+}
+
+func TestNewDatasetLineProtocol(t *testing.T) {
+	d, err := newDataset(Mapping{Format: FormatInflux}, stringOpener(testLineProtocol))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"host", "region", "zone"}; !reflect.DeepEqual(d.tagKeys, want) {
+		t.Errorf("incorrect tag keys: got %v want %v", d.tagKeys, want)
+	}
+	wantFields := map[string][]string{
+		"cpu": {"usage", "count", "msg"},
+		"mem": {"free", "ok"},
+	}
+	if !reflect.DeepEqual(d.fieldKeys, wantFields) {
+		t.Errorf("incorrect field keys: got %v want %v", d.fieldKeys, wantFields)
+	}
+	// count is an integer, then a float
+	wantTypes := map[string][]string{
+		"cpu": {common.FieldTypeFloat64, common.FieldTypeFloat64, common.FieldTypeString},
+		"mem": {common.FieldTypeUint64, common.FieldTypeBool},
+	}
+	if !reflect.DeepEqual(d.fieldTypes, wantTypes) {
+		t.Errorf("incorrect field types: got %v want %v", d.fieldTypes, wantTypes)
+	}
+	if d.records != 3 {
+		t.Errorf("incorrect number of records: got %d want 3", d.records)
+	}
+
+	// mapped tags and fields only
+	d, err = newDataset(Mapping{
+		Format: FormatInflux,
+		Tags:   []string{"zone", "host"},
+		Fields: []string{"usage"},
+	}, stringOpener(testLineProtocol))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"zone", "host"}; !reflect.DeepEqual(d.tagKeys, want) {
+		t.Errorf("incorrect mapped tag keys: got %v want %v", d.tagKeys, want)
+	}
+	if want := map[string][]string{"cpu": {"usage"}}; !reflect.DeepEqual(d.fieldKeys, want) {
+		t.Errorf("incorrect mapped field keys: got %v want %v", d.fieldKeys, want)
+	}
+	if d.records != 2 {
+		t.Errorf("incorrect number of mapped records: got %d want 2", d.records)
+	}
+}
+
+func TestLineProtocolParse(t *testing.T) {
+	lr := newLineProtocolReader(strings.NewReader(testLineProtocol), &Mapping{TimeFormat: common.PrecisionNanoseconds})
+	rec, err := lr.next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.measurement != "cpu" || rec.tags["host"] != "a" || rec.tags["region"] != "eu" {
+		t.Errorf("incorrect series: got %s %v", rec.measurement, rec.tags)
+	}
+	if got := rec.fields["msg"]; got.raw != `hi, "there"` || got.kind != common.FieldTypeString {
+		t.Errorf("incorrect string field: got %+v", got)
+	}
+	if got := rec.fields["count"]; got.raw != "3" || got.kind != common.FieldTypeInt64 {
+		t.Errorf("incorrect integer field: got %+v", got)
+	}
+	if want := time.Unix(0, 1622505600000000000).UTC(); !rec.timestamp.Equal(want) {
+		t.Errorf("incorrect timestamp: got %v want %v", rec.timestamp, want)
+	}
+
+	rec, err = lr.next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rec, err = lr.next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rec.tags["zone"]; got != "z 1" {
+		t.Errorf("incorrect escaped tag value: got %q want %q", got, "z 1")
+	}
+	if _, err = lr.next(); err != io.EOF {
+		t.Errorf("incorrect error after the last line: got %v want EOF", err)
+	}
+}
+
+func TestNewDatasetErrors(t *testing.T) {
+	cases := []struct {
+		desc    string
+		mapping Mapping
+		input   string
+		wantErr string
+	}{
+		{
+			desc:    "bad format",
+			mapping: Mapping{Format: "parquet"},
+			wantErr: "invalid dataset format specified: 'parquet'",
+		},
+		{
+			desc:    "bad time format",
+			mapping: Mapping{Format: FormatCSV, TimeFormat: "m"},
+			wantErr: "invalid dataset time format specified: 'm'",
+		},
+		{
+			desc:    "empty CSV",
+			mapping: Mapping{Format: FormatCSV},
+			wantErr: errNoRecords,
+		},
+		{
+			desc:    "CSV without time column",
+			mapping: Mapping{Format: FormatCSV, TimeColumn: "ts"},
+			input:   testCSV,
+			wantErr: "dataset has no column 'ts'",
+		},
+		{
+			desc:    "CSV without tag column",
+			mapping: Mapping{Format: FormatCSV, Tags: []string{"rack"}},
+			input:   testCSV,
+			wantErr: "dataset has no column 'rack'",
+		},
+		{
+			desc:    "CSV row with missing columns",
+			mapping: Mapping{Format: FormatCSV},
+			input:   "time,temp\n2021-06-01T00:00:00Z\n",
+			wantErr: "line 2: got 1 columns, the header has 2",
+		},
+		{
+			desc:    "CSV bad timestamp",
+			mapping: Mapping{Format: FormatCSV, TimeFormat: common.PrecisionSeconds},
+			input:   testCSV,
+			wantErr: "line 2: cannot parse timestamp '2021-06-01T00:00:10Z'",
+		},
+		{
+			desc:    "CSV without fields",
+			mapping: Mapping{Format: FormatCSV},
+			input:   "time,temp\n2021-06-01T00:00:00Z,\n",
+			wantErr: errNoRecords,
+		},
+		{
+			desc:    "line protocol without timestamp",
+			mapping: Mapping{Format: FormatInflux},
+			input:   "cpu usage=1\n",
+			wantErr: "line 1: no timestamp",
+		},
+		{
+			desc:    "line protocol bad field",
+			mapping: Mapping{Format: FormatInflux},
+			input:   "cpu usage=abc 1\n",
+			wantErr: "line 1: field 'usage'",
+		},
+		{
+			desc:    "line protocol bad tag",
+			mapping: Mapping{Format: FormatInflux},
+			input:   "cpu,host usage=1 1\n",
+			wantErr: "line 1: invalid key=value pair 'host'",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			_, err := newDataset(c.mapping, stringOpener(c.input))
+			if err == nil {
+				t.Fatalf("unexpected lack of error")
+			}
+			if !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("incorrect error: got\n%s\nwant it to contain\n%s", err.Error(), c.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("", Mapping{}); err == nil || err.Error() != errNoDatasetFile {
+		t.Errorf("incorrect error for no dataset file: got %v want %s", err, errNoDatasetFile)
+	}
+	if _, err := Open("/does/not/exist.csv", Mapping{}); err == nil {
+		t.Errorf("unexpected lack of error for missing dataset file")
+	}
+	d, err := Open("../../../../docs/sample-configs/dataset-sensor-export.csv", Mapping{Tags: []string{"sensor_id"}})
+	if err != nil {
+		t.Fatalf("unexpected error for the sample dataset: %v", err)
+	}
+	if d.mapping.Format != FormatCSV || d.records != 6 {
+		t.Errorf("incorrect sample dataset: format %s, %d records", d.mapping.Format, d.records)
+	}
+
+	// gzipped line protocol
+	dir, err := ioutil.TempDir("", "dataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "export.lp.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte(testLineProtocol))
+	gz.Close()
+	f.Close()
+
+	d, err = Open(path, Mapping{})
+	if err != nil {
+		t.Fatalf("unexpected error for gzipped dataset: %v", err)
+	}
+	if d.mapping.Format != FormatInflux || d.records != 3 {
+		t.Errorf("incorrect gzipped dataset: format %s, %d records", d.mapping.Format, d.records)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2021, 6, 1, 0, 0, 1, 500000000, time.UTC)
+	cases := map[string]string{
+		TimeFormatRFC3339:            "2021-06-01T00:00:01.5Z",
+		common.PrecisionMilliseconds: "1622505601500",
+		common.PrecisionMicroseconds: "1622505601500000",
+		common.PrecisionNanoseconds:  "1622505601500000000",
+	}
+	for format, s := range cases {
+		got, err := parseTime(s, format)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", format, err)
+		} else if !got.Equal(want) {
+			t.Errorf("%s: incorrect time: got %v want %v", format, got, want)
+		}
+	}
+	if got, err := parseTime("1622505601", common.PrecisionSeconds); err != nil || !got.Equal(want.Truncate(time.Second)) {
+		t.Errorf("s: incorrect time: got %v, %v", got, err)
+	}
+}
+
+func TestMergeKindsAndConvert(t *testing.T) {
+	kinds := []struct {
+		a, b, want string
+	}{
+		{"", common.FieldTypeInt64, common.FieldTypeInt64},
+		{common.FieldTypeBool, common.FieldTypeBool, common.FieldTypeBool},
+		{common.FieldTypeInt64, common.FieldTypeFloat64, common.FieldTypeFloat64},
+		{common.FieldTypeUint64, common.FieldTypeInt64, common.FieldTypeFloat64},
+		{common.FieldTypeInt64, common.FieldTypeBool, common.FieldTypeString},
+		{common.FieldTypeString, common.FieldTypeFloat64, common.FieldTypeString},
+	}
+	for _, c := range kinds {
+		if got := mergeKinds(c.a, c.b); got != c.want {
+			t.Errorf("mergeKinds(%q, %q): got %q want %q", c.a, c.b, got, c.want)
+		}
+	}
+
+	values := []struct {
+		raw, fieldType string
+		want           interface{}
+	}{
+		{"3", common.FieldTypeFloat64, float64(3)},
+		{"3", common.FieldTypeInt64, int64(3)},
+		{"3", common.FieldTypeUint64, uint64(3)},
+		{"T", common.FieldTypeBool, true},
+		{"false", common.FieldTypeBool, false},
+		{"3", common.FieldTypeString, "3"},
+		{"abc", common.FieldTypeFloat64, nil},
+	}
+	for _, c := range values {
+		if got := convert(c.raw, c.fieldType); got != c.want {
+			t.Errorf("convert(%q, %q): got %#v want %#v", c.raw, c.fieldType, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package dataset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const maxLineSize = 16 * 1024 * 1024
+
+// lineProtocolReader reads the lines of an InfluxDB line protocol dataset.
+// Empty lines and comments are skipped.
+type lineProtocolReader struct {
+	scanner *bufio.Scanner
+	m       *Mapping
+	line    int
+}
+
+func newLineProtocolReader(r io.Reader, m *Mapping) *lineProtocolReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &lineProtocolReader{scanner: scanner, m: m}
+}
+
+// next returns the next line with at least one of the mapped fields.
+func (lr *lineProtocolReader) next() (*record, error) {
+	for lr.scanner.Scan() {
+		lr.line++
+		line := strings.TrimSpace(lr.scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		rec, err := lr.parse(line)
+		if err != nil {
+			return nil, err
+		}
+		if len(rec.fieldKeys) > 0 {
+			return rec, nil
+		}
+	}
+	if err := lr.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read dataset: %v", err)
+	}
+	return nil, io.EOF
+}
+
+// parse parses a line of line protocol:
+// <measurement>[,<tag key>=<tag value>...] <field key>=<field value>[,...] <timestamp>
+func (lr *lineProtocolReader) parse(line string) (*record, error) {
+	var sections [][]byte
+	for _, s := range serialize.SplitLineProtocol([]byte(line), ' ') {
+		if len(s) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	if len(sections) < 3 {
+		return nil, fmt.Errorf(errNoTimestampFmt, lr.line)
+	}
+	if len(sections) > 3 {
+		return nil, fmt.Errorf(errBadLineFmt, lr.line, "too many sections, unescaped spaces?")
+	}
+
+	series := serialize.SplitLineProtocol(sections[0], ',')
+	rec := &record{
+		measurement: serialize.UnescapeLineProtocol(series[0]),
+		tags:        make(map[string]string, len(series)-1),
+		fields:      make(map[string]value),
+	}
+	for _, kv := range series[1:] {
+		k, v, err := serialize.SplitLineProtocolKeyValue(kv)
+		if err != nil {
+			return nil, fmt.Errorf(errBadLineFmt, lr.line, err)
+		}
+		rec.tagKeys = append(rec.tagKeys, k)
+		rec.tags[k] = serialize.UnescapeLineProtocol(v)
+	}
+	for _, kv := range serialize.SplitLineProtocol(sections[1], ',') {
+		k, v, err := serialize.SplitLineProtocolKeyValue(kv)
+		if err != nil {
+			return nil, fmt.Errorf(errBadLineFmt, lr.line, err)
+		}
+		fv, err := parseFieldValue(v)
+		if err != nil {
+			return nil, fmt.Errorf(errBadLineFmt, lr.line, fmt.Sprintf("field '%s': %v", k, err))
+		}
+		rec.fieldKeys = append(rec.fieldKeys, k)
+		rec.fields[k] = fv
+	}
+
+	var err error
+	rec.timestamp, err = parseTime(string(sections[2]), lr.m.TimeFormat)
+	if err != nil {
+		return nil, fmt.Errorf(errBadTimestampFmt, lr.line, sections[2], err)
+	}
+
+	rec.tagKeys = keep(rec.tagKeys, lr.m.Tags, func(k string) bool { _, ok := rec.tags[k]; return ok })
+	rec.fieldKeys = keep(rec.fieldKeys, lr.m.Fields, func(k string) bool { _, ok := rec.fields[k]; return ok })
+	return rec, nil
+}
+
+// keep returns the keys of mapped that the record has, in their order, or
+// all the keys when mapped is empty.
+func keep(keys, mapped []string, has func(string) bool) []string {
+	if len(mapped) == 0 {
+		return keys
+	}
+	kept := make([]string, 0, len(mapped))
+	for _, k := range mapped {
+		if has(k) {
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
+
+// parseFieldValue tells the type of a field value by its syntax.
+func parseFieldValue(s []byte) (value, error) {
+	v, err := serialize.ParseLineProtocolFieldValue(s)
+	if err != nil {
+		return value{}, err
+	}
+	switch v := v.(type) {
+	case string:
+		return value{raw: v, kind: common.FieldTypeString}, nil
+	case int64:
+		return value{raw: string(s[:len(s)-1]), kind: common.FieldTypeInt64}, nil
+	case uint64:
+		return value{raw: string(s[:len(s)-1]), kind: common.FieldTypeUint64}, nil
+	case bool:
+		return value{raw: string(s), kind: common.FieldTypeBool}, nil
+	}
+	return value{raw: string(s), kind: common.FieldTypeFloat64}, nil
+}
@@ -0,0 +1,180 @@
+package dataset
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a Simulator of the points of a dataset.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	Dataset *Dataset
+	// Start and End are the time window the dataset is moved into when Shift
+	// is set: its first timestamp becomes Start and the records shifted past
+	// End are dropped.
+	Start time.Time
+	End   time.Time
+	Shift bool
+	// Copies is how many times every series of the dataset is written, the
+	// copies told apart by a suffix to the value of the first tag. 0 or 1
+	// writes the dataset as is.
+	Copies uint64
+}
+
+// Validate checks that the series of the dataset can be multiplied.
+func (sc *SimulatorConfig) Validate() error {
+	if sc.Copies > 1 && len(sc.Dataset.tagKeys) == 0 {
+		return fmt.Errorf(errCopiesNeedTag)
+	}
+	return nil
+}
+
+// NewSimulator produces a Simulator of the records of the dataset, up to the
+// points limit. The interval is not used, the records keep their own spacing.
+func (sc *SimulatorConfig) NewSimulator(_ time.Duration, limit uint64) common.Simulator {
+	closer, reader, err := sc.Dataset.reader()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	s := &Simulator{
+		dataset:   sc.Dataset,
+		closer:    closer,
+		reader:    reader,
+		copies:    sc.Copies,
+		maxPoints: limit,
+		tagKeys:   make([][]byte, len(sc.Dataset.tagKeys)),
+		fieldKeys: make(map[string][][]byte, len(sc.Dataset.fieldKeys)),
+	}
+	if s.copies == 0 {
+		s.copies = 1
+	}
+	if sc.Shift {
+		s.shift = true
+		s.offset = sc.Start.Sub(sc.Dataset.start)
+		s.end = sc.End
+	}
+	for i, k := range sc.Dataset.tagKeys {
+		s.tagKeys[i] = []byte(k)
+	}
+	for m, keys := range sc.Dataset.fieldKeys {
+		s.fieldKeys[m] = make([][]byte, len(keys))
+		for i, k := range keys {
+			s.fieldKeys[m][i] = []byte(k)
+		}
+	}
+
+	s.advance()
+	return s
+}
+
+// Simulator writes the records of a dataset as points, with every tag and
+// field of their measurement, nil when a record does not have it.
+type Simulator struct {
+	dataset *Dataset
+	closer  io.Closer
+	reader  recordReader
+
+	tagKeys   [][]byte
+	fieldKeys map[string][][]byte
+
+	current *record
+	copy    uint64
+	copies  uint64
+
+	shift  bool
+	offset time.Duration
+	end    time.Time
+
+	madePoints uint64
+	maxPoints  uint64
+}
+
+// Finished tells whether all the records, or the points limit, were written.
+func (s *Simulator) Finished() bool {
+	return s.current == nil || (s.maxPoints > 0 && s.madePoints >= s.maxPoints)
+}
+
+// Next writes the current record, or its next copy, to the point.
+func (s *Simulator) Next(p *data.Point) bool {
+	rec := s.current
+	p.SetMeasurementName([]byte(rec.measurement))
+	for i, k := range s.dataset.tagKeys {
+		v, ok := rec.tags[k]
+		if !ok {
+			p.AppendTag(s.tagKeys[i], nil)
+			continue
+		}
+		if i == 0 && s.copy > 0 {
+			v += "_" + strconv.FormatUint(s.copy, 10)
+		}
+		p.AppendTag(s.tagKeys[i], v)
+	}
+
+	types := s.dataset.fieldTypes[rec.measurement]
+	for i, k := range s.dataset.fieldKeys[rec.measurement] {
+		v, ok := rec.fields[k]
+		if !ok {
+			p.AppendField(s.fieldKeys[rec.measurement][i], nil)
+			continue
+		}
+		p.AppendField(s.fieldKeys[rec.measurement][i], convert(v.raw, types[i]))
+	}
+
+	ts := rec.timestamp.Add(s.offset)
+	p.SetTimestamp(&ts)
+
+	s.madePoints++
+	s.copy++
+	if s.copy == s.copies {
+		s.copy = 0
+		s.advance()
+	}
+	return true
+}
+
+// advance reads the next record within the time window, closing the dataset
+// after the last one.
+func (s *Simulator) advance() {
+	for {
+		rec, err := s.reader.next()
+		if err == io.EOF {
+			s.current = nil
+			s.closer.Close()
+			return
+		}
+		if err != nil {
+			panic(err.Error())
+		}
+		if s.shift && !rec.timestamp.Add(s.offset).Before(s.end) {
+			continue
+		}
+		s.current = rec
+		return
+	}
+}
+
+// Fields returns the fields of every measurement of the dataset.
+func (s *Simulator) Fields() map[string][]string {
+	return s.dataset.fieldKeys
+}
+
+// TagKeys returns the tag keys of the dataset.
+func (s *Simulator) TagKeys() []string {
+	return s.dataset.tagKeys
+}
+
+// TagTypes returns the types of the tags of the dataset, all strings.
+func (s *Simulator) TagTypes() []string {
+	return s.dataset.Headers().TagTypes
+}
+
+// Headers returns the tags, fields and field types of the dataset.
+func (s *Simulator) Headers() *common.GeneratedDataHeaders {
+	return s.dataset.Headers()
+}
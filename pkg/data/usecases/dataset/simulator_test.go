@@ -0,0 +1,136 @@
+package dataset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+func testDataset(t *testing.T) *Dataset {
+	d, err := newDataset(Mapping{
+		Format:            FormatCSV,
+		MeasurementColumn: "kind",
+		Tags:              []string{"host", "region"},
+	}, stringOpener(testCSV))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return d
+}
+
+// simulate returns all the points of the simulator.
+func simulate(s interface {
+	Finished() bool
+	Next(*data.Point) bool
+}) []*data.Point {
+	var points []*data.Point
+	for !s.Finished() {
+		p := data.NewPoint()
+		if s.Next(p) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+func TestSimulatorNext(t *testing.T) {
+	d := testDataset(t)
+	sc := &SimulatorConfig{Dataset: d}
+	points := simulate(sc.NewSimulator(time.Second, 0))
+	if len(points) != 3 {
+		t.Fatalf("incorrect number of points: got %d want 3", len(points))
+	}
+
+	p := points[0]
+	if got := string(p.MeasurementName()); got != "env" {
+		t.Errorf("incorrect measurement: got %s want env", got)
+	}
+	tags := p.TagValues()
+	if len(tags) != 2 || tags[0] != "a" || tags[1] != "eu" {
+		t.Errorf("incorrect tags: got %v", tags)
+	}
+	fields := p.FieldValues()
+	if len(fields) != 3 || fields[0] != 21.5 || fields[1] != int64(1) || fields[2] != "run" {
+		t.Errorf("incorrect fields: got %v", fields)
+	}
+	if want := time.Date(2021, 6, 1, 0, 0, 10, 0, time.UTC); !p.Timestamp().Equal(want) {
+		t.Errorf("incorrect timestamp: got %v want %v", p.Timestamp(), want)
+	}
+
+	// missing tags and fields are nil
+	if tags := points[1].TagValues(); tags[1] != nil {
+		t.Errorf("incorrect missing tag: got %v", tags[1])
+	}
+	if got := string(points[2].MeasurementName()); got != "power" {
+		t.Errorf("incorrect measurement: got %s want power", got)
+	}
+	if fields := points[2].FieldValues(); len(fields) != 2 || fields[0] != int64(3) {
+		t.Errorf("incorrect fields of the power measurement: got %v", fields)
+	}
+}
+
+func TestSimulatorCopies(t *testing.T) {
+	sc := &SimulatorConfig{Dataset: testDataset(t), Copies: 3}
+	if err := sc.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	points := simulate(sc.NewSimulator(time.Second, 0))
+	if len(points) != 9 {
+		t.Fatalf("incorrect number of points: got %d want 9", len(points))
+	}
+	want := []string{"a", "a_1", "a_2", "b", "b_1", "b_2"}
+	for i, w := range want {
+		if got := points[i].TagValues()[0]; got != w {
+			t.Errorf("incorrect host of point %d: got %v want %s", i, got, w)
+		}
+	}
+
+	sc = &SimulatorConfig{Dataset: testDataset(t), Copies: 2}
+	sc.Dataset.tagKeys = nil
+	if err := sc.Validate(); err == nil || err.Error() != errCopiesNeedTag {
+		t.Errorf("incorrect error for copies without tags: got %v want %s", err, errCopiesNeedTag)
+	}
+}
+
+func TestSimulatorShift(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	sc := &SimulatorConfig{
+		Dataset: testDataset(t),
+		Start:   start,
+		End:     start.Add(15 * time.Second),
+		Shift:   true,
+	}
+	points := simulate(sc.NewSimulator(time.Second, 0))
+	// the dataset starts at its second row, the third one is past the end
+	if len(points) != 2 {
+		t.Fatalf("incorrect number of points: got %d want 2", len(points))
+	}
+	if want := start.Add(10 * time.Second); !points[0].Timestamp().Equal(want) {
+		t.Errorf("incorrect shifted timestamp: got %v want %v", points[0].Timestamp(), want)
+	}
+	if !points[1].Timestamp().Equal(start) {
+		t.Errorf("incorrect shifted timestamp: got %v want %v", points[1].Timestamp(), start)
+	}
+}
+
+func TestSimulatorLimit(t *testing.T) {
+	sc := &SimulatorConfig{Dataset: testDataset(t), Copies: 2}
+	if points := simulate(sc.NewSimulator(time.Second, 5)); len(points) != 5 {
+		t.Errorf("incorrect number of points: got %d want 5", len(points))
+	}
+}
+
+func TestSimulatorHeaders(t *testing.T) {
+	d := testDataset(t)
+	s := (&SimulatorConfig{Dataset: d}).NewSimulator(time.Second, 0)
+	if got := s.TagKeys(); len(got) != 2 || got[0] != "host" {
+		t.Errorf("incorrect tag keys: got %v", got)
+	}
+	if got := s.Fields()["env"]; len(got) != 3 {
+		t.Errorf("incorrect fields: got %v", got)
+	}
+	if got := s.Headers().FieldTypesOf("power"); len(got) != 2 || got[0] != "int64" {
+		t.Errorf("incorrect field types: got %v", got)
+	}
+}
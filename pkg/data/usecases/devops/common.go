@@ -0,0 +1,20 @@
+package devops
+
+import "github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+
+// monotonicND returns a maker of counters that increase by normally
+// distributed steps, like the totals reported by system and application
+// agents.
+func monotonicND(mean, stdDev float64) func() common.Distribution {
+	return func() common.Distribution {
+		return common.MWD(common.ND(mean, stdDev), 0)
+	}
+}
+
+// clampedND returns a maker of gauges that move by normally distributed steps
+// within [min, max], starting at state.
+func clampedND(mean, stdDev, min, max, state float64) func() common.Distribution {
+	return func() common.Distribution {
+		return common.CWD(common.ND(mean, stdDev), min, max, state)
+	}
+}
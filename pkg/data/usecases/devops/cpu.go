@@ -0,0 +1,49 @@
+package devops
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+var (
+	labelCPU = []byte("cpu")
+
+	cpuFields = []common.LabeledDistributionMaker{
+		{Label: []byte("usage_user"), DistributionMaker: cpuND},
+		{Label: []byte("usage_system"), DistributionMaker: cpuND},
+		{Label: []byte("usage_idle"), DistributionMaker: cpuND},
+		{Label: []byte("usage_nice"), DistributionMaker: cpuND},
+		{Label: []byte("usage_iowait"), DistributionMaker: cpuND},
+		{Label: []byte("usage_irq"), DistributionMaker: cpuND},
+		{Label: []byte("usage_softirq"), DistributionMaker: cpuND},
+		{Label: []byte("usage_steal"), DistributionMaker: cpuND},
+		{Label: []byte("usage_guest"), DistributionMaker: cpuND},
+		{Label: []byte("usage_guest_nice"), DistributionMaker: cpuND},
+	}
+)
+
+// cpuND is a random walk of a percentage
+func cpuND() common.Distribution {
+	return common.CWD(common.ND(0.0, 1.0), 0.0, 100.0, rand.Float64()*100.0)
+}
+
+// CPUMeasurement represents the usage percentages of a host CPU.
+type CPUMeasurement struct {
+	*common.SubsystemMeasurement
+}
+
+// NewCPUMeasurement creates a CPUMeasurement with start time.
+func NewCPUMeasurement(start time.Time) *CPUMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, cpuFields)
+	return &CPUMeasurement{
+		SubsystemMeasurement: sub,
+	}
+}
+
+// ToPoint serializes CPUMeasurement to data.Point.
+func (m *CPUMeasurement) ToPoint(p *data.Point) {
+	m.ToPointAllInt64(p, labelCPU, cpuFields)
+}
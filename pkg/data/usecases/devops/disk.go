@@ -0,0 +1,84 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const (
+	oneTB     = 1024 * 1024 * 1024 * 1024
+	inodeSize = 4096
+)
+
+var (
+	labelDisk       = []byte("disk")
+	labelDiskPath   = []byte("path")
+	labelDiskFSType = []byte("fstype")
+
+	labelDiskTotal       = []byte("total")
+	labelDiskFree        = []byte("free")
+	labelDiskUsed        = []byte("used")
+	labelDiskUsedPercent = []byte("used_percent")
+	labelDiskINodesTotal = []byte("inodes_total")
+	labelDiskINodesFree  = []byte("inodes_free")
+	labelDiskINodesUsed  = []byte("inodes_used")
+
+	diskFSTypeChoices = []string{
+		"ext3",
+		"ext4",
+		"btrfs",
+	}
+)
+
+// DiskMeasurement represents the usage of a 1TB disk of a host. Only the free
+// space changes, the other fields are derived from it.
+type DiskMeasurement struct {
+	*common.SubsystemMeasurement
+	path   string
+	fsType string
+
+	freeBytesDist common.Distribution
+}
+
+// NewDiskMeasurement creates a DiskMeasurement with start time.
+func NewDiskMeasurement(start time.Time) *DiskMeasurement {
+	sub := common.NewSubsystemMeasurement(start, 0)
+	return &DiskMeasurement{
+		SubsystemMeasurement: sub,
+		path:                 fmt.Sprintf("/dev/sda%d", rand.Intn(10)),
+		fsType:               common.RandomStringSliceChoice(diskFSTypeChoices),
+		freeBytesDist:        common.CWD(common.ND(50, 1), 0, oneTB, oneTB/2),
+	}
+}
+
+// Tick advances the free space of the disk.
+func (m *DiskMeasurement) Tick(d time.Duration) {
+	m.SubsystemMeasurement.Tick(d)
+	m.freeBytesDist.Advance()
+}
+
+// ToPoint serializes DiskMeasurement to data.Point.
+func (m *DiskMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelDisk)
+	copy := m.Timestamp
+	p.SetTimestamp(&copy)
+	p.AppendTag(labelDiskPath, m.path)
+	p.AppendTag(labelDiskFSType, m.fsType)
+
+	free := int64(m.freeBytesDist.Get())
+	total := int64(oneTB)
+	used := total - free
+	usedPercent := int64(100.0 * (float64(used) / float64(total)))
+
+	p.AppendField(labelDiskTotal, total)
+	p.AppendField(labelDiskFree, free)
+	p.AppendField(labelDiskUsed, used)
+	p.AppendField(labelDiskUsedPercent, usedPercent)
+	p.AppendField(labelDiskINodesTotal, total/inodeSize)
+	p.AppendField(labelDiskINodesFree, free/inodeSize)
+	p.AppendField(labelDiskINodesUsed, used/inodeSize)
+}
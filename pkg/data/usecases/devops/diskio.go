@@ -0,0 +1,47 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+var (
+	labelDiskIO       = []byte("diskio")
+	labelDiskIOSerial = []byte("serial")
+
+	diskIOFields = []common.LabeledDistributionMaker{
+		{Label: []byte("reads"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("writes"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("read_bytes"), DistributionMaker: monotonicND(100, 1)},
+		{Label: []byte("write_bytes"), DistributionMaker: monotonicND(100, 1)},
+		{Label: []byte("read_time"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("write_time"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("io_time"), DistributionMaker: monotonicND(5, 1)},
+	}
+)
+
+// DiskIOMeasurement represents the IO counters of a disk of a host.
+type DiskIOMeasurement struct {
+	*common.SubsystemMeasurement
+	serial string
+}
+
+// NewDiskIOMeasurement creates a DiskIOMeasurement with start time.
+func NewDiskIOMeasurement(start time.Time) *DiskIOMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, diskIOFields)
+	serial := fmt.Sprintf("%03d_%03d_%04d", rand.Intn(1000), rand.Intn(1000), rand.Intn(10000))
+	return &DiskIOMeasurement{
+		SubsystemMeasurement: sub,
+		serial:               serial,
+	}
+}
+
+// ToPoint serializes DiskIOMeasurement to data.Point.
+func (m *DiskIOMeasurement) ToPoint(p *data.Point) {
+	p.AppendTag(labelDiskIOSerial, m.serial)
+	m.ToPointAllInt64(p, labelDiskIO, diskIOFields)
+}
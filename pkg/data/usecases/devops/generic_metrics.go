@@ -0,0 +1,108 @@
+package devops
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const genericMetricFmt = "metric_%d"
+
+var labelGenericMetrics = []byte("generic")
+
+// GenericMetricsSimulatorConfig is used to create a devops-generic Simulator,
+// where every host reports a single measurement with a host specific number
+// of numeric fields, up to MaxMetricCount.
+// It fulfills the common.SimulatorConfig interface.
+type GenericMetricsSimulatorConfig struct {
+	*common.BaseSimulatorConfig
+	// MaxMetricCount is the number of fields of the widest host.
+	MaxMetricCount uint64
+}
+
+// NewSimulator produces a devops-generic Simulator with the given config over
+// the specified interval and points limit.
+func (gc *GenericMetricsSimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	sc := *gc.BaseSimulatorConfig
+	sc.GeneratorConstructor = NewHostGenericMetrics(gc.MaxMetricCount)
+	return sc.NewSimulator(interval, limit)
+}
+
+// NewHostGenericMetrics returns a constructor of hosts for the devops-generic
+// use case. Each host gets its own number of fields between 1 and
+// maxMetricCount, following a log-uniform distribution: most hosts are
+// narrow and a few are close to maxMetricCount wide.
+func NewHostGenericMetrics(maxMetricCount uint64) func(i int, start time.Time) common.Generator {
+	labels := genericMetricLabels(maxMetricCount)
+	return func(i int, start time.Time) common.Generator {
+		metricCount := genericMetricCount(maxMetricCount)
+		h := newHostWithMeasurementGenerator(i, start, func(start time.Time) []common.SimulatedMeasurement {
+			return []common.SimulatedMeasurement{
+				NewGenericMetricsMeasurement(start, metricCount, labels),
+			}
+		})
+		return &h
+	}
+}
+
+// genericMetricCount picks the number of fields of a host.
+func genericMetricCount(maxMetricCount uint64) uint64 {
+	count := uint64(math.Ceil(math.Pow(float64(maxMetricCount), rand.Float64())))
+	if count < 1 {
+		return 1
+	}
+	if count > maxMetricCount {
+		return maxMetricCount
+	}
+	return count
+}
+
+func genericMetricLabels(maxMetricCount uint64) [][]byte {
+	labels := make([][]byte, maxMetricCount)
+	for i := range labels {
+		labels[i] = []byte(fmt.Sprintf(genericMetricFmt, i))
+	}
+	return labels
+}
+
+// GenericMetricsMeasurement is a measurement with a configurable number of
+// numeric fields, each one a random walk between 0 and 100.
+//
+// Its points always carry one field per label, so that the targets that load
+// CSV rows against a header get the same columns from every host. The fields
+// past the host's own metric count are left empty.
+type GenericMetricsMeasurement struct {
+	*common.SubsystemMeasurement
+	labels [][]byte
+}
+
+// NewGenericMetricsMeasurement creates a GenericMetricsMeasurement with
+// metricCount non-empty fields out of the given labels.
+func NewGenericMetricsMeasurement(start time.Time, metricCount uint64, labels [][]byte) *GenericMetricsMeasurement {
+	sub := common.NewSubsystemMeasurement(start, int(metricCount))
+	for i := range sub.Distributions {
+		sub.Distributions[i] = common.CWD(common.ND(0.0, 1.0), 0.0, 100.0, rand.Float64()*100.0)
+	}
+	return &GenericMetricsMeasurement{
+		SubsystemMeasurement: sub,
+		labels:               labels,
+	}
+}
+
+// ToPoint serializes GenericMetricsMeasurement to data.Point.
+func (m *GenericMetricsMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelGenericMetrics)
+	p.SetTimestamp(&m.Timestamp)
+
+	for i, label := range m.labels {
+		if i < len(m.Distributions) {
+			p.AppendField(label, m.Distributions[i].Get())
+		} else {
+			p.AppendField(label, nil)
+		}
+	}
+}
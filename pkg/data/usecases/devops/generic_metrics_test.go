@@ -0,0 +1,102 @@
+package devops
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+func TestGenericMetricCount(t *testing.T) {
+	rand.Seed(123)
+	for _, max := range []uint64{1, 2, 100, 5000} {
+		for i := 0; i < 1000; i++ {
+			if got := genericMetricCount(max); got < 1 || got > max {
+				t.Fatalf("metric count out of range for max %d: got %d", max, got)
+			}
+		}
+	}
+}
+
+func TestNewHostGenericMetrics(t *testing.T) {
+	rand.Seed(123)
+	const maxMetricCount = 1000
+	newHost := NewHostGenericMetrics(maxMetricCount)
+	counts := make(map[int]bool)
+	for i := 0; i < 20; i++ {
+		h := newHost(i, time.Now()).(*Host)
+		if got := len(h.Tags()); got != len(MachineTagKeys) {
+			t.Fatalf("incorrect number of tags: got %d want %d", got, len(MachineTagKeys))
+		}
+		ms := h.Measurements()
+		if got := len(ms); got != 1 {
+			t.Fatalf("incorrect number of measurements: got %d want %d", got, 1)
+		}
+		m := ms[0].(*GenericMetricsMeasurement)
+
+		p := data.NewPoint()
+		m.ToPoint(p)
+		if got := string(p.MeasurementName()); got != "generic" {
+			t.Errorf("incorrect measurement name: got %s want generic", got)
+		}
+		keys := p.FieldKeys()
+		if got := len(keys); got != maxMetricCount {
+			t.Fatalf("incorrect number of field keys: got %d want %d", got, maxMetricCount)
+		}
+		if got := string(keys[maxMetricCount-1]); got != "metric_999" {
+			t.Errorf("incorrect last field key: got %s want metric_999", got)
+		}
+
+		nonEmpty := 0
+		for j, v := range p.FieldValues() {
+			if v == nil {
+				continue
+			}
+			if j != nonEmpty {
+				t.Fatalf("non-empty field %d after an empty one", j)
+			}
+			nonEmpty++
+		}
+		if nonEmpty != len(m.Distributions) {
+			t.Errorf("incorrect number of non-empty fields: got %d want %d", nonEmpty, len(m.Distributions))
+		}
+		counts[nonEmpty] = true
+	}
+	if len(counts) < 2 {
+		t.Errorf("metric count does not vary per host: %v", counts)
+	}
+}
+
+func TestGenericMetricsSimulatorConfig(t *testing.T) {
+	now := time.Now()
+	gc := &GenericMetricsSimulatorConfig{
+		BaseSimulatorConfig: &common.BaseSimulatorConfig{
+			Start:              now,
+			End:                now.Add(time.Minute),
+			InitGeneratorScale: 3,
+			GeneratorScale:     3,
+		},
+		MaxMetricCount: 10,
+	}
+	s := gc.NewSimulator(10*time.Second, 0)
+	fields := s.Fields()
+	if got := len(fields["generic"]); got != 10 {
+		t.Errorf("incorrect number of header fields: got %d want %d", got, 10)
+	}
+	if gc.GeneratorConstructor != nil {
+		t.Errorf("config was modified by NewSimulator")
+	}
+
+	points := 0
+	p := data.NewPoint()
+	for !s.Finished() {
+		s.Next(p)
+		p.Reset()
+		points++
+	}
+	if want := 6 * 3; points != want {
+		t.Errorf("incorrect number of points: got %d want %d", points, want)
+	}
+}
@@ -0,0 +1,161 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const (
+	hostNameFmt = "host_%d"
+
+	machineRackChoicesPerDatacenter = 100
+	machineServiceChoices           = 20
+	machineServiceVersionChoices    = 2
+)
+
+// region is a cloud region with its datacenters.
+type region struct {
+	Name        string
+	Datacenters []string
+}
+
+// The tag values are the ones of TSBS, so the data and the queries can be
+// compared with its results.
+var (
+	regions = []region{
+		{"us-east-1", []string{"us-east-1a", "us-east-1b", "us-east-1c", "us-east-1e"}},
+		{"us-west-1", []string{"us-west-1a", "us-west-1b"}},
+		{"us-west-2", []string{"us-west-2a", "us-west-2b", "us-west-2c"}},
+		{"eu-west-1", []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"}},
+		{"eu-central-1", []string{"eu-central-1a", "eu-central-1b"}},
+		{"ap-southeast-1", []string{"ap-southeast-1a", "ap-southeast-1b"}},
+		{"ap-southeast-2", []string{"ap-southeast-2a", "ap-southeast-2b"}},
+		{"ap-northeast-1", []string{"ap-northeast-1a", "ap-northeast-1c"}},
+		{"sa-east-1", []string{"sa-east-1a", "sa-east-1b", "sa-east-1c"}},
+	}
+
+	machineTeamChoices = []string{
+		"SF",
+		"NYC",
+		"LON",
+		"CHI",
+	}
+	machineOSChoices = []string{
+		"Ubuntu16.10",
+		"Ubuntu16.04LTS",
+		"Ubuntu15.10",
+	}
+	machineArchChoices = []string{
+		"x64",
+		"x86",
+	}
+	machineServiceEnvironmentChoices = []string{
+		"production",
+		"staging",
+		"test",
+	}
+
+	// MachineTagKeys are the keys of the tags of every host, in order.
+	MachineTagKeys = [][]byte{
+		[]byte("hostname"),
+		[]byte("region"),
+		[]byte("datacenter"),
+		[]byte("rack"),
+		[]byte("os"),
+		[]byte("arch"),
+		[]byte("team"),
+		[]byte("service"),
+		[]byte("service_version"),
+		[]byte("service_environment"),
+	}
+)
+
+// Host models a machine being monitored by a DevOps agent which sends back
+// system and application measurements.
+type Host struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+}
+
+// TickAll advances all Distributions of a Host.
+func (h *Host) TickAll(d time.Duration) {
+	for i := range h.simulatedMeasurements {
+		h.simulatedMeasurements[i].Tick(d)
+	}
+}
+
+// Measurements returns the host measurements.
+func (h Host) Measurements() []common.SimulatedMeasurement {
+	return h.simulatedMeasurements
+}
+
+// Tags returns the host tags.
+func (h Host) Tags() []common.Tag {
+	return h.tags
+}
+
+func newHostMeasurements(start time.Time) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewCPUMeasurement(start),
+		NewDiskIOMeasurement(start),
+		NewDiskMeasurement(start),
+		NewMemMeasurement(start),
+		NewNetMeasurement(start),
+		NewNginxMeasurement(start),
+		NewPostgresqlMeasurement(start),
+		NewRedisMeasurement(start),
+	}
+}
+
+func newCPUOnlyHostMeasurements(start time.Time) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewCPUMeasurement(start),
+	}
+}
+
+// NewHost creates a new host in a simulated devops use case.
+func NewHost(i int, start time.Time) common.Generator {
+	h := newHostWithMeasurementGenerator(i, start, newHostMeasurements)
+	return &h
+}
+
+// NewHostCPUOnly creates a new host in a simulated cpu-only use case, which
+// is a devops use case that only generates CPU measurements.
+func NewHostCPUOnly(i int, start time.Time) common.Generator {
+	h := newHostWithMeasurementGenerator(i, start, newCPUOnlyHostMeasurements)
+	return &h
+}
+
+func newHostWithMeasurementGenerator(i int, start time.Time, generator func(time.Time) []common.SimulatedMeasurement) Host {
+	sm := generator(start)
+
+	r := regions[rand.Intn(len(regions))]
+	rackID := rand.Int63n(machineRackChoicesPerDatacenter)
+	serviceID := rand.Int63n(machineServiceChoices)
+	serviceVersionID := rand.Int63n(machineServiceVersionChoices)
+
+	values := []string{
+		fmt.Sprintf(hostNameFmt, i),
+		r.Name,
+		common.RandomStringSliceChoice(r.Datacenters),
+		fmt.Sprintf("%d", rackID),
+		common.RandomStringSliceChoice(machineOSChoices),
+		common.RandomStringSliceChoice(machineArchChoices),
+		common.RandomStringSliceChoice(machineTeamChoices),
+		fmt.Sprintf("%d", serviceID),
+		fmt.Sprintf("%d", serviceVersionID),
+		common.RandomStringSliceChoice(machineServiceEnvironmentChoices),
+	}
+	tags := make([]common.Tag, len(MachineTagKeys))
+	for j, key := range MachineTagKeys {
+		tags[j] = common.Tag{Key: key, Value: values[j]}
+	}
+
+	return Host{
+		simulatedMeasurements: sm,
+		tags:                  tags,
+	}
+}
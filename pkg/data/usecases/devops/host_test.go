@@ -0,0 +1,50 @@
+package devops
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewHost(t *testing.T) {
+	now := time.Now()
+	h := NewHost(1, now).(*Host)
+	if got := len(h.Measurements()); got != 8 {
+		t.Errorf("incorrect number of measurements: got %d want %d", got, 8)
+	}
+	tags := h.Tags()
+	if got := len(tags); got != len(MachineTagKeys) {
+		t.Fatalf("incorrect number of tags: got %d want %d", got, len(MachineTagKeys))
+	}
+	for i, tag := range tags {
+		if got := string(tag.Key); got != string(MachineTagKeys[i]) {
+			t.Errorf("incorrect tag key %d: got %s want %s", i, got, MachineTagKeys[i])
+		}
+		if _, ok := tag.Value.(string); !ok {
+			t.Errorf("tag %s is not a string: %v", tag.Key, tag.Value)
+		}
+	}
+	if got := tags[0].Value; got != "host_1" {
+		t.Errorf("incorrect hostname: got %v want host_1", got)
+	}
+}
+
+func TestNewHostCPUOnly(t *testing.T) {
+	h := NewHostCPUOnly(1, time.Now()).(*Host)
+	ms := h.Measurements()
+	if got := len(ms); got != 1 {
+		t.Fatalf("incorrect number of measurements: got %d want %d", got, 1)
+	}
+	if _, ok := ms[0].(*CPUMeasurement); !ok {
+		t.Errorf("measurement is not a CPUMeasurement: %T", ms[0])
+	}
+}
+
+func TestHostTickAll(t *testing.T) {
+	now := time.Now()
+	h := NewHost(0, now).(*Host)
+	h.TickAll(time.Second)
+	cpu := h.Measurements()[0].(*CPUMeasurement)
+	if got := cpu.Timestamp; !got.Equal(now.Add(time.Second)) {
+		t.Errorf("measurement not ticked: got %v want %v", got, now.Add(time.Second))
+	}
+}
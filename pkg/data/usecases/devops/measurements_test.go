@@ -0,0 +1,103 @@
+package devops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+func TestMeasurementsToPoint(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		m          common.SimulatedMeasurement
+		name       string
+		tagKeys    []string
+		fieldCount int
+	}{
+		{m: NewCPUMeasurement(now), name: "cpu", fieldCount: len(cpuFields)},
+		{m: NewDiskIOMeasurement(now), name: "diskio", tagKeys: []string{"serial"}, fieldCount: len(diskIOFields)},
+		{m: NewDiskMeasurement(now), name: "disk", tagKeys: []string{"path", "fstype"}, fieldCount: 7},
+		{m: NewMemMeasurement(now), name: "mem", fieldCount: 9},
+		{m: NewNetMeasurement(now), name: "net", tagKeys: []string{"interface"}, fieldCount: len(netFields)},
+		{m: NewNginxMeasurement(now), name: "nginx", tagKeys: []string{"port", "server"}, fieldCount: len(nginxFields)},
+		{m: NewPostgresqlMeasurement(now), name: "postgresl", fieldCount: len(pgFields)},
+		{m: NewRedisMeasurement(now), name: "redis", tagKeys: []string{"port", "server"}, fieldCount: len(redisFields) + 1},
+	}
+	for _, c := range cases {
+		c.m.Tick(time.Second)
+		p := data.NewPoint()
+		c.m.ToPoint(p)
+		if got := string(p.MeasurementName()); got != c.name {
+			t.Errorf("incorrect measurement name: got %s want %s", got, c.name)
+		}
+		if got := p.Timestamp(); !got.Equal(now.Add(time.Second)) {
+			t.Errorf("%s: incorrect timestamp: got %v want %v", c.name, got, now.Add(time.Second))
+		}
+		tagKeys := p.TagKeys()
+		if len(tagKeys) != len(c.tagKeys) {
+			t.Errorf("%s: incorrect number of tags: got %d want %d", c.name, len(tagKeys), len(c.tagKeys))
+		} else {
+			for i, k := range tagKeys {
+				if string(k) != c.tagKeys[i] {
+					t.Errorf("%s: incorrect tag key: got %s want %s", c.name, k, c.tagKeys[i])
+				}
+			}
+		}
+		fieldKeys := p.FieldKeys()
+		if got := len(fieldKeys); got != c.fieldCount {
+			t.Errorf("%s: incorrect number of fields: got %d want %d", c.name, got, c.fieldCount)
+		}
+		for _, k := range fieldKeys {
+			if p.GetFieldValue(k) == nil {
+				t.Errorf("%s: field %s returned a nil value unexpectedly", c.name, k)
+			}
+		}
+	}
+}
+
+func TestDiskMeasurementToPoint(t *testing.T) {
+	m := NewDiskMeasurement(time.Now())
+	m.Tick(time.Second)
+	p := data.NewPoint()
+	m.ToPoint(p)
+
+	total := p.GetFieldValue(labelDiskTotal).(int64)
+	free := p.GetFieldValue(labelDiskFree).(int64)
+	used := p.GetFieldValue(labelDiskUsed).(int64)
+	if total != oneTB || used != total-free {
+		t.Errorf("inconsistent disk usage: total %d free %d used %d", total, free, used)
+	}
+	if got := p.GetFieldValue(labelDiskINodesFree).(int64); got != free/inodeSize {
+		t.Errorf("incorrect free inodes: got %d want %d", got, free/inodeSize)
+	}
+}
+
+func TestMemMeasurementToPoint(t *testing.T) {
+	m := NewMemMeasurement(time.Now())
+	m.Tick(time.Second)
+	p := data.NewPoint()
+	m.ToPoint(p)
+
+	total := p.GetFieldValue(labelMemTotal).(int64)
+	if !(total == 8<<30 || total == 12<<30 || total == 16<<30) {
+		t.Errorf("unexpected total memory: %d", total)
+	}
+	usedPercent := p.GetFieldValue(labelMemUsedPercent).(float64)
+	availablePercent := p.GetFieldValue(labelMemAvailablePercent).(float64)
+	if d := usedPercent + availablePercent - 100; d > 1e-6 || d < -1e-6 {
+		t.Errorf("used and available percentages do not add up: %f + %f", usedPercent, availablePercent)
+	}
+}
+
+func TestRedisMeasurementUptime(t *testing.T) {
+	m := NewRedisMeasurement(time.Now())
+	m.Tick(10 * time.Second)
+	m.Tick(10 * time.Second)
+	p := data.NewPoint()
+	m.ToPoint(p)
+	if got := p.GetFieldValue(labelRedisUptime).(int64); got != 20 {
+		t.Errorf("incorrect uptime: got %d want 20", got)
+	}
+}
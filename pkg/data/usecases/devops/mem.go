@@ -0,0 +1,83 @@
+package devops
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+var (
+	labelMem = []byte("mem")
+
+	labelMemTotal            = []byte("total")
+	labelMemAvailable        = []byte("available")
+	labelMemUsed             = []byte("used")
+	labelMemFree             = []byte("free")
+	labelMemCached           = []byte("cached")
+	labelMemBuffered         = []byte("buffered")
+	labelMemUsedPercent      = []byte("used_percent")
+	labelMemAvailablePercent = []byte("available_percent")
+	labelMemBufferedPercent  = []byte("buffered_percent")
+
+	memBytesTotalChoices = []int64{8 << 30, 12 << 30, 16 << 30}
+)
+
+// MemMeasurement represents the memory usage of a host. The total memory of a
+// host does not change.
+type MemMeasurement struct {
+	*common.SubsystemMeasurement
+	bytesTotal int64
+
+	bytesUsedDist     common.Distribution
+	bytesCachedDist   common.Distribution
+	bytesBufferedDist common.Distribution
+}
+
+// NewMemMeasurement creates a MemMeasurement with start time.
+func NewMemMeasurement(start time.Time) *MemMeasurement {
+	sub := common.NewSubsystemMeasurement(start, 0)
+	bytesTotal := common.RandomInt64SliceChoice(memBytesTotalChoices)
+	total := float64(bytesTotal)
+	bytesDist := func() common.Distribution {
+		return common.CWD(common.ND(0.0, total/64), 0.0, total, rand.Float64()*total)
+	}
+	return &MemMeasurement{
+		SubsystemMeasurement: sub,
+		bytesTotal:           bytesTotal,
+		bytesUsedDist:        bytesDist(),
+		bytesCachedDist:      bytesDist(),
+		bytesBufferedDist:    bytesDist(),
+	}
+}
+
+// Tick advances the memory usage.
+func (m *MemMeasurement) Tick(d time.Duration) {
+	m.SubsystemMeasurement.Tick(d)
+	m.bytesUsedDist.Advance()
+	m.bytesCachedDist.Advance()
+	m.bytesBufferedDist.Advance()
+}
+
+// ToPoint serializes MemMeasurement to data.Point.
+func (m *MemMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelMem)
+	copy := m.Timestamp
+	p.SetTimestamp(&copy)
+
+	total := float64(m.bytesTotal)
+	used := m.bytesUsedDist.Get()
+	cached := m.bytesCachedDist.Get()
+	buffered := m.bytesBufferedDist.Get()
+
+	p.AppendField(labelMemTotal, m.bytesTotal)
+	p.AppendField(labelMemAvailable, int64(total-used))
+	p.AppendField(labelMemUsed, int64(used))
+	p.AppendField(labelMemFree, int64(total-used))
+	p.AppendField(labelMemCached, int64(cached))
+	p.AppendField(labelMemBuffered, int64(buffered))
+	p.AppendField(labelMemUsedPercent, 100.0*(used/total))
+	p.AppendField(labelMemAvailablePercent, 100.0*(total-used)/total)
+	p.AppendField(labelMemBufferedPercent, 100.0*buffered/total)
+}
@@ -0,0 +1,47 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+var (
+	labelNet          = []byte("net")
+	labelNetInterface = []byte("interface")
+
+	netFields = []common.LabeledDistributionMaker{
+		{Label: []byte("bytes_sent"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("bytes_recv"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("packets_sent"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("packets_recv"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("err_in"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("err_out"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("drop_in"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("drop_out"), DistributionMaker: monotonicND(5, 1)},
+	}
+)
+
+// NetMeasurement represents the counters of a network interface of a host.
+type NetMeasurement struct {
+	*common.SubsystemMeasurement
+	interfaceName string
+}
+
+// NewNetMeasurement creates a NetMeasurement with start time.
+func NewNetMeasurement(start time.Time) *NetMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, netFields)
+	return &NetMeasurement{
+		SubsystemMeasurement: sub,
+		interfaceName:        fmt.Sprintf("eth%d", rand.Intn(4)),
+	}
+}
+
+// ToPoint serializes NetMeasurement to data.Point.
+func (m *NetMeasurement) ToPoint(p *data.Point) {
+	p.AppendTag(labelNetInterface, m.interfaceName)
+	m.ToPointAllInt64(p, labelNet, netFields)
+}
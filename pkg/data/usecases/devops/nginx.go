@@ -0,0 +1,51 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+var (
+	labelNginx       = []byte("nginx")
+	labelNginxPort   = []byte("port")
+	labelNginxServer = []byte("server")
+
+	nginxFields = []common.LabeledDistributionMaker{
+		{Label: []byte("accepts"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("active"), DistributionMaker: clampedND(5, 1, 0, 100, 0)},
+		{Label: []byte("handled"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("reading"), DistributionMaker: clampedND(5, 1, 0, 100, 0)},
+		{Label: []byte("requests"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("waiting"), DistributionMaker: clampedND(5, 1, 0, 100, 0)},
+		{Label: []byte("writing"), DistributionMaker: clampedND(5, 1, 0, 100, 0)},
+	}
+)
+
+// NginxMeasurement represents the connection stats of an nginx server running
+// on a host.
+type NginxMeasurement struct {
+	*common.SubsystemMeasurement
+	port   string
+	server string
+}
+
+// NewNginxMeasurement creates a NginxMeasurement with start time.
+func NewNginxMeasurement(start time.Time) *NginxMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, nginxFields)
+	return &NginxMeasurement{
+		SubsystemMeasurement: sub,
+		port:                 fmt.Sprintf("%d", rand.Intn(20000)+1024),
+		server:               fmt.Sprintf("nginx_%d", rand.Intn(100000)),
+	}
+}
+
+// ToPoint serializes NginxMeasurement to data.Point.
+func (m *NginxMeasurement) ToPoint(p *data.Point) {
+	p.AppendTag(labelNginxPort, m.port)
+	p.AppendTag(labelNginxServer, m.server)
+	m.ToPointAllInt64(p, labelNginx, nginxFields)
+}
@@ -0,0 +1,52 @@
+package devops
+
+import (
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+var (
+	// labelPostgresql keeps the measurement name of TSBS, so that the
+	// datasets are the same.
+	labelPostgresql = []byte("postgresl")
+
+	pgFields = []common.LabeledDistributionMaker{
+		{Label: []byte("numbackends"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("xact_commit"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("xact_rollback"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("blks_read"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("blks_hit"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("tup_returned"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("tup_fetched"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("tup_inserted"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("tup_updated"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("tup_deleted"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("conflicts"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("temp_files"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("temp_bytes"), DistributionMaker: clampedND(1024, 1, 0, 1024*1024*1024, 0)},
+		{Label: []byte("deadlocks"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("blk_read_time"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("blk_write_time"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+	}
+)
+
+// PostgresqlMeasurement represents the database stats of a PostgreSQL server
+// running on a host.
+type PostgresqlMeasurement struct {
+	*common.SubsystemMeasurement
+}
+
+// NewPostgresqlMeasurement creates a PostgresqlMeasurement with start time.
+func NewPostgresqlMeasurement(start time.Time) *PostgresqlMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, pgFields)
+	return &PostgresqlMeasurement{
+		SubsystemMeasurement: sub,
+	}
+}
+
+// ToPoint serializes PostgresqlMeasurement to data.Point.
+func (m *PostgresqlMeasurement) ToPoint(p *data.Point) {
+	m.ToPointAllInt64(p, labelPostgresql, pgFields)
+}
@@ -0,0 +1,85 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const sixteenGB = 16 * 1024 * 1024 * 1024
+
+var (
+	labelRedis       = []byte("redis")
+	labelRedisPort   = []byte("port")
+	labelRedisServer = []byte("server")
+	labelRedisUptime = []byte("uptime_in_seconds")
+
+	redisFields = []common.LabeledDistributionMaker{
+		{Label: []byte("total_connections_received"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("expired_keys"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("evicted_keys"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("keyspace_hits"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("keyspace_misses"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("instantaneous_ops_per_sec"), DistributionMaker: clampedND(1, 1, 0, 1e9, 0)},
+		{Label: []byte("instantaneous_input_kbps"), DistributionMaker: clampedND(1, 1, 0, 1e9, 0)},
+		{Label: []byte("instantaneous_output_kbps"), DistributionMaker: clampedND(1, 1, 0, 1e9, 0)},
+		{Label: []byte("connected_clients"), DistributionMaker: clampedND(50, 1, 0, 10000, 0)},
+		{Label: []byte("used_memory"), DistributionMaker: clampedND(50, 1, 0, sixteenGB, sixteenGB/2)},
+		{Label: []byte("used_memory_rss"), DistributionMaker: clampedND(50, 1, 0, sixteenGB, sixteenGB/2)},
+		{Label: []byte("used_memory_peak"), DistributionMaker: clampedND(50, 1, 0, sixteenGB, sixteenGB/2)},
+		{Label: []byte("used_memory_lua"), DistributionMaker: clampedND(50, 1, 0, sixteenGB, sixteenGB/2)},
+		{Label: []byte("rdb_changes_since_last_save"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("sync_full"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("sync_partial_ok"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("sync_partial_err"), DistributionMaker: monotonicND(5, 1)},
+		{Label: []byte("pubsub_channels"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("pubsub_patterns"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("latest_fork_usec"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("connected_slaves"), DistributionMaker: clampedND(5, 1, 0, 10, 0)},
+		{Label: []byte("master_repl_offset"), DistributionMaker: monotonicND(50, 1)},
+		{Label: []byte("repl_backlog_active"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("repl_backlog_size"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("repl_backlog_histlen"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("mem_fragmentation_ratio"), DistributionMaker: clampedND(5, 1, 0, 100, 0)},
+		{Label: []byte("used_cpu_sys"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("used_cpu_user"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("used_cpu_sys_children"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+		{Label: []byte("used_cpu_user_children"), DistributionMaker: clampedND(5, 1, 0, 1000, 0)},
+	}
+)
+
+// RedisMeasurement represents the stats of a Redis server running on a host.
+type RedisMeasurement struct {
+	*common.SubsystemMeasurement
+	port   string
+	server string
+	uptime time.Duration
+}
+
+// NewRedisMeasurement creates a RedisMeasurement with start time.
+func NewRedisMeasurement(start time.Time) *RedisMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, redisFields)
+	return &RedisMeasurement{
+		SubsystemMeasurement: sub,
+		port:                 fmt.Sprintf("%d", rand.Intn(20000)+1024),
+		server:               fmt.Sprintf("redis_%d", rand.Intn(100000)),
+		uptime:               time.Duration(0),
+	}
+}
+
+// Tick advances the stats and the uptime of the server.
+func (m *RedisMeasurement) Tick(d time.Duration) {
+	m.SubsystemMeasurement.Tick(d)
+	m.uptime += d
+}
+
+// ToPoint serializes RedisMeasurement to data.Point.
+func (m *RedisMeasurement) ToPoint(p *data.Point) {
+	p.AppendTag(labelRedisPort, m.port)
+	p.AppendTag(labelRedisServer, m.server)
+	m.ToPointAllInt64(p, labelRedis, redisFields)
+	p.AppendField(labelRedisUptime, int64(m.uptime.Seconds()))
+}
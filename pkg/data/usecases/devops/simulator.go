@@ -0,0 +1,18 @@
+package devops
+
+import (
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a devops Simulator.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig common.BaseSimulatorConfig
+
+// NewSimulator produces a devops Simulator with the given config over the
+// specified interval and points limit. Every host reports all its
+// measurements at each interval, in order.
+func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	return (*common.BaseSimulatorConfig)(sc).NewSimulator(interval, limit)
+}
@@ -0,0 +1,47 @@
+package devops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+func TestSimulator(t *testing.T) {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	sc := &SimulatorConfig{
+		Start:                start,
+		End:                  start.Add(time.Minute),
+		InitGeneratorScale:   2,
+		GeneratorScale:       2,
+		GeneratorConstructor: NewHost,
+	}
+	s := sc.NewSimulator(10*time.Second, 0)
+
+	if got := len(s.Fields()); got != 8 {
+		t.Errorf("incorrect number of measurements: got %d want 8", got)
+	}
+	if got := s.TagKeys(); len(got) != len(MachineTagKeys) || got[0] != "hostname" {
+		t.Errorf("incorrect tag keys: got %v", got)
+	}
+
+	// 6 epochs of 8 measurements for 2 hosts
+	want := 6 * 8 * 2
+	counts := map[string]int{}
+	n := 0
+	for !s.Finished() {
+		p := data.NewPoint()
+		if s.Next(p) {
+			counts[string(p.MeasurementName())]++
+		}
+		n++
+	}
+	if n != want {
+		t.Errorf("incorrect number of points: got %d want %d", n, want)
+	}
+	for name, c := range counts {
+		if c != 12 {
+			t.Errorf("incorrect number of %s points: got %d want 12", name, c)
+		}
+	}
+}
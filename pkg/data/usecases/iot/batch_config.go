@@ -1,21 +1,9 @@
 package iot
 
-import "math/rand"
-
-var (
-	// Batch chances.
-	bMissingChance        = 0.01
-	bOutOfOrderChance     = 0.05
-	bInsertPreviousChance = 0.5
-
-	// Entry chances.
-	eMissingChance        = 0.1
-	eOutOfOrderChance     = 0.3
-	eInsertPreviousChance = 0.5
-
-	// Zero values.
-	zeroTagChance   = 0.01
-	zeroFieldChance = 0.1
+import (
+	"math/rand"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
 )
 
 type batchConfig struct {
@@ -32,9 +20,11 @@ type batchConfig struct {
 	OutOfOrderEntries   map[int]bool
 }
 
-func newBatchConfig(outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount int) *batchConfig {
+// newBatchConfig draws the configuration of the next batch with the chances
+// of the given quality profile.
+func newBatchConfig(q *common.QualityProfile, outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount int) *batchConfig {
 
-	batchMissing := rand.Float64() < bMissingChance
+	batchMissing := rand.Float64() < q.BatchMissingChance
 
 	if batchMissing {
 		return &batchConfig{
@@ -42,11 +32,11 @@ func newBatchConfig(outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagC
 		}
 	}
 
-	batchOutOfOrder := rand.Float64() < bOutOfOrderChance
+	batchOutOfOrder := rand.Float64() < q.BatchOutOfOrderChance
 
 	batchInsertPrevious := false
 	if outOfOrderBatchCount > 0 {
-		batchInsertPrevious = rand.Float64() < bInsertPreviousChance
+		batchInsertPrevious = rand.Float64() < q.BatchInsertPreviousChance
 	}
 
 	zeroFields := make(map[int]int)
@@ -55,27 +45,27 @@ func newBatchConfig(outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagC
 	missingEntries := make(map[int]bool)
 	outOfOrderEntries := make(map[int]bool)
 
-	for i := 0; i < defaultBatchSize; i++ {
-		if outOfOrderEntryCount > 0 && rand.Float64() < eInsertPreviousChance {
+	for i := 0; i < int(q.BatchSize); i++ {
+		if outOfOrderEntryCount > 0 && rand.Float64() < q.EntryInsertPreviousChance {
 			insertPreviousEntry[i] = true
 			outOfOrderEntryCount--
 		}
 
-		if rand.Float64() < eMissingChance {
+		if rand.Float64() < q.EntryMissingChance {
 			missingEntries[i] = true
 			// Since the entry is missing, no point in setting zero values or making it out-of-order.
 			continue
 		}
 
-		if fieldCount > 0 && rand.Float64() < zeroFieldChance {
+		if fieldCount > 0 && rand.Float64() < q.ZeroFieldChance {
 			zeroFields[i] = rand.Intn(fieldCount)
 		}
 
-		if tagCount > 0 && rand.Float64() < zeroTagChance {
+		if tagCount > 0 && rand.Float64() < q.ZeroTagChance {
 			zeroTags[i] = rand.Intn(tagCount)
 		}
 
-		if rand.Float64() < eOutOfOrderChance {
+		if rand.Float64() < q.EntryOutOfOrderChance {
 			outOfOrderEntries[i] = true
 		}
 	}
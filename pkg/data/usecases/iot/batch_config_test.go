@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -15,13 +16,14 @@ var (
 func TestNewBatchConfig(t *testing.T) {
 
 	batchRuns := make([][]*batchConfig, numberOfRuns)
+	profile := common.QualityProfiles[common.QualityDefault]
 
 	for i := 0; i < numberOfRuns; i++ {
 		rand.Seed(123)
 		batchRuns[i] = make([]*batchConfig, numberOfBatches)
 
 		for j := 0; j < numberOfBatches; j++ {
-			batchRuns[i][j] = newBatchConfig(j, j, j+5, j+5)
+			batchRuns[i][j] = newBatchConfig(&profile, j, j, j+5, j+5)
 		}
 	}
 
@@ -0,0 +1,121 @@
+package iot
+
+import (
+	"bytes"
+	"math/rand"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/internal/utils"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+const (
+	// Trucks are on the road during the day, the most at 14:00 UTC.
+	seasonalAmplitude = 0.5
+	seasonalPeriod    = 24 * time.Hour
+	seasonalPeak      = 14 * time.Hour
+
+	// Fuel consumption bursts about once every 100 readings.
+	spikeRate = 0.01
+
+	// Elevation changes its level about once every 1000 readings.
+	stepChance = 0.001
+
+	// Fuel consumption goes wrong about once a day, for 10 minutes to an hour.
+	anomalyMeanGap   = 24 * time.Hour
+	anomalyMinLength = 10 * time.Minute
+	anomalyMaxLength = time.Hour
+)
+
+var (
+	labelAnomaly = []byte("anomaly")
+
+	spikeMagnitudeUD = common.UD(5, 20)
+	stepLevelUD      = common.UD(-500, 500)
+
+	// anomalyKinds are the anomalies of the fuel consumption. The anomaly
+	// field of the readings is the index of the active one plus 1, or 0.
+	anomalyKinds = []common.AnomalyWindow{
+		{Label: "fuel_leak", Factor: 1.5},
+		{Label: "sensor_dropout", Factor: 0},
+		{Label: "sensor_drift", Factor: 1, Offset: 10},
+	}
+)
+
+// applyPatterns wraps the distributions of the readings with the given value
+// patterns, keeping the precision of the fields. The patterns are applied in
+// the order of common.PatternChoices, whatever order they are given in.
+func (m *ReadingsMeasurement) applyPatterns(start, end time.Time, patterns []string) {
+	for _, pattern := range common.PatternChoices {
+		if !utils.IsIn(pattern, patterns) {
+			continue
+		}
+		switch pattern {
+		case common.PatternSeasonal:
+			m.wrap(labelVelocity, 0, func(d common.Distribution) common.Distribution {
+				return common.SD(d, seasonalAmplitude, seasonalPeriod, seasonalPeak)
+			})
+			m.wrap(labelFuelConsumption, 1, func(d common.Distribution) common.Distribution {
+				return common.SD(d, seasonalAmplitude, seasonalPeriod, seasonalPeak)
+			})
+		case common.PatternSpikes:
+			m.wrap(labelFuelConsumption, 1, func(d common.Distribution) common.Distribution {
+				return common.SPD(d, spikeRate, spikeMagnitudeUD)
+			})
+		case common.PatternSteps:
+			m.wrap(labelElevation, 0, func(d common.Distribution) common.Distribution {
+				return common.SCD(d, stepLevelUD, stepChance)
+			})
+		case common.PatternAnomalies:
+			m.wrap(labelFuelConsumption, 1, func(d common.Distribution) common.Distribution {
+				m.anomaly = common.AD(d, newAnomalyWindows(start, end))
+				return m.anomaly
+			})
+		}
+	}
+
+	for _, d := range m.Distributions {
+		common.SetDistributionTime(d, start)
+	}
+}
+
+// wrap replaces the distribution of the field with the given label by its
+// wrapped version, rounded to the given precision.
+func (m *ReadingsMeasurement) wrap(label []byte, precision int, wrapper func(common.Distribution) common.Distribution) {
+	for i, f := range readingsFields {
+		if bytes.Equal(f.Label, label) {
+			m.Distributions[i] = common.FP(wrapper(m.Distributions[i]), precision)
+			return
+		}
+	}
+	panic("unknown readings field " + string(label))
+}
+
+// newAnomalyWindows draws random anomaly windows between start and end, with
+// exponentially distributed gaps between them.
+func newAnomalyWindows(start, end time.Time) []common.AnomalyWindow {
+	var windows []common.AnomalyWindow
+	gap := func() time.Duration {
+		return time.Duration(rand.ExpFloat64() * float64(anomalyMeanGap))
+	}
+
+	for t := start.Add(gap()); t.Before(end); t = t.Add(gap()) {
+		w := anomalyKinds[rand.Intn(len(anomalyKinds))]
+		w.Start = t
+		w.End = t.Add(anomalyMinLength + time.Duration(rand.Int63n(int64(anomalyMaxLength-anomalyMinLength))))
+		windows = append(windows, w)
+		t = w.End
+	}
+
+	return windows
+}
+
+// anomalyCode returns the value of the anomaly field for the given label.
+func anomalyCode(label string) int {
+	for i, kind := range anomalyKinds {
+		if kind.Label == label {
+			return i + 1
+		}
+	}
+	return 0
+}
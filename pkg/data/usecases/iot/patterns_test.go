@@ -0,0 +1,83 @@
+package iot
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+func readingsValues(patterns []string, ticks int) [][]interface{} {
+	rand.Seed(123)
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewReadingsMeasurement(start)
+	m.applyPatterns(start, start.Add(time.Duration(ticks)*time.Minute), patterns)
+
+	values := make([][]interface{}, ticks)
+	for i := range values {
+		p := data.NewPoint()
+		m.ToPoint(p)
+		values[i] = p.FieldValues()
+		m.Tick(time.Minute)
+	}
+	return values
+}
+
+func TestReadingsMeasurementApplyPatterns(t *testing.T) {
+	ticks := 7 * 24 * 60
+
+	plain := readingsValues(nil, ticks)
+	if got := len(plain[0]); got != len(readingsFields) {
+		t.Errorf("incorrect field count without patterns: got %d want %d", got, len(readingsFields))
+	}
+
+	all := readingsValues(common.PatternChoices, ticks)
+	if got := len(all[0]); got != len(readingsFields)+1 {
+		t.Fatalf("incorrect field count with anomalies: got %d want %d", got, len(readingsFields)+1)
+	}
+	anomalies := 0
+	for _, values := range all {
+		if values[len(values)-1].(float64) != 0 {
+			anomalies++
+		}
+	}
+	// About one anomaly of 10 to 60 minutes a day.
+	if anomalies == 0 || anomalies > ticks/10 {
+		t.Errorf("unexpected number of anomalous readings: got %d of %d", anomalies, ticks)
+	}
+
+	reversed := make([]string, len(common.PatternChoices))
+	for i, pattern := range common.PatternChoices {
+		reversed[len(reversed)-1-i] = pattern
+	}
+	if !reflect.DeepEqual(all, readingsValues(reversed, ticks)) {
+		t.Errorf("readings depend on the order of the patterns")
+	}
+}
+
+func TestNewTruckWithPatterns(t *testing.T) {
+	start := time.Now()
+	cases := []struct {
+		patterns    []string
+		wantAnomaly bool
+	}{
+		{patterns: nil},
+		{patterns: []string{common.PatternSeasonal, common.PatternSteps}},
+		{patterns: []string{common.PatternAnomalies}, wantAnomaly: true},
+	}
+
+	for _, c := range cases {
+		truck := NewTruckWithPatterns(c.patterns, start.Add(24*time.Hour))(1, start)
+		if got := len(truck.Measurements()); got != 2 {
+			t.Errorf("%v: incorrect measurement count: got %d want %d", c.patterns, got, 2)
+		}
+		p := data.NewPoint()
+		truck.Measurements()[0].ToPoint(p)
+		if got := p.GetFieldValue(labelAnomaly) != nil; got != c.wantAnomaly {
+			t.Errorf("%v: incorrect anomaly field presence: got %v want %v", c.patterns, got, c.wantAnomaly)
+		}
+	}
+}
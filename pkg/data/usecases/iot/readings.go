@@ -130,6 +130,8 @@ var (
 // ReadingsMeasurement represents a subset of truck measurement readings.
 type ReadingsMeasurement struct {
 	*common.SubsystemMeasurement
+	// anomaly is set when the readings have anomaly windows, see applyPatterns.
+	anomaly *common.AnomalyDistribution
 }
 
 // ToPoint serializes ReadingsMeasurement to serialize.Point.
@@ -141,6 +143,9 @@ func (m *ReadingsMeasurement) ToPoint(p *data.Point) {
 	for i, d := range m.Distributions {
 		p.AppendField(readingsFields[i].Label, float64(d.Get()))
 	}
+	if m.anomaly != nil {
+		p.AppendField(labelAnomaly, float64(anomalyCode(m.anomaly.Label())))
+	}
 }
 
 // NewReadingsMeasurement creates a new ReadingsMeasurement with start time.
@@ -7,18 +7,34 @@ import (
 )
 
 const (
-	// The default size of a batch of entries within a simulation.
-	defaultBatchSize = 10
+	// Names of the counters of the Simulator summary.
+	summaryMissingBatches    = "missing batches"
+	summaryMissingEntries    = "missing entries"
+	summaryOutOfOrderBatches = "out-of-order batches"
+	summaryOutOfOrderEntries = "out-of-order entries"
+	summaryLateEntries       = "entries written at max lateness"
+	summaryZeroFields        = "zero fields"
+	summaryZeroTags          = "zero tags"
 )
 
 // SimulatorConfig is used to create an IoT Simulator.
 // It fulfills the common.SimulatorConfig interface.
-type SimulatorConfig common.BaseSimulatorConfig
+type SimulatorConfig struct {
+	*common.BaseSimulatorConfig
+	// Quality sets the chances of missing, out-of-order and zero-valued
+	// entries. When nil, the default profile is used.
+	Quality *common.QualityProfile
+}
 
 // NewSimulator produces an IoT Simulator with the given
 // config over the specified interval and points limit.
 func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
-	s := (*common.BaseSimulatorConfig)(sc).NewSimulator(interval, limit)
+	s := sc.BaseSimulatorConfig.NewSimulator(interval, limit)
+	
+	quality := common.QualityProfiles[common.QualityDefault]
+	if sc.Quality != nil {
+		quality = *sc.Quality
+	}
 	
 	maxFieldCount := 0
 	
@@ -29,10 +45,13 @@ func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) co
 	}
 	
 	return &Simulator{
-		base:            s,
-		batchSize:       defaultBatchSize,
-		configGenerator: newBatchConfig,
-		maxFieldCount:   maxFieldCount,
+		base:      s,
+		batchSize: quality.BatchSize,
+		configGenerator: func(outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount int) *batchConfig {
+			return newBatchConfig(&quality, outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount)
+		},
+		maxFieldCount: maxFieldCount,
+		maxLateness:   quality.MaxLateness,
 	}
 }
 
@@ -46,6 +65,9 @@ type Simulator struct {
 	configGenerator func(outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount int) *batchConfig
 	// maxFieldCount is the maximum amount of fields an entry can have
 	maxFieldCount int
+	// maxLateness is how far behind the newest entry pending out-of-order
+	// items can fall before they are inserted, 0 means no limit.
+	maxLateness time.Duration
 	
 	// Mutable state.
 	currBatch         []*data.Point
@@ -54,6 +76,10 @@ type Simulator struct {
 	// offset is used for dealing with batch generation and keeping the
 	// insert index consistent.
 	offset int
+	// newest is the latest timestamp of the entries taken from the base simulator.
+	newest time.Time
+	// summary counts the missing, out-of-order and zero-valued items.
+	summary map[string]uint64
 }
 
 // Fields returns the fields of an entry.
@@ -93,12 +119,22 @@ func (s *Simulator) Next(p *data.Point) bool {
 	return false
 }
 
-func (s *Simulator) Headers() *common.GeneratedDataHeaders {
-	return &common.GeneratedDataHeaders{
-		TagTypes:  s.TagTypes(),
-		TagKeys:   s.TagKeys(),
-		FieldKeys: s.Fields(),
+// Summary returns how many items were dropped, sent out of order or zeroed.
+func (s *Simulator) Summary() map[string]uint64 {
+	return s.summary
+}
+
+// count adds n to the summary counter of the given name.
+func (s *Simulator) count(name string, n int) {
+	if s.summary == nil {
+		s.summary = make(map[string]uint64)
 	}
+	s.summary[name] += uint64(n)
+}
+
+// Headers returns the tags and fields of an entry, with their types.
+func (s *Simulator) Headers() *common.GeneratedDataHeaders {
+	return s.base.Headers()
 }
 
 // pendingOutOfOrderItems returns whether the simulator has pending
@@ -135,6 +171,47 @@ func (s *Simulator) batchPending() []*data.Point {
 	return batch
 }
 
+// batchOverdue creates a batch from the pending items which fell more than
+// maxLateness behind the newest entry, so that they are not delayed further.
+func (s *Simulator) batchOverdue() []*data.Point {
+	if s.maxLateness <= 0 || s.newest.IsZero() {
+		return nil
+	}
+	deadline := s.newest.Add(-s.maxLateness)
+	
+	// Out-of-order batches can carry older out-of-order entries, so any of
+	// their entries can be overdue.
+	for i, batch := range s.outOfOrderBatches {
+		for _, entry := range batch {
+			if isBefore(entry, deadline) {
+				s.outOfOrderBatches = append(s.outOfOrderBatches[:i], s.outOfOrderBatches[i+1:]...)
+				s.count(summaryLateEntries, len(batch))
+				return batch
+			}
+		}
+	}
+	
+	var batch []*data.Point
+	pending := s.outOfOrderEntries[:0]
+	for _, entry := range s.outOfOrderEntries {
+		if isBefore(entry, deadline) {
+			batch = append(batch, entry)
+		} else {
+			pending = append(pending, entry)
+		}
+	}
+	s.outOfOrderEntries = pending
+	s.count(summaryLateEntries, len(batch))
+	
+	return batch
+}
+
+// isBefore tells whether the entry is timestamped before t.
+func isBefore(entry *data.Point, t time.Time) bool {
+	ts := entry.Timestamp()
+	return ts != nil && ts.Before(t)
+}
+
 // simulateNextBatch is used to generate a new batch of entries once the current one is depleted.
 func (s *Simulator) simulateNextBatch() bool {
 	if s.base.Finished() {
@@ -146,6 +223,11 @@ func (s *Simulator) simulateNextBatch() bool {
 		return false
 	}
 	
+	if batch := s.batchOverdue(); len(batch) > 0 {
+		s.currBatch = batch
+		return true
+	}
+	
 	bc := s.configGenerator(len(s.outOfOrderBatches), len(s.outOfOrderEntries), s.maxFieldCount, len(s.TagKeys()))
 	
 	if bc.InsertPrevious {
@@ -158,6 +240,7 @@ func (s *Simulator) simulateNextBatch() bool {
 	}
 	
 	if bc.Missing {
+		s.count(summaryMissingBatches, 1)
 		s.flushBatch()
 		return s.simulateNextBatch()
 	}
@@ -203,6 +286,7 @@ func (s *Simulator) generateBatch(bc *batchConfig) []*data.Point {
 				index = index % len(keys)
 			}
 			entry.ClearFieldValue(keys[index])
+			s.count(summaryZeroFields, 1)
 		}
 		
 		if index, ok := bc.ZeroTags[i]; ok {
@@ -211,6 +295,7 @@ func (s *Simulator) generateBatch(bc *batchConfig) []*data.Point {
 				panic("trying to zero a tag value with a non-existant index")
 			}
 			entry.ClearTagValue(keys[index])
+			s.count(summaryZeroTags, 1)
 		}
 		
 		batch[i] = entry
@@ -241,15 +326,20 @@ func (s *Simulator) getNextEntry(index int, bc *batchConfig) (*data.Point, bool)
 			if valid = s.base.Next(entry); !valid {
 				break
 			}
+			if ts := entry.Timestamp(); ts != nil && ts.After(s.newest) {
+				s.newest = *ts
+			}
 		}
 		
 		if bc.MissingEntries[index+s.offset] {
+			s.count(summaryMissingEntries, 1)
 			s.offset++
 			continue
 		}
 		
 		if bc.OutOfOrderEntries[index+s.offset] {
 			s.outOfOrderEntries = append(s.outOfOrderEntries, entry)
+			s.count(summaryOutOfOrderEntries, 1)
 			s.offset++
 			continue
 		}
@@ -266,6 +356,7 @@ func (s *Simulator) generateOutOfOrderBatch(bc *batchConfig) {
 	
 	if len(batch) > 0 {
 		s.outOfOrderBatches = append(s.outOfOrderBatches, batch)
+		s.count(summaryOutOfOrderBatches, 1)
 	}
 }
 
@@ -277,5 +368,6 @@ func (s *Simulator) flushBatch() {
 		if !valid {
 			break
 		}
+		s.count(summaryMissingEntries, 1)
 	}
 }
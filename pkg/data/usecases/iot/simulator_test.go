@@ -557,14 +557,83 @@ func TestSimulatorNext(t *testing.T) {
 	
 }
 
+func TestSimulatorMaxLateness(t *testing.T) {
+	cases := []struct {
+		desc        string
+		maxLateness time.Duration
+		result      []int
+		summary     map[string]uint64
+	}{
+		{
+			desc:   "no max lateness",
+			result: []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 0, 1},
+			summary: map[string]uint64{
+				summaryOutOfOrderBatches: 1,
+			},
+		},
+		{
+			desc:        "max lateness of 3s",
+			maxLateness: 3 * time.Second,
+			result:      []int{2, 3, 4, 5, 0, 1, 6, 7, 8, 9, 10, 11, 12, 13},
+			summary: map[string]uint64{
+				summaryOutOfOrderBatches: 1,
+				summaryLateEntries:       2,
+			},
+		},
+	}
+	
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			m := newMockBaseSimulator()
+			for i, p := range m.pending {
+				ts := m.now.Add(time.Duration(i) * time.Second)
+				p.SetTimestamp(&ts)
+			}
+			batches := 0
+			s := &Simulator{
+				base:      m,
+				batchSize: 2,
+				// Only the first batch is out of order, and it is never
+				// inserted unless it gets too late.
+				configGenerator: func(i, j, k, z int) *batchConfig {
+					batches++
+					return &batchConfig{OutOfOrder: batches == 1}
+				},
+				maxLateness: c.maxLateness,
+			}
+			
+			results := make([]*data.Point, 0)
+			for i := 0; i < pointCount; i++ {
+				point := data.NewPoint()
+				if !s.Next(point) {
+					break
+				}
+				results = append(results, point)
+			}
+			
+			if len(results) != len(c.result) {
+				t.Fatalf("simulator didn't return correct number of points, got %d want %d", len(results), len(c.result))
+			}
+			if i, ok := checkResults(m.pending, results, c.result); !ok {
+				t.Errorf("results not as expected at index %d:\ngot\n%v\nwant\n%v", i, results[i], m.pending[c.result[i]])
+			}
+			if got := s.Summary(); !reflect.DeepEqual(got, c.summary) {
+				t.Errorf("incorrect summary: got %v want %v", got, c.summary)
+			}
+		})
+	}
+}
+
 func TestSimulatorTagTypes(t *testing.T) {
 	sc := &SimulatorConfig{
-		Start: time.Now(),
-		End:   time.Now(),
-		
-		InitGeneratorScale:   1,
-		GeneratorScale:       1,
-		GeneratorConstructor: NewTruck,
+		BaseSimulatorConfig: &common.BaseSimulatorConfig{
+			Start: time.Now(),
+			End:   time.Now(),
+			
+			InitGeneratorScale:   1,
+			GeneratorScale:       1,
+			GeneratorConstructor: NewTruck,
+		},
 	}
 	s := sc.NewSimulator(time.Second, 1).(*Simulator)
 	p := data.NewPoint()
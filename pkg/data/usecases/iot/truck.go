@@ -102,6 +102,23 @@ func NewTruck(i int, start time.Time) common.Generator {
 	return &truck
 }
 
+// NewTruckWithPatterns returns a constructor of trucks whose readings follow
+// the given value patterns (see common.PatternChoices) until end.
+func NewTruckWithPatterns(patterns []string, end time.Time) func(i int, start time.Time) common.Generator {
+	generator := func(start time.Time) []common.SimulatedMeasurement {
+		readings := NewReadingsMeasurement(start)
+		readings.applyPatterns(start, end, patterns)
+		return []common.SimulatedMeasurement{
+			readings,
+			NewDiagnosticsMeasurement(start),
+		}
+	}
+	return func(i int, start time.Time) common.Generator {
+		truck := newTruckWithMeasurementGenerator(i, start, generator)
+		return &truck
+	}
+}
+
 func newTruckWithMeasurementGenerator(i int, start time.Time, generator func(time.Time) []common.SimulatedMeasurement) Truck {
 	sm := generator(start)
 
@@ -4,36 +4,155 @@ import (
 	"fmt"
 	"github.com/cnosdb/tsdb-comparisons/internal/utils"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/custom"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/dataset"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/devops"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/iot"
+	"time"
 )
 
 const errCannotParseTimeFmt = "cannot parse time from string '%s': %v"
 
+// realTimeHorizon is how long a real-time simulation runs when no duration is
+// set. Generators get their patterns up to its end, so it is not unbounded.
+const realTimeHorizon = 365 * 24 * time.Hour
+
 func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig, error) {
 	var ret common.SimulatorConfig
 	var err error
-	tsStart, err := utils.ParseUTCTime(dgc.TimeStart)
-	if err != nil {
-		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeStart, err)
-	}
-	tsEnd, err := utils.ParseUTCTime(dgc.TimeEnd)
+	tsStart, tsEnd, err := timeRange(dgc)
 	if err != nil {
-		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
+		return nil, err
 	}
 	
 	switch dgc.Use {
 	
 	case common.UseCaseIoT:
+		quality := dgc.QualityProfile()
 		ret = &iot.SimulatorConfig{
+			BaseSimulatorConfig: &common.BaseSimulatorConfig{
+				Start: tsStart,
+				End:   tsEnd,
+				
+				InitGeneratorScale:   dgc.InitialScale,
+				GeneratorScale:       dgc.Scale,
+				ChurnRate:            dgc.ChurnRate,
+				RealTime:             dgc.RealTime,
+				TimestampJitter:      dgc.TimestampJitter,
+				TimestampPrecision:   common.PrecisionDuration(dgc.TimestampPrecision),
+				GeneratorConstructor: iot.NewTruckWithPatterns(dgc.Patterns, tsEnd),
+			},
+			Quality: &quality,
+		}
+	case common.UseCaseDevops:
+		ret = &devops.SimulatorConfig{
 			Start: tsStart,
 			End:   tsEnd,
 			
 			InitGeneratorScale:   dgc.InitialScale,
 			GeneratorScale:       dgc.Scale,
-			GeneratorConstructor: iot.NewTruck,
+			ChurnRate:            dgc.ChurnRate,
+			RealTime:             dgc.RealTime,
+			TimestampJitter:      dgc.TimestampJitter,
+			TimestampPrecision:   common.PrecisionDuration(dgc.TimestampPrecision),
+			GeneratorConstructor: devops.NewHost,
+		}
+	case common.UseCaseCPUOnly:
+		ret = &devops.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+			
+			InitGeneratorScale:   dgc.InitialScale,
+			GeneratorScale:       dgc.Scale,
+			ChurnRate:            dgc.ChurnRate,
+			RealTime:             dgc.RealTime,
+			TimestampJitter:      dgc.TimestampJitter,
+			TimestampPrecision:   common.PrecisionDuration(dgc.TimestampPrecision),
+			GeneratorConstructor: devops.NewHostCPUOnly,
+		}
+	case common.UseCaseDevopsGeneric:
+		ret = &devops.GenericMetricsSimulatorConfig{
+			BaseSimulatorConfig: &common.BaseSimulatorConfig{
+				Start: tsStart,
+				End:   tsEnd,
+				
+				InitGeneratorScale: dgc.InitialScale,
+				GeneratorScale:     dgc.Scale,
+				ChurnRate:          dgc.ChurnRate,
+				RealTime:           dgc.RealTime,
+				TimestampJitter:    dgc.TimestampJitter,
+				TimestampPrecision: common.PrecisionDuration(dgc.TimestampPrecision),
+			},
+			MaxMetricCount: dgc.MaxMetricCountPerHost,
+		}
+	case common.UseCaseCustom:
+		schema, err := custom.LoadSchema(dgc.CustomSchema)
+		if err != nil {
+			return nil, err
+		}
+		ret = &custom.SimulatorConfig{
+			BaseSimulatorConfig: &common.BaseSimulatorConfig{
+				Start: tsStart,
+				End:   tsEnd,
+				
+				InitGeneratorScale: dgc.InitialScale,
+				GeneratorScale:     dgc.Scale,
+				ChurnRate:          dgc.ChurnRate,
+				RealTime:           dgc.RealTime,
+				TimestampJitter:    dgc.TimestampJitter,
+				TimestampPrecision: common.PrecisionDuration(dgc.TimestampPrecision),
+			},
+			Schema: schema,
 		}
+	case common.UseCaseDataset:
+		ds, err := dataset.Open(dgc.Dataset, dataset.Mapping{
+			Format:            dgc.DatasetFormat,
+			Measurement:       dgc.DatasetMeasurement,
+			MeasurementColumn: dgc.DatasetMeasurementCol,
+			TimeColumn:        dgc.DatasetTimeColumn,
+			TimeFormat:        dgc.DatasetTimeFormat,
+			Tags:              dgc.DatasetTags,
+			Fields:            dgc.DatasetFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sc := &dataset.SimulatorConfig{
+			Dataset: ds,
+			Start:   tsStart,
+			End:     tsEnd,
+			Shift:   dgc.DatasetShift,
+			Copies:  dgc.Scale,
+		}
+		if err := sc.Validate(); err != nil {
+			return nil, err
+		}
+		ret = sc
 	default:
 		err = fmt.Errorf("unknown use case: '%s'", dgc.Use)
 	}
 	return ret, err
 }
+
+// timeRange returns the time range to simulate: the one of the timestamp
+// flags or, in real-time mode, the one starting at the current log interval.
+func timeRange(dgc *common.DataGeneratorConfig) (time.Time, time.Time, error) {
+	if dgc.RealTime {
+		start := time.Now().UTC().Truncate(dgc.LogInterval)
+		duration := dgc.RealTimeDuration
+		if duration == 0 {
+			duration = realTimeHorizon
+		}
+		return start, start.Add(duration), nil
+	}
+	
+	tsStart, err := utils.ParseUTCTime(dgc.TimeStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeStart, err)
+	}
+	tsEnd, err := utils.ParseUTCTime(dgc.TimeEnd)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
+	}
+	return tsStart, tsEnd, nil
+}
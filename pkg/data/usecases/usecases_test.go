@@ -2,6 +2,9 @@ package usecases
 
 import (
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/custom"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/dataset"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/devops"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/iot"
 	"reflect"
 	"testing"
@@ -34,6 +37,35 @@ func TestGetSimulatorConfig(t *testing.T) {
 	}
 	
 	checkType(common.UseCaseIoT, &iot.SimulatorConfig{})
+	checkType(common.UseCaseDevops, &devops.SimulatorConfig{})
+	checkType(common.UseCaseCPUOnly, &devops.SimulatorConfig{})
+	checkType(common.UseCaseDevopsGeneric, &devops.GenericMetricsSimulatorConfig{})
+	
+	dgc.CustomSchema = "../../../docs/sample-configs/custom-sensor-fleet.yaml"
+	checkType(common.UseCaseCustom, &custom.SimulatorConfig{})
+	
+	dgc.CustomSchema = ""
+	dgc.Use = common.UseCaseCustom
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for custom use case without schema")
+	}
+	
+	dgc.Dataset = "../../../docs/sample-configs/dataset-sensor-export.csv"
+	dgc.DatasetTags = []string{"sensor_id", "site"}
+	dgc.DatasetMeasurementCol = "measurement"
+	checkType(common.UseCaseDataset, &dataset.SimulatorConfig{})
+	
+	dgc.DatasetTags = nil
+	dgc.Scale = 2
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for multiplied dataset without tags")
+	}
+	dgc.Scale = 1
+	
+	dgc.Dataset = ""
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for dataset use case without dataset")
+	}
 	
 	dgc.Use = "bogus use case"
 	_, err := GetSimulatorConfig(dgc)
@@ -41,3 +73,43 @@ func TestGetSimulatorConfig(t *testing.T) {
 		t.Errorf("unexpected lack of error for bogus use case")
 	}
 }
+
+func TestTimeRangeRealTime(t *testing.T) {
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T00:00:01Z",
+		},
+		LogInterval: defaultLogInterval,
+	}
+	start, end, err := timeRange(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC); !start.Equal(want) || !end.Equal(want.Add(time.Second)) {
+		t.Errorf("incorrect time range: got %v - %v", start, end)
+	}
+	
+	dgc.RealTime = true
+	dgc.RealTimeDuration = time.Hour
+	before := time.Now()
+	start, end, err = timeRange(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error in real-time mode: %v", err)
+	}
+	if start.After(time.Now()) || !start.After(before.Add(-defaultLogInterval)) {
+		t.Errorf("real-time start not at the current log interval: got %v now %v", start, before)
+	}
+	if start.UnixNano()%int64(defaultLogInterval) != 0 {
+		t.Errorf("real-time start not aligned to the log interval: got %v", start)
+	}
+	if got := end.Sub(start); got != time.Hour {
+		t.Errorf("incorrect real-time duration: got %v want %v", got, time.Hour)
+	}
+	
+	dgc.RealTimeDuration = 0
+	start, end, _ = timeRange(dgc)
+	if got := end.Sub(start); got != realTimeHorizon {
+		t.Errorf("incorrect real-time duration without a set one: got %v want %v", got, realTimeHorizon)
+	}
+}
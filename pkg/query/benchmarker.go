@@ -2,27 +2,35 @@ package query
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"os"
 	"runtime/pprof"
 	"sync"
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
 	"github.com/spf13/pflag"
 	"golang.org/x/time/rate"
 )
 
 const (
-	labelAllQueries  = "all queries"
-	labelColdQueries = "cold queries"
-	labelWarmQueries = "warm queries"
+	labelAllQueries  = results.LabelAllQueries
+	labelColdQueries = results.LabelColdQueries
+	labelWarmQueries = results.LabelWarmQueries
 
 	defaultReadSize = 4 << 20 // 4 MB
 )
 
+// Ways to handle a query that returns an error, see BenchmarkRunnerConfig.OnError
+const (
+	OnErrorAbort = "abort"
+	OnErrorSkip  = "skip"
+	OnErrorRetry = "retry"
+)
+
 // BenchmarkRunnerConfig is the configuration of the benchmark runner.
 type BenchmarkRunnerConfig struct {
 	DBName           string `mapstructure:"db-name"`
@@ -38,6 +46,39 @@ type BenchmarkRunnerConfig struct {
 	PrintInterval    uint64 `mapstructure:"print-interval"`
 	PrewarmQueries   bool   `mapstructure:"prewarm-queries"`
 	ResultsFile      string `mapstructure:"results-file"`
+	// OnError is what to do when a query returns an error: abort the benchmark,
+	// skip the query or retry it up to MaxRetries times before skipping it.
+	// Skipped queries are counted as errors under their label.
+	OnError    string `mapstructure:"on-error"`
+	MaxRetries uint   `mapstructure:"max-retries"`
+	// OpenLoop sends queries on a fixed schedule of LimitRPS queries per second
+	// instead of as fast as the workers allow, and measures their latencies
+	// from their intended send times.
+	OpenLoop bool `mapstructure:"open-loop"`
+	// Duration stops the benchmark after a wall-clock time, WarmupDuration
+	// leaves the queries completed at the start of the run out of the stats,
+	// like BurnIn does for a number of queries.
+	Duration       time.Duration `mapstructure:"duration"`
+	WarmupDuration time.Duration `mapstructure:"warmup-duration"`
+	// Loop reads the query file again from the start when it ends, until
+	// Duration or Limit is reached
+	Loop bool `mapstructure:"loop"`
+	// LoopUntilStopped lets Loop run without Duration or Limit until Stop is
+	// called. It is set by the mixed workloads, whose queries stop when
+	// loading completes, not by flags.
+	LoopUntilStopped bool `mapstructure:"-" json:"-"`
+
+	RecordAnswersFile   string  `mapstructure:"record-answers"`
+	VerifyAnswersFile   string  `mapstructure:"verify-answers"`
+	VerifyTolerance     float64 `mapstructure:"verify-tolerance"`
+	VerifyIgnoreColumns string  `mapstructure:"verify-ignore-columns"`
+	VerifyColumnAliases string  `mapstructure:"verify-column-aliases"`
+
+	// Reporter configures the metrics sinks that receive the stats printed every PrintInterval queries
+	reporter.Config `mapstructure:",squash"`
+	// Target is the name of the queried database, recorded in the results file.
+	// It is set by the query runners, not by flags.
+	Target string `mapstructure:"-" json:"-"`
 }
 
 // AddToFlagSet adds command line flags needed by the BenchmarkRunnerConfig to the flag set.
@@ -45,7 +86,11 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.String("db-name", "benchmark", "Name of database to use for queries")
 	fs.Uint64("burn-in", 0, "Number of queries to ignore before collecting statistics.")
 	fs.Uint64("max-queries", 0, "Limit the number of queries to send, 0 = no limit")
+	fs.Duration("duration", 0, "Stop sending queries after this wall-clock time, 0 = no limit")
+	fs.Duration("warmup-duration", 0, "Time at the start of the run whose queries are not counted in the statistics (a time-based burn-in)")
+	fs.Bool("loop", false, "Read the query file again from the start when it ends, until duration or max-queries is reached")
 	fs.Uint64("max-rps", 0, "Limit the rate of queries per second, 0 = no limit")
+	fs.Bool("open-loop", false, "Send queries at a constant rate of max-rps queries per second, whether the previous ones have completed or not, and measure latencies from their intended send times")
 	fs.Uint64("print-interval", 100, "Print timing stats to stderr after this many queries (0 to disable)")
 	fs.String("memprofile", "", "Write a memory profile to this file.")
 	fs.String("hdr-latencies", "", "Write the High Dynamic Range (HDR) Histogram of Response Latencies to this file.")
@@ -55,29 +100,49 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Int("debug", 0, "Whether to print debug messages.")
 	fs.String("file", "", "File name to read queries from")
 	fs.String("results-file", "", "Write the test results summary json to this file")
+	fs.String("on-error", OnErrorAbort, "What to do when a query fails, one of: "+OnErrorAbort+", "+OnErrorSkip+" (count it as an error and go on), "+OnErrorRetry+" (retry it up to max-retries times, then skip it)")
+	fs.Uint("max-retries", 3, "Number of times to retry a failed query with --on-error="+OnErrorRetry)
+	fs.String("record-answers", "", "Write the canonical result of every query to this file, to be used as reference with --verify-answers")
+	fs.String("verify-answers", "", "Compare the result of every query with the answers recorded in this file (by --record-answers, possibly against another database)")
+	fs.Float64("verify-tolerance", 1e-4, "Relative tolerance when comparing floating point values during result verification")
+	fs.String("verify-ignore-columns", "", "Comma separated list of column names to leave out of result verification (e.g. 'time,ts')")
+	fs.String("verify-column-aliases", "", "YAML file mapping query labels ('*' for all queries) to the names the result columns of this database have in the reference answers, e.g. '\"*\": {ts: time}'")
+	c.Config.AddToFlagSet(fs)
 }
 
 // BenchmarkRunner contains the common components for running a query benchmarking
 // program against a database.
 type BenchmarkRunner struct {
 	BenchmarkRunnerConfig
-	br      *bufio.Reader
-	sp      statProcessor
-	scanner *scanner
-	ch      chan Query
+	br       *bufio.Reader
+	file     *os.File
+	sp       statProcessor
+	scanner  *scanner
+	ch       chan Query
+	verifier *resultVerifier
+	schedule *schedule // schedule is the send schedule of the queries in open-loop mode
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 // NewBenchmarkRunner creates a new instance of BenchmarkRunner which is
 // common functionality to be used by query benchmarker programs
 func NewBenchmarkRunner(config BenchmarkRunnerConfig) *BenchmarkRunner {
 	runner := &BenchmarkRunner{BenchmarkRunnerConfig: config}
-	runner.scanner = newScanner(&runner.Limit)
+	runner.stop = make(chan struct{})
+	runner.scanner = newScanner(&runner.Limit).setStop(runner.stop)
+	metricsReporter, err := reporter.New(config.Config)
+	if err != nil {
+		panic(fmt.Sprintf("could not initialize BenchmarkRunner: %v", err))
+	}
 	spArgs := &statProcessorArgs{
 		limit:            &runner.Limit,
 		printInterval:    runner.PrintInterval,
 		prewarmQueries:   runner.PrewarmQueries,
 		burnIn:           runner.BurnIn,
+		warmupDuration:   runner.WarmupDuration,
 		hdrLatenciesFile: runner.HDRLatenciesFile,
+		reporter:         metricsReporter,
 	}
 
 	runner.sp = newStatProcessor(spArgs)
@@ -104,6 +169,19 @@ func (b *BenchmarkRunner) DatabaseName() string {
 	return b.DBName
 }
 
+// Stop makes a running benchmark stop reading new queries. Queries already
+// read are still executed and Run returns after printing the usual summary.
+func (b *BenchmarkRunner) Stop() {
+	b.stopOnce.Do(func() { close(b.stop) })
+}
+
+// IntervalQuantiles returns the number of queries completed since the previous
+// call (or the start of Run) and the quantiles of their latencies in milliseconds,
+// keyed like the overallQuantiles of the results file. Burn-in queries are not counted.
+func (b *BenchmarkRunner) IntervalQuantiles() (int64, map[string]float64) {
+	return b.sp.intervalQuantiles()
+}
+
 // ProcessorCreate is a function that creates a new Processor (called in Run)
 type ProcessorCreate func() Processor
 
@@ -116,6 +194,15 @@ type Processor interface {
 	ProcessQuery(q Query, isWarm bool) ([]*Stat, error)
 }
 
+// ResultProcessor is a Processor that can also return the result of a query,
+// which is needed for result verification (--record-answers, --verify-answers)
+type ResultProcessor interface {
+	Processor
+
+	// ProcessQueryWithResult handles a given query like ProcessQuery, but also returns its result
+	ProcessQueryWithResult(q Query, isWarm bool) ([]*Stat, *ResultSet, error)
+}
+
 // GetBufferedReader returns the buffered Reader that should be used by the loader
 func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 	if b.br == nil {
@@ -125,6 +212,7 @@ func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 			if err != nil {
 				panic(fmt.Sprintf("cannot open file for read %s: %v", b.FileName, err))
 			}
+			b.file = file
 			b.br = bufio.NewReaderSize(file, defaultReadSize)
 		} else {
 			// Read from STDIN
@@ -134,6 +222,14 @@ func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 	return b.br
 }
 
+// rewind makes the buffered reader read the query file again from the start
+func (b *BenchmarkRunner) rewind() {
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		panic(fmt.Sprintf("cannot rewind file %s: %v", b.FileName, err))
+	}
+	b.br.Reset(b.file)
+}
+
 // Run does the bulk of the benchmark execution.
 // It launches a goroutine to track stats, creates workers to process queries,
 // read in the input, execute the queries, and then does cleanup.
@@ -146,8 +242,38 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	if spArgs.burnIn > b.Limit {
 		panic("burn-in is larger than limit")
 	}
+	switch b.OnError {
+	case "", OnErrorAbort, OnErrorSkip, OnErrorRetry:
+	default:
+		panic(fmt.Sprintf("unknown on-error mode '%s'", b.OnError))
+	}
+	if b.Loop {
+		if len(b.FileName) == 0 {
+			panic("looping over the queries needs a query file, they cannot be read again from STDIN")
+		}
+		if b.Duration == 0 && b.Limit == 0 && !b.LoopUntilStopped {
+			panic("looping over the queries needs a duration or a query limit")
+		}
+	}
+	if b.OpenLoop {
+		if b.LimitRPS == 0 {
+			panic("open-loop mode needs a query rate (max-rps)")
+		}
+		b.schedule = newSchedule(b.LimitRPS)
+	}
 	b.ch = make(chan Query, b.Workers)
 
+	if len(b.RecordAnswersFile) > 0 || len(b.VerifyAnswersFile) > 0 {
+		if _, ok := processorCreateFn().(ResultProcessor); !ok {
+			panic("result verification is not supported by this query runner")
+		}
+		var err error
+		b.verifier, err = newResultVerifier(b.VerifyAnswersFile, b.RecordAnswersFile, b.VerifyTolerance, b.VerifyIgnoreColumns, b.VerifyColumnAliases)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	// Launch the stats processor:
 	go b.sp.process(b.Workers)
 
@@ -163,13 +289,21 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	// Read in jobs, closing the job channel when done:
 	// Wall clock start time
 	wallStart := time.Now()
-	b.scanner.setReader(b.GetBufferedReader()).scan(queryPool, b.ch)
+	if b.Duration > 0 {
+		timer := time.AfterFunc(b.Duration, b.Stop)
+		defer timer.Stop()
+	}
+	b.scanQueries(queryPool)
 	close(b.ch)
 
 	// Block for workers to finish sending requests, closing the stats channel when done:
 	wg.Wait()
 	b.sp.CloseAndWait()
 
+	if b.verifier != nil {
+		b.finishVerification()
+	}
+
 	// Wall clock end time
 	wallEnd := time.Now()
 	wallTook := wallEnd.Sub(wallStart)
@@ -194,24 +328,37 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	}
 }
 
+// scanQueries reads the queries into the workers' channel, once or, with Loop,
+// over and over until the benchmark is stopped or the limit is reached
+func (b *BenchmarkRunner) scanQueries(queryPool *sync.Pool) {
+	b.scanner.setReader(b.GetBufferedReader())
+	for {
+		n, eof := b.scanner.scan(queryPool, b.ch)
+		if !b.Loop || !eof || n == 0 {
+			return
+		}
+		b.rewind()
+	}
+}
+
 func (b *BenchmarkRunner) saveTestResult(took time.Duration, start time.Time, end time.Time) {
-	testResult := LoaderTestResult{
-		ResultFormatVersion: BenchmarkTestResultVersion,
+	testResult := results.Result{
+		ResultFormatVersion: results.FormatVersion,
+		Kind:                results.KindQuery,
+		Target:              b.Target,
+		DBName:              b.DBName,
 		RunnerConfig:        b.BenchmarkRunnerConfig,
-		StartTime:           start.UTC().Unix() * 1000,
-		EndTime:             end.UTC().Unix() * 1000,
+		StartTime:           start.UnixNano() / int64(time.Millisecond),
+		EndTime:             end.UnixNano() / int64(time.Millisecond),
 		DurationMillis:      took.Milliseconds(),
-		Totals:              b.sp.GetTotalsMap(),
+		Query:               b.sp.GetTotals(),
 	}
-
-	_, _ = fmt.Printf("Saving results json file to %s\n", b.BenchmarkRunnerConfig.ResultsFile)
-	file, err := json.MarshalIndent(testResult, "", " ")
-	if err != nil {
-		log.Fatal(err)
+	if b.verifier != nil {
+		testResult.Query.Verification = b.verifier.totals()
 	}
 
-	err = ioutil.WriteFile(b.BenchmarkRunnerConfig.ResultsFile, file, 0644)
-	if err != nil {
+	_, _ = fmt.Printf("Saving results json file to %s\n", b.BenchmarkRunnerConfig.ResultsFile)
+	if err := testResult.WriteFile(b.BenchmarkRunnerConfig.ResultsFile); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -219,32 +366,100 @@ func (b *BenchmarkRunner) saveTestResult(took time.Duration, start time.Time, en
 func (b *BenchmarkRunner) processorHandler(wg *sync.WaitGroup, rateLimiter *rate.Limiter, queryPool *sync.Pool, processor Processor, workerNum int) {
 	processor.Init(workerNum)
 	for query := range b.ch {
-		r := rateLimiter.Reserve()
-		time.Sleep(r.Delay())
-
-		stats, err := processor.ProcessQuery(query, false)
-		if err != nil {
-			panic(err)
+		var intended time.Time
+		if b.schedule != nil {
+			intended = b.schedule.intendedTime()
+			b.schedule.wait(intended)
+		} else {
+			r := rateLimiter.Reserve()
+			time.Sleep(r.Delay())
 		}
-		b.sp.send(stats)
+
+		ok := b.runQuery(query, false, func() ([]*Stat, error) {
+			if b.schedule == nil {
+				return b.processQuery(processor, query)
+			}
+			lag := lagSince(intended)
+			stats, err := b.processQuery(processor, query)
+			addLag(stats, lag)
+			return stats, err
+		})
 
 		// If PrewarmQueries is set, we run the query as 'cold' first (see above),
 		// then we immediately run it a second time and report that as the 'warm' stat.
 		// This guarantees that the warm stat will reflect optimal cache performance.
 		spArgs := b.sp.getArgs()
-		if spArgs.prewarmQueries {
+		if ok && spArgs.prewarmQueries {
 			// Warm run
-			stats, err = processor.ProcessQuery(query, true)
-			if err != nil {
-				panic(err)
-			}
-			b.sp.sendWarm(stats)
+			b.runQuery(query, true, func() ([]*Stat, error) {
+				return processor.ProcessQuery(query, true)
+			})
 		}
 		queryPool.Put(query)
 	}
 	wg.Done()
 }
 
+// runQuery executes a query with run and sends its stats, handling errors as
+// configured by OnError. It returns false if the query failed and was skipped.
+func (b *BenchmarkRunner) runQuery(q Query, isWarm bool, run func() ([]*Stat, error)) bool {
+	attempts := uint(1)
+	if b.OnError == OnErrorRetry {
+		attempts += b.MaxRetries
+	}
+	var err error
+	for attempt := uint(0); attempt < attempts; attempt++ {
+		var stats []*Stat
+		stats, err = run()
+		if err == nil {
+			if isWarm {
+				b.sp.sendWarm(stats)
+			} else {
+				b.sp.send(stats)
+			}
+			return true
+		}
+		if b.Debug > 0 {
+			log.Printf("query '%s' failed (attempt %d of %d): %v", q.HumanDescriptionName(), attempt+1, attempts, err)
+		}
+	}
+	if b.OnError == "" || b.OnError == OnErrorAbort {
+		panic(err)
+	}
+	b.sp.sendError(q.HumanLabelName(), isWarm)
+	return false
+}
+
+// processQuery runs the cold execution of a query, collecting its result for
+// verification when it is enabled.
+func (b *BenchmarkRunner) processQuery(processor Processor, q Query) ([]*Stat, error) {
+	if b.verifier == nil {
+		return processor.ProcessQuery(q, false)
+	}
+	stats, rs, err := processor.(ResultProcessor).ProcessQueryWithResult(q, false)
+	if err != nil {
+		return nil, err
+	}
+	b.verifier.check(q, rs)
+	return stats, nil
+}
+
+// finishVerification closes the recorded answers file and prints the verification summary.
+func (b *BenchmarkRunner) finishVerification() {
+	if err := b.verifier.close(); err != nil {
+		log.Fatal(err)
+	}
+	if len(b.RecordAnswersFile) > 0 {
+		_, _ = fmt.Printf("Saved query answers to %s\n", b.RecordAnswersFile)
+	}
+	if err := b.verifier.write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	if n := b.verifier.mismatches(); n > 0 {
+		_, _ = fmt.Printf("%d queries failed result verification\n", n)
+	}
+}
+
 func getRateLimiter(limitRPS uint64, workers uint) *rate.Limiter {
 	var requestRate = rate.Inf
 	var requestBurst = 0
@@ -1,6 +1,9 @@
 package query
 
 import (
+	"bytes"
+	"fmt"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
 	"golang.org/x/time/rate"
 	"io/ioutil"
 	"math"
@@ -8,7 +11,9 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type testProcessor struct {
@@ -104,6 +109,85 @@ func TestProcessorHandlerPreWarm(t *testing.T) {
 		t.Errorf("total queries wrong: want %d got %d", 2*qLimit, p1.count+p2.count)
 	}
 }
+
+// failingProcessor fails every query failures times before it succeeds
+type failingProcessor struct {
+	failures int
+	attempts map[string]int
+}
+
+func (p *failingProcessor) Init(_ int) {}
+
+func (p *failingProcessor) ProcessQuery(q Query, _ bool) ([]*Stat, error) {
+	id := string(q.HumanDescriptionName())
+	p.attempts[id]++
+	if p.attempts[id] <= p.failures {
+		return nil, fmt.Errorf("query %s timed out", id)
+	}
+	return []*Stat{GetStat().Init(q.HumanLabelName(), 1)}, nil
+}
+
+func TestProcessorHandlerOnError(t *testing.T) {
+	cases := []struct {
+		desc       string
+		onError    string
+		maxRetries uint
+		failures   int
+		wantErrors uint64
+		wantTries  int
+	}{
+		{desc: "skip", onError: OnErrorSkip, failures: 1, wantErrors: 3, wantTries: 1},
+		{desc: "retry succeeds", onError: OnErrorRetry, maxRetries: 2, failures: 2, wantErrors: 0, wantTries: 3},
+		{desc: "retry gives up", onError: OnErrorRetry, maxRetries: 1, failures: 2, wantErrors: 3, wantTries: 2},
+	}
+	for _, c := range cases {
+		var sent, errors uint64
+		b := &BenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{OnError: c.onError, MaxRetries: c.maxRetries}}
+		b.sp = &mockStatProcessor{
+			args:        &statProcessorArgs{},
+			onSend:      func(stats []*Stat) { sent += uint64(len(stats)) },
+			onSendError: func(_ []byte, _ bool) { errors++ },
+		}
+		b.ch = make(chan Query, 3)
+		for i := 0; i < 3; i++ {
+			b.ch <- &testQuery{HumanLabel: []byte("label"), HumanDescription: []byte(fmt.Sprint(i))}
+		}
+		close(b.ch)
+
+		p := &failingProcessor{failures: c.failures, attempts: map[string]int{}}
+		var wg sync.WaitGroup
+		wg.Add(1)
+		b.processorHandler(&wg, rate.NewLimiter(rate.Inf, 0), &testQueryPool, p, 0)
+
+		if errors != c.wantErrors {
+			t.Errorf("%s: wrong number of errors: got %d want %d", c.desc, errors, c.wantErrors)
+		}
+		if sent+errors != 3 {
+			t.Errorf("%s: wrong number of stats and errors: got %d want %d", c.desc, sent+errors, 3)
+		}
+		for id, tries := range p.attempts {
+			if tries != c.wantTries {
+				t.Errorf("%s: wrong number of tries of query %s: got %d want %d", c.desc, id, tries, c.wantTries)
+			}
+		}
+	}
+}
+
+func TestProcessorHandlerOnErrorAbort(t *testing.T) {
+	b := &BenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{OnError: OnErrorAbort, MaxRetries: 3}}
+	p := &failingProcessor{failures: 1, attempts: map[string]int{}}
+	q := &testQuery{HumanDescription: []byte("q")}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("the code did not panic")
+		}
+		if p.attempts["q"] != 1 {
+			t.Errorf("query was retried: got %d tries want %d", p.attempts["q"], 1)
+		}
+	}()
+	b.runQuery(q, false, func() ([]*Stat, error) { return p.ProcessQuery(q, false) })
+}
+
 func TestBenchmarkRunnerGetBufferedReaderPanicOnMissingFile(t *testing.T) {
 	dumbFileName := "some-random-file-that-should-not-exist"
 	_, err := os.Stat(dumbFileName)
@@ -208,6 +292,145 @@ func TestBenchmarkRunnerRunPanicOnBurnInBiggerThanLimit(t *testing.T) {
 	t.Errorf("the code did not panic")
 }
 
+func TestBenchmarkRunnerScanQueriesLoop(t *testing.T) {
+	totalQueries := uint64(3)
+	var b bytes.Buffer
+	err := encodeQueries(&b, totalQueries, func(i uint64) Query {
+		return &testQuery{HumanLabel: []byte("testlabel")}
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	f, err := ioutil.TempFile("", "queries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	limit := uint64(8)
+	r := &BenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{FileName: f.Name(), Loop: true, Limit: limit}}
+	r.scanner = newScanner(&r.Limit)
+	r.ch = make(chan Query, 2*limit)
+	r.scanQueries(&sync.Pool{New: func() interface{} { return &testQuery{} }})
+	close(r.ch)
+
+	got := uint64(0)
+	for q := range r.ch {
+		// queries are numbered within the file in every pass
+		if want := got % totalQueries; q.GetID() != want {
+			t.Errorf("wrong id of query %d: got %d want %d", got, q.GetID(), want)
+		}
+		got++
+	}
+	if got != limit {
+		t.Errorf("wrong number of queries: got %d want %d", got, limit)
+	}
+}
+
+// sleepProcessor takes delay to process every query.
+type sleepProcessor struct {
+	delay     time.Duration
+	processed *uint64
+}
+
+func (sp *sleepProcessor) Init(workerNum int) {}
+func (sp *sleepProcessor) ProcessQuery(q Query, isWarm bool) ([]*Stat, error) {
+	time.Sleep(sp.delay)
+	atomic.AddUint64(sp.processed, 1)
+	return []*Stat{GetStat().Init(q.HumanLabelName(), float64(sp.delay.Milliseconds()))}, nil
+}
+
+func TestBenchmarkRunnerRunDurationAndWarmup(t *testing.T) {
+	var b bytes.Buffer
+	err := encodeQueries(&b, 3, func(i uint64) Query {
+		return &testQuery{HumanLabel: []byte("testlabel")}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "queries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	r := NewBenchmarkRunner(BenchmarkRunnerConfig{
+		FileName:       f.Name(),
+		Workers:        1,
+		Loop:           true,
+		Duration:       300 * time.Millisecond,
+		WarmupDuration: 150 * time.Millisecond,
+	})
+	processed := uint64(0)
+	start := time.Now()
+	r.Run(&sync.Pool{New: func() interface{} { return &testQuery{} }}, func() Processor {
+		return &sleepProcessor{delay: 10 * time.Millisecond, processed: &processed}
+	})
+
+	// the looping query file only ends with the duration
+	if took := time.Since(start); took < 300*time.Millisecond || took > 2*time.Second {
+		t.Errorf("run did not stop after its duration: took %v", took)
+	}
+	totals := r.sp.GetTotals()
+	if totals.Queries == 0 {
+		t.Errorf("no queries measured after the warmup")
+	}
+	// about half of the queries run during the warmup
+	if got := atomic.LoadUint64(&processed); totals.Queries >= got*3/4 {
+		t.Errorf("warmup queries measured: got %d of %d queries", totals.Queries, got)
+	}
+}
+
+func TestBenchmarkRunnerRunLoopUntilStopped(t *testing.T) {
+	var b bytes.Buffer
+	err := encodeQueries(&b, 3, func(i uint64) Query {
+		return &testQuery{HumanLabel: []byte("testlabel")}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "queries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// neither a duration nor a limit, the queries loop until Stop
+	r := NewBenchmarkRunner(BenchmarkRunnerConfig{
+		FileName:         f.Name(),
+		Workers:          1,
+		Loop:             true,
+		LoopUntilStopped: true,
+	})
+	timer := time.AfterFunc(200*time.Millisecond, r.Stop)
+	defer timer.Stop()
+	processed := uint64(0)
+	start := time.Now()
+	r.Run(&sync.Pool{New: func() interface{} { return &testQuery{} }}, func() Processor {
+		return &sleepProcessor{delay: 10 * time.Millisecond, processed: &processed}
+	})
+
+	if took := time.Since(start); took < 200*time.Millisecond || took > 2*time.Second {
+		t.Errorf("run did not stop when stopped: took %v", took)
+	}
+	// the query file has 3 queries, it was read again
+	if got := atomic.LoadUint64(&processed); got <= 3 {
+		t.Errorf("query file not looped: got %d queries", got)
+	}
+}
+
 func TestBenchmarkRunnerRunNoQueries(t *testing.T) {
 	// SETUP
 	// ..empty query file
@@ -221,18 +444,16 @@ func TestBenchmarkRunnerRunNoQueries(t *testing.T) {
 		t.Fatalf("Could not create temp file: %v", err)
 	}
 
-	spStarted := false
+	spStarted := make(chan struct{})
 	sendStatsCalled := false
-	// lock controlls access to spStarted and sendStatsCalled
+	// lock controlls access to sendStatsCalled
 	// wg gets Done when sp is closed
 	wg := &sync.WaitGroup{}
 	lock := &sync.Mutex{}
 	sp := mockStatProcessor{
 		args: &statProcessorArgs{},
 		onProcess: func(_ uint) {
-			lock.Lock()
-			spStarted = true
-			lock.Unlock()
+			close(spStarted)
 		},
 		onSend: func(_ []*Stat) {
 			lock.Lock()
@@ -276,7 +497,10 @@ func TestBenchmarkRunnerRunNoQueries(t *testing.T) {
 	wg.Wait()
 	lock.Lock()
 	// ASSERT
-	if !spStarted {
+	// the stat processor runs in a goroutine of its own
+	select {
+	case <-spStarted:
+	case <-time.After(time.Second):
 		t.Error("stat processor wasn't started")
 	}
 	if processorsCreated != b.Workers {
@@ -294,11 +518,12 @@ func TestBenchmarkRunnerRunNoQueries(t *testing.T) {
 }
 
 type mockStatProcessor struct {
-	args      *statProcessorArgs
-	onSend    func([]*Stat)
-	onProcess func(uint)
-	closed    bool
-	wg        *sync.WaitGroup
+	args        *statProcessorArgs
+	onSend      func([]*Stat)
+	onSendError func([]byte, bool)
+	onProcess   func(uint)
+	closed      bool
+	wg          *sync.WaitGroup
 }
 
 func (m *mockStatProcessor) getArgs() *statProcessorArgs {
@@ -314,6 +539,11 @@ func (m *mockStatProcessor) sendWarm(stats []*Stat) {
 		m.onSend(stats)
 	}
 }
+func (m *mockStatProcessor) sendError(label []byte, isWarm bool) {
+	if m.onSendError != nil {
+		m.onSendError(label, isWarm)
+	}
+}
 func (m *mockStatProcessor) process(workers uint) {
 	if m.onProcess != nil {
 		m.onProcess(workers)
@@ -323,9 +553,11 @@ func (m *mockStatProcessor) CloseAndWait() {
 	m.closed = true
 	m.wg.Done()
 }
-func (m *mockStatProcessor) GetTotalsMap() map[string]interface{} {
-	totals := make(map[string]interface{})
-	return totals
+func (m *mockStatProcessor) GetTotals() *results.QueryTotals {
+	return &results.QueryTotals{}
+}
+func (m *mockStatProcessor) intervalQuantiles() (int64, map[string]float64) {
+	return 0, nil
 }
 
 type mockProcessor struct {
@@ -25,6 +25,8 @@ type QueryGeneratorConfig struct {
 	TimescaleUseTags       bool `mapstructure:"timescale-use-tags"`
 	TimescaleUseTimeBucket bool `mapstructure:"timescale-use-time-bucket"`
 	
+	InfluxQueryLanguage string `mapstructure:"influx-query-language"`
+	
 	DbName string `mapstructure:"db-name"`
 }
 
@@ -57,5 +59,7 @@ func (c *QueryGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Bool("timescale-use-tags", true, "TimescaleDB only: Use separate tags table when querying")
 	fs.Bool("timescale-use-time-bucket", true, "TimescaleDB only: Use time bucket. Set to false to test on native PostgreSQL")
 	
-	fs.String("db-name", "benchmark", "Specify database name. Timestream requires it in order to generate the queries")
+	fs.String("influx-query-language", "influxql", "InfluxDB only: Language of the queries: influxql (/query), flux (/api/v2/query of 2.x) or sql (/api/v3/query_sql of 3.x)")
+	
+	fs.String("db-name", "benchmark", "Specify database name. Timestream requires it in order to generate the queries, as do InfluxDB Flux queries for their bucket")
 }
@@ -1,11 +1,14 @@
 package factories
 
 import (
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/clickhouse"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/cnosdb"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/influx"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/iotdb"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/questdb"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/tdengine"
 	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/timescaledb"
+	"github.com/cnosdb/tsdb-comparisons/cmd/generate_queries/databases/victoriametrics"
 
 	"github.com/cnosdb/tsdb-comparisons/pkg/query/config"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
@@ -13,7 +16,10 @@ import (
 
 func InitQueryFactories(config *config.QueryGeneratorConfig) map[string]interface{} {
 	factories := make(map[string]interface{})
-	factories[constants.FormatInflux] = &influx.BaseGenerator{}
+	factories[constants.FormatInflux] = &influx.BaseGenerator{
+		QueryLanguage: config.InfluxQueryLanguage,
+		Bucket:        config.DbName,
+	}
 	factories[constants.FormatCnosDB] = &cnosdb.BaseGenerator{}
 	factories[constants.FormatTimescaleDB] = &timescaledb.BaseGenerator{
 		UseJSON:       config.TimescaleUseJSON,
@@ -22,6 +28,9 @@ func InitQueryFactories(config *config.QueryGeneratorConfig) map[string]interfac
 	}
 	factories[constants.FormatTDEngine] = &tdengine.BaseGenerator{}
 	factories[constants.FormatIOTDB] = &iotdb.BaseGenerator{}
+	factories[constants.FormatQuestDB] = &questdb.BaseGenerator{}
+	factories[constants.FormatVictoriaMetrics] = &victoriametrics.BaseGenerator{}
+	factories[constants.FormatClickHouse] = &clickhouse.BaseGenerator{}
 
 	return factories
 }
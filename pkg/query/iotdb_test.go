@@ -0,0 +1,38 @@
+package query
+
+import "testing"
+
+func TestNewIoTDB(t *testing.T) {
+	check := func(iq *IoTDB) {
+		testValidNewQuery(t, iq)
+		if got := len(iq.SqlQuery); got != 0 {
+			t.Errorf("new query has non-0 sql query: got %d", got)
+		}
+	}
+	iq := NewIoTDB()
+	check(iq)
+	iq.HumanLabel = []byte("foo")
+	iq.HumanDescription = []byte("bar")
+	iq.SqlQuery = []byte("SELECT * FROM root.benchmark.readings")
+	iq.SetID(1)
+	if got := string(iq.HumanLabelName()); got != "foo" {
+		t.Errorf("incorrect label name: got %s", got)
+	}
+	if got := string(iq.HumanDescriptionName()); got != "bar" {
+		t.Errorf("incorrect desc: got %s", got)
+	}
+	iq.Release()
+
+	// Since we use a pool, check that the next one is reset
+	iq = NewIoTDB()
+	check(iq)
+	iq.Release()
+}
+
+func TestIoTDBSetAndGetID(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		q := NewIoTDB()
+		testSetAndGetID(t, q)
+		q.Release()
+	}
+}
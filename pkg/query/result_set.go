@@ -0,0 +1,304 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the textual time formats that are recognized and normalized
+// to RFC3339 when canonicalizing a ResultSet, in the order they are tried.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// ResultSet is the response of a single query as returned by a Processor.
+// Values can be of any type the database driver returns, the BenchmarkRunner
+// turns the ResultSet into a canonical form before recording or comparing it.
+type ResultSet struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// NewResultSet returns an empty ResultSet with the given column names.
+func NewResultSet(columns ...string) *ResultSet {
+	return &ResultSet{Columns: columns, Rows: [][]interface{}{}}
+}
+
+// AppendRow adds a row of values to the ResultSet. The values are expected to be
+// in the same order as the columns of the ResultSet.
+func (rs *ResultSet) AppendRow(values ...interface{}) {
+	row := make([]interface{}, len(values))
+	copy(row, values)
+	rs.Rows = append(rs.Rows, row)
+}
+
+// canonical returns a database independent representation of the ResultSet.
+// Column names are normalized (lower case, without quotes) and renamed by
+// aliases, the map of normalized names of this database to those of the
+// reference, and columns whose normalized name is in ignore are dropped. Every
+// value is normalized (numbers to float64, times to UTC RFC3339 strings) and
+// stays in its column, finally the rows are sorted.
+func (rs *ResultSet) canonical(ignore map[string]bool, aliases map[string]string) *ResultSet {
+	out := &ResultSet{Rows: make([][]interface{}, 0, len(rs.Rows))}
+	keep := make([]bool, 0, len(rs.Columns))
+	for _, c := range rs.Columns {
+		name := normalizeColumn(c)
+		if alias, ok := aliases[name]; ok {
+			name = normalizeColumn(alias)
+		}
+		k := !ignore[normalizeColumn(c)] && !ignore[name]
+		keep = append(keep, k)
+		if k {
+			out.Columns = append(out.Columns, name)
+		}
+	}
+	for _, row := range rs.Rows {
+		values := make([]interface{}, 0, len(row))
+		for i, v := range row {
+			if i < len(keep) && !keep[i] {
+				continue
+			}
+			values = append(values, normalizeValue(v))
+		}
+		out.Rows = append(out.Rows, values)
+	}
+	out.sortRows()
+	return out
+}
+
+// normalizeColumn returns the name columns are matched by: lower case and
+// without the quotes some databases keep around identifiers.
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "\"`'[]"))
+}
+
+func (rs *ResultSet) sortRows() {
+	sort.SliceStable(rs.Rows, func(i, j int) bool {
+		return rowKey(rs.Rows[i]) < rowKey(rs.Rows[j])
+	})
+}
+
+// inColumnsOf returns the canonical ResultSet with its columns in the order of
+// the columns of other, matched by name, or an error naming the first column
+// that has no match. ResultSets without column names are compared by position.
+func (rs *ResultSet) inColumnsOf(other *ResultSet) (*ResultSet, error) {
+	if len(rs.Columns) == 0 || len(other.Columns) == 0 {
+		return rs, nil
+	}
+	if len(rs.Columns) != len(other.Columns) {
+		return nil, fmt.Errorf("columns differ: got %v want %v", rs.Columns, other.Columns)
+	}
+	used := make([]bool, len(rs.Columns))
+	order := make([]int, len(other.Columns))
+	identity := true
+	for i, c := range other.Columns {
+		order[i] = -1
+		for j, name := range rs.Columns {
+			if !used[j] && name == c {
+				order[i], used[j] = j, true
+				break
+			}
+		}
+		if order[i] < 0 {
+			return nil, fmt.Errorf("column %s missing: got %v", c, rs.Columns)
+		}
+		identity = identity && order[i] == i
+	}
+	if identity {
+		return rs, nil
+	}
+	out := &ResultSet{Columns: other.Columns, Rows: make([][]interface{}, 0, len(rs.Rows))}
+	for _, row := range rs.Rows {
+		values := make([]interface{}, len(order))
+		for i, j := range order {
+			if j < len(row) {
+				values[i] = row[j]
+			}
+		}
+		out.Rows = append(out.Rows, values)
+	}
+	out.sortRows()
+	return out, nil
+}
+
+// diff compares two canonical ResultSets and returns a description of the first
+// difference found, or an empty string if they are equal. The columns are
+// matched by name, floating point values are considered equal if their
+// relative difference is within tolerance.
+func (rs *ResultSet) diff(other *ResultSet, tolerance float64) string {
+	if len(rs.Rows) != len(other.Rows) {
+		return fmt.Sprintf("row count differs: got %d want %d", len(rs.Rows), len(other.Rows))
+	}
+	rs, err := rs.inColumnsOf(other)
+	if err != nil {
+		return err.Error()
+	}
+	for i := range rs.Rows {
+		got, want := rs.Rows[i], other.Rows[i]
+		if len(got) != len(want) {
+			return fmt.Sprintf("row %d: column count differs: got %d want %d", i, len(got), len(want))
+		}
+		for j := range got {
+			if !valuesEqual(got[j], want[j], tolerance) {
+				return fmt.Sprintf("row %d: got %v want %v", i, formatRow(got), formatRow(want))
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeValue converts a value returned by a database driver or decoded from
+// a response body into one of nil, bool, float64 or string.
+func normalizeValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case bool:
+		return x
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	case int:
+		return float64(x)
+	case int8:
+		return float64(x)
+	case int16:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case uint8:
+		return float64(x)
+	case uint16:
+		return float64(x)
+	case uint32:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case json.Number:
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return x.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return normalizeString(string(x))
+	case string:
+		return normalizeString(x)
+	default:
+		return normalizeString(fmt.Sprint(x))
+	}
+}
+
+// normalizeString turns textual numbers and timestamps into their canonical
+// representation, so that CSV and JSON responses compare equal.
+func normalizeString(s string) interface{} {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return s
+}
+
+// typeRank orders normalized values of different types: nil < bool < float64 < string.
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func compareValues(a, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		if x == y {
+			return 0
+		} else if !x {
+			return -1
+		}
+		return 1
+	case float64:
+		y := b.(float64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(x, b.(string))
+	}
+	return 0
+}
+
+func valuesEqual(a, b interface{}, tolerance float64) bool {
+	if typeRank(a) != typeRank(b) {
+		return false
+	}
+	x, ok := a.(float64)
+	if !ok {
+		return compareValues(a, b) == 0
+	}
+	y := b.(float64)
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.IsNaN(x) && math.IsNaN(y)
+	}
+	scale := math.Max(1, math.Max(math.Abs(x), math.Abs(y)))
+	return math.Abs(x-y) <= tolerance*scale
+}
+
+// rowKey is used to sort canonical rows. Floats are rounded so that values
+// within tolerance of each other are very likely to sort the same way.
+func rowKey(row []interface{}) string {
+	parts := make([]string, len(row))
+	for i, v := range row {
+		switch x := v.(type) {
+		case float64:
+			parts[i] = strconv.FormatFloat(x, 'g', 6, 64)
+		default:
+			parts[i] = fmt.Sprint(x)
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func formatRow(row []interface{}) string {
+	parts := make([]string, len(row))
+	for i, v := range row {
+		parts[i] = fmt.Sprint(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,128 @@
+package query
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResultSetCanonical(t *testing.T) {
+	ts := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := NewResultSet("time", "name", "mean_velocity")
+	a.AppendRow(ts, "truck_2", float32(0.5))
+	a.AppendRow(ts, "truck_1", 12)
+
+	b := NewResultSet("avg(velocity)", "name", "ts")
+	b.AppendRow("12", []byte("truck_1"), "2016-01-01T00:00:00Z")
+	b.AppendRow(0.5, "truck_2", "2016-01-01 00:00:00")
+
+	aliases := map[string]string{"avg(velocity)": "mean_velocity", "ts": "time"}
+	ca := a.canonical(nil, nil)
+	cb := b.canonical(nil, aliases)
+	if d := cb.diff(ca, 0); d != "" {
+		t.Errorf("equivalent result sets differ: %s", d)
+	}
+	if d := b.canonical(nil, nil).diff(ca, 0); d == "" {
+		t.Errorf("result sets with unmatched columns do not differ")
+	}
+}
+
+func TestResultSetCanonicalKeepsColumns(t *testing.T) {
+	a := NewResultSet("name", "latitude", "longitude")
+	a.AppendRow("truck_1", 10.0, 20.0)
+
+	swapped := NewResultSet("name", "latitude", "longitude")
+	swapped.AppendRow("truck_1", 20.0, 10.0)
+	if d := swapped.canonical(nil, nil).diff(a.canonical(nil, nil), 0); d == "" {
+		t.Errorf("swapped values do not differ")
+	}
+
+	reordered := NewResultSet(`"Longitude"`, "NAME", "latitude")
+	reordered.AppendRow(20.0, "truck_1", 10.0)
+	if d := reordered.canonical(nil, nil).diff(a.canonical(nil, nil), 0); d != "" {
+		t.Errorf("reordered columns differ: %s", d)
+	}
+}
+
+func TestResultSetCanonicalIgnore(t *testing.T) {
+	a := NewResultSet("time", "value")
+	a.AppendRow(int64(1), 1.0)
+	b := NewResultSet("value")
+	b.AppendRow(1.0)
+
+	ignore := map[string]bool{"time": true}
+	ca := a.canonical(ignore, nil)
+	if got := len(ca.Columns); got != 1 {
+		t.Errorf("ignored column not dropped: got %d columns", got)
+	}
+	if d := ca.diff(b.canonical(ignore, nil), 0); d != "" {
+		t.Errorf("result sets differ after ignoring time: %s", d)
+	}
+}
+
+func TestResultSetDiff(t *testing.T) {
+	cases := []struct {
+		desc      string
+		a         [][]interface{}
+		b         [][]interface{}
+		tolerance float64
+		wantDiff  bool
+	}{
+		{
+			desc: "equal",
+			a:    [][]interface{}{{"a", 1.0}},
+			b:    [][]interface{}{{"a", 1.0}},
+		},
+		{
+			desc:     "row count",
+			a:        [][]interface{}{{"a", 1.0}},
+			b:        [][]interface{}{},
+			wantDiff: true,
+		},
+		{
+			desc:     "column count",
+			a:        [][]interface{}{{"a", 1.0}},
+			b:        [][]interface{}{{"a"}},
+			wantDiff: true,
+		},
+		{
+			desc:      "within tolerance",
+			a:         [][]interface{}{{100.0}},
+			b:         [][]interface{}{{100.001}},
+			tolerance: 1e-4,
+		},
+		{
+			desc:      "outside tolerance",
+			a:         [][]interface{}{{100.0}},
+			b:         [][]interface{}{{100.1}},
+			tolerance: 1e-4,
+			wantDiff:  true,
+		},
+		{
+			desc:     "different types",
+			a:        [][]interface{}{{"1a"}},
+			b:        [][]interface{}{{1.0}},
+			wantDiff: true,
+		},
+		{
+			desc: "nulls",
+			a:    [][]interface{}{{nil, "x"}},
+			b:    [][]interface{}{{nil, "x"}},
+		},
+		{
+			desc:     "swapped values",
+			a:        [][]interface{}{{nil, "x"}},
+			b:        [][]interface{}{{"x", nil}},
+			wantDiff: true,
+		},
+	}
+	for _, c := range cases {
+		a := &ResultSet{Rows: c.a}
+		b := &ResultSet{Rows: c.b}
+		d := a.canonical(nil, nil).diff(b.canonical(nil, nil), c.tolerance)
+		if c.wantDiff && d == "" {
+			t.Errorf("%s: expected a difference", c.desc)
+		} else if !c.wantDiff && d != "" {
+			t.Errorf("%s: unexpected difference: %s", c.desc, d)
+		}
+	}
+}
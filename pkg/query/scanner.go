@@ -12,6 +12,8 @@ import (
 type scanner struct {
 	r     io.Reader
 	limit *uint64
+	stop  <-chan struct{}
+	count uint64 // count is the number of queries scanned from all the sources so far
 }
 
 // newScanner returns a new scanner for a given Reader and its limit
@@ -25,33 +27,50 @@ func (s *scanner) setReader(r io.Reader) *scanner {
 	return s
 }
 
-// scan reads encoded Queries and places them into a channel
-func (s *scanner) scan(pool *sync.Pool, c chan Query) {
+// setStop sets a channel that, once closed, makes the scanner stop reading
+// before the end of its source
+func (s *scanner) setStop(stop <-chan struct{}) *scanner {
+	s.stop = stop
+	return s
+}
+
+// scan reads encoded Queries and places them into a channel. It returns the
+// number of queries read and whether it stopped at the end of the source rather
+// than because of the limit or the stop channel. The limit applies to the
+// queries of all the sources scanned, queries are numbered within each source.
+func (s *scanner) scan(pool *sync.Pool, c chan Query) (uint64, bool) {
 	decoder := gob.NewDecoder(s.r)
 
 	n := uint64(0)
 	for {
-		if *s.limit > 0 && n >= *s.limit {
+		if *s.limit > 0 && s.count >= *s.limit {
 			// request queries limit reached, time to quit
-			break
+			return n, false
 		}
 
 		q := pool.Get().(Query)
 		err := decoder.Decode(q)
 		if err == io.EOF {
 			// EOF, all done
-			break
+			pool.Put(q)
+			return n, true
 		}
 		if err != nil {
 			// Can't read, time to quit
 			log.Fatal(err)
 		}
 
-		// We have a query, send it to the runner
+		// We have a query, send it to the runner, unless we were stopped
 		q.SetID(n)
-		c <- q
+		select {
+		case c <- q:
+		case <-s.stop:
+			pool.Put(q)
+			return n, false
+		}
 
 		// Queries counter
 		n++
+		s.count++
 	}
 }
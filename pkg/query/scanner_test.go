@@ -58,7 +58,7 @@ func runScan(t *testing.T, b *bytes.Buffer, limit, numQueries uint64, pool *sync
 		for q := range queryChan {
 			err := chk(i, q)
 			if err != nil {
-				t.Fatalf(err.Error())
+				t.Error(err)
 			}
 			i++
 			got++
@@ -150,3 +150,37 @@ func TestScanTimescaleDB(t *testing.T) {
 		return nil
 	})
 }
+
+func TestScannerStop(t *testing.T) {
+	totalQueries := uint64(7)
+	var b bytes.Buffer
+	err := encodeQueries(&b, totalQueries, func(i uint64) Query {
+		return &testQuery{
+			HumanLabel:       []byte("testlabel"),
+			HumanDescription: []byte("testDesc"),
+		}
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	limit := uint64(0)
+	stop := make(chan struct{})
+	queryChan := make(chan Query, 1)
+	done := make(chan struct{})
+	input := bufio.NewReaderSize(bytes.NewReader(b.Bytes()), 1<<20)
+	go func() {
+		newScanner(&limit).setReader(input).setStop(stop).scan(&testQueryPool, queryChan)
+		close(done)
+	}()
+
+	// read two queries, then stop the scanner while it is blocked on the third
+	<-queryChan
+	<-queryChan
+	close(stop)
+	<-done
+	got := 2 + len(queryChan)
+	if got >= int(totalQueries) {
+		t.Errorf("scanner did not stop: got %d queries of %d", got, totalQueries)
+	}
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// schedule assigns the queries of an open-loop benchmark their intended send
+// times, at a constant arrival rate that does not depend on how fast the
+// database answers.
+//
+// The latency of a query is measured from its intended send time rather than
+// from when a worker got to send it. When the database stalls, the queries that
+// should have been sent in the meantime wait for a free worker and that wait is
+// part of their latency, instead of being silently left out of the measurements
+// (coordinated omission). This is the correction HdrHistogram's
+// RecordCorrectedValue approximates with synthetic samples, applied with the
+// actual waits.
+type schedule struct {
+	interval time.Duration
+	next     uint64 // next is the number of the next query to schedule
+
+	startOnce sync.Once
+	start     time.Time
+}
+
+// newSchedule returns a schedule of rate queries per second
+func newSchedule(rate uint64) *schedule {
+	return &schedule{interval: time.Second / time.Duration(rate)}
+}
+
+// intendedTime returns the intended send time of the next query. The schedule
+// starts with the first call.
+func (s *schedule) intendedTime() time.Time {
+	s.startOnce.Do(func() { s.start = time.Now() })
+	n := atomic.AddUint64(&s.next, 1) - 1
+	return s.start.Add(time.Duration(n) * s.interval)
+}
+
+// wait blocks until the intended send time t
+func (s *schedule) wait(t time.Time) {
+	if d := time.Until(t); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// lagSince returns how late a query intended to be sent at t is, i.e. for how
+// long it waited for a free worker.
+func lagSince(t time.Time) time.Duration {
+	if lag := time.Since(t); lag > 0 {
+		return lag
+	}
+	return 0
+}
+
+// addLag adds the time a query waited to be sent to the latencies of its stats.
+func addLag(stats []*Stat, lag time.Duration) {
+	lagMillis := float64(lag.Nanoseconds()) / 1e6
+	for _, s := range stats {
+		s.value += lagMillis
+	}
+}
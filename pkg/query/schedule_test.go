@@ -0,0 +1,76 @@
+package query
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestScheduleIntendedTime(t *testing.T) {
+	s := newSchedule(100)
+	first := s.intendedTime()
+	for i := 1; i < 5; i++ {
+		if got, want := s.intendedTime().Sub(first), time.Duration(i)*10*time.Millisecond; got != want {
+			t.Errorf("wrong offset of query %d: got %v want %v", i, got, want)
+		}
+	}
+}
+
+func TestLagSince(t *testing.T) {
+	if lag := lagSince(time.Now().Add(time.Hour)); lag != 0 {
+		t.Errorf("query not yet due is late: %v", lag)
+	}
+	if lag := lagSince(time.Now().Add(-time.Second)); lag < time.Second {
+		t.Errorf("wrong lag: got %v want at least %v", lag, time.Second)
+	}
+}
+
+// slowProcessor takes delay to answer every query and reports no latency of its own
+type slowProcessor struct {
+	delay time.Duration
+}
+
+func (p *slowProcessor) Init(_ int) {}
+
+func (p *slowProcessor) ProcessQuery(q Query, _ bool) ([]*Stat, error) {
+	time.Sleep(p.delay)
+	return []*Stat{GetStat().Init(q.HumanLabelName(), 0)}, nil
+}
+
+func TestProcessorHandlerOpenLoop(t *testing.T) {
+	const queries = 4
+	var latencies []float64
+	b := &BenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{OpenLoop: true, LimitRPS: 1000}}
+	b.schedule = newSchedule(b.LimitRPS)
+	b.sp = &mockStatProcessor{
+		args: &statProcessorArgs{},
+		onSend: func(stats []*Stat) {
+			for _, s := range stats {
+				latencies = append(latencies, s.value)
+			}
+		},
+	}
+	b.ch = make(chan Query, queries)
+	for i := 0; i < queries; i++ {
+		b.ch <- &testQuery{HumanLabel: []byte("label")}
+	}
+	close(b.ch)
+
+	// a single worker taking 10ms per query falls behind a schedule of 1 query
+	// per ms, the queries waiting for it are late by about 9ms more each
+	var wg sync.WaitGroup
+	wg.Add(1)
+	b.processorHandler(&wg, rate.NewLimiter(rate.Inf, 0), &testQueryPool, &slowProcessor{delay: 10 * time.Millisecond}, 0)
+
+	if len(latencies) != queries {
+		t.Fatalf("wrong number of stats: got %d want %d", len(latencies), queries)
+	}
+	if latencies[0] > 5 {
+		t.Errorf("first query is late: %vms", latencies[0])
+	}
+	if latencies[queries-1] < 20 {
+		t.Errorf("waiting time missing from the latency of the last query: got %vms want at least 20ms", latencies[queries-1])
+	}
+}
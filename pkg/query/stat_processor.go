@@ -5,10 +5,12 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
 	"io/ioutil"
 	"log"
 	"os"
-	"regexp"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -19,17 +21,21 @@ type statProcessor interface {
 	getArgs() *statProcessorArgs
 	send(stats []*Stat)
 	sendWarm(stats []*Stat)
+	sendError(label []byte, isWarm bool)
 	process(workers uint)
 	CloseAndWait()
-	GetTotalsMap() map[string]interface{}
+	GetTotals() *results.QueryTotals
+	intervalQuantiles() (int64, map[string]float64)
 }
 
 type statProcessorArgs struct {
-	prewarmQueries   bool    // PrewarmQueries tells the StatProcessor whether we're running each query twice to prewarm the cache
-	limit            *uint64 // limit is the number of statistics to analyze before stopping
-	burnIn           uint64  // burnIn is the number of statistics to ignore before analyzing
-	printInterval    uint64  // printInterval is how often print intermediate stats (number of queries)
-	hdrLatenciesFile string  // hdrLatenciesFile is the filename to Write the High Dynamic Range (HDR) Histogram of Response Latencies to
+	prewarmQueries   bool              // PrewarmQueries tells the StatProcessor whether we're running each query twice to prewarm the cache
+	limit            *uint64           // limit is the number of statistics to analyze before stopping
+	burnIn           uint64            // burnIn is the number of statistics to ignore before analyzing
+	warmupDuration   time.Duration     // warmupDuration is the time from the start during which statistics are ignored
+	printInterval    uint64            // printInterval is how often print intermediate stats (number of queries)
+	hdrLatenciesFile string            // hdrLatenciesFile is the filename to Write the High Dynamic Range (HDR) Histogram of Response Latencies to
+	reporter         reporter.Reporter // reporter receives the intermediate and final stats, if not nil
 
 }
 
@@ -42,13 +48,16 @@ type defaultStatProcessor struct {
 	startTime   time.Time
 	endTime     time.Time
 	statMapping map[string]*statGroup
+
+	intervalMu sync.Mutex
+	interval   *statGroup // interval collects the latencies of all queries since the last call to intervalQuantiles
 }
 
 func newStatProcessor(args *statProcessorArgs) statProcessor {
 	if args == nil {
 		panic("Stat Processor needs args")
 	}
-	return &defaultStatProcessor{args: args}
+	return &defaultStatProcessor{args: args, interval: newStatGroup(0)}
 }
 
 func (sp *defaultStatProcessor) getArgs() *statProcessorArgs {
@@ -76,6 +85,14 @@ func (sp *defaultStatProcessor) sendWarm(stats []*Stat) {
 	sp.send(stats)
 }
 
+// sendError counts a failed query with the given label.
+func (sp *defaultStatProcessor) sendError(label []byte, isWarm bool) {
+	s := GetStat().Init(label, 0)
+	s.isWarm = isWarm
+	s.isError = true
+	sp.c <- s
+}
+
 // process collects latency results, aggregating them into summary
 // statistics. Optionally, they are printed to stderr at regular intervals.
 func (sp *defaultStatProcessor) process(workers uint) {
@@ -92,12 +109,18 @@ func (sp *defaultStatProcessor) process(workers uint) {
 	}
 
 	i := uint64(0)
+	// ignored is the number of queries left out of the stats by the burn-in and the warmup
+	ignored := sp.args.burnIn
 	sp.startTime = time.Now()
 	prevTime := sp.startTime
 	prevRequestCount := uint64(0)
+	warmupEnd := sp.startTime.Add(sp.args.warmupDuration)
+	warmingUp := sp.args.warmupDuration > 0
 
 	for stat := range sp.c {
-		atomic.AddUint64(&sp.opsCount, 1)
+		if !stat.isError {
+			atomic.AddUint64(&sp.opsCount, 1)
+		}
 		if i < sp.args.burnIn {
 			i++
 			statPool.Put(stat)
@@ -108,14 +131,47 @@ func (sp *defaultStatProcessor) process(workers uint) {
 				log.Fatal(err)
 			}
 		}
+		if warmingUp {
+			now := time.Now()
+			if now.Before(warmupEnd) {
+				i++
+				statPool.Put(stat)
+				continue
+			}
+			// the rates only cover the queries after the warmup
+			warmingUp = false
+			ignored = i
+			sp.startTime = now
+			prevTime = now
+			prevRequestCount = 0
+			atomic.StoreUint64(&sp.opsCount, 0)
+			if !stat.isError {
+				atomic.AddUint64(&sp.opsCount, 1)
+			}
+			_, err := fmt.Fprintf(os.Stderr, "warmup complete after %v and %d queries with %d workers\n", sp.args.warmupDuration, i, workers)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
 		if _, ok := sp.statMapping[string(stat.label)]; !ok {
 			sp.statMapping[string(stat.label)] = newStatGroup(*sp.args.limit)
 		}
 
-		sp.statMapping[string(stat.label)].push(stat.value)
+		if stat.isError {
+			sp.countError(stat)
+			if !sp.args.prewarmQueries || !stat.isWarm {
+				i++
+			}
+		} else {
+			sp.statMapping[string(stat.label)].push(stat.value)
+			sp.countRowsRead(stat)
+		}
 
-		if !stat.isPartial {
+		if !stat.isPartial && !stat.isError {
 			sp.statMapping[allQueriesLabel].push(stat.value)
+			sp.intervalMu.Lock()
+			sp.interval.push(stat.value)
+			sp.intervalMu.Unlock()
 
 			// Only needed when differentiating between cold & warm
 			if sp.args.prewarmQueries {
@@ -144,7 +200,7 @@ func (sp *defaultStatProcessor) process(workers uint) {
 			intervalQueryRate := float64(sp.opsCount-prevRequestCount) / float64(took.Seconds())
 			overallQueryRate := float64(sp.opsCount) / float64(sinceStart.Seconds())
 			_, err := fmt.Fprintf(os.Stderr, "After %d queries with %d workers:\nInterval query rate: %0.2f queries/sec\tOverall query rate: %0.2f queries/sec\n",
-				i-sp.args.burnIn,
+				i-ignored,
 				workers,
 				intervalQueryRate,
 				overallQueryRate,
@@ -160,14 +216,25 @@ func (sp *defaultStatProcessor) process(workers uint) {
 			if err != nil {
 				log.Fatal(err)
 			}
+			r := sp.statsReport(now, i-ignored, overallQueryRate)
+			r.Add("interval_query_rate", "", intervalQueryRate)
+			sp.sendReport(r)
 			prevRequestCount = sp.opsCount
 			prevTime = now
 		}
 	}
+	if warmingUp {
+		ignored = i
+		atomic.StoreUint64(&sp.opsCount, 0)
+		_, err := fmt.Fprintf(os.Stderr, "run ended before the warmup was complete, no queries were measured\n")
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 	sinceStart := time.Now().Sub(sp.startTime)
 	overallQueryRate := float64(sp.opsCount) / float64(sinceStart.Seconds())
 	// the final stats output goes to stdout:
-	_, err := fmt.Printf("Run complete after %d queries with %d workers (Overall query rate %0.2f queries/sec):\n", i-sp.args.burnIn, workers, overallQueryRate)
+	_, err := fmt.Printf("Run complete after %d queries with %d workers (Overall query rate %0.2f queries/sec):\n", i-ignored, workers, overallQueryRate)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -175,6 +242,12 @@ func (sp *defaultStatProcessor) process(workers uint) {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if sp.args.reporter != nil {
+		sp.sendReport(sp.statsReport(time.Now(), i-ignored, overallQueryRate))
+		if err := sp.args.reporter.Close(); err != nil {
+			log.Printf("could not close metrics reporter: %v", err)
+		}
+	}
 
 	if len(sp.args.hdrLatenciesFile) > 0 {
 		_, _ = fmt.Printf("Saving High Dynamic Range (HDR) Histogram of Response Latencies to %s\n", sp.args.hdrLatenciesFile)
@@ -194,6 +267,70 @@ func (sp *defaultStatProcessor) process(workers uint) {
 	sp.wg.Done()
 }
 
+// countError counts a failed query under its label and all queries
+func (sp *defaultStatProcessor) countError(stat *Stat) {
+	sp.statMapping[string(stat.label)].pushError()
+	sp.statMapping[labelAllQueries].pushError()
+	if sp.args.prewarmQueries {
+		if stat.isWarm {
+			sp.statMapping[labelWarmQueries].pushError()
+		} else {
+			sp.statMapping[labelColdQueries].pushError()
+		}
+	}
+}
+
+// countRowsRead adds the rows read by a query under its label and all queries.
+// With prewarmed queries only the warm run of each query is counted, the
+// stats of the queries left out by the burn-in and the warmup never get here.
+func (sp *defaultStatProcessor) countRowsRead(stat *Stat) {
+	rows, bytes, ok := stat.RowsRead()
+	if !ok || (sp.args.prewarmQueries && !stat.isWarm) {
+		return
+	}
+	sp.statMapping[string(stat.label)].pushRowsRead(rows, bytes)
+	if !stat.isPartial {
+		sp.statMapping[labelAllQueries].pushRowsRead(rows, bytes)
+	}
+}
+
+// statsReport returns a report of the number of completed queries, the overall
+// query rate and the latencies of every stat group
+func (sp *defaultStatProcessor) statsReport(now time.Time, queries uint64, overallQueryRate float64) *reporter.Report {
+	r := &reporter.Report{Time: now, Subsystem: reporter.SubsystemQuery}
+	r.Add("queries", "", float64(queries))
+	r.Add("overall_query_rate", "", overallQueryRate)
+	labels := make([]string, 0, len(sp.statMapping))
+	for label := range sp.statMapping {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		g := sp.statMapping[label]
+		r.Add("latency_count", label, float64(g.count))
+		r.Add("latency_min_ms", label, g.Min())
+		r.Add("latency_median_ms", label, g.Median())
+		r.Add("latency_mean_ms", label, g.Mean())
+		r.Add("latency_p95_ms", label, float64(g.latencyHDRHistogram.ValueAtQuantile(95.0))/hdrScaleFactor)
+		r.Add("latency_p99_ms", label, float64(g.latencyHDRHistogram.ValueAtQuantile(99.0))/hdrScaleFactor)
+		r.Add("latency_max_ms", label, g.Max())
+		r.Add("latency_stddev_ms", label, g.StdDev())
+		r.Add("errors", label, float64(g.errors))
+		r.Add("error_rate", label, g.ErrorRate())
+	}
+	return r
+}
+
+// sendReport sends r to the metrics sinks, errors are logged but do not stop the benchmark
+func (sp *defaultStatProcessor) sendReport(r *reporter.Report) {
+	if sp.args.reporter == nil {
+		return
+	}
+	if err := sp.args.reporter.Report(r); err != nil {
+		log.Printf("could not send report: %v", err)
+	}
+}
+
 func generateQuantileMap(hist *hdrhistogram.Histogram) (int64, map[string]float64) {
 	ops := hist.TotalCount()
 	q0 := 0.0
@@ -215,35 +352,52 @@ func generateQuantileMap(hist *hdrhistogram.Histogram) (int64, map[string]float6
 	return ops, mp
 }
 
-func (sp *defaultStatProcessor) GetTotalsMap() map[string]interface{} {
-	totals := make(map[string]interface{})
-	// PrewarmQueries tells the StatProcessor whether we're running each query twice to prewarm the cache
-	totals["prewarmQueries"] = sp.args.prewarmQueries
-	// limit is the number of statistics to analyze before stopping
-	totals["limit"] = sp.args.limit
-	// burnIn is the number of statistics to ignore before analyzing
-	totals["burnIn"] = sp.args.burnIn
-	sinceStart := time.Now().Sub(sp.startTime)
-	// calculate overall query rates
-	queryRates := make(map[string]interface{})
-	for label, statGroup := range sp.statMapping {
-		overallQueryRate := float64(statGroup.count) / sinceStart.Seconds()
-		queryRates[stripRegex(label)] = overallQueryRate
-	}
-	totals["overallQueryRates"] = queryRates
-	// calculate overall quantiles
-	quantiles := make(map[string]interface{})
-	for label, statGroup := range sp.statMapping {
-		_, all := generateQuantileMap(statGroup.latencyHDRHistogram)
-		quantiles[stripRegex(label)] = all
-	}
-	totals["overallQuantiles"] = quantiles
-	return totals
+// intervalQuantiles returns the number of queries and the quantiles of their
+// latencies (in milliseconds) since the previous call, then starts a new interval.
+func (sp *defaultStatProcessor) intervalQuantiles() (int64, map[string]float64) {
+	sp.intervalMu.Lock()
+	defer sp.intervalMu.Unlock()
+	count, quantiles := generateQuantileMap(sp.interval.latencyHDRHistogram)
+	sp.interval.latencyHDRHistogram.Reset()
+	sp.interval.sum = 0
+	sp.interval.count = 0
+	return count, quantiles
 }
 
-func stripRegex(in string) string {
-	reg, _ := regexp.Compile("[^a-zA-Z0-9]+")
-	return reg.ReplaceAllString(in, "_")
+// GetTotals returns the query totals of the results file
+func (sp *defaultStatProcessor) GetTotals() *results.QueryTotals {
+	sinceStart := time.Now().Sub(sp.startTime)
+	totals := &results.QueryTotals{
+		QueryRate:      float64(sp.opsCount) / sinceStart.Seconds(),
+		BurnIn:         sp.args.burnIn,
+		PrewarmQueries: sp.args.prewarmQueries,
+		Latencies:      make([]results.Latency, 0, len(sp.statMapping)),
+	}
+	if all, ok := sp.statMapping[labelAllQueries]; ok {
+		totals.Queries = uint64(all.count)
+		totals.Errors = uint64(all.errors)
+	}
+	for label, g := range sp.statMapping {
+		totals.Latencies = append(totals.Latencies, results.Latency{
+			Label:  label,
+			Count:  g.count,
+			Rate:   float64(g.count) / sinceStart.Seconds(),
+			Min:    g.Min(),
+			Median: g.Median(),
+			Mean:   g.Mean(),
+			P95:    float64(g.latencyHDRHistogram.ValueAtQuantile(95.0)) / hdrScaleFactor,
+			P99:    float64(g.latencyHDRHistogram.ValueAtQuantile(99.0)) / hdrScaleFactor,
+			P999:   float64(g.latencyHDRHistogram.ValueAtQuantile(99.9)) / hdrScaleFactor,
+			Max:    g.Max(),
+			StdDev: g.StdDev(),
+
+			Errors:    uint64(g.errors),
+			ErrorRate: g.ErrorRate(),
+			RowsRead:  g.RowsRead(),
+		})
+	}
+	results.SortLatencies(totals.Latencies)
+	return totals
 }
 
 // CloseAndWait closes the stats channel and blocks until the StatProcessor has finished all the stats on its channel.
@@ -1,8 +1,12 @@
 package query
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/reporter"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
 )
 
 func TestStatProcessorSend(t *testing.T) {
@@ -77,3 +81,148 @@ func TestStatProcessorSendWarm(t *testing.T) {
 		t.Errorf("empty stat array changed channel length: got %d want %d", got, wantLen)
 	}
 }
+
+func TestStatProcessorIntervalQuantiles(t *testing.T) {
+	limit := uint64(0)
+	sp := newStatProcessor(&statProcessorArgs{limit: &limit})
+	for _, v := range []float64{10, 20, 30} {
+		sp.(*defaultStatProcessor).interval.push(v)
+	}
+
+	count, quantiles := sp.intervalQuantiles()
+	if count != 3 {
+		t.Errorf("wrong interval count: got %d want %d", count, 3)
+	}
+	if got := quantiles["q100"]; got != 30 {
+		t.Errorf("wrong interval max: got %v want %v", got, 30)
+	}
+
+	// a new interval starts after every call
+	if count, _ = sp.intervalQuantiles(); count != 0 {
+		t.Errorf("interval was not reset: got count %d", count)
+	}
+}
+
+type testReporter struct {
+	reports []*reporter.Report
+	closed  bool
+}
+
+func (r *testReporter) Report(report *reporter.Report) error {
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *testReporter) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestStatProcessorReport(t *testing.T) {
+	limit := uint64(0)
+	tr := &testReporter{}
+	sp := newStatProcessor(&statProcessorArgs{limit: &limit, reporter: tr}).(*defaultStatProcessor)
+	sp.statMapping = map[string]*statGroup{
+		labelAllQueries: newStatGroup(0),
+		"label":         newStatGroup(0),
+	}
+	sp.statMapping["label"].push(10)
+	sp.statMapping[labelAllQueries].push(10)
+
+	sp.sendReport(sp.statsReport(time.Now(), 1, 2.5))
+	if len(tr.reports) != 1 {
+		t.Fatalf("wrong number of reports: got %d want %d", len(tr.reports), 1)
+	}
+	values := make(map[string]float64)
+	for _, m := range tr.reports[0].Metrics {
+		values[m.Name+"/"+m.Label] = m.Value
+	}
+	if got := values["overall_query_rate/"]; got != 2.5 {
+		t.Errorf("wrong overall query rate: got %v want %v", got, 2.5)
+	}
+	if got := values["latency_max_ms/label"]; got != 10 {
+		t.Errorf("wrong max latency: got %v want %v", got, 10)
+	}
+	if got := values["latency_count/"+labelAllQueries]; got != 1 {
+		t.Errorf("wrong count: got %v want %v", got, 1)
+	}
+}
+
+func TestStatProcessorCountError(t *testing.T) {
+	limit := uint64(0)
+	sp := newStatProcessor(&statProcessorArgs{limit: &limit, prewarmQueries: true}).(*defaultStatProcessor)
+	sp.statMapping = map[string]*statGroup{
+		labelAllQueries:  newStatGroup(0),
+		labelColdQueries: newStatGroup(0),
+		labelWarmQueries: newStatGroup(0),
+		"label":          newStatGroup(0),
+	}
+	for _, v := range []float64{10, 20, 30} {
+		sp.statMapping["label"].push(v)
+		sp.statMapping[labelAllQueries].push(v)
+	}
+	s := GetStat().Init([]byte("label"), 0)
+	s.isError = true
+	sp.countError(s)
+
+	totals := sp.GetTotals()
+	if totals.Queries != 3 {
+		t.Errorf("wrong number of queries: got %d want %d", totals.Queries, 3)
+	}
+	if totals.Errors != 1 {
+		t.Errorf("wrong number of errors: got %d want %d", totals.Errors, 1)
+	}
+	want := map[string]uint64{labelAllQueries: 1, labelColdQueries: 1, labelWarmQueries: 0, "label": 1}
+	for _, l := range totals.Latencies {
+		if l.Errors != want[l.Label] {
+			t.Errorf("wrong number of errors for %s: got %d want %d", l.Label, l.Errors, want[l.Label])
+		}
+		if l.Label == "label" && l.ErrorRate != 0.25 {
+			t.Errorf("wrong error rate: got %v want %v", l.ErrorRate, 0.25)
+		}
+	}
+	if got := sp.statMapping["label"].string(); !strings.HasSuffix(got, "count: 3, errors: 1 (25.00%)") {
+		t.Errorf("errors missing from summary: %s", got)
+	}
+}
+
+func TestStatProcessorCountRowsRead(t *testing.T) {
+	limit := uint64(0)
+	sp := newStatProcessor(&statProcessorArgs{limit: &limit, prewarmQueries: true}).(*defaultStatProcessor)
+	sp.statMapping = map[string]*statGroup{
+		labelAllQueries: newStatGroup(0),
+		"a":             newStatGroup(0),
+		"b":             newStatGroup(0),
+	}
+	for _, q := range []struct {
+		label       string
+		rows, bytes uint64
+		warm        bool
+	}{
+		{"b", 10, 100, true},
+		{"a", 1, 10, true},
+		{"b", 30, 300, true},
+		// the cold runs of prewarmed queries are not counted
+		{"b", 1000, 10000, false},
+	} {
+		s := GetStat().Init([]byte(q.label), 1).SetRowsRead(q.rows, q.bytes)
+		s.isWarm = q.warm
+		sp.countRowsRead(s)
+	}
+	// nor are the queries whose database reports no rows read
+	sp.countRowsRead(GetStat().Init([]byte("a"), 1))
+
+	want := map[string]results.RowsRead{
+		"a":             {Queries: 1, Min: 1, Mean: 1, Max: 1, Sum: 1, BytesMean: 10, BytesSum: 10},
+		"b":             {Queries: 2, Min: 10, Mean: 20, Max: 30, Sum: 40, BytesMean: 200, BytesSum: 400},
+		labelAllQueries: {Queries: 3, Min: 1, Mean: 41.0 / 3, Max: 30, Sum: 41, BytesMean: 410.0 / 3, BytesSum: 410},
+	}
+	for _, l := range sp.GetTotals().Latencies {
+		if l.RowsRead == nil || *l.RowsRead != want[l.Label] {
+			t.Errorf("wrong rows read for %s: got %+v want %+v", l.Label, l.RowsRead, want[l.Label])
+		}
+	}
+	if got := sp.statMapping["b"].string(); !strings.HasSuffix(got, "\nrows read min: 10, mean: 20.00, max: 30, sum: 40, bytes read mean: 200.00, sum: 400") {
+		t.Errorf("rows read missing from summary: %s", got)
+	}
+}
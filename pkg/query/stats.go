@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
 )
 
 var (
@@ -20,6 +21,13 @@ type Stat struct {
 	value     float64
 	isWarm    bool
 	isPartial bool
+	// isError marks the Stat of a query that failed, its value is not a latency
+	isError bool
+	// hasRowsRead marks the Stat of a query whose database reported the rows
+	// and bytes it read
+	hasRowsRead bool
+	rowsRead    uint64
+	bytesRead   uint64
 }
 
 var statPool = &sync.Pool{
@@ -49,14 +57,33 @@ func (s *Stat) Init(label []byte, value float64) *Stat {
 	s.label = append(s.label, label...)
 	s.value = value
 	s.isWarm = false
+	s.isError = false
+	s.hasRowsRead = false
 	return s
 }
 
+// SetRowsRead records the rows and bytes read by the query, as reported by
+// the database.
+func (s *Stat) SetRowsRead(rows, bytes uint64) *Stat {
+	s.hasRowsRead = true
+	s.rowsRead = rows
+	s.bytesRead = bytes
+	return s
+}
+
+// RowsRead returns the rows and bytes read by the query, and false if they
+// were not recorded.
+func (s *Stat) RowsRead() (uint64, uint64, bool) {
+	return s.rowsRead, s.bytesRead, s.hasRowsRead
+}
+
 func (s *Stat) reset() *Stat {
 	s.label = s.label[:0]
 	s.value = 0.0
 	s.isWarm = false
 	s.isPartial = false
+	s.isError = false
+	s.hasRowsRead = false
 	return s
 }
 
@@ -65,6 +92,14 @@ type statGroup struct {
 	latencyHDRHistogram *hdrhistogram.Histogram
 	sum                 float64
 	count               int64
+	errors              int64 // errors is the number of failed queries, not included in count
+
+	// rows read by the queries that reported them, readQueries of them
+	readQueries uint64
+	rowsRead    uint64
+	bytesRead   uint64
+	minRowsRead uint64
+	maxRowsRead uint64
 }
 
 // newStatGroup returns a new StatGroup with an initial size
@@ -90,9 +125,52 @@ func (s *statGroup) push(n float64) {
 	s.count++
 }
 
+// pushRowsRead adds the rows and bytes read by a query to a StatGroup.
+func (s *statGroup) pushRowsRead(rows, bytes uint64) {
+	if s.readQueries == 0 || rows < s.minRowsRead {
+		s.minRowsRead = rows
+	}
+	if rows > s.maxRowsRead {
+		s.maxRowsRead = rows
+	}
+	s.readQueries++
+	s.rowsRead += rows
+	s.bytesRead += bytes
+}
+
+// RowsRead returns the rows read stats of the StatGroup, nil if none of its
+// queries reported them.
+func (s *statGroup) RowsRead() *results.RowsRead {
+	if s.readQueries == 0 {
+		return nil
+	}
+	return &results.RowsRead{
+		Queries:   s.readQueries,
+		Min:       s.minRowsRead,
+		Mean:      float64(s.rowsRead) / float64(s.readQueries),
+		Max:       s.maxRowsRead,
+		Sum:       s.rowsRead,
+		BytesMean: float64(s.bytesRead) / float64(s.readQueries),
+		BytesSum:  s.bytesRead,
+	}
+}
+
+// pushError counts a failed query in a StatGroup.
+func (s *statGroup) pushError() {
+	s.errors++
+}
+
+// ErrorRate returns the fraction of the queries of the StatGroup that failed
+func (s *statGroup) ErrorRate() float64 {
+	if s.errors == 0 {
+		return 0
+	}
+	return float64(s.errors) / float64(s.count+s.errors)
+}
+
 // string makes a simple description of a statGroup.
 func (s *statGroup) string() string {
-	return fmt.Sprintf("min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms, sum: %5.1fsec, count: %d",
+	str := fmt.Sprintf("min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms, sum: %5.1fsec, count: %d",
 		s.Min(),
 		s.Median(),
 		s.Mean(),
@@ -100,6 +178,14 @@ func (s *statGroup) string() string {
 		s.StdDev(),
 		s.sum/hdrScaleFactor,
 		s.count)
+	if s.errors > 0 {
+		str += fmt.Sprintf(", errors: %d (%.2f%%)", s.errors, s.ErrorRate()*100)
+	}
+	if r := s.RowsRead(); r != nil {
+		str += fmt.Sprintf("\nrows read min: %d, mean: %.2f, max: %d, sum: %d, bytes read mean: %.2f, sum: %d",
+			r.Min, r.Mean, r.Max, r.Sum, r.BytesMean, r.BytesSum)
+	}
+	return str
 }
 
 func (s *statGroup) write(w io.Writer) error {
@@ -5,12 +5,6 @@ import "testing"
 func TestNewTDengine(t *testing.T) {
 	check := func(tq *TDengine) {
 		testValidNewQuery(t, tq)
-		if got := len(tq.SuperTable); got != 0 {
-			t.Errorf("new query has non-0 hypertable label: got %d", got)
-		}
-		if got := len(tq.Table); got != 0 {
-			t.Errorf("new query has non-0 table label: got %d", got)
-		}
 		if got := len(tq.SqlQuery); got != 0 {
 			t.Errorf("new query has non-0 sql query: got %d", got)
 		}
@@ -19,8 +13,6 @@ func TestNewTDengine(t *testing.T) {
 	check(tq)
 	tq.HumanLabel = []byte("foo")
 	tq.HumanDescription = []byte("bar")
-	tq.SuperTable = []byte("super table")
-	tq.Table = []byte("table")
 	tq.SqlQuery = []byte("SELECT * FROM *")
 	tq.SetID(1)
 	if got := string(tq.HumanLabelName()); got != "foo" {
@@ -0,0 +1,269 @@
+package query
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/results"
+	"gopkg.in/yaml.v2"
+)
+
+// allQueriesLabel is the label of the column aliases applying to every query.
+const allQueriesLabel = "*"
+
+// answer is a single line of an answers file: the canonical result of one
+// query, keyed by the ID the scanner assigned to the query.
+type answer struct {
+	ID          uint64          `json:"id"`
+	Label       string          `json:"label"`
+	Description string          `json:"description"`
+	Columns     []string        `json:"columns"`
+	Rows        [][]interface{} `json:"rows"`
+}
+
+// verificationGroup counts verification outcomes for a single query label.
+type verificationGroup struct {
+	checked    uint64
+	mismatched uint64
+	missing    uint64
+}
+
+// resultVerifier records the canonical results of queries to an answers file
+// and/or compares them with the answers recorded by a previous run, possibly
+// against a different database.
+type resultVerifier struct {
+	mu        sync.Mutex
+	tolerance float64
+	ignore    map[string]bool
+	aliases   map[string]map[string]string
+	reference map[uint64]*answer
+	out       *bufio.Writer
+	outFile   *os.File
+	groups    map[string]*verificationGroup
+	errOut    io.Writer
+}
+
+// newResultVerifier creates a resultVerifier that reads reference answers from
+// referenceFile and writes the answers of this run to recordFile. Either may be empty.
+// aliasesFile, if not empty, is a YAML file mapping query labels ("*" for all
+// of them) to the names the columns of this database have in the reference.
+func newResultVerifier(referenceFile, recordFile string, tolerance float64, ignoreColumns, aliasesFile string) (*resultVerifier, error) {
+	v := &resultVerifier{
+		tolerance: tolerance,
+		ignore:    make(map[string]bool),
+		groups:    make(map[string]*verificationGroup),
+		errOut:    os.Stderr,
+	}
+	for _, c := range strings.Split(ignoreColumns, ",") {
+		c = strings.TrimSpace(c)
+		if c != "" {
+			v.ignore[normalizeColumn(c)] = true
+		}
+	}
+	if aliasesFile != "" {
+		data, err := ioutil.ReadFile(aliasesFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read column aliases file %s: %v", aliasesFile, err)
+		}
+		if v.aliases, err = parseColumnAliases(data); err != nil {
+			return nil, fmt.Errorf("cannot parse column aliases file %s: %v", aliasesFile, err)
+		}
+	}
+	if referenceFile != "" {
+		f, err := os.Open(referenceFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open answers file %s: %v", referenceFile, err)
+		}
+		defer f.Close()
+		v.reference, err = readAnswers(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read answers file %s: %v", referenceFile, err)
+		}
+	}
+	if recordFile != "" {
+		f, err := os.Create(recordFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create answers file %s: %v", recordFile, err)
+		}
+		v.outFile = f
+		v.out = bufio.NewWriter(f)
+	}
+	return v, nil
+}
+
+// parseColumnAliases decodes the column aliases of every query label, with
+// the column names normalized.
+func parseColumnAliases(data []byte) (map[string]map[string]string, error) {
+	var raw map[string]map[string]string
+	if err := yaml.UnmarshalStrict(data, &raw); err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]map[string]string, len(raw))
+	for label, columns := range raw {
+		aliases[label] = make(map[string]string, len(columns))
+		for from, to := range columns {
+			aliases[label][normalizeColumn(from)] = normalizeColumn(to)
+		}
+	}
+	return aliases, nil
+}
+
+// aliasesOf returns the column aliases of the queries with the given label,
+// those of the label itself taking precedence over those of all queries.
+func (v *resultVerifier) aliasesOf(label string) map[string]string {
+	all, own := v.aliases[allQueriesLabel], v.aliases[label]
+	if len(all) == 0 {
+		return own
+	}
+	if len(own) == 0 {
+		return all
+	}
+	merged := make(map[string]string, len(all)+len(own))
+	for from, to := range all {
+		merged[from] = to
+	}
+	for from, to := range own {
+		merged[from] = to
+	}
+	return merged
+}
+
+// readAnswers decodes an answers file, one JSON encoded answer per line.
+func readAnswers(r io.Reader) (map[uint64]*answer, error) {
+	answers := make(map[uint64]*answer)
+	decoder := json.NewDecoder(r)
+	for {
+		a := &answer{}
+		err := decoder.Decode(a)
+		if err == io.EOF {
+			return answers, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		answers[a.ID] = a
+	}
+}
+
+// check canonicalizes the result of query q, records it if requested and
+// compares it with the reference answer for the same query ID.
+func (v *resultVerifier) check(q Query, rs *ResultSet) {
+	if rs == nil {
+		rs = NewResultSet()
+	}
+	label := string(q.HumanLabelName())
+	c := rs.canonical(v.ignore, v.aliasesOf(label))
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.out != nil {
+		line, err := json.Marshal(&answer{
+			ID:          q.GetID(),
+			Label:       label,
+			Description: string(q.HumanDescriptionName()),
+			Columns:     c.Columns,
+			Rows:        c.Rows,
+		})
+		if err != nil {
+			panic(fmt.Sprintf("cannot encode answer for query %d: %v", q.GetID(), err))
+		}
+		v.out.Write(line)
+		v.out.WriteByte('\n')
+	}
+
+	if v.reference == nil {
+		return
+	}
+	g, ok := v.groups[label]
+	if !ok {
+		g = &verificationGroup{}
+		v.groups[label] = g
+	}
+	g.checked++
+	ref, ok := v.reference[q.GetID()]
+	if !ok {
+		g.missing++
+		_, _ = fmt.Fprintf(v.errOut, "verification: query %d (%s): no reference answer\n", q.GetID(), label)
+		return
+	}
+	want := (&ResultSet{Columns: ref.Columns, Rows: ref.Rows}).canonical(v.ignore, nil)
+	if d := c.diff(want, v.tolerance); d != "" {
+		g.mismatched++
+		_, _ = fmt.Fprintf(v.errOut, "verification: query %d (%s): %s\n", q.GetID(), label, d)
+	}
+}
+
+// close flushes and closes the recorded answers file, if any.
+func (v *resultVerifier) close() error {
+	if v.out == nil {
+		return nil
+	}
+	if err := v.out.Flush(); err != nil {
+		return err
+	}
+	return v.outFile.Close()
+}
+
+func (v *resultVerifier) sortedLabels() []string {
+	labels := make([]string, 0, len(v.groups))
+	for label := range v.groups {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// mismatches returns the total number of queries whose result did not match the reference.
+func (v *resultVerifier) mismatches() uint64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	total := uint64(0)
+	for _, g := range v.groups {
+		total += g.mismatched + g.missing
+	}
+	return total
+}
+
+// write prints a per label summary of the verification to w.
+func (v *resultVerifier) write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.reference == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "Result verification:\n"); err != nil {
+		return err
+	}
+	for _, label := range v.sortedLabels() {
+		g := v.groups[label]
+		_, err := fmt.Fprintf(w, "%s:\nchecked: %d, mismatched: %d, missing reference: %d\n",
+			label, g.checked, g.mismatched, g.missing)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// totals returns the verification summary for the results file.
+func (v *resultVerifier) totals() map[string]results.VerificationTotals {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	totals := make(map[string]results.VerificationTotals)
+	for label, g := range v.groups {
+		totals[label] = results.VerificationTotals{
+			Checked:    g.checked,
+			Mismatched: g.mismatched,
+			Missing:    g.missing,
+		}
+	}
+	return totals
+}
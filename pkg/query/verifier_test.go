@@ -0,0 +1,97 @@
+package query
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultVerifierRecordAndVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	answers := filepath.Join(dir, "answers.jsonl")
+
+	newQuery := func(id uint64, label string) Query {
+		q := testQueryPool.Get().(*testQuery)
+		q.HumanLabel = []byte(label)
+		q.SetID(id)
+		return q
+	}
+
+	rec, err := newResultVerifier("", answers, 1e-4, "", "")
+	if err != nil {
+		t.Fatalf("could not create recorder: %v", err)
+	}
+	rs := NewResultSet("name", "value")
+	rs.AppendRow("truck_1", 1.5)
+	rec.check(newQuery(0, "foo"), rs)
+	rec.check(newQuery(1, "bar"), NewResultSet("name"))
+	if err := rec.close(); err != nil {
+		t.Fatalf("could not close recorder: %v", err)
+	}
+
+	aliases := filepath.Join(dir, "aliases.yaml")
+	if err := ioutil.WriteFile(aliases, []byte("foo:\n  avg(value): value\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ver, err := newResultVerifier(answers, "", 1e-4, "", aliases)
+	if err != nil {
+		t.Fatalf("could not create verifier: %v", err)
+	}
+	var errOut bytes.Buffer
+	ver.errOut = &errOut
+
+	same := NewResultSet("avg(value)", "name")
+	same.AppendRow("1.5", "truck_1")
+	ver.check(newQuery(0, "foo"), same)
+	wrong := NewResultSet("name")
+	wrong.AppendRow("truck_2")
+	ver.check(newQuery(1, "bar"), wrong)
+	ver.check(newQuery(2, "bar"), nil)
+
+	if got := ver.groups["foo"].mismatched; got != 0 {
+		t.Errorf("foo: unexpected mismatches: got %d", got)
+	}
+	if got := ver.groups["bar"].mismatched; got != 1 {
+		t.Errorf("bar: wrong mismatch count: got %d want 1", got)
+	}
+	if got := ver.groups["bar"].missing; got != 1 {
+		t.Errorf("bar: wrong missing count: got %d want 1", got)
+	}
+	if got := ver.mismatches(); got != 2 {
+		t.Errorf("wrong total mismatches: got %d want 2", got)
+	}
+	if errOut.Len() == 0 {
+		t.Errorf("mismatches were not reported")
+	}
+}
+
+func TestNewResultVerifierMissingFile(t *testing.T) {
+	_, err := newResultVerifier("some-random-file-that-should-not-exist", "", 0, "", "")
+	if err == nil {
+		t.Errorf("expected error for missing answers file")
+	}
+}
+
+func TestParseColumnAliases(t *testing.T) {
+	aliases, err := parseColumnAliases([]byte("\"*\":\n  TS: time\nfoo:\n  ts: created\n  \"avg(v)\": v\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v := &resultVerifier{aliases: aliases}
+	if got := v.aliasesOf("bar")["ts"]; got != "time" {
+		t.Errorf("incorrect alias for all queries: got %s want time", got)
+	}
+	foo := v.aliasesOf("foo")
+	if foo["ts"] != "created" || foo["avg(v)"] != "v" {
+		t.Errorf("incorrect aliases of the query label: got %v", foo)
+	}
+	if _, err := parseColumnAliases([]byte("foo: [a, b]\n")); err == nil {
+		t.Errorf("expected an error for an invalid aliases file")
+	}
+}
@@ -0,0 +1,158 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// jsonLine is the JSON encoding of a Report.
+type jsonLine struct {
+	Time      string       `json:"time"`
+	Subsystem string       `json:"subsystem"`
+	Metrics   []jsonMetric `json:"metrics"`
+}
+
+type jsonMetric struct {
+	Name  string  `json:"name"`
+	Label string  `json:"label,omitempty"`
+	Value float64 `json:"value"`
+}
+
+// jsonLinesReporter writes every report as a single line of JSON.
+type jsonLinesReporter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+	c  io.Closer
+}
+
+// NewJSONLinesFile returns a Reporter appending reports to the file fileName,
+// one JSON object per line.
+func NewJSONLinesFile(fileName string) (Reporter, error) {
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLines(f), nil
+}
+
+// NewJSONLines returns a Reporter writing reports to w, one JSON object per
+// line. If w is an io.Closer it is closed with the Reporter.
+func NewJSONLines(w io.Writer) Reporter {
+	r := &jsonLinesReporter{w: bufio.NewWriter(w)}
+	r.c, _ = w.(io.Closer)
+	return r
+}
+
+func (r *jsonLinesReporter) Report(report *Report) error {
+	line := jsonLine{
+		Time:      report.Time.UTC().Format(time.RFC3339Nano),
+		Subsystem: report.Subsystem,
+		Metrics:   make([]jsonMetric, 0, len(report.Metrics)),
+	}
+	for _, m := range report.Metrics {
+		line.Metrics = append(line.Metrics, jsonMetric(m))
+	}
+	b, err := json.Marshal(&line)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.w.Write(b); err != nil {
+		return err
+	}
+	if err := r.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	// flush every report, so that the file can be followed while the benchmark runs
+	return r.w.Flush()
+}
+
+func (r *jsonLinesReporter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.w.Flush(); err != nil {
+		return err
+	}
+	if r.c != nil {
+		return r.c.Close()
+	}
+	return nil
+}
+
+// csvHeader is the header of the CSV files written by a csvReporter
+var csvHeader = []string{"time", "subsystem", "label", "metric", "value"}
+
+// csvReporter writes every metric of a report as a CSV record.
+type csvReporter struct {
+	mu          sync.Mutex
+	w           *csv.Writer
+	c           io.Closer
+	wroteHeader bool
+}
+
+// NewCSVFile returns a Reporter appending reports to the CSV file fileName. The
+// header is only written if the file is empty.
+func NewCSVFile(fileName string) (Reporter, error) {
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r := NewCSV(f).(*csvReporter)
+	r.wroteHeader = info.Size() > 0
+	return r, nil
+}
+
+// NewCSV returns a Reporter writing reports to w as CSV, one record per metric
+// with the columns time,subsystem,label,metric,value. If w is an io.Closer it
+// is closed with the Reporter.
+func NewCSV(w io.Writer) Reporter {
+	r := &csvReporter{w: csv.NewWriter(w)}
+	r.c, _ = w.(io.Closer)
+	return r
+}
+
+func (r *csvReporter) Report(report *Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.wroteHeader {
+		if err := r.w.Write(csvHeader); err != nil {
+			return err
+		}
+		r.wroteHeader = true
+	}
+	t := strconv.FormatInt(report.Time.Unix(), 10)
+	for _, m := range report.Metrics {
+		record := []string{t, report.Subsystem, m.Label, m.Name, strconv.FormatFloat(m.Value, 'f', -1, 64)}
+		if err := r.w.Write(record); err != nil {
+			return err
+		}
+	}
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func (r *csvReporter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.w.Flush()
+	if err := r.w.Error(); err != nil {
+		return err
+	}
+	if r.c != nil {
+		return r.c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// metricNamespace prefixes the names of all the Prometheus metrics
+	metricNamespace = "tsdb_comparisons"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// seriesKey identifies a Prometheus time series
+type seriesKey struct {
+	name  string
+	label string
+}
+
+// prometheusReporter keeps the latest value of every metric and serves them in
+// the Prometheus text exposition format. Metrics are exposed as gauges named
+// tsdb_comparisons_<subsystem>_<metric>, with the metric label (if any) as the
+// 'label' label.
+type prometheusReporter struct {
+	mu       sync.Mutex
+	values   map[seriesKey]float64
+	updated  time.Time
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewPrometheus returns a Reporter serving the reported metrics at /metrics on
+// the address listen.
+func NewPrometheus(listen string) (Reporter, error) {
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen for Prometheus metrics on %s: %v", listen, err)
+	}
+	r := newPrometheusReporter()
+	r.listener = l
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r)
+	r.server = &http.Server{Handler: mux}
+	go r.server.Serve(l)
+	return r, nil
+}
+
+func newPrometheusReporter() *prometheusReporter {
+	return &prometheusReporter{values: make(map[seriesKey]float64)}
+}
+
+func (r *prometheusReporter) Report(report *Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range report.Metrics {
+		name := metricName(report.Subsystem, m.Name)
+		r.values[seriesKey{name: name, label: m.Label}] = m.Value
+	}
+	r.updated = report.Time
+	return nil
+}
+
+func (r *prometheusReporter) Close() error {
+	if r.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return r.server.Shutdown(ctx)
+}
+
+// ServeHTTP writes all the metrics in the Prometheus text format.
+func (r *prometheusReporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(r.exposition())
+}
+
+func (r *prometheusReporter) exposition() []byte {
+	r.mu.Lock()
+	keys := make([]seriesKey, 0, len(r.values))
+	for k := range r.values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].label < keys[j].label
+	})
+
+	var buf bytes.Buffer
+	prevName := ""
+	for _, k := range keys {
+		if k.name != prevName {
+			fmt.Fprintf(&buf, "# TYPE %s gauge\n", k.name)
+			prevName = k.name
+		}
+		buf.WriteString(k.name)
+		if k.label != "" {
+			fmt.Fprintf(&buf, "{label=\"%s\"}", escapeLabelValue(k.label))
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatFloat(r.values[k], 'g', -1, 64))
+		buf.WriteByte('\n')
+	}
+	if !r.updated.IsZero() {
+		name := metricNamespace + "_last_report_timestamp_seconds"
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n%s %d\n", name, name, r.updated.Unix())
+	}
+	r.mu.Unlock()
+	return buf.Bytes()
+}
+
+// metricName returns a valid Prometheus metric name for a metric of a subsystem
+func metricName(subsystem, name string) string {
+	return metricNamespace + "_" + sanitizeName(subsystem) + "_" + sanitizeName(name)
+}
+
+// sanitizeName replaces all characters not allowed in Prometheus metric names with '_'
+func sanitizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
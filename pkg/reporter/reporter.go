@@ -0,0 +1,120 @@
+// Package reporter sends the progress of load and query benchmarks to metrics
+// sinks, so that long running benchmarks can be followed without parsing their
+// console output.
+package reporter
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// SubsystemLoad is the Subsystem of the reports of a load benchmark
+	SubsystemLoad = "load"
+	// SubsystemQuery is the Subsystem of the reports of a query benchmark
+	SubsystemQuery = "query"
+)
+
+// Metric is a single named value of a Report.
+type Metric struct {
+	Name string
+	// Label distinguishes metrics with the same name, e.g. the query type of
+	// query latencies. It is empty for metrics of the whole benchmark.
+	Label string
+	Value float64
+}
+
+// Report is a snapshot of the progress of a benchmark.
+type Report struct {
+	Time      time.Time
+	Subsystem string
+	Metrics   []Metric
+}
+
+// Add appends a metric to the Report.
+func (r *Report) Add(name, label string, value float64) {
+	r.Metrics = append(r.Metrics, Metric{Name: name, Label: label, Value: value})
+}
+
+// Reporter is a metrics sink that receives the reports of a benchmark.
+type Reporter interface {
+	// Report sends a single report to the sink
+	Report(r *Report) error
+	// Close flushes the sink and releases its resources
+	Close() error
+}
+
+// Config holds the configuration of the metrics sinks, each sink is enabled
+// only when its setting is not empty.
+type Config struct {
+	PrometheusListen string `yaml:"report-prometheus-listen" mapstructure:"report-prometheus-listen" json:"report-prometheus-listen"`
+	JSONLinesFile    string `yaml:"report-jsonl-file" mapstructure:"report-jsonl-file" json:"report-jsonl-file"`
+	CSVFile          string `yaml:"report-csv-file" mapstructure:"report-csv-file" json:"report-csv-file"`
+}
+
+// AddToFlagSet adds command line flags needed by the Config to the flag set.
+func (c Config) AddToFlagSet(fs *pflag.FlagSet) {
+	c.AddToFlagSetWithPrefix("", fs)
+}
+
+// AddToFlagSetWithPrefix adds the flags of the Config to the flag set, with
+// their names prefixed by prefix.
+func (c Config) AddToFlagSetWithPrefix(prefix string, fs *pflag.FlagSet) {
+	fs.String(prefix+"report-prometheus-listen", "", "Serve the progress of the benchmark as Prometheus metrics on this address (e.g. ':9091'), at /metrics")
+	fs.String(prefix+"report-jsonl-file", "", "Append the progress of the benchmark to this file as JSON lines")
+	fs.String(prefix+"report-csv-file", "", "Append the progress of the benchmark to this CSV file (time,subsystem,label,metric,value)")
+}
+
+// New returns a Reporter sending reports to all the sinks enabled in c. If no
+// sink is enabled, reports are discarded.
+func New(c Config) (Reporter, error) {
+	var sinks multiReporter
+	if c.PrometheusListen != "" {
+		r, err := NewPrometheus(c.PrometheusListen)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, r)
+	}
+	if c.JSONLinesFile != "" {
+		r, err := NewJSONLinesFile(c.JSONLinesFile)
+		if err != nil {
+			sinks.Close()
+			return nil, err
+		}
+		sinks = append(sinks, r)
+	}
+	if c.CSVFile != "" {
+		r, err := NewCSVFile(c.CSVFile)
+		if err != nil {
+			sinks.Close()
+			return nil, err
+		}
+		sinks = append(sinks, r)
+	}
+	return sinks, nil
+}
+
+// multiReporter sends every report to all of its sinks.
+type multiReporter []Reporter
+
+func (m multiReporter) Report(r *Report) error {
+	var firstErr error
+	for _, sink := range m {
+		if err := sink.Report(r); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m multiReporter) Close() error {
+	var firstErr error
+	for _, sink := range m {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
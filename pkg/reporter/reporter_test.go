@@ -0,0 +1,150 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	r := &Report{Time: time.Unix(1600000000, 0), Subsystem: SubsystemQuery}
+	r.Add("overall_query_rate", "", 12.5)
+	r.Add("latency_p99_ms", "Cnosdb \"high-load\"", 3)
+	return r
+}
+
+func TestJSONLines(t *testing.T) {
+	var b bytes.Buffer
+	r := NewJSONLines(&b)
+	if err := r.Report(testReport()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"time":"2020-09-13T12:26:40Z","subsystem":"query","metrics":[` +
+		`{"name":"overall_query_rate","value":12.5},` +
+		`{"name":"latency_p99_ms","label":"Cnosdb \"high-load\"","value":3}]}` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("wrong JSON line\ngot  %s\nwant %s", got, want)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var b bytes.Buffer
+	r := NewCSV(&b)
+	for i := 0; i < 2; i++ {
+		if err := r.Report(testReport()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("wrong number of lines: got %d want %d:\n%s", len(lines), 5, b.String())
+	}
+	if lines[0] != "time,subsystem,label,metric,value" {
+		t.Errorf("wrong header: %s", lines[0])
+	}
+	if want := `1600000000,query,"Cnosdb ""high-load""",latency_p99_ms,3`; lines[2] != want {
+		t.Errorf("wrong record\ngot  %s\nwant %s", lines[2], want)
+	}
+}
+
+func TestCSVFileAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reporter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "progress.csv")
+
+	// the header is only written to empty files
+	for i := 0; i < 2; i++ {
+		r, err := NewCSVFile(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Report(testReport()); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(b), "time,subsystem"); got != 1 {
+		t.Errorf("header written %d times", got)
+	}
+}
+
+func TestPrometheusExposition(t *testing.T) {
+	r := newPrometheusReporter()
+	if err := r.Report(testReport()); err != nil {
+		t.Fatal(err)
+	}
+	report := &Report{Time: time.Unix(1600000010, 0), Subsystem: SubsystemLoad}
+	report.Add("metric.rate", "", 100)
+	if err := r.Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# TYPE tsdb_comparisons_load_metric_rate gauge
+tsdb_comparisons_load_metric_rate 100
+# TYPE tsdb_comparisons_query_latency_p99_ms gauge
+tsdb_comparisons_query_latency_p99_ms{label="Cnosdb \"high-load\""} 3
+# TYPE tsdb_comparisons_query_overall_query_rate gauge
+tsdb_comparisons_query_overall_query_rate 12.5
+# TYPE tsdb_comparisons_last_report_timestamp_seconds gauge
+tsdb_comparisons_last_report_timestamp_seconds 1600000010
+`
+	if got := string(r.exposition()); got != want {
+		t.Errorf("wrong exposition\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrometheusServe(t *testing.T) {
+	r, err := NewPrometheus("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := r.Report(testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := r.(*prometheusReporter)
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", pr.listener.Addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "tsdb_comparisons_query_overall_query_rate 12.5\n") {
+		t.Errorf("metric not served, got:\n%s", body)
+	}
+}
+
+func TestNewWithoutSinks(t *testing.T) {
+	r, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Report(testReport()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+package results
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Verdicts of a comparison with the baseline
+const (
+	verdictRegression  = "REGRESSION"
+	verdictImprovement = "improvement"
+	// verdictUnsure is appended to a verdict when significance could not be
+	// tested, i.e. when there is a single run on either side
+	verdictUnsure = "?"
+)
+
+// Run is a results file to compare, with the name it is reported under. Runs
+// with the same name and kind are repetitions of the same benchmark.
+type Run struct {
+	Name   string
+	Result *Result
+}
+
+// CompareOptions configures how runs are compared with the baseline.
+type CompareOptions struct {
+	// Baseline is the name of the runs the others are compared with, the name
+	// of the first run of each kind if empty
+	Baseline string
+	// Threshold is the minimum relative change, in percent, that is flagged
+	Threshold float64
+	// Alpha is the significance level of the Welch's t-tests
+	Alpha float64
+}
+
+// Comparison is the result of comparing runs with a baseline.
+type Comparison struct {
+	Tables []*Table
+	// Regressions is the number of flagged regressions
+	Regressions int
+}
+
+// group holds the runs of a kind with the same name
+type group struct {
+	name    string
+	results []*Result
+}
+
+// Compare compares the throughput of the runs, and their latencies per query
+// type, with the baseline runs. A change is flagged as a regression or an
+// improvement when it is larger than the threshold and, if there are enough
+// observations to test it, statistically significant.
+func Compare(runs []Run, opts CompareOptions) (*Comparison, error) {
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no runs to compare")
+	}
+	groups := map[string][]*group{}
+	found := opts.Baseline == ""
+	for _, r := range runs {
+		found = found || r.Name == opts.Baseline
+		kind := r.Result.Kind
+		var g *group
+		for _, kg := range groups[kind] {
+			if kg.name == r.Name {
+				g = kg
+			}
+		}
+		if g == nil {
+			g = &group{name: r.Name}
+			groups[kind] = append(groups[kind], g)
+		}
+		g.results = append(g.results, r.Result)
+	}
+	if !found {
+		return nil, fmt.Errorf("no runs named '%s' to use as baseline", opts.Baseline)
+	}
+
+	c := &Comparison{}
+	if gs := groups[KindLoad]; len(gs) > 0 {
+		c.compareLoad(orderBaselineFirst(gs, opts.Baseline), opts)
+	}
+	if gs := groups[KindQuery]; len(gs) > 0 {
+		gs = orderBaselineFirst(gs, opts.Baseline)
+		c.compareQueryRate(gs, opts)
+		c.compareLatencies(gs, opts)
+	}
+	return c, nil
+}
+
+// orderBaselineFirst moves the baseline group to the front, or keeps the first
+// group as baseline if there is none with the baseline name
+func orderBaselineFirst(groups []*group, baseline string) []*group {
+	for i, g := range groups {
+		if g.name == baseline {
+			ordered := append([]*group{g}, groups[:i]...)
+			return append(ordered, groups[i+1:]...)
+		}
+	}
+	return groups
+}
+
+func (c *Comparison) compareLoad(groups []*group, opts CompareOptions) {
+	t := &Table{
+		Title:  "Load throughput (baseline: " + groups[0].name + ")",
+		Header: []string{"run", "runs", "metrics/s", "rows/s", "failed metrics", "delta metrics/s", "p-value", "verdict"},
+	}
+	samples := make([]sample, len(groups))
+	for i, g := range groups {
+		metricRates := make([]float64, 0, len(g.results))
+		rowRates := make([]float64, 0, len(g.results))
+		failedMetrics := uint64(0)
+		for _, r := range g.results {
+			metricRates = append(metricRates, r.Load.MetricRate)
+			rowRates = append(rowRates, r.Load.RowRate)
+			failedMetrics += r.Load.FailedMetrics
+		}
+		samples[i] = newSample(metricRates)
+		row := []string{g.name, fmt.Sprint(len(g.results)), formatFloat(samples[i].mean), formatFloat(newSample(rowRates).mean),
+			fmt.Sprint(failedMetrics)}
+		t.Rows = append(t.Rows, append(row, c.delta(samples[0], samples[i], i == 0, true, opts)...))
+	}
+	c.Tables = append(c.Tables, t)
+}
+
+func (c *Comparison) compareQueryRate(groups []*group, opts CompareOptions) {
+	t := &Table{
+		Title:  "Query throughput (baseline: " + groups[0].name + ")",
+		Header: []string{"run", "runs", "queries/s", "errors", "delta queries/s", "p-value", "verdict"},
+	}
+	samples := make([]sample, len(groups))
+	for i, g := range groups {
+		rates := make([]float64, 0, len(g.results))
+		errors := uint64(0)
+		for _, r := range g.results {
+			rates = append(rates, r.Query.QueryRate)
+			errors += r.Query.Errors
+		}
+		samples[i] = newSample(rates)
+		row := []string{g.name, fmt.Sprint(len(g.results)), formatFloat(samples[i].mean), fmt.Sprint(errors)}
+		t.Rows = append(t.Rows, append(row, c.delta(samples[0], samples[i], i == 0, true, opts)...))
+	}
+	c.Tables = append(c.Tables, t)
+}
+
+// latencyStats are the latencies of a query type in the runs of a group
+type latencyStats struct {
+	sample
+	median, p95, p99 float64
+}
+
+func (c *Comparison) compareLatencies(groups []*group, opts CompareOptions) {
+	t := &Table{
+		Title:  "Query latency (baseline: " + groups[0].name + ")",
+		Header: []string{"query type", "run", "count", "mean ms", "med ms", "p95 ms", "p99 ms", "delta mean", "delta p99", "p-value", "verdict"},
+	}
+	for _, queryType := range queryTypes(groups) {
+		var base *latencyStats
+		for _, g := range groups {
+			stats := groupLatencies(g, queryType)
+			if stats == nil {
+				continue
+			}
+			isBase := base == nil
+			if isBase {
+				if g != groups[0] {
+					// not run by the baseline, there is nothing to compare with
+					break
+				}
+				base = stats
+			}
+			row := []string{queryType, g.name, fmt.Sprintf("%.0f", stats.n), formatFloat(stats.mean),
+				formatFloat(stats.median), formatFloat(stats.p95), formatFloat(stats.p99)}
+			deltaP99 := "-"
+			if !isBase {
+				deltaP99 = formatDelta(relativeChange(base.p99, stats.p99))
+			}
+			delta := c.delta(base.sample, stats.sample, isBase, false, opts)
+			row = append(row, delta[0], deltaP99)
+			t.Rows = append(t.Rows, append(row, delta[1:]...))
+		}
+	}
+	c.Tables = append(c.Tables, t)
+}
+
+// queryTypes returns the query types run by any group, sorted, followed by
+// all queries together
+func queryTypes(groups []*group) []string {
+	seen := map[string]bool{}
+	var types []string
+	for _, g := range groups {
+		for _, r := range g.results {
+			for _, l := range r.Query.Latencies {
+				queryType := QueryType(l.Label)
+				if !seen[queryType] && queryType != LabelAllQueries {
+					seen[queryType] = true
+					types = append(types, queryType)
+				}
+			}
+		}
+	}
+	sort.Strings(types)
+	return append(types, LabelAllQueries)
+}
+
+// groupLatencies pools the latencies of a query type over the runs of a
+// group. Quantiles are averaged. It returns nil if no run has the query type.
+func groupLatencies(g *group, queryType string) *latencyStats {
+	var samples []sample
+	stats := &latencyStats{}
+	for _, r := range g.results {
+		for _, l := range r.Query.Latencies {
+			if QueryType(l.Label) != queryType {
+				continue
+			}
+			samples = append(samples, sample{n: float64(l.Count), mean: l.Mean, stdDev: l.StdDev})
+			stats.median += l.Median
+			stats.p95 += l.P95
+			stats.p99 += l.P99
+		}
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	n := float64(len(samples))
+	stats.sample = pool(samples)
+	stats.median /= n
+	stats.p95 /= n
+	stats.p99 /= n
+	return stats
+}
+
+// delta returns the relative change of the mean of s from the baseline, the
+// p-value of the change and the verdict. For the baseline itself they are
+// empty.
+func (c *Comparison) delta(base, s sample, isBase, higherIsBetter bool, opts CompareOptions) []string {
+	if isBase {
+		return []string{"-", "-", ""}
+	}
+	change := relativeChange(base.mean, s.mean)
+	p := welchPValue(base, s)
+	return []string{formatDelta(change), formatPValue(p), c.verdict(change, p, higherIsBetter, opts)}
+}
+
+// verdict flags a change larger than the threshold, unless a significance test
+// was possible and did not reject that it is due to chance
+func (c *Comparison) verdict(change, p float64, higherIsBetter bool, opts CompareOptions) string {
+	if math.IsNaN(change) || math.Abs(change) < opts.Threshold {
+		return ""
+	}
+	if !math.IsNaN(p) && p >= opts.Alpha {
+		return ""
+	}
+	v := verdictImprovement
+	if change > 0 != higherIsBetter {
+		v = verdictRegression
+		c.Regressions++
+	}
+	if math.IsNaN(p) {
+		v += verdictUnsure
+	}
+	return v
+}
+
+// relativeChange returns the change from base to v in percent, NaN if base is 0
+func relativeChange(base, v float64) float64 {
+	if base == 0 {
+		return math.NaN()
+	}
+	return (v - base) / base * 100
+}
+
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+func formatDelta(change float64) string {
+	if math.IsNaN(change) {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", change)
+}
+
+func formatPValue(p float64) string {
+	switch {
+	case math.IsNaN(p):
+		return "-"
+	case p < 0.001:
+		return "<0.001"
+	default:
+		return fmt.Sprintf("%.3f", p)
+	}
+}
@@ -0,0 +1,154 @@
+package results
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWelchPValue(t *testing.T) {
+	a := newSample([]float64{1, 2, 3, 4, 5})
+	b := newSample([]float64{3, 4, 5, 6, 7})
+	// t = -2 with 8 degrees of freedom
+	if got := welchPValue(a, b); math.Abs(got-0.08052) > 1e-4 {
+		t.Errorf("wrong p-value: got %f want 0.08052", got)
+	}
+	if got := welchPValue(a, a); math.Abs(got-1) > 1e-9 {
+		t.Errorf("wrong p-value of equal samples: got %f want 1", got)
+	}
+	if got := welchPValue(a, newSample([]float64{1})); !math.IsNaN(got) {
+		t.Errorf("p-value of a single observation should be NaN, got %f", got)
+	}
+	constant := newSample([]float64{2, 2})
+	if got := welchPValue(constant, newSample([]float64{3, 3})); got != 0 {
+		t.Errorf("wrong p-value of different constant samples: got %f want 0", got)
+	}
+}
+
+func TestPool(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7}
+	want := newSample(values)
+	got := pool([]sample{newSample(values[:3]), newSample(values[3:])})
+	if got.n != want.n || math.Abs(got.mean-want.mean) > 1e-9 || math.Abs(got.stdDev-want.stdDev) > 1e-9 {
+		t.Errorf("wrong pooled sample: got %+v want %+v", got, want)
+	}
+}
+
+func loadRun(name string, metricRate float64) Run {
+	return Run{Name: name, Result: &Result{Kind: KindLoad, Load: &LoadTotals{MetricRate: metricRate}}}
+}
+
+func queryRun(name string, rate, mean float64) Run {
+	return Run{Name: name, Result: &Result{Kind: KindQuery, Query: &QueryTotals{
+		QueryRate: rate,
+		Latencies: []Latency{
+			{Label: name + " stationary trucks", Count: 1000, Mean: mean, StdDev: 1, P99: 2 * mean},
+			{Label: LabelAllQueries, Count: 1000, Mean: mean, StdDev: 1, P99: 2 * mean},
+		},
+	}}}
+}
+
+func TestCompare(t *testing.T) {
+	runs := []Run{
+		loadRun("cnosdb", 1000), loadRun("cnosdb", 1010), loadRun("cnosdb", 990),
+		loadRun("influx", 800), loadRun("influx", 810), loadRun("influx", 790),
+		loadRun("timescaledb", 1500),
+		queryRun("cnosdb", 100, 10), queryRun("influx", 100, 20), queryRun("timescaledb", 100, 10.01),
+	}
+	runs[8].Result.Query.Errors = 7
+	c, err := Compare(runs, CompareOptions{Threshold: 5, Alpha: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Tables) != 3 {
+		t.Fatalf("wrong number of tables: got %d want 3", len(c.Tables))
+	}
+
+	load := c.Tables[0]
+	wantLoad := [][]string{
+		{"cnosdb", "3", "1000.00", "0.00", "0", "-", "-", ""},
+		{"influx", "3", "800.00", "0.00", "0", "-20.0%", "<0.001", "REGRESSION"},
+		{"timescaledb", "1", "1500.00", "0.00", "0", "+50.0%", "-", "improvement?"},
+	}
+	checkRows(t, load, wantLoad)
+
+	if got := strings.Join(c.Tables[1].Rows[1][:4], ","); got != "influx,1,100.00,7" {
+		t.Errorf("wrong query throughput row: got %s", got)
+	}
+
+	latency := c.Tables[2]
+	wantLatency := [][]string{
+		{"stationary trucks", "cnosdb", "1000", "10.00", "0.00", "0.00", "20.00", "-", "-", "-", ""},
+		{"stationary trucks", "influx", "1000", "20.00", "0.00", "0.00", "40.00", "+100.0%", "+100.0%", "<0.001", "REGRESSION"},
+		{"stationary trucks", "timescaledb", "1000", "10.01", "0.00", "0.00", "20.02", "+0.1%", "+0.1%", "0.823", ""},
+	}
+	checkRows(t, latency, append(wantLatency, relabel(wantLatency, LabelAllQueries)...))
+
+	// 1 load and 2 latency regressions
+	if c.Regressions != 3 {
+		t.Errorf("wrong number of regressions: got %d want 3", c.Regressions)
+	}
+}
+
+func TestCompareBaseline(t *testing.T) {
+	runs := []Run{loadRun("cnosdb", 1000), loadRun("influx", 800)}
+	c, err := Compare(runs, CompareOptions{Baseline: "influx", Threshold: 5, Alpha: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Tables[0].Rows[0][0]; got != "influx" {
+		t.Errorf("baseline not first: got %s", got)
+	}
+	if got := c.Tables[0].Rows[1][5]; got != "+25.0%" {
+		t.Errorf("wrong delta: got %s want +25.0%%", got)
+	}
+	if _, err := Compare(runs, CompareOptions{Baseline: "missing"}); err == nil {
+		t.Errorf("expected an error for a missing baseline")
+	}
+}
+
+func TestTableOutput(t *testing.T) {
+	table := &Table{
+		Title:  "Load throughput",
+		Header: []string{"run", "metrics/s"},
+		Rows:   [][]string{{"cnosdb", "1000.00"}, {"a|b", "1.00"}},
+	}
+	var b bytes.Buffer
+	if err := table.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "### Load throughput\n\n| run | metrics/s |\n| --- | --- |\n| cnosdb | 1000.00 |\n| a\\|b | 1.00 |\n"
+	if got := b.String(); got != want {
+		t.Errorf("wrong markdown\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	b.Reset()
+	if err := table.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want = "Load throughput\nrun     metrics/s\ncnosdb  1000.00\na|b     1.00\n"
+	if got := b.String(); got != want {
+		t.Errorf("wrong text\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func checkRows(t *testing.T, table *Table, want [][]string) {
+	t.Helper()
+	if len(table.Rows) != len(want) {
+		t.Fatalf("%s: wrong number of rows: got %d want %d\n%v", table.Title, len(table.Rows), len(want), table.Rows)
+	}
+	for i := range want {
+		if got := strings.Join(table.Rows[i], ","); got != strings.Join(want[i], ",") {
+			t.Errorf("%s: wrong row %d\ngot  %s\nwant %s", table.Title, i, got, strings.Join(want[i], ","))
+		}
+	}
+}
+
+func relabel(rows [][]string, label string) [][]string {
+	relabeled := make([][]string, len(rows))
+	for i, row := range rows {
+		relabeled[i] = append([]string{label}, row[1:]...)
+	}
+	return relabeled
+}
@@ -0,0 +1,265 @@
+// Package results defines the results files written by the loaders and the
+// query runners with --results-file, so that runs of different targets can be
+// compared with each other.
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// FormatVersion is the version of the results files written by this package.
+// Files of version 0.1 can still be read.
+const FormatVersion = "1.0"
+
+const legacyFormatVersion = "0.1"
+
+const (
+	// KindLoad is the Kind of the results of a load benchmark
+	KindLoad = "load"
+	// KindQuery is the Kind of the results of a query benchmark
+	KindQuery = "query"
+)
+
+// Labels of the query stat groups that are not query types
+const (
+	LabelAllQueries  = "all queries"
+	LabelColdQueries = "cold queries"
+	LabelWarmQueries = "warm queries"
+)
+
+// Result holds the results of a single load or query benchmark run. Exactly
+// one of Load and Query is set, depending on Kind.
+type Result struct {
+	ResultFormatVersion string `json:"ResultFormatVersion"`
+	Kind                string `json:"Kind"`
+	// Target is the name of the benchmarked database, e.g. 'cnosdb'
+	Target string `json:"Target,omitempty"`
+	DBName string `json:"DBName,omitempty"`
+
+	// RunnerConfig is the configuration of the loader or query runner
+	RunnerConfig interface{} `json:"RunnerConfig,omitempty"`
+
+	// Run info, times are in milliseconds since the Unix epoch
+	StartTime      int64 `json:"StartTime"`
+	EndTime        int64 `json:"EndTime"`
+	DurationMillis int64 `json:"DurationMillis"`
+
+	Load  *LoadTotals  `json:"Load,omitempty"`
+	Query *QueryTotals `json:"Query,omitempty"`
+}
+
+// LoadTotals are the totals of a load benchmark.
+type LoadTotals struct {
+	Metrics    uint64  `json:"Metrics"`
+	Rows       uint64  `json:"Rows"`
+	MetricRate float64 `json:"MetricRate"`
+	RowRate    float64 `json:"RowRate"`
+	// FailedMetrics and FailedRows could not be written, even after retrying.
+	// They are not included in Metrics, Rows and the rates.
+	FailedMetrics uint64 `json:"FailedMetrics"`
+	FailedRows    uint64 `json:"FailedRows"`
+}
+
+// QueryTotals are the totals of a query benchmark.
+type QueryTotals struct {
+	Queries        uint64  `json:"Queries"`
+	QueryRate      float64 `json:"QueryRate"`
+	BurnIn         uint64  `json:"BurnIn"`
+	PrewarmQueries bool    `json:"PrewarmQueries"`
+	// Errors is the number of queries that failed, even after retrying. They
+	// are not included in Queries and the rates.
+	Errors uint64 `json:"Errors"`
+	// Latencies holds the latency stats of every query type, and of all the
+	// queries together, sorted by label
+	Latencies []Latency `json:"Latencies"`
+	// Verification holds the result verification counts by label, if enabled
+	Verification map[string]VerificationTotals `json:"Verification,omitempty"`
+}
+
+// Latency holds the latency stats of the queries with the same label. All the
+// latencies are in milliseconds. Failed queries are only counted in Errors.
+type Latency struct {
+	Label  string  `json:"Label"`
+	Count  int64   `json:"Count"`
+	Rate   float64 `json:"Rate"`
+	Min    float64 `json:"Min"`
+	Median float64 `json:"Median"`
+	Mean   float64 `json:"Mean"`
+	P95    float64 `json:"P95"`
+	P99    float64 `json:"P99"`
+	P999   float64 `json:"P999"`
+	Max    float64 `json:"Max"`
+	StdDev float64 `json:"StdDev"`
+
+	Errors uint64 `json:"Errors"`
+	// ErrorRate is the fraction of the queries with the label that failed
+	ErrorRate float64 `json:"ErrorRate"`
+	// RowsRead holds the rows and bytes read by the queries with the label,
+	// for the databases that report them
+	RowsRead *RowsRead `json:"RowsRead,omitempty"`
+}
+
+// RowsRead holds the rows and bytes read by the measured queries with the
+// same label, as the database reports them.
+type RowsRead struct {
+	Queries   uint64  `json:"Queries"`
+	Min       uint64  `json:"Min"`
+	Mean      float64 `json:"Mean"`
+	Max       uint64  `json:"Max"`
+	Sum       uint64  `json:"Sum"`
+	BytesMean float64 `json:"BytesMean"`
+	BytesSum  uint64  `json:"BytesSum"`
+}
+
+// VerificationTotals are the result verification counts of a query label.
+type VerificationTotals struct {
+	Checked    uint64 `json:"Checked"`
+	Mismatched uint64 `json:"Mismatched"`
+	Missing    uint64 `json:"Missing"`
+}
+
+// QueryType returns the query type of a latency label, i.e. the label without
+// the database name the query generators prefix it with. This makes the labels
+// of different targets comparable.
+func QueryType(label string) string {
+	switch label {
+	case LabelAllQueries, LabelColdQueries, LabelWarmQueries:
+		return label
+	}
+	if i := strings.IndexByte(label, ' '); i >= 0 {
+		return label[i+1:]
+	}
+	return label
+}
+
+// WriteFile writes r as indented JSON to the file fileName.
+func (r *Result) WriteFile(fileName string) error {
+	if r.ResultFormatVersion == "" {
+		r.ResultFormatVersion = FormatVersion
+	}
+	b, err := json.MarshalIndent(r, "", " ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, b, 0644)
+}
+
+// ReadFile reads a results file, upgrading it to the current format if it was
+// written by an older version.
+func ReadFile(fileName string) (*Result, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return r, nil
+}
+
+// Parse decodes the JSON encoding of a Result.
+func Parse(b []byte) (*Result, error) {
+	var version struct {
+		ResultFormatVersion string
+	}
+	if err := json.Unmarshal(b, &version); err != nil {
+		return nil, err
+	}
+	switch version.ResultFormatVersion {
+	case FormatVersion:
+		r := &Result{}
+		if err := json.Unmarshal(b, r); err != nil {
+			return nil, err
+		}
+		if r.Kind != KindLoad && r.Kind != KindQuery {
+			return nil, fmt.Errorf("unknown results kind '%s'", r.Kind)
+		}
+		if r.Kind == KindLoad && r.Load == nil || r.Kind == KindQuery && r.Query == nil {
+			return nil, fmt.Errorf("no totals for results of kind '%s'", r.Kind)
+		}
+		return r, nil
+	case legacyFormatVersion:
+		return parseLegacy(b)
+	default:
+		return nil, fmt.Errorf("unsupported results format version '%s'", version.ResultFormatVersion)
+	}
+}
+
+// legacyResult is the format of the results files of version 0.1
+type legacyResult struct {
+	RunnerConfig   map[string]interface{}
+	StartTime      int64
+	EndTime        int64
+	DurationMillis int64
+	Totals         struct {
+		MetricRate        float64                       `json:"metricRate"`
+		RowRate           float64                       `json:"rowRate"`
+		BurnIn            uint64                        `json:"burnIn"`
+		PrewarmQueries    bool                          `json:"prewarmQueries"`
+		OverallQueryRates map[string]float64            `json:"overallQueryRates"`
+		OverallQuantiles  map[string]map[string]float64 `json:"overallQuantiles"`
+	}
+}
+
+// parseLegacy converts a results file of version 0.1. Those files only hold
+// rates and latency quantiles, so query counts, means and standard deviations
+// are left empty.
+func parseLegacy(b []byte) (*Result, error) {
+	var l legacyResult
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, err
+	}
+	r := &Result{
+		ResultFormatVersion: FormatVersion,
+		RunnerConfig:        l.RunnerConfig,
+		StartTime:           l.StartTime,
+		EndTime:             l.EndTime,
+		DurationMillis:      l.DurationMillis,
+	}
+	// the loaders and the query runners used different keys for the database name
+	for _, key := range []string{"db-name", "DBName"} {
+		if name, ok := l.RunnerConfig[key].(string); ok {
+			r.DBName = name
+		}
+	}
+	if l.Totals.OverallQuantiles == nil {
+		// the loaders wrote the start and end times in seconds
+		r.Kind = KindLoad
+		r.StartTime *= 1000
+		r.EndTime *= 1000
+		r.Load = &LoadTotals{MetricRate: l.Totals.MetricRate, RowRate: l.Totals.RowRate}
+		return r, nil
+	}
+
+	// labels were stored with all non alphanumeric characters replaced by '_'
+	r.Kind = KindQuery
+	r.Query = &QueryTotals{BurnIn: l.Totals.BurnIn, PrewarmQueries: l.Totals.PrewarmQueries}
+	for key, q := range l.Totals.OverallQuantiles {
+		label := strings.Replace(key, "_", " ", -1)
+		r.Query.Latencies = append(r.Query.Latencies, Latency{
+			Label:  label,
+			Rate:   l.Totals.OverallQueryRates[key],
+			Min:    q["q0"],
+			Median: q["q50"],
+			P95:    q["q95"],
+			P99:    q["q99"],
+			P999:   q["q999"],
+			Max:    q["q100"],
+		})
+		if label == LabelAllQueries {
+			r.Query.QueryRate = l.Totals.OverallQueryRates[key]
+		}
+	}
+	SortLatencies(r.Query.Latencies)
+	return r, nil
+}
+
+// SortLatencies sorts latencies by label.
+func SortLatencies(latencies []Latency) {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].Label < latencies[j].Label })
+}
@@ -0,0 +1,135 @@
+package results
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteReadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "results.json")
+
+	want := &Result{
+		Kind:           KindQuery,
+		Target:         "cnosdb",
+		DBName:         "benchmark",
+		StartTime:      1600000000123,
+		EndTime:        1600000010123,
+		DurationMillis: 10000,
+		Query: &QueryTotals{
+			Queries:   100,
+			QueryRate: 10,
+			Latencies: []Latency{
+				{Label: "all queries", Count: 100, Rate: 10, Mean: 2.5, StdDev: 1},
+				{Label: "cnosdb last location per truck", Count: 100, Rate: 10, Mean: 2.5, StdDev: 1},
+			},
+			Verification: map[string]VerificationTotals{"cnosdb last location per truck": {Checked: 100, Mismatched: 1}},
+		},
+	}
+	if err := want.WriteFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ResultFormatVersion != FormatVersion {
+		t.Errorf("wrong version: got %s want %s", got.ResultFormatVersion, FormatVersion)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong result\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		desc string
+		in   string
+	}{
+		{desc: "unknown version", in: `{"ResultFormatVersion": "9.9", "Kind": "load", "Load": {}}`},
+		{desc: "unknown kind", in: `{"ResultFormatVersion": "1.0", "Kind": "other"}`},
+		{desc: "no totals", in: `{"ResultFormatVersion": "1.0", "Kind": "query"}`},
+		{desc: "not json", in: `ResultFormatVersion`},
+	}
+	for _, c := range cases {
+		if _, err := Parse([]byte(c.in)); err == nil {
+			t.Errorf("%s: expected an error", c.desc)
+		}
+	}
+}
+
+func TestParseLegacy(t *testing.T) {
+	load := `{
+ "ResultFormatVersion": "0.1",
+ "RunnerConfig": {"db-name": "benchmark", "workers": 4},
+ "StartTime": 1600000000,
+ "EndTime": 1600000010,
+ "DurationMillis": 10000,
+ "Totals": {"metricRate": 1000.5, "rowRate": 100.5}
+}`
+	r, err := Parse([]byte(load))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Kind != KindLoad || r.DBName != "benchmark" || r.StartTime != 1600000000000 {
+		t.Errorf("wrong run info: %+v", r)
+	}
+	if want := (LoadTotals{MetricRate: 1000.5, RowRate: 100.5}); *r.Load != want {
+		t.Errorf("wrong load totals: got %+v want %+v", *r.Load, want)
+	}
+
+	queries := `{
+ "ResultFormatVersion": "0.1",
+ "RunnerConfig": {"DBName": "benchmark"},
+ "StartTime": 1600000000000,
+ "EndTime": 1600000010000,
+ "DurationMillis": 10000,
+ "Totals": {
+  "burnIn": 10,
+  "overallQueryRates": {"all_queries": 20, "Influx_stationary_trucks": 20},
+  "overallQuantiles": {
+   "all_queries": {"q0": 1, "q50": 2, "q95": 3, "q99": 4, "q999": 5, "q100": 6},
+   "Influx_stationary_trucks": {"q0": 1, "q50": 2, "q95": 3, "q99": 4, "q999": 5, "q100": 6}
+  }
+ }
+}`
+	r, err = Parse([]byte(queries))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Kind != KindQuery || r.DBName != "benchmark" || r.StartTime != 1600000000000 {
+		t.Errorf("wrong run info: %+v", r)
+	}
+	if r.Query.QueryRate != 20 || r.Query.BurnIn != 10 {
+		t.Errorf("wrong query totals: %+v", r.Query)
+	}
+	want := []Latency{
+		{Label: "Influx stationary trucks", Rate: 20, Min: 1, Median: 2, P95: 3, P99: 4, P999: 5, Max: 6},
+		{Label: "all queries", Rate: 20, Min: 1, Median: 2, P95: 3, P99: 4, P999: 5, Max: 6},
+	}
+	if !reflect.DeepEqual(r.Query.Latencies, want) {
+		t.Errorf("wrong latencies\ngot  %+v\nwant %+v", r.Query.Latencies, want)
+	}
+}
+
+func TestQueryType(t *testing.T) {
+	cases := map[string]string{
+		"all queries":                         "all queries",
+		"cold queries":                        "cold queries",
+		"TimescaleDB last location per truck": "last location per truck",
+		"cnosdb last location per truck":      "last location per truck",
+		"single":                              "single",
+	}
+	for label, want := range cases {
+		if got := QueryType(label); got != want {
+			t.Errorf("wrong query type of '%s': got '%s' want '%s'", label, got, want)
+		}
+	}
+}
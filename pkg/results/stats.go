@@ -0,0 +1,142 @@
+package results
+
+import "math"
+
+// sample summarizes a set of observations by their number, mean and standard
+// deviation.
+type sample struct {
+	n      float64
+	mean   float64
+	stdDev float64
+}
+
+// newSample returns the sample of the values.
+func newSample(values []float64) sample {
+	s := sample{n: float64(len(values))}
+	if s.n == 0 {
+		return s
+	}
+	for _, v := range values {
+		s.mean += v
+	}
+	s.mean /= s.n
+	if s.n > 1 {
+		sq := 0.0
+		for _, v := range values {
+			sq += (v - s.mean) * (v - s.mean)
+		}
+		s.stdDev = math.Sqrt(sq / (s.n - 1))
+	}
+	return s
+}
+
+// pool combines samples of the same quantity, e.g. the latencies of the same
+// query type in repeated runs, into a single sample.
+func pool(samples []sample) sample {
+	var p sample
+	for _, s := range samples {
+		p.n += s.n
+		p.mean += s.n * s.mean
+	}
+	if p.n == 0 {
+		return p
+	}
+	p.mean /= p.n
+	if p.n > 1 {
+		sq := 0.0
+		for _, s := range samples {
+			sq += (s.n-1)*s.stdDev*s.stdDev + s.n*(s.mean-p.mean)*(s.mean-p.mean)
+		}
+		p.stdDev = math.Sqrt(sq / (p.n - 1))
+	}
+	return p
+}
+
+// welchPValue returns the two-sided p-value of Welch's t-test for the means of
+// a and b being equal. It returns NaN if either sample has less than two
+// observations, since their variance is then unknown.
+func welchPValue(a, b sample) float64 {
+	if a.n < 2 || b.n < 2 {
+		return math.NaN()
+	}
+	va := a.stdDev * a.stdDev / a.n
+	vb := b.stdDev * b.stdDev / b.n
+	if va+vb == 0 {
+		if a.mean == b.mean {
+			return 1
+		}
+		return 0
+	}
+	t := (a.mean - b.mean) / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/(a.n-1) + vb*vb/(b.n-1))
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// regularizedIncompleteBeta returns I_x(a, b), evaluated with its continued
+// fraction (Numerical Recipes, 6.4).
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	// the continued fraction converges quickly only for x < (a+1)/(a+b+2)
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction of the incomplete
+// beta function with the modified Lentz's method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		// even step
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		// odd step
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
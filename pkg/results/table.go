@@ -0,0 +1,58 @@
+package results
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Table is a titled table of a Comparison.
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// WriteText writes the table as aligned plain text columns.
+func (t *Table) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s\n", t.Title); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteMarkdown writes the table as a Markdown table, preceded by its title as
+// a heading.
+func (t *Table) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", t.Title)
+	writeMarkdownRow(&b, t.Header)
+	separator := make([]string, len(t.Header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(&b, separator)
+	for _, row := range t.Rows {
+		writeMarkdownRow(&b, row)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownCellReplacer = strings.NewReplacer("|", `\|`)
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, c := range cells {
+		b.WriteString(" ")
+		b.WriteString(markdownCellReplacer.Replace(c))
+		b.WriteString(" |")
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,92 @@
+package clickhouse
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+const (
+	tagsKey = "tags"
+)
+
+// allows for testing
+var fatal = log.Fatalf
+
+// dbCreator creates the benchmark database and a MergeTree table for every
+// measurement of the headers of the data source.
+type dbCreator struct {
+	ds   targets.DataSource
+	opts *LoadingOptions
+
+	tables []*table
+}
+
+func (d *dbCreator) Init() {
+	d.tables = tablesOf(d.ds.Headers())
+}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	body, err := d.exec(fmt.Sprintf("SELECT count() FROM system.databases WHERE name = '%s'", dbName))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return strings.TrimSpace(string(body)) != "0"
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	_, err := d.exec("DROP DATABASE IF EXISTS " + dbName)
+	return err
+}
+
+func (d *dbCreator) CreateDB(dbName string) error {
+	_, err := d.exec("CREATE DATABASE " + dbName)
+	return err
+}
+
+// PostCreateDB creates the tables which do not exist yet, also when loading
+// into an existing database.
+func (d *dbCreator) PostCreateDB(dbName string) error {
+	for _, t := range d.tables {
+		if _, err := d.exec(createTableQuery(dbName, t, d.opts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exec runs a query over the HTTP interface and returns the body of its response.
+func (d *dbCreator) exec(query string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, d.opts.URL()+"/", bytes.NewBufferString(query))
+	if err != nil {
+		return nil, err
+	}
+	setCredentials(req.Header, d.opts.User, d.opts.Password)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %s", query, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned code %d: %s", query, resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// setCredentials sets the headers authenticating a request to the HTTP interface.
+func setCredentials(h http.Header, user, password string) {
+	h.Set("X-ClickHouse-User", user)
+	if password != "" {
+		h.Set("X-ClickHouse-Key", password)
+	}
+}
@@ -0,0 +1,99 @@
+package clickhouse
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+type testDataSource struct {
+	headers *common.GeneratedDataHeaders
+}
+
+func (d *testDataSource) NextItem() data.LoadedPoint            { return data.LoadedPoint{} }
+func (d *testDataSource) Headers() *common.GeneratedDataHeaders { return d.headers }
+
+// testOptions returns the loading options connecting to a test server.
+func testOptions(t *testing.T, serverURL string) *LoadingOptions {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &LoadingOptions{Host: host, Port: port, User: "default", InsertFormat: InsertFormatTSV}
+}
+
+func TestDBCreator(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		queries = append(queries, string(b))
+		if r.Header.Get("X-ClickHouse-User") != "default" {
+			t.Errorf("missing user header")
+		}
+		w.Write([]byte("1\n"))
+	}))
+	defer server.Close()
+
+	d := &dbCreator{
+		ds: &testDataSource{headers: &common.GeneratedDataHeaders{
+			TagKeys:   []string{"name"},
+			TagTypes:  []string{"string"},
+			FieldKeys: map[string][]string{"readings": {"latitude"}, "diagnostics": {"status"}},
+		}},
+		opts: testOptions(t, server.URL),
+	}
+	d.Init()
+	if !d.DBExists("benchmark") {
+		t.Errorf("existing database not found")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.PostCreateDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"SELECT count() FROM system.databases WHERE name = 'benchmark'",
+		"DROP DATABASE IF EXISTS benchmark",
+		"CREATE DATABASE benchmark",
+		"CREATE TABLE IF NOT EXISTS benchmark.diagnostics (time DateTime64(9, 'UTC'), name LowCardinality(Nullable(String)), " +
+			"status Nullable(Float64)) ENGINE = MergeTree ORDER BY (name, time) SETTINGS allow_nullable_key = 1",
+		"CREATE TABLE IF NOT EXISTS benchmark.readings (time DateTime64(9, 'UTC'), name LowCardinality(Nullable(String)), " +
+			"latitude Nullable(Float64)) ENGINE = MergeTree ORDER BY (name, time) SETTINGS allow_nullable_key = 1",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("incorrect queries: got %v want %v", queries, want)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("incorrect query %d:\ngot\n%s\nwant\n%s", i, queries[i], want[i])
+		}
+	}
+}
+
+func TestDBCreatorError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Code: 81. DB::Exception: Database benchmark doesn't exist"))
+	}))
+	defer server.Close()
+
+	d := &dbCreator{ds: &testDataSource{headers: &common.GeneratedDataHeaders{}}, opts: testOptions(t, server.URL)}
+	d.Init()
+	if err := d.CreateDB("benchmark"); err == nil {
+		t.Errorf("no error returned for a failed query")
+	}
+}
@@ -0,0 +1,186 @@
+package clickhouse
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// nullValue is how missing tag and field values are serialized
+const nullValue = "NULL"
+
+// tsvTimeFmt is the format of the timestamps in TabSeparated rows, parsed in
+// the UTC time zone of the time column.
+const tsvTimeFmt = "2006-01-02 15:04:05.000000000"
+
+// encoder appends the rows of a table to the body of an INSERT query in one
+// of the insert formats, and returns the number of metrics of the row.
+type encoder func(buf []byte, t *table, row *insertData) ([]byte, uint64, error)
+
+// splitRow returns the timestamp, the tag values and the field values of a
+// row. Tags and fields missing at the end of the row are NULL.
+func splitRow(t *table, row *insertData) (int64, []string, []string, error) {
+	tags := make([]string, len(t.tagKeys))
+	var tagParts []string
+	if len(row.tags) > 0 {
+		tagParts = serialize.SplitCSV(row.tags, -1)
+	}
+	for i := range tags {
+		tags[i] = nullValue
+		if i < len(tagParts) {
+			if kv := strings.SplitN(tagParts[i], "=", 2); len(kv) == 2 {
+				tags[i] = kv[1]
+			}
+		}
+	}
+
+	parts := serialize.SplitCSV(row.fields, -1)
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid timestamp %s of table %s", parts[0], t.name)
+	}
+	if len(parts)-1 > len(t.fieldKeys) {
+		return 0, nil, nil, fmt.Errorf("row of table %s has %d fields, expected %d", t.name, len(parts)-1, len(t.fieldKeys))
+	}
+	fields := make([]string, len(t.fieldKeys))
+	for i := range fields {
+		fields[i] = nullValue
+		if i+1 < len(parts) {
+			fields[i] = parts[i+1]
+		}
+	}
+	return ts, tags, fields, nil
+}
+
+// appendTSV appends a row in the TabSeparated format: the values are written
+// as text, separated by tabs, with NULL as \N.
+func appendTSV(buf []byte, t *table, row *insertData) ([]byte, uint64, error) {
+	ts, tags, fields, err := splitRow(t, row)
+	if err != nil {
+		return buf, 0, err
+	}
+	buf = append(buf, time.Unix(0, ts).UTC().Format(tsvTimeFmt)...)
+	for i, v := range tags {
+		buf = appendTSVValue(append(buf, '\t'), t.tagTypes[i], v)
+	}
+	for i, v := range fields {
+		buf = appendTSVValue(append(buf, '\t'), t.fieldTypes[i], v)
+	}
+	return append(buf, '\n'), uint64(len(fields)), nil
+}
+
+func appendTSVValue(buf []byte, serializedType, v string) []byte {
+	if v == nullValue {
+		return append(buf, `\N`...)
+	}
+	if serializedType != common.FieldTypeString {
+		return append(buf, v...)
+	}
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '\t':
+			buf = append(buf, `\t`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\\':
+			buf = append(buf, `\\`...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+// appendRowBinary appends a row in the RowBinary format: the time as the
+// little-endian int64 of its nanoseconds, then every nullable value as a
+// byte set to 1 for NULL, else to 0 and followed by the binary value.
+func appendRowBinary(buf []byte, t *table, row *insertData) ([]byte, uint64, error) {
+	ts, tags, fields, err := splitRow(t, row)
+	if err != nil {
+		return buf, 0, err
+	}
+	buf = appendUint64(buf, uint64(ts))
+	for i, v := range tags {
+		if buf, err = appendRowBinaryValue(buf, t.tagTypes[i], v); err != nil {
+			return buf, 0, fmt.Errorf("tag %s of table %s: %v", t.tagKeys[i], t.name, err)
+		}
+	}
+	for i, v := range fields {
+		if buf, err = appendRowBinaryValue(buf, t.fieldTypes[i], v); err != nil {
+			return buf, 0, fmt.Errorf("field %s of table %s: %v", t.fieldKeys[i], t.name, err)
+		}
+	}
+	return buf, uint64(len(fields)), nil
+}
+
+func appendRowBinaryValue(buf []byte, serializedType, v string) ([]byte, error) {
+	if v == nullValue {
+		return append(buf, 1), nil
+	}
+	buf = append(buf, 0)
+	switch serializedType {
+	case common.FieldTypeString:
+		var n [binary.MaxVarintLen64]byte
+		buf = append(buf, n[:binary.PutUvarint(n[:], uint64(len(v)))]...)
+		return append(buf, v...), nil
+	case common.FieldTypeFloat32:
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return buf, err
+		}
+		return appendUint32(buf, math.Float32bits(float32(f))), nil
+	case common.FieldTypeFloat64:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return buf, err
+		}
+		return appendUint64(buf, math.Float64bits(f)), nil
+	case common.FieldTypeInt64:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return buf, err
+		}
+		return appendUint64(buf, uint64(n)), nil
+	case "int32":
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return buf, err
+		}
+		return appendUint32(buf, uint32(n)), nil
+	case common.FieldTypeUint64:
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return buf, err
+		}
+		return appendUint64(buf, n), nil
+	case common.FieldTypeBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return buf, err
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	default:
+		return buf, fmt.Errorf("unrecognized type %s", serializedType)
+	}
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
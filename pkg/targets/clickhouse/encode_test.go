@@ -0,0 +1,121 @@
+package clickhouse
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendTSV(t *testing.T) {
+	tables := tableMap(tablesOf(testHeaders()))
+	cases := []struct {
+		desc    string
+		table   string
+		row     *insertData
+		want    string
+		metrics uint64
+	}{
+		{
+			desc:    "regular row",
+			table:   "readings",
+			row:     &insertData{tags: "name=truck_0,load_capacity=1500", fields: "1451606400000000001,52.5,NULL"},
+			want:    "2016-01-01 00:00:00.000000001\ttruck_0\t1500\t52.5\t\\N\n",
+			metrics: 2,
+		},
+		{
+			desc:    "NULL tag and escaped string",
+			table:   "diagnostics",
+			row:     &insertData{tags: "name=NULL,load_capacity=1500", fields: "1451606400000000000,1,true,a\tb\\\\c"},
+			want:    "2016-01-01 00:00:00.000000000\t\\N\t1500\t1\ttrue\ta\\tb\\\\c\n",
+			metrics: 3,
+		},
+		{
+			desc:    "string with a comma and a quote",
+			table:   "diagnostics",
+			row:     &insertData{tags: `name=truck\,0,load_capacity=1500`, fields: `1451606400000000000,1,true,a\,b'c`},
+			want:    "2016-01-01 00:00:00.000000000\ttruck,0\t1500\t1\ttrue\ta,b'c\n",
+			metrics: 3,
+		},
+		{
+			desc:    "missing tags and fields",
+			table:   "diagnostics",
+			row:     &insertData{tags: "name=truck_1", fields: "1451606400000000000,0"},
+			want:    "2016-01-01 00:00:00.000000000\ttruck_1\t\\N\t0\t\\N\t\\N\n",
+			metrics: 3,
+		},
+	}
+	for _, c := range cases {
+		buf, metrics, err := appendTSV(nil, tables[c.table], c.row)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+			continue
+		}
+		if string(buf) != c.want {
+			t.Errorf("%s: incorrect row: got %q want %q", c.desc, buf, c.want)
+		}
+		if metrics != c.metrics {
+			t.Errorf("%s: incorrect metrics: got %d want %d", c.desc, metrics, c.metrics)
+		}
+	}
+}
+
+func TestAppendRowBinary(t *testing.T) {
+	tables := tableMap(tablesOf(testHeaders()))
+	buf, metrics, err := appendRowBinary(nil, tables["diagnostics"], &insertData{
+		tags:   "name=ab,load_capacity=NULL",
+		fields: "1,-2,true,xyz",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{
+		1, 0, 0, 0, 0, 0, 0, 0, // time
+		0, 2, 'a', 'b', // name
+		1,                                                 // load_capacity
+		0, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // status
+		0, 1, // online
+		0, 3, 'x', 'y', 'z', // state
+	}
+	if !bytes.Equal(buf, want) {
+		t.Errorf("incorrect row:\ngot  %v\nwant %v", buf, want)
+	}
+	if metrics != 3 {
+		t.Errorf("incorrect metrics: got %d want 3", metrics)
+	}
+
+	buf, _, err = appendRowBinary(nil, tables["readings"], &insertData{fields: "0,1.5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []byte{
+		0, 0, 0, 0, 0, 0, 0, 0, // time
+		1,                               // name
+		1,                               // load_capacity
+		0, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f, // latitude
+		1, // velocity
+	}
+	if !bytes.Equal(buf, want) {
+		t.Errorf("incorrect row:\ngot  %v\nwant %v", buf, want)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tables := tableMap(tablesOf(testHeaders()))
+	cases := []struct {
+		desc  string
+		table string
+		row   *insertData
+	}{
+		{"invalid timestamp", "readings", &insertData{fields: "now,1"}},
+		{"too many fields", "readings", &insertData{fields: "0,1,2,3"}},
+		{"invalid integer", "diagnostics", &insertData{fields: "0,1.5"}},
+		{"invalid boolean", "diagnostics", &insertData{fields: "0,1,maybe"}},
+	}
+	for _, c := range cases {
+		if _, _, err := appendRowBinary(nil, tables[c.table], c.row); err == nil {
+			t.Errorf("%s: no error returned", c.desc)
+		}
+	}
+	if _, _, err := appendTSV(nil, tables["readings"], &insertData{fields: "now,1"}); err == nil {
+		t.Errorf("no error returned for an invalid timestamp")
+	}
+}
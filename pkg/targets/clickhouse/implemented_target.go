@@ -0,0 +1,70 @@
+package clickhouse
+
+import (
+	"sync"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/spf13/pflag"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &clickHouseTarget{}
+}
+
+type clickHouseTarget struct {
+}
+
+func (t *clickHouseTarget) TargetName() string {
+	return constants.FormatClickHouse
+}
+
+func (t *clickHouseTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *clickHouseTarget) Benchmark(
+	targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	return NewBenchmark(targetDB, &loadingOptions, dataSourceConfig)
+}
+
+// QueryPool returns the pool of the SQL queries, run over the HTTP interface.
+func (t *clickHouseTarget) QueryPool() *sync.Pool {
+	return &query.TimescaleDBPool
+}
+
+func (t *clickHouseTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	opts := &QueryOptions{
+		URL:            loadingOptions.URL(),
+		DBName:         runner.DatabaseName(),
+		User:           loadingOptions.User,
+		Password:       loadingOptions.Password,
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
+}
+
+func (t *clickHouseTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"host", "localhost", "Hostname of the ClickHouse instance")
+	flagSet.String(flagPrefix+"port", "8123", "Port of the HTTP interface")
+	flagSet.String(flagPrefix+"user", "default", "User to connect to ClickHouse as")
+	flagSet.String(flagPrefix+"password", "", "Password for the user connecting to ClickHouse")
+	flagSet.String(flagPrefix+"order-by", "", "ORDER BY expression of the tables (default: the first tag and time)")
+	flagSet.String(flagPrefix+"partition-by", "toYYYYMM(time)", "PARTITION BY expression of the tables, none if empty")
+	flagSet.String(flagPrefix+"insert-format", InsertFormatTSV, "Input format of the batches inserted: tsv (TabSeparated) or rowbinary (RowBinary)")
+	flagSet.Bool(flagPrefix+"log-batches", false, "Whether to time individual batches.")
+}
@@ -0,0 +1,180 @@
+package clickhouse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/valyala/fasthttp"
+)
+
+const httpClientName = "load_clickhouse"
+
+// Input formats of the INSERT queries, by insert format
+var insertFormats = map[string]string{
+	InsertFormatTSV:       "TabSeparated",
+	InsertFormatRowBinary: "RowBinary",
+}
+
+// allows for testing
+var printFn = fmt.Printf
+
+var methodPost = []byte("POST")
+
+// httpWriter posts INSERT queries to the HTTP interface, the query in the
+// URL and the rows in the body.
+type httpWriter struct {
+	client   fasthttp.Client
+	url      string
+	user     string
+	password string
+}
+
+func newHTTPWriter(opts *LoadingOptions) *httpWriter {
+	return &httpWriter{
+		client:   fasthttp.Client{Name: httpClientName},
+		url:      opts.URL() + "/?query=",
+		user:     opts.User,
+		password: opts.Password,
+	}
+}
+
+func (w *httpWriter) Write(query string, body []byte) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetMethodBytes(methodPost)
+	req.Header.SetRequestURI(w.url + url.QueryEscape(query))
+	req.Header.Set("X-ClickHouse-User", w.user)
+	if w.password != "" {
+		req.Header.Set("X-ClickHouse-Key", w.password)
+	}
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := w.client.Do(req, resp); err != nil {
+		return load.Retryable(err)
+	}
+	if sc := resp.StatusCode(); sc != fasthttp.StatusOK {
+		err := fmt.Errorf("invalid write response (status %d): %s", sc, resp.Body())
+		if load.IsRetryableHTTPStatus(sc) {
+			return load.Retryable(err)
+		}
+		return err
+	}
+	return nil
+}
+
+type processor struct {
+	opts   *LoadingOptions
+	dbName string
+	ds     targets.DataSource
+
+	tables      map[string]*table
+	encode      encoder
+	format      string
+	writer      *httpWriter
+	retryPolicy *load.RetryPolicy
+	buf         []byte
+}
+
+func newProcessor(opts *LoadingOptions, dbName string, ds targets.DataSource) *processor {
+	return &processor{
+		opts:   opts,
+		dbName: dbName,
+		ds:     ds,
+	}
+}
+
+// Init sets up the processor. The headers of the data source have already
+// been read by the DBCreator.
+func (p *processor) Init(_ int, _, _ bool) {
+	p.tables = tableMap(tablesOf(p.ds.Headers()))
+	switch p.opts.InsertFormat {
+	case InsertFormatTSV:
+		p.encode = appendTSV
+	case InsertFormatRowBinary:
+		p.encode = appendRowBinary
+	default:
+		fatal("invalid insert format '%s', choices: %v", p.opts.InsertFormat, InsertFormatChoices)
+		return
+	}
+	p.format = insertFormats[p.opts.InsertFormat]
+	p.writer = newHTTPWriter(p.opts)
+}
+
+func (p *processor) Close(_ bool) {}
+
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures inserts the rows of every table of the batch with
+// an INSERT query. The rows of a table that could not be inserted, even after
+// retrying, are counted as failed.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
+	batches := b.(*tableArr)
+	rowCnt := uint64(0)
+	metricCnt := uint64(0)
+	failedRowCnt := uint64(0)
+	failedMetricCnt := uint64(0)
+	for tableName, rows := range batches.m {
+		if !doLoad {
+			rowCnt += uint64(len(rows))
+			continue
+		}
+		start := time.Now()
+		numMetrics, err := p.insert(tableName, rows)
+		if err != nil {
+			printFn("Error writing %d rows to %s: %v\n", len(rows), tableName, err)
+			failedRowCnt += uint64(len(rows))
+			failedMetricCnt += numMetrics
+			continue
+		}
+		rowCnt += uint64(len(rows))
+		metricCnt += numMetrics
+
+		if p.opts.LogBatches {
+			took := time.Since(start)
+			batchSize := len(rows)
+			fmt.Printf("BATCH: batchsize %d row rate %f/sec (took %v)\n", batchSize, float64(batchSize)/took.Seconds(), took)
+		}
+	}
+	batches.m = map[string][]*insertData{}
+	batches.cnt = 0
+	return metricCnt, rowCnt, failedMetricCnt, failedRowCnt
+}
+
+// insert encodes the rows in the insert format and posts them to the table.
+func (p *processor) insert(tableName string, rows []*insertData) (uint64, error) {
+	t, ok := p.tables[tableName]
+	if !ok {
+		return 0, fmt.Errorf("table %s is not in the headers", tableName)
+	}
+	numMetrics := uint64(0)
+	p.buf = p.buf[:0]
+	for _, row := range rows {
+		var n uint64
+		var err error
+		p.buf, n, err = p.encode(p.buf, t, row)
+		if err != nil {
+			return numMetrics, err
+		}
+		numMetrics += n
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s.%s (%s) FORMAT %s", p.dbName, t.name, strings.Join(t.columns(), ", "), p.format)
+	err := p.retryPolicy.Do(func() error {
+		return p.writer.Write(query, p.buf)
+	})
+	return numMetrics, err
+}
@@ -0,0 +1,126 @@
+package clickhouse
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+func testBatch() *tableArr {
+	b := (&factory{}).New().(*tableArr)
+	b.Append(data.NewLoadedPoint(&point{
+		table: "readings",
+		row:   &insertData{tags: "name=truck_0,load_capacity=1500", fields: "1451606400000000000,1.5,2"},
+	}))
+	return b
+}
+
+func TestProcessorInsertFormats(t *testing.T) {
+	cases := []struct {
+		format    string
+		wantQuery string
+		wantBody  string
+	}{
+		{
+			format:    InsertFormatTSV,
+			wantQuery: "INSERT INTO benchmark.readings (time, name, load_capacity, latitude, velocity) FORMAT TabSeparated",
+			wantBody:  "2016-01-01 00:00:00.000000000\ttruck_0\t1500\t1.5\t2\n",
+		},
+		{
+			format:    InsertFormatRowBinary,
+			wantQuery: "INSERT INTO benchmark.readings (time, name, load_capacity, latitude, velocity) FORMAT RowBinary",
+			wantBody: "\x00\x00\xaf\x71\x54\x24\x25\x14" + "\x00\x07truck_0" + "\x00\x00\x80\xbb\x44" +
+				"\x00\x00\x00\x00\x00\x00\x00\xf8\x3f" + "\x00\x00\x00\x00\x00\x00\x00\x00\x40",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			var query, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query().Get("query")
+				b, _ := ioutil.ReadAll(r.Body)
+				body = string(b)
+			}))
+			defer server.Close()
+
+			opts := testOptions(t, server.URL)
+			opts.InsertFormat = c.format
+			p := newProcessor(opts, "benchmark", &testDataSource{headers: testHeaders()})
+			p.Init(0, true, false)
+			metrics, rows := p.ProcessBatch(testBatch(), true)
+			if metrics != 2 || rows != 1 {
+				t.Errorf("incorrect counts: got %d metrics %d rows want 2 and 1", metrics, rows)
+			}
+			if query != c.wantQuery {
+				t.Errorf("incorrect query: got %s want %s", query, c.wantQuery)
+			}
+			if body != c.wantBody {
+				t.Errorf("incorrect body:\ngot  %q\nwant %q", body, c.wantBody)
+			}
+		})
+	}
+}
+
+func TestProcessorRetriesFailedWrites(t *testing.T) {
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printed := 0
+	printFn = func(string, ...interface{}) (int, error) {
+		printed++
+		return 0, nil
+	}
+
+	cases := []struct {
+		desc     string
+		status   int
+		attempts int
+	}{
+		{desc: "server error is retried", status: http.StatusServiceUnavailable, attempts: 3},
+		{desc: "bad request is not retried", status: http.StatusBadRequest, attempts: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			printed = 0
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(c.status)
+			}))
+			defer server.Close()
+
+			p := newProcessor(testOptions(t, server.URL), "benchmark", &testDataSource{headers: testHeaders()})
+			p.Init(0, true, false)
+			p.SetRetryPolicy(load.NewRetryPolicy(load.RetryConfig{MaxAttempts: 3}))
+			metrics, rows, failedMetrics, failedRows := p.ProcessBatchWithFailures(testBatch(), true)
+			if attempts != c.attempts {
+				t.Errorf("incorrect number of attempts: got %d want %d", attempts, c.attempts)
+			}
+			if metrics != 0 || rows != 0 || failedMetrics != 2 || failedRows != 1 {
+				t.Errorf("incorrect counts: got %d %d %d %d want 0 0 2 1", metrics, rows, failedMetrics, failedRows)
+			}
+			if printed != 1 {
+				t.Errorf("printFn called incorrect # of times: got %d want 1", printed)
+			}
+		})
+	}
+}
+
+func TestProcessorInvalidInsertFormat(t *testing.T) {
+	fatalCalled := false
+	oldFatal := fatal
+	defer func() { fatal = oldFatal }()
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+
+	opts := &LoadingOptions{Host: "localhost", Port: "8123", InsertFormat: "csv"}
+	p := newProcessor(opts, "benchmark", &testDataSource{headers: testHeaders()})
+	p.Init(0, true, false)
+	if !fatalCalled {
+		t.Errorf("fatal was not called for an invalid insert format")
+	}
+}
@@ -0,0 +1,34 @@
+package clickhouse
+
+import "fmt"
+
+// Insert formats the batches can be written in
+const (
+	InsertFormatTSV       = "tsv"
+	InsertFormatRowBinary = "rowbinary"
+)
+
+// InsertFormatChoices are the input formats of the INSERT queries.
+var InsertFormatChoices = []string{InsertFormatTSV, InsertFormatRowBinary}
+
+// Loading option vars:
+type LoadingOptions struct {
+	Host     string `yaml:"host"`
+	Port     string
+	User     string
+	Password string
+
+	// OrderBy and PartitionBy are the expressions of the ORDER BY and
+	// PARTITION BY clauses of the MergeTree tables. An empty OrderBy sorts
+	// the rows by the first tag and time.
+	OrderBy     string `yaml:"order-by" mapstructure:"order-by"`
+	PartitionBy string `yaml:"partition-by" mapstructure:"partition-by"`
+
+	InsertFormat string `yaml:"insert-format" mapstructure:"insert-format"`
+	LogBatches   bool   `yaml:"log-batches" mapstructure:"log-batches"`
+}
+
+// URL returns the URL of the HTTP interface.
+func (o *LoadingOptions) URL() string {
+	return fmt.Sprintf("http://%s:%s", o.Host, o.Port)
+}
@@ -0,0 +1,212 @@
+package clickhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// summaryHeader is the header of the responses reporting the rows and bytes
+// read by the query.
+const summaryHeader = "X-ClickHouse-Summary"
+
+// QueryOptions holds the settings of the query processors.
+type QueryOptions struct {
+	URL            string
+	DBName         string
+	User           string
+	Password       string
+	Debug          int
+	PrintResponses bool
+}
+
+var httpClientOnce = sync.Once{}
+var httpClient *http.Client
+
+func getHttpClient() *http.Client {
+	httpClientOnce.Do(func() {
+		tr := &http.Transport{
+			MaxIdleConnsPerHost: 1024,
+		}
+		httpClient = &http.Client{Transport: tr}
+	})
+	return httpClient
+}
+
+type queryProcessor struct {
+	opts   *QueryOptions
+	url    string
+	client *http.Client
+}
+
+// NewQueryProcessor returns a query.Processor that runs the SQL of
+// query.TimescaleDB queries over the HTTP interface, recording the rows read
+// by every query.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(_ int) {
+	v := url.Values{}
+	v.Set("database", p.opts.DBName)
+	v.Set("default_format", "JSONCompact")
+	v.Set("output_format_json_quote_64bit_integers", "0")
+	// buffer the whole response, so that the summary header is only sent
+	// once the query is done
+	v.Set("wait_end_of_query", "1")
+	p.url = p.opts.URL + "/?" + v.Encode()
+	p.client = getHttpClient()
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	stats, _, err := p.execute(q, false)
+	return stats, err
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, isWarm bool) ([]*query.Stat, *query.ResultSet, error) {
+	return p.execute(q, true)
+}
+
+// execute runs the query and, if collect is set, returns its rows.
+func (p *queryProcessor) execute(q query.Query, collect bool) ([]*query.Stat, *query.ResultSet, error) {
+	tq := q.(*query.TimescaleDB)
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(tq.SqlQuery))
+	if err != nil {
+		return nil, nil, err
+	}
+	setCredentials(req.Header, p.opts.User, p.opts.Password)
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	lag := float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("query returned code %d: %s", resp.StatusCode, body)
+	}
+
+	readRows, readBytes, err := parseSummary(resp.Header.Get(summaryHeader))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch p.opts.Debug {
+	case 0:
+	case 1:
+		fmt.Printf("debug: %s in %7.2fms, %d rows read\n", tq.HumanLabel, lag, readRows)
+	case 2:
+		fmt.Printf("debug: %s in %7.2fms, %d rows read -- %s\n", tq.HumanLabel, lag, readRows, tq.HumanDescription)
+	default:
+		fmt.Printf("debug: %s in %7.2fms, %d rows read -- %s\n", tq.HumanLabel, lag, readRows, tq.HumanDescription)
+		fmt.Printf("debug:   request: %s\n", tq.SqlQuery)
+		fmt.Printf("debug:   response: %s\n", body)
+	}
+
+	var rs *query.ResultSet
+	if collect || p.opts.PrintResponses {
+		rs, err = parseResponse(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if p.opts.PrintResponses {
+		prettyPrintResponse(rs, tq)
+	}
+
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag).SetRowsRead(readRows, readBytes)
+	return []*query.Stat{stat}, rs, nil
+}
+
+// parseSummary returns the rows and bytes read by a query from the JSON
+// object of the summary header, whose values are strings.
+func parseSummary(summary string) (uint64, uint64, error) {
+	if summary == "" {
+		return 0, 0, fmt.Errorf("response has no %s header", summaryHeader)
+	}
+	var s struct {
+		ReadRows  string `json:"read_rows"`
+		ReadBytes string `json:"read_bytes"`
+	}
+	if err := json.Unmarshal([]byte(summary), &s); err != nil {
+		return 0, 0, fmt.Errorf("cannot decode %s header: %v", summaryHeader, err)
+	}
+	rows, err := strconv.ParseUint(s.ReadRows, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid read_rows in %s header: %v", summaryHeader, err)
+	}
+	bytes, err := strconv.ParseUint(s.ReadBytes, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid read_bytes in %s header: %v", summaryHeader, err)
+	}
+	return rows, bytes, nil
+}
+
+// jsonCompactResponse is the body of a response in the JSONCompact format.
+type jsonCompactResponse struct {
+	Meta []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"meta"`
+	Data [][]interface{} `json:"data"`
+}
+
+// parseResponse converts the body of a JSONCompact response into a
+// query.ResultSet.
+func parseResponse(body []byte) (*query.ResultSet, error) {
+	var resp jsonCompactResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	columns := make([]string, 0, len(resp.Meta))
+	for _, m := range resp.Meta {
+		columns = append(columns, m.Name)
+	}
+	rs := query.NewResultSet(columns...)
+	for _, row := range resp.Data {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row has %d values, expected %d", len(row), len(columns))
+		}
+		rs.AppendRow(row...)
+	}
+	return rs, nil
+}
+
+// prettyPrintResponse prints a Query and its response in JSON format with two
+// keys: 'query' which has a value of the SQL used to generate the second key
+// 'results' which is an array of each row in the return set.
+func prettyPrintResponse(rs *query.ResultSet, q *query.TimescaleDB) {
+	results := make([]map[string]interface{}, 0, len(rs.Rows))
+	for _, values := range rs.Rows {
+		row := make(map[string]interface{})
+		for i, column := range rs.Columns {
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	resp := make(map[string]interface{})
+	resp["query"] = string(q.SqlQuery)
+	resp["results"] = results
+
+	line, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(line) + "\n")
+}
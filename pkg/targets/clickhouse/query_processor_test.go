@@ -0,0 +1,124 @@
+package clickhouse
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// recordedResponse is a JSONCompact response of ClickHouse to a last-loc
+// query, and recordedSummary its summary header.
+const (
+	recordedResponse = `{"meta":[{"name":"name","type":"LowCardinality(Nullable(String))"},` +
+		`{"name":"driver","type":"Nullable(String)"},{"name":"longitude","type":"Nullable(Float64)"}],` +
+		`"data":[["truck_0","Seth",41.67129],["truck_1",null,12.5]],"rows":2,` +
+		`"statistics":{"elapsed":0.001,"rows_read":8640,"bytes_read":120960}}`
+	recordedSummary = `{"read_rows":"8640","read_bytes":"120960","written_rows":"0","written_bytes":"0","total_rows_to_read":"8640"}`
+)
+
+func testQuery() *query.TimescaleDB {
+	q := query.NewTimescaleDB()
+	q.HumanLabel = []byte("ClickHouse last location per truck")
+	q.Hypertable = []byte("readings")
+	q.SqlQuery = []byte("SELECT name, driver, longitude FROM readings")
+	return q
+}
+
+func TestQueryProcessor(t *testing.T) {
+	var database, sql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		database = r.URL.Query().Get("database")
+		b, _ := ioutil.ReadAll(r.Body)
+		sql = string(b)
+		w.Header().Set(summaryHeader, recordedSummary)
+		w.Write([]byte(recordedResponse))
+	}))
+	defer server.Close()
+
+	p := NewQueryProcessor(&QueryOptions{URL: server.URL, DBName: "benchmark", User: "default"})
+	p.Init(0)
+	stats, rs, err := p.(query.ResultProcessor).ProcessQueryWithResult(testQuery(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if database != "benchmark" || sql != "SELECT name, driver, longitude FROM readings" {
+		t.Errorf("incorrect request: database %s, query %s", database, sql)
+	}
+	if len(stats) != 1 {
+		t.Errorf("incorrect stats: %v", stats)
+	}
+
+	wantColumns := []string{"name", "driver", "longitude"}
+	if len(rs.Columns) != len(wantColumns) {
+		t.Fatalf("incorrect columns: got %v want %v", rs.Columns, wantColumns)
+	}
+	for i := range wantColumns {
+		if rs.Columns[i] != wantColumns[i] {
+			t.Errorf("incorrect column %d: got %s want %s", i, rs.Columns[i], wantColumns[i])
+		}
+	}
+	if len(rs.Rows) != 2 {
+		t.Fatalf("incorrect number of rows: got %d want 2", len(rs.Rows))
+	}
+	if row := rs.Rows[1]; row[0] != "truck_1" || row[1] != nil || row[2] != 12.5 {
+		t.Errorf("incorrect row: %v", row)
+	}
+
+	if rows, bytes, ok := stats[0].RowsRead(); !ok || rows != 8640 || bytes != 120960 {
+		t.Errorf("incorrect rows read: %d rows, %d bytes", rows, bytes)
+	}
+}
+
+func TestQueryProcessorErrors(t *testing.T) {
+	cases := []struct {
+		desc    string
+		status  int
+		summary string
+		body    string
+	}{
+		{
+			desc:    "syntax error",
+			status:  http.StatusBadRequest,
+			summary: recordedSummary,
+			body:    "Code: 62. DB::Exception: Syntax error",
+		},
+		{
+			desc:   "missing summary",
+			status: http.StatusOK,
+			body:   recordedResponse,
+		},
+		{
+			desc:    "invalid summary",
+			status:  http.StatusOK,
+			summary: `{"read_rows":"many"}`,
+			body:    recordedResponse,
+		},
+		{
+			desc:    "invalid response",
+			status:  http.StatusOK,
+			summary: recordedSummary,
+			body:    `{"meta":[{"name":"name"}],"data":[["truck_0","Seth"]]}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if c.summary != "" {
+					w.Header().Set(summaryHeader, c.summary)
+				}
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body))
+			}))
+			defer server.Close()
+
+			p := NewQueryProcessor(&QueryOptions{URL: server.URL, DBName: "benchmark"})
+			p.Init(0)
+			if _, _, err := p.(query.ResultProcessor).ProcessQueryWithResult(testQuery(), false); err == nil {
+				t.Errorf("no error returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package clickhouse
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// hostnameIndexer is used to consistently send the same hostnames to the same worker
+type hostnameIndexer struct {
+	partitions uint
+}
+
+func (i *hostnameIndexer) GetIndex(item data.LoadedPoint) uint {
+	p := item.Data.(*point)
+	hostname := strings.SplitN(p.row.tags, ",", 2)[0]
+	h := fnv.New32a()
+	h.Write([]byte(hostname))
+	return uint(h.Sum32()) % i.partitions
+}
+
+// insertData holds the tags and the fields of a row as serialized, the
+// fields being prefixed with the timestamp
+type insertData struct {
+	tags   string
+	fields string
+}
+
+// point is a single row of data keyed by which table it belongs
+type point struct {
+	table string
+	row   *insertData
+}
+
+type tableArr struct {
+	m   map[string][]*insertData
+	cnt uint
+}
+
+func (ta *tableArr) Len() uint {
+	return ta.cnt
+}
+
+func (ta *tableArr) Append(item data.LoadedPoint) {
+	that := item.Data.(*point)
+	k := that.table
+	ta.m[k] = append(ta.m[k], that.row)
+	ta.cnt++
+}
+
+type factory struct{}
+
+func (f *factory) New() targets.Batch {
+	return &tableArr{
+		m:   map[string][]*insertData{},
+		cnt: 0,
+	}
+}
@@ -0,0 +1,116 @@
+package clickhouse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+// timeColumn is the first column of every table, the timestamps of its rows
+const timeColumn = "time"
+
+// table is the MergeTree table of a measurement. Its columns are the time,
+// the tags and the fields of the measurement, in that order: the tags are
+// stored in every row instead of in a table of their own, ClickHouse being
+// better at scanning repeated values than at joining.
+type table struct {
+	name       string
+	tagKeys    []string
+	tagTypes   []string
+	fieldKeys  []string
+	fieldTypes []string
+}
+
+// tablesOf returns the tables of the measurements of the headers, sorted by name.
+func tablesOf(headers *common.GeneratedDataHeaders) []*table {
+	names := make([]string, 0, len(headers.FieldKeys))
+	for name := range headers.FieldKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tables := make([]*table, 0, len(names))
+	for _, name := range names {
+		tables = append(tables, &table{
+			name:       name,
+			tagKeys:    headers.TagKeys,
+			tagTypes:   headers.TagTypes,
+			fieldKeys:  headers.FieldKeys[name],
+			fieldTypes: headers.FieldTypesOf(name),
+		})
+	}
+	return tables
+}
+
+// tableMap returns the tables by name.
+func tableMap(tables []*table) map[string]*table {
+	m := make(map[string]*table, len(tables))
+	for _, t := range tables {
+		m[t.name] = t
+	}
+	return m
+}
+
+// columns returns the names of the columns of the table.
+func (t *table) columns() []string {
+	cols := make([]string, 0, 1+len(t.tagKeys)+len(t.fieldKeys))
+	cols = append(cols, timeColumn)
+	cols = append(cols, t.tagKeys...)
+	return append(cols, t.fieldKeys...)
+}
+
+// createTableQuery returns the statement creating the table in database
+// dbName. Tags and fields are nullable, as generated values may be missing,
+// so the sorting key may contain nullable columns.
+func createTableQuery(dbName string, t *table, opts *LoadingOptions) string {
+	defs := make([]string, 0, 1+len(t.tagKeys)+len(t.fieldKeys))
+	defs = append(defs, timeColumn+" DateTime64(9, 'UTC')")
+	for i, tag := range t.tagKeys {
+		colType := fmt.Sprintf("Nullable(%s)", serializedTypeToClickHouseType(t.tagTypes[i]))
+		if t.tagTypes[i] == common.FieldTypeString {
+			// few distinct values, stored as a dictionary
+			colType = fmt.Sprintf("LowCardinality(%s)", colType)
+		}
+		defs = append(defs, tag+" "+colType)
+	}
+	for i, field := range t.fieldKeys {
+		defs = append(defs, fmt.Sprintf("%s Nullable(%s)", field, serializedTypeToClickHouseType(t.fieldTypes[i])))
+	}
+
+	orderBy := opts.OrderBy
+	if orderBy == "" {
+		orderBy = timeColumn
+		if len(t.tagKeys) > 0 {
+			orderBy = t.tagKeys[0] + ", " + timeColumn
+		}
+	}
+
+	q := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (%s) ENGINE = MergeTree", dbName, t.name, strings.Join(defs, ", "))
+	if opts.PartitionBy != "" {
+		q += " PARTITION BY " + opts.PartitionBy
+	}
+	return q + fmt.Sprintf(" ORDER BY (%s) SETTINGS allow_nullable_key = 1", orderBy)
+}
+
+func serializedTypeToClickHouseType(serializedType string) string {
+	switch serializedType {
+	case common.FieldTypeString:
+		return "String"
+	case common.FieldTypeFloat32:
+		return "Float32"
+	case common.FieldTypeFloat64:
+		return "Float64"
+	case common.FieldTypeInt64:
+		return "Int64"
+	case "int32":
+		return "Int32"
+	case common.FieldTypeUint64:
+		return "UInt64"
+	case common.FieldTypeBool:
+		return "Bool"
+	default:
+		panic(fmt.Sprintf("unrecognized type %s", serializedType))
+	}
+}
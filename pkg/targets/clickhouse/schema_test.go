@@ -0,0 +1,91 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+func testHeaders() *common.GeneratedDataHeaders {
+	return &common.GeneratedDataHeaders{
+		TagKeys:  []string{"name", "load_capacity"},
+		TagTypes: []string{"string", "float32"},
+		FieldKeys: map[string][]string{
+			"readings":    {"latitude", "velocity"},
+			"diagnostics": {"status", "online", "state"},
+		},
+		FieldTypes: map[string][]string{
+			"diagnostics": {"int64", "bool", "string"},
+		},
+	}
+}
+
+func TestTablesOf(t *testing.T) {
+	tables := tablesOf(testHeaders())
+	if len(tables) != 2 || tables[0].name != "diagnostics" || tables[1].name != "readings" {
+		t.Fatalf("incorrect tables: %v", tables)
+	}
+	want := []string{"time", "name", "load_capacity", "status", "online", "state"}
+	got := tables[0].columns()
+	if len(got) != len(want) {
+		t.Fatalf("incorrect columns: got %v want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("incorrect column %d: got %s want %s", i, got[i], want[i])
+		}
+	}
+	if got := tables[1].fieldTypes; got[0] != "float64" || got[1] != "float64" {
+		t.Errorf("incorrect default field types: %v", got)
+	}
+}
+
+func TestCreateTableQuery(t *testing.T) {
+	tables := tablesOf(testHeaders())
+	cases := []struct {
+		desc  string
+		table *table
+		opts  *LoadingOptions
+		want  string
+	}{
+		{
+			desc:  "default sorting key",
+			table: tables[1],
+			opts:  &LoadingOptions{PartitionBy: "toYYYYMM(time)"},
+			want: "CREATE TABLE IF NOT EXISTS benchmark.readings (time DateTime64(9, 'UTC'), " +
+				"name LowCardinality(Nullable(String)), load_capacity Nullable(Float32), " +
+				"latitude Nullable(Float64), velocity Nullable(Float64)) ENGINE = MergeTree " +
+				"PARTITION BY toYYYYMM(time) ORDER BY (name, time) SETTINGS allow_nullable_key = 1",
+		},
+		{
+			desc:  "typed fields without partitions",
+			table: tables[0],
+			opts:  &LoadingOptions{OrderBy: "time, name"},
+			want: "CREATE TABLE IF NOT EXISTS benchmark.diagnostics (time DateTime64(9, 'UTC'), " +
+				"name LowCardinality(Nullable(String)), load_capacity Nullable(Float32), " +
+				"status Nullable(Int64), online Nullable(Bool), state Nullable(String)) ENGINE = MergeTree " +
+				"ORDER BY (time, name) SETTINGS allow_nullable_key = 1",
+		},
+		{
+			desc:  "no tags",
+			table: &table{name: "cpu", fieldKeys: []string{"usage"}, fieldTypes: []string{"uint64"}},
+			opts:  &LoadingOptions{},
+			want: "CREATE TABLE IF NOT EXISTS benchmark.cpu (time DateTime64(9, 'UTC'), usage Nullable(UInt64)) " +
+				"ENGINE = MergeTree ORDER BY (time) SETTINGS allow_nullable_key = 1",
+		},
+	}
+	for _, c := range cases {
+		if got := createTableQuery("benchmark", c.table, c.opts); got != c.want {
+			t.Errorf("%s: incorrect query:\ngot\n%s\nwant\n%s", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestSerializedTypeToClickHouseTypeUnknown(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("did not panic on an unknown type")
+		}
+	}()
+	serializedTypeToClickHouseType("complex128")
+}
@@ -0,0 +1,25 @@
+package clickhouse
+
+import (
+	"io"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/timescaledb"
+)
+
+// Serializer writes a Point in the CSV format of TimescaleDB, a line of tags
+// followed by a line with the measurement, the timestamp and the field values:
+//
+// tags,<tag1>=<value1>,<tag2>=<value2>,...
+// <measurement>,<timestamp>,<field1>,<field2>,...
+//
+// Data files start with the header of the tables, written by generate_data,
+// from which the MergeTree tables are created.
+type Serializer struct {
+	csv timescaledb.Serializer
+}
+
+// Serialize writes Point p to the given Writer w.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	return s.csv.Serialize(p, w)
+}
@@ -0,0 +1,29 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+)
+
+func TestClickHouseSerializerSerialize(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with unsigned, boolean and string fields",
+			InputPoint: serialize.TestPointTyped(),
+			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,18446744073709551615,true,running,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "tags,hostname=NULL\ncpu,1451606400000000000,38.24311829\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
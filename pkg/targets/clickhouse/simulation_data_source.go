@@ -0,0 +1,75 @@
+package clickhouse
+
+import (
+	"strconv"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+type simulationDataSource struct {
+	simulator common.Simulator
+	headers   *common.GeneratedDataHeaders
+}
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	d.headers = d.simulator.Headers()
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	if d.headers == nil {
+		fatal("headers not read before starting to read points")
+		return data.LoadedPoint{}
+	}
+	newSimulatorPoint := data.NewPoint()
+	var write bool
+	for !d.simulator.Finished() {
+		write = d.simulator.Next(newSimulatorPoint)
+		if write {
+			break
+		}
+		newSimulatorPoint.Reset()
+	}
+	if d.simulator.Finished() || !write {
+		return data.LoadedPoint{}
+	}
+	newLoadPoint := &insertData{}
+	tagValues := newSimulatorPoint.TagValues()
+	tagKeys := newSimulatorPoint.TagKeys()
+	buf := make([]byte, 0, 256)
+	for i, v := range tagValues {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, tagKeys[i]...)
+		buf = append(buf, '=')
+		buf = serialize.CSVFormatAppend(v, buf)
+	}
+	newLoadPoint.tags = string(buf)
+	buf = buf[:0]
+	buf = strconv.AppendInt(buf, newSimulatorPoint.Timestamp().UTC().UnixNano(), 10)
+	for _, v := range newSimulatorPoint.FieldValues() {
+		buf = append(buf, ',')
+		buf = serialize.CSVFormatAppend(v, buf)
+	}
+	newLoadPoint.fields = string(buf)
+
+	return data.NewLoadedPoint(&point{
+		table: string(newSimulatorPoint.MeasurementName()),
+		row:   newLoadPoint,
+	})
+}
@@ -0,0 +1,73 @@
+package cnosdb
+
+import (
+	"bufio"
+
+	"github.com/cnosdb/tsdb-comparisons/internal/inputs"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+type benchmark struct {
+	dbName    string
+	opts      *LoadingOptions
+	ds        targets.DataSource
+	precision string
+}
+
+// NewBenchmark returns the benchmark loading the points of the data source
+// into the database dbName. The timestamps are sent along with their
+// precision: the one of the simulator, or the one in the header of the file.
+func NewBenchmark(dbName string, opts *LoadingOptions, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var ds targets.DataSource
+	var precision string
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		br := load.GetBufferedReader(dataSourceConfig.File.Location)
+		var err error
+		if precision, err = common.ReadPrecisionHeader(br); err != nil {
+			return nil, err
+		}
+		ds = &fileDataSource{scanner: bufio.NewScanner(br)}
+	} else {
+		simulator, err := (&inputs.DataGenerator{}).CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		precision = dataSourceConfig.Simulator.TimestampPrecision
+		serializer := serialize.WithTimestampPrecision(&Serializer{}, common.PrecisionDuration(precision))
+		ds = load.NewSerializedDataSource(simulator, serializer)
+	}
+
+	return &benchmark{
+		dbName:    dbName,
+		opts:      opts,
+		ds:        ds,
+		precision: precision,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{}
+}
+
+func (b *benchmark) GetPointIndexer(_ uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{dbName: b.dbName, opts: b.opts, precision: b.precision}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{opts: b.opts}
+}
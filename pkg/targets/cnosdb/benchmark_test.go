@@ -0,0 +1,70 @@
+package cnosdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+)
+
+func TestNewBenchmarkPrecision(t *testing.T) {
+	opts := &LoadingOptions{URLs: "http://localhost:8902", Consistency: "all"}
+	dir, err := ioutil.TempDir("", "cnosdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "data")
+	line := "cpu,hostname=host_0 usage=1.5 1451606400000"
+	if err := ioutil.WriteFile(file, []byte(common.PrecisionHeader("ms")+line+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBenchmark("benchmark", opts, &source.DataSourceConfig{
+		Type: source.FileDataSourceType,
+		File: &source.FileDataSourceConfig{Location: file},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := b.GetProcessor().(*processor).precision; got != "ms" {
+		t.Errorf("incorrect precision of the file: got %s want ms", got)
+	}
+	if got := string(b.GetDataSource().NextItem().Data.([]byte)); got != line {
+		t.Errorf("incorrect first line: got %s want %s", got, line)
+	}
+
+	b, err = NewBenchmark("benchmark", opts, &source.DataSourceConfig{
+		Type: source.SimulatorDataSourceType,
+		Simulator: &common.DataGeneratorConfig{
+			BaseConfig: common.BaseConfig{
+				Format:    constants.FormatCnosDB,
+				Use:       common.UseCaseIoT,
+				Scale:     1,
+				Seed:      123,
+				TimeStart: "2016-01-01T00:00:00Z",
+				TimeEnd:   "2016-01-01T00:01:00Z",
+			},
+			InitialScale:         1,
+			LogInterval:          10 * time.Second,
+			InterleavedNumGroups: 1,
+			TimestampPrecision:   "s",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := b.GetProcessor().(*processor).precision; got != "s" {
+		t.Errorf("incorrect precision of the simulator: got %s want s", got)
+	}
+	got := string(b.GetDataSource().NextItem().Data.([]byte))
+	if !regexp.MustCompile(` 1451606400\n?$`).MatchString(got) {
+		t.Errorf("timestamp not written in seconds: %s", got)
+	}
+}
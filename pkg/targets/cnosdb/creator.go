@@ -1,4 +1,4 @@
-package main
+package cnosdb
 
 import (
 	"bytes"
@@ -13,11 +13,12 @@ import (
 )
 
 type dbCreator struct {
+	opts      *LoadingOptions
 	daemonURL string
 }
 
 func (d *dbCreator) Init() {
-	d.daemonURL = daemonURLs[0] // pick first one since it always exists
+	d.daemonURL = d.opts.DaemonURLs()[0] // pick first one since it always exists
 }
 
 func (d *dbCreator) DBExists(dbName string) bool {
@@ -27,7 +28,7 @@ func (d *dbCreator) DBExists(dbName string) bool {
 	}
 
 	for _, db := range dbs {
-		if db == loader.DatabaseName() {
+		if db == dbName {
 			return true
 		}
 	}
@@ -41,8 +42,8 @@ func (d *dbCreator) listDatabases() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	if basicAuth != "" {
-		req.Header.Add(fasthttp.HeaderAuthorization, basicAuth)
+	if auth := d.opts.BasicAuth(); auth != "" {
+		req.Header.Add(fasthttp.HeaderAuthorization, auth)
 	}
 
 	client := &http.Client{}
@@ -82,8 +83,8 @@ func (d *dbCreator) RemoveOldDB(dbName string) error {
 	if err != nil {
 		return err
 	}
-	if basicAuth != "" {
-		req.Header.Add(fasthttp.HeaderAuthorization, basicAuth)
+	if auth := d.opts.BasicAuth(); auth != "" {
+		req.Header.Add(fasthttp.HeaderAuthorization, auth)
 	}
 
 	client := &http.Client{}
@@ -114,8 +115,8 @@ func (d *dbCreator) CreateDB(dbName string) error {
 	if err != nil {
 		return err
 	}
-	if basicAuth != "" {
-		req.Header.Add(fasthttp.HeaderAuthorization, basicAuth)
+	if auth := d.opts.BasicAuth(); auth != "" {
+		req.Header.Add(fasthttp.HeaderAuthorization, auth)
 	}
 
 	client := &http.Client{}
@@ -1,4 +1,4 @@
-package main
+package cnosdb
 
 import (
 	"bytes"
@@ -43,51 +43,51 @@ func getHttpClient() *http.Client {
 	return httpClient
 }
 
-// NewHTTPClient creates a new HTTPClient.
-func NewHTTPClient(url string) *HTTPClient {
+// NewHTTPClient creates a new HTTPClient, sending basicAuth as the
+// Authorization header unless it is empty.
+func NewHTTPClient(url, basicAuth string) *HTTPClient {
 	return &HTTPClient{
 		client:       getHttpClient(),
 		url:          []byte(url),
 		urlPrefixLen: len(url),
+		basicAuth:    basicAuth,
 	}
 }
 
-// Do performs the action specified by the given Query. It uses fasthttp, and
-// tries to minimize heap allocations.
-func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, err error) {
+// Do performs the action specified by the given Query and returns its latency
+// and the response body. It uses fasthttp, and tries to minimize heap allocations.
+func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, body []byte, err error) {
 	w.url = w.url[:w.urlPrefixLen]
 	w.url = append(w.url, []byte(url.QueryEscape(opts.database))...)
 
 	// populate a request with data from the Query:
 	req, err := http.NewRequest(string(q.Method), string(w.url), bytes.NewReader(q.Body))
 	if err != nil {
-		panic(err)
+		return 0, nil, err
 	}
-	if basicAuth != "" {
-		req.Header.Add(fasthttp.HeaderAuthorization, basicAuth)
+	if w.basicAuth != "" {
+		req.Header.Add(fasthttp.HeaderAuthorization, w.basicAuth)
 	}
 
 	// Perform the request while tracking latency:
 	start := time.Now()
 	resp, err := w.client.Do(req)
 	if err != nil {
-		panic(err)
+		return 0, nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		respMsg, err := io.ReadAll(resp.Body)
-		if err == nil {
-			panic(fmt.Sprintf("query request returned non-200 code: %d: %s", resp.StatusCode, respMsg))
-		} else {
-			panic(fmt.Sprintf("query request returned non-200 code: %d", resp.StatusCode))
+		if err != nil {
+			return 0, nil, fmt.Errorf("query request returned non-200 code: %d", resp.StatusCode)
 		}
+		return 0, nil, fmt.Errorf("query request returned non-200 code: %d: %s", resp.StatusCode, respMsg)
 	}
 
-	var body []byte
 	body, err = io.ReadAll(resp.Body)
 
 	if err != nil {
-		panic(err)
+		return 0, nil, err
 	}
 
 	lag = float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
@@ -129,5 +129,5 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 		}
 	}
 
-	return lag, err
+	return lag, body, err
 }
@@ -0,0 +1,45 @@
+package cnosdb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+func TestProcessQueryReturnsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("query timed out"))
+	}))
+	deadServer := httptest.NewServer(http.NotFoundHandler())
+	deadServer.Close()
+	defer server.Close()
+
+	cases := []struct {
+		desc    string
+		url     string
+		wantErr string
+	}{
+		{desc: "non-200 response", url: server.URL, wantErr: "non-200 code: 500: query timed out"},
+		{desc: "transport error", url: deadServer.URL},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			p := NewQueryProcessor(&QueryOptions{URLs: []string{c.url}, Database: "benchmark"})
+			p.Init(0)
+			q := query.NewHTTP()
+			q.HumanLabel = []byte("label")
+			q.Method = []byte("POST")
+			stats, err := p.ProcessQuery(q, false)
+			if err == nil {
+				t.Fatalf("expected an error, got stats %v", stats)
+			}
+			if !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("incorrect error: got %v want %s", err, c.wantErr)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package cnosdb
 
 // This file lifted wholesale from mountacnosdb by Mark Rushakoff.
 
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/valyala/fasthttp"
 )
 
@@ -33,6 +34,13 @@ type HTTPWriterConfig struct {
 
 	// Debug label for more informative errors.
 	DebugInfo string
+
+	// Precision of the timestamps of the points, one of s, ms, us or ns.
+	// Empty means the server's default, nanoseconds.
+	Precision string
+
+	// Value of the Authorization header, none if empty.
+	BasicAuth string
 }
 
 // HTTPWriter is a Writer that writes to an CnosDB HTTP server.
@@ -45,13 +53,17 @@ type HTTPWriter struct {
 
 // NewHTTPWriter returns a new HTTPWriter from the supplied HTTPWriterConfig.
 func NewHTTPWriter(c HTTPWriterConfig, consistency string) *HTTPWriter {
+	writeURL := c.Host + "/api/v1/write?consistency=" + consistency + "&db=" + url.QueryEscape(c.Database)
+	if c.Precision != "" {
+		writeURL += "&precision=" + c.Precision
+	}
 	return &HTTPWriter{
 		client: fasthttp.Client{
 			Name: httpClientName,
 		},
 
 		c:   c,
-		url: []byte(c.Host + "/api/v1/write?consistency=" + consistency + "&db=" + url.QueryEscape(c.Database)),
+		url: []byte(writeURL),
 	}
 }
 
@@ -64,8 +76,8 @@ func (w *HTTPWriter) initializeReq(req *fasthttp.Request, body []byte, isGzip bo
 	req.Header.SetContentTypeBytes(textPlain)
 	req.Header.SetMethodBytes(methodPost)
 	req.Header.SetRequestURIBytes(w.url)
-	if basicAuth != "" {
-		req.Header.Add(fasthttp.HeaderAuthorization, basicAuth)
+	if w.c.BasicAuth != "" {
+		req.Header.Add(fasthttp.HeaderAuthorization, w.c.BasicAuth)
 	}
 
 	if isGzip {
@@ -78,12 +90,17 @@ func (w *HTTPWriter) executeReq(req *fasthttp.Request, resp *fasthttp.Response)
 	start := time.Now()
 	err := w.client.Do(req, resp)
 	lat := time.Since(start).Nanoseconds()
-	if err == nil {
-		sc := resp.StatusCode()
-		if sc == 422 && backpressurePred(resp.Body()) {
-			err = errBackoff
-		} else if sc != fasthttp.StatusOK {
-			err = fmt.Errorf("[DebugInfo: %s] Invalid write response (status %d): %s", w.c.DebugInfo, sc, resp.Body())
+	if err != nil {
+		// the request did not reach the server or got no response
+		return lat, load.Retryable(err)
+	}
+	sc := resp.StatusCode()
+	if sc == 422 && backpressurePred(resp.Body()) {
+		err = errBackoff
+	} else if sc != fasthttp.StatusOK {
+		err = fmt.Errorf("[DebugInfo: %s] Invalid write response (status %d): %s", w.c.DebugInfo, sc, resp.Body())
+		if load.IsRetryableHTTPStatus(sc) {
+			err = load.Retryable(err)
 		}
 	}
 	return lat, err
@@ -1,4 +1,4 @@
-package main
+package cnosdb
 
 import (
 	"context"
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/valyala/fasthttp"
 )
 
@@ -36,16 +37,17 @@ func runHTTPServer(c chan struct{}) {
 		if strings.Contains(r.URL.RawQuery, shouldBackoffParam) {
 			coinflip := atomic.AddInt64(&i, 1)
 			if coinflip%2 == 1 {
-				w.WriteHeader(http.StatusInternalServerError)
+				w.WriteHeader(http.StatusUnprocessableEntity)
 				fmt.Fprintf(w, string(backoffMagicWords0))
 			} else {
-				w.WriteHeader(http.StatusNoContent)
+				w.WriteHeader(http.StatusOK)
 				fmt.Fprintf(w, "")
 			}
 		} else if strings.Contains(r.URL.RawQuery, shouldInvalidParam) {
-			fmt.Fprintf(w, "success should be an empty msg")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "invalid line protocol")
 		} else {
-			w.WriteHeader(http.StatusNoContent)
+			w.WriteHeader(http.StatusOK)
 			fmt.Fprintf(w, "")
 		}
 	})
@@ -109,6 +111,20 @@ func TestNewHTTPWriter(t *testing.T) {
 	}
 }
 
+func TestNewHTTPWriterPrecision(t *testing.T) {
+	conf := testConf
+	conf.Precision = "ms"
+	w := NewHTTPWriter(conf, testConsistency)
+	if got := string(w.url); !strings.HasSuffix(got, "&precision=ms") {
+		t.Errorf("url does not contain correct precision: got %s", got)
+	}
+
+	w = NewHTTPWriter(testConf, testConsistency)
+	if got := string(w.url); strings.Contains(got, "precision") {
+		t.Errorf("url contains a precision when none is set: got %s", got)
+	}
+}
+
 func TestHTTPWriterInitializeReq(t *testing.T) {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
@@ -180,6 +196,8 @@ func TestHTTPWriterExecuteReq(t *testing.T) {
 	lat, err = w.executeReq(req, resp)
 	if err == nil {
 		t.Errorf("unexpected non-error response received")
+	} else if load.IsRetryable(err) {
+		t.Errorf("invalid write should not be retried: %v", err)
 	}
 	if lat <= 0 {
 		t.Errorf("latency is unrealistic (<= 0): %d", lat)
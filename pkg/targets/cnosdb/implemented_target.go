@@ -1,13 +1,16 @@
 package cnosdb
 
 import (
+	"sync"
+	"time"
+
 	"github.com/blagojts/viper"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/spf13/pflag"
-	"time"
 )
 
 func NewTarget() targets.ImplementedTarget {
@@ -23,6 +26,8 @@ func (t *cnosdbTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.Fla
 	flagSet.String(flagPrefix+"consistency", "all", "Write consistency. Must be one of: any, one, quorum, all.")
 	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to sleep between requests when server indicates backpressure is needed.")
 	flagSet.Bool(flagPrefix+"gzip", true, "Whether to gzip encode requests (default true).")
+	flagSet.String(flagPrefix+"username", "root", "Basic access authentication username")
+	flagSet.String(flagPrefix+"password", "", "Basic access authentication password")
 }
 
 func (t *cnosdbTarget) TargetName() string {
@@ -33,6 +38,35 @@ func (t *cnosdbTarget) Serializer() serialize.PointSerializer {
 	return &Serializer{}
 }
 
-func (t *cnosdbTarget) Benchmark(string, *source.DataSourceConfig, *viper.Viper) (targets.Benchmark, error) {
-	panic("not implemented")
+func (t *cnosdbTarget) Benchmark(
+	targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	return NewBenchmark(targetDB, &loadingOptions, dataSourceConfig)
+}
+
+// QueryPool returns the pool of the HTTP queries, whose bodies are the SQL
+// statements.
+func (t *cnosdbTarget) QueryPool() *sync.Pool {
+	return &query.HTTPPool
+}
+
+// QueryProcessor returns the processors sending the queries to the same
+// servers, and as the same user, as the writes.
+func (t *cnosdbTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	opts := &QueryOptions{
+		URLs:           loadingOptions.DaemonURLs(),
+		Database:       runner.DatabaseName(),
+		BasicAuth:      loadingOptions.BasicAuth(),
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
 }
@@ -1,4 +1,4 @@
-package main
+package cnosdb
 
 import (
 	"bytes"
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 	
+	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data"
 )
 
@@ -17,26 +18,27 @@ func emptyLog(_ string, _ ...interface{}) (int, error) {
 }
 
 func TestProcessorInit(t *testing.T) {
-	daemonURLs = []string{"url1", "url2"}
+	daemonURLs := []string{"url1", "url2"}
+	opts := &LoadingOptions{URLs: "url1,url2", Consistency: "one"}
 	printFn = emptyLog
-	p := &processor{}
+	p := &processor{dbName: "benchmark", opts: opts}
 	p.Init(0, false, false)
 	p.Close(true)
 	if got := p.httpWriter.c.Host; got != daemonURLs[0] {
 		t.Errorf("incorrect host: got %s want %s", got, daemonURLs[0])
 	}
-	if got := p.httpWriter.c.Database; got != loader.DatabaseName() {
-		t.Errorf("incorrect database: got %s want %s", got, loader.DatabaseName())
+	if got := p.httpWriter.c.Database; got != "benchmark" {
+		t.Errorf("incorrect database: got %s want %s", got, "benchmark")
 	}
 	
-	p = &processor{}
+	p = &processor{dbName: "benchmark", opts: opts}
 	p.Init(1, false, false)
 	p.Close(true)
 	if got := p.httpWriter.c.Host; got != daemonURLs[1] {
 		t.Errorf("incorrect host: got %s want %s", got, daemonURLs[1])
 	}
 	
-	p = &processor{}
+	p = &processor{dbName: "benchmark", opts: opts}
 	p.Init(len(daemonURLs), false, false)
 	p.Close(true)
 	if got := p.httpWriter.c.Host; got != daemonURLs[0] {
@@ -102,7 +104,7 @@ func TestProcessorProcessBatch(t *testing.T) {
 		doLoad        bool
 		useGzip       bool
 		shouldBackoff bool
-		shouldFatal   bool
+		shouldFail    bool
 	}{
 		{
 			doLoad:  false,
@@ -122,44 +124,44 @@ func TestProcessorProcessBatch(t *testing.T) {
 			shouldBackoff: true,
 		},
 		{
-			doLoad:      true,
-			shouldFatal: true,
+			doLoad:     true,
+			shouldFail: true,
 		},
 	}
 	
 	for _, c := range cases {
 		var ch chan struct{}
-		fatalCalled := false
-		if c.shouldFatal {
-			fatal = func(format string, args ...interface{}) {
-				fatalCalled = true
-			}
-		} else {
-			fatal = func(format string, args ...interface{}) {
-				t.Errorf("fatal called for case %v unexpectedly\n", c)
-				fmt.Printf(format, args...)
-			}
+		fatal = func(format string, args ...interface{}) {
+			t.Errorf("fatal called for case %v unexpectedly\n", c)
+			fmt.Printf(format, args...)
+		}
+		if !c.shouldFail {
 			ch = launchHTTPServer()
 		}
 		
-		p := &processor{}
+		p := &processor{opts: &LoadingOptions{UseGzip: c.useGzip}}
+		p.SetRetryPolicy(load.NewRetryPolicy(load.RetryConfig{MaxAttempts: 3}))
 		w := NewHTTPWriter(testConf, testConsistency)
 		
 		// If the case should backoff, we tell our dummy server to do so by
-		// modifying the URL params. This should keep ProcessBatch in a loop
-		// until it gets a response that is not a backoff (every other response from the server).
+		// modifying the URL params. The retry policy retries the write until
+		// it gets a response that is not a backoff (every other response from the server).
 		if c.shouldBackoff {
 			normalURL := string(w.url)
 			w.url = []byte(fmt.Sprintf("%s&%s=true", normalURL, shouldBackoffParam))
 		}
 		
 		p.initWithHTTPWriter(0, w)
-		useGzip = c.useGzip
-		mCnt, rCnt := p.ProcessBatch(b, c.doLoad)
-		if c.shouldFatal {
-			if !fatalCalled {
-				t.Errorf("fatal was not called when it should have been")
+		mCnt, rCnt, failedMCnt, failedRCnt := p.ProcessBatchWithFailures(b, c.doLoad)
+		if c.shouldFail {
+			// no server is running, the batch is counted as failed
+			if mCnt != 0 || rCnt != 0 {
+				t.Errorf("failed batch counted as loaded: got %d metrics and %d rows", mCnt, rCnt)
+			}
+			if failedMCnt != b.metrics || failedRCnt != uint64(b.rows) {
+				t.Errorf("failed batch not counted as failed: got %d metrics and %d rows", failedMCnt, failedRCnt)
 			}
+			p.Close(true)
 			continue
 		} else {
 			if mCnt != b.metrics {
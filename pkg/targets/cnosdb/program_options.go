@@ -0,0 +1,52 @@
+package cnosdb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConsistencyChoices are the write consistencies of CnosDB.
+var ConsistencyChoices = []string{"any", "one", "quorum", "all"}
+
+// Loading option vars:
+type LoadingOptions struct {
+	URLs              string        `yaml:"urls"`
+	ReplicationFactor int           `yaml:"replication-factor" mapstructure:"replication-factor"`
+	Consistency       string        `yaml:"consistency"`
+	Backoff           time.Duration `yaml:"backoff"`
+	UseGzip           bool          `yaml:"gzip" mapstructure:"gzip"`
+	User              string        `yaml:"username" mapstructure:"username"`
+	Pass              string        `yaml:"password" mapstructure:"password"`
+}
+
+// Validate checks the consistency and the URLs of the options.
+func (o *LoadingOptions) Validate() error {
+	valid := false
+	for _, c := range ConsistencyChoices {
+		valid = valid || c == o.Consistency
+	}
+	if !valid {
+		return fmt.Errorf("invalid consistency '%s', choices: %v", o.Consistency, ConsistencyChoices)
+	}
+	if o.URLs == "" {
+		return fmt.Errorf("missing 'urls' flag")
+	}
+	return nil
+}
+
+// DaemonURLs returns the URLs of the servers, which the workers write to in
+// a round-robin fashion.
+func (o *LoadingOptions) DaemonURLs() []string {
+	return strings.Split(o.URLs, ",")
+}
+
+// BasicAuth returns the value of the Authorization header of the user, empty
+// if neither the user nor the password is set.
+func (o *LoadingOptions) BasicAuth() string {
+	if o.User == "" && o.Pass == "" {
+		return ""
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(o.User+":"+o.Pass))
+}
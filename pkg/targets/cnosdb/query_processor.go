@@ -0,0 +1,66 @@
+package cnosdb
+
+import (
+	"strings"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// QueryOptions holds the settings of the query processors.
+type QueryOptions struct {
+	// URLs are the servers the workers send the queries to in a round-robin fashion
+	URLs     []string
+	Database string
+	// BasicAuth is the value of the Authorization header, empty to send none
+	BasicAuth      string
+	Debug          int
+	PrintResponses bool
+}
+
+type queryProcessor struct {
+	w    *HTTPClient
+	opts *QueryOptions
+	do   *HTTPClientDoOptions
+}
+
+// NewQueryProcessor returns a query.Processor that runs the SQL queries of
+// CnosDB with its HTTP API.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(workerNumber int) {
+	p.do = &HTTPClientDoOptions{
+		debug:                p.opts.Debug,
+		prettyPrintResponses: p.opts.PrintResponses,
+		database:             p.opts.Database,
+	}
+	url := strings.TrimSuffix(p.opts.URLs[workerNumber%len(p.opts.URLs)], "/")
+	p.w = NewHTTPClient(url+"/api/v1/sql?tenant=cnosdb&db=", p.opts.BasicAuth)
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
+	hq := q.(*query.HTTP)
+	lag, _, err := p.w.Do(hq, p.do)
+	if err != nil {
+		return nil, err
+	}
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	return []*query.Stat{stat}, nil
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, _ bool) ([]*query.Stat, *query.ResultSet, error) {
+	hq := q.(*query.HTTP)
+	lag, body, err := p.w.Do(hq, p.do)
+	if err != nil {
+		return nil, nil, err
+	}
+	rs, err := parseResponse(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	return []*query.Stat{stat}, rs, nil
+}
@@ -0,0 +1,67 @@
+package cnosdb
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// parseResponse converts the body of a CnosDB /api/v1/sql response into a
+// query.ResultSet. Both the JSON (array of row objects) and the CSV (with
+// header line) response formats are understood.
+func parseResponse(body []byte) (*query.ResultSet, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return query.NewResultSet(), nil
+	}
+	if trimmed[0] == '[' {
+		return parseJSONResponse(trimmed)
+	}
+	return parseCSVResponse(trimmed)
+}
+
+func parseJSONResponse(body []byte) (*query.ResultSet, error) {
+	var rows []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	if len(rows) == 0 {
+		return query.NewResultSet(), nil
+	}
+	columns := make([]string, 0, len(rows[0]))
+	for k := range rows[0] {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	rs := query.NewResultSet(columns...)
+	values := make([]interface{}, len(columns))
+	for _, row := range rows {
+		for i, c := range columns {
+			values[i] = row[c]
+		}
+		rs.AppendRow(values...)
+	}
+	return rs, nil
+}
+
+func parseCSVResponse(body []byte) (*query.ResultSet, error) {
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	rs := query.NewResultSet(records[0]...)
+	for _, record := range records[1:] {
+		values := make([]interface{}, len(record))
+		for i, v := range record {
+			values[i] = v
+		}
+		rs.AppendRow(values...)
+	}
+	return rs, nil
+}
@@ -1,11 +1,13 @@
-package main
+package cnosdb
 
 import (
 	"bufio"
 	"bytes"
-	"strings"
+	"log"
+	"sync"
 
 	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 )
@@ -14,6 +16,15 @@ const errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
 
 var newLine = []byte("\n")
 
+// allows for testing
+var fatal = log.Fatalf
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+	},
+}
+
 type fileDataSource struct {
 	scanner *bufio.Scanner
 }
@@ -43,16 +54,16 @@ func (b *batch) Len() uint {
 
 func (b *batch) Append(item data.LoadedPoint) {
 	that := item.Data.([]byte)
-	thatStr := string(that)
 	b.rows++
 	// Each line is format "csv-tags csv-fields timestamp", so we split by space
-	// and then on the middle element, we split by comma to count number of fields added
-	args := strings.Split(thatStr, " ")
+	// and then on the middle element, we split by comma to count number of fields added.
+	// Escaped spaces and commas, and the ones of string field values, are not separators
+	args := serialize.SplitLineProtocol(that, ' ')
 	if len(args) != 3 {
 		fatal(errNotThreeTuplesFmt, len(args))
 		return
 	}
-	b.metrics += uint64(len(strings.Split(args[1], ",")))
+	b.metrics += uint64(len(serialize.SplitLineProtocol(args[1], ',')))
 
 	b.buf.Write(that)
 	b.buf.Write(newLine)
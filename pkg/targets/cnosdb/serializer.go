@@ -1,13 +1,24 @@
 package cnosdb
 
 import (
+	"bytes"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"io"
+	"strings"
+	"time"
 )
 
 // Serializer writes a Point in a serialized form for MongoDB
-type Serializer struct{}
+type Serializer struct {
+	// Precision is the unit the timestamps are written in, nanoseconds if 0.
+	Precision time.Duration
+}
+
+// SetTimestampPrecision sets the unit the timestamps are written in.
+func (s *Serializer) SetTimestampPrecision(unit time.Duration) {
+	s.Precision = unit
+}
 
 // Serialize writes Point data to the given writer, conforming to the
 // CnosDBwire protocol.
@@ -19,7 +30,7 @@ type Serializer struct{}
 // foo,tag0=bar baz=-1.0 100\n
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	buf := make([]byte, 0, 1024)
-	buf = append(buf, p.MeasurementName()...)
+	buf = appendEscaped(buf, p.MeasurementName(), measurementSpecials)
 	
 	fakeTags := make([]int, 0)
 	tagKeys := p.TagKeys()
@@ -31,9 +42,9 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 		switch v := tagValues[i].(type) {
 		case string:
 			buf = append(buf, ',')
-			buf = append(buf, tagKeys[i]...)
+			buf = appendEscaped(buf, tagKeys[i], keySpecials)
 			buf = append(buf, '=')
-			buf = append(buf, []byte(v)...)
+			buf = appendEscaped(buf, []byte(v), keySpecials)
 		default:
 			fakeTags = append(fakeTags, i)
 		}
@@ -72,7 +83,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 		return nil
 	}
 	buf = append(buf, ' ')
-	buf = serialize.FastFormatAppend(p.Timestamp().UTC().UnixNano(), buf)
+	buf = serialize.FastFormatAppend(serialize.UnixIn(*p.Timestamp(), s.Precision), buf)
 	buf = append(buf, '\n')
 	_, err = w.Write(buf)
 	
@@ -80,16 +91,59 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 }
 
 func appendField(buf, key []byte, v interface{}) []byte {
-	buf = append(buf, key...)
+	buf = appendEscaped(buf, key, keySpecials)
 	buf = append(buf, '=')
 	
+	switch v := v.(type) {
+	case string:
+		return appendStringField(buf, []byte(v))
+	case []byte:
+		return appendStringField(buf, v)
+	}
+	
 	buf = serialize.FastFormatAppend(v, buf)
 	
-	// Influx uses 'i' to indicate integers:
+	// Influx uses 'i' to indicate integers and 'u' unsigned integers:
 	switch v.(type) {
 	case int, int64:
 		buf = append(buf, 'i')
+	case uint, uint64:
+		buf = append(buf, 'u')
 	}
 	
 	return buf
 }
+
+// appendStringField appends a string field value, which is double quoted
+// with its quotes and backslashes escaped.
+func appendStringField(buf, v []byte) []byte {
+	buf = append(buf, '"')
+	for _, c := range v {
+		if c == '"' || c == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '"')
+}
+
+// Characters escaped with a backslash in measurements, and in tag keys, tag
+// values and field keys.
+const (
+	measurementSpecials = ", "
+	keySpecials         = ",= "
+)
+
+// appendEscaped appends v with its special characters escaped.
+func appendEscaped(buf, v []byte, specials string) []byte {
+	if !bytes.ContainsAny(v, specials) {
+		return append(buf, v...)
+	}
+	for _, c := range v {
+		if strings.IndexByte(specials, c) >= 0 {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
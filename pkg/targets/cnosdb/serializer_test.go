@@ -3,6 +3,7 @@ package cnosdb
 import (
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"testing"
+	"time"
 )
 
 func TestInfluxSerializerSerialize(t *testing.T) {
@@ -22,6 +23,16 @@ func TestInfluxSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointMultiField(),
 			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b big_usage_guest=5000000000i,usage_guest=38i,usage_guest_nice=38.24311829 1451606400000000000\n",
 		},
+		{
+			Desc:       "a Point with unsigned, boolean and string fields",
+			InputPoint: serialize.TestPointTyped(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b bytes_sent=18446744073709551615u,online=true,state=\"running\",usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+		{
+			Desc:       "a Point with special characters",
+			InputPoint: serialize.TestPointEscaped(),
+			Output:     "cpu\\ load,host\\ name=host\\,0\\=a usage\\=guest=38.24311829 1451606400000000000\n",
+		},
 		{
 			Desc:       "a Point with no tags",
 			InputPoint: serialize.TestPointNoTags(),
@@ -39,3 +50,15 @@ func TestInfluxSerializerSerialize(t *testing.T) {
 	
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
+
+func TestInfluxSerializerSerializePrecision(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point in milliseconds",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b usage_guest_nice=38.24311829 1451606400000\n",
+		},
+	}
+	
+	serialize.SerializerTest(t, cases, serialize.WithTimestampPrecision(&Serializer{}, time.Millisecond))
+}
@@ -2,11 +2,15 @@ package constants
 
 // Formats supported for generation
 const (
-	FormatInflux      = "influx"
-	FormatTimescaleDB = "timescaledb"
-	FormatCnosDB      = "cnosdb"
-	FormatTDEngine    = "tdengine"
-	FormatIOTDB       = "iotdb"
+	FormatInflux          = "influx"
+	FormatTimescaleDB     = "timescaledb"
+	FormatCnosDB          = "cnosdb"
+	FormatTDEngine        = "tdengine"
+	FormatIOTDB           = "iotdb"
+	FormatQuestDB         = "questdb"
+	FormatVictoriaMetrics = "victoriametrics"
+	FormatClickHouse      = "clickhouse"
+	FormatPrometheus      = "prometheus"
 )
 
 func SupportedFormats() []string {
@@ -16,5 +20,9 @@ func SupportedFormats() []string {
 		FormatCnosDB,
 		FormatTDEngine,
 		FormatIOTDB,
+		FormatQuestDB,
+		FormatVictoriaMetrics,
+		FormatClickHouse,
+		FormatPrometheus,
 	}
 }
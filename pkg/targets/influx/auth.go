@@ -0,0 +1,17 @@
+package influx
+
+// Authorization returns the value of the Authorization header of the requests
+// of the given API version, empty if there is no token.
+func Authorization(apiVersion int, token string) string {
+	if token == "" {
+		return ""
+	}
+	switch apiVersion {
+	case 2:
+		return "Token " + token
+	case 3:
+		return "Bearer " + token
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,22 @@
+package influx
+
+import "testing"
+
+func TestAuthorization(t *testing.T) {
+	cases := []struct {
+		desc       string
+		apiVersion int
+		token      string
+		want       string
+	}{
+		{desc: "v1 ignores the token", apiVersion: 1, token: "secret", want: ""},
+		{desc: "v2", apiVersion: 2, token: "secret", want: "Token secret"},
+		{desc: "v3", apiVersion: 3, token: "secret", want: "Bearer secret"},
+		{desc: "no token", apiVersion: 2, token: "", want: ""},
+	}
+	for _, c := range cases {
+		if got := Authorization(c.apiVersion, c.token); got != c.want {
+			t.Errorf("%s: incorrect header: got %q want %q", c.desc, got, c.want)
+		}
+	}
+}
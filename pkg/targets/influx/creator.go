@@ -0,0 +1,295 @@
+package influx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type dbCreator struct {
+	opts      *LoadingOptions
+	daemonURL string
+}
+
+func (d *dbCreator) Init() {
+	d.daemonURL = d.opts.DaemonURLs()[0] // pick first one since it always exists
+}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	switch d.opts.APIVersion {
+	case 2:
+		id, err := d.bucketID(dbName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return id != ""
+	case 3:
+		dbs, err := d.listDatabasesV3()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, db := range dbs {
+			if db == dbName {
+				return true
+			}
+		}
+		return false
+	}
+
+	dbs, err := d.listDatabases()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, db := range dbs {
+		if db == dbName {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dbCreator) listDatabases() ([]string, error) {
+	u := fmt.Sprintf("%s/query?q=show%%20databases", d.daemonURL)
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("listDatabases error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do ad-hoc parsing to find existing database names:
+	// {"results":[{"series":[{"name":"databases","columns":["name"],"values":[["_internal"],["benchmark_db"]]}]}]}%
+	type listingType struct {
+		Results []struct {
+			Series []struct {
+				Values [][]string
+			}
+		}
+	}
+	var listing listingType
+	err = json.Unmarshal(body, &listing)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []string{}
+	for _, nestedName := range listing.Results[0].Series[0].Values {
+		name := nestedName[0]
+		// the _internal database is skipped:
+		if name == "_internal" {
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret, nil
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	switch d.opts.APIVersion {
+	case 2:
+		return d.removeBucket(dbName)
+	case 3:
+		return d.removeDatabaseV3(dbName)
+	}
+
+	u := fmt.Sprintf("%s/query?q=drop+database+%s", d.daemonURL, dbName)
+	resp, err := http.Post(u, "text/plain", nil)
+	if err != nil {
+		return fmt.Errorf("drop db error: %s", err.Error())
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("drop db returned non-200 code: %d", resp.StatusCode)
+	}
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (d *dbCreator) CreateDB(dbName string) error {
+	switch d.opts.APIVersion {
+	case 2:
+		return d.createBucket(dbName)
+	case 3:
+		return d.createDatabaseV3(dbName)
+	}
+
+	u, err := url.Parse(d.daemonURL)
+	if err != nil {
+		return err
+	}
+
+	// serialize params the right way:
+	u.Path = "query"
+	v := u.Query()
+	v.Set("consistency", "all")
+	v.Set("q", fmt.Sprintf("CREATE DATABASE %s WITH REPLICATION %d", dbName, d.opts.ReplicationFactor))
+	u.RawQuery = v.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// does the body need to be read into the void?
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("bad db create")
+	}
+
+	time.Sleep(time.Second)
+	return nil
+}
+
+// do sends a request with the token of API versions 2 and 3 and the given
+// JSON body, if any, and decodes the JSON response into out, if any. It
+// returns the status code of the response.
+func (d *dbCreator) do(method, path string, body, out interface{}) (int, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequest(method, d.daemonURL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth := Authorization(d.opts.APIVersion, d.opts.Token); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, fmt.Errorf("%s %s returned code %d: %s", method, path, resp.StatusCode, respBody)
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%s %s returned invalid response: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// bucketID returns the id of the bucket of API version 2 with the given name in
+// the organization, empty if there is none.
+func (d *dbCreator) bucketID(name string) (string, error) {
+	var listing struct {
+		Buckets []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"buckets"`
+	}
+	v := url.Values{}
+	v.Set("org", d.opts.Org)
+	v.Set("name", name)
+	if _, err := d.do("GET", "/api/v2/buckets?"+v.Encode(), nil, &listing); err != nil {
+		return "", fmt.Errorf("list buckets error: %s", err.Error())
+	}
+	for _, b := range listing.Buckets {
+		if b.Name == name {
+			return b.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (d *dbCreator) removeBucket(name string) error {
+	id, err := d.bucketID(name)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return nil
+	}
+	if _, err := d.do("DELETE", "/api/v2/buckets/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("drop bucket error: %s", err.Error())
+	}
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (d *dbCreator) createBucket(name string) error {
+	var orgs struct {
+		Orgs []struct {
+			ID string `json:"id"`
+		} `json:"orgs"`
+	}
+	v := url.Values{}
+	v.Set("org", d.opts.Org)
+	if _, err := d.do("GET", "/api/v2/orgs?"+v.Encode(), nil, &orgs); err != nil {
+		return fmt.Errorf("find org error: %s", err.Error())
+	}
+	if len(orgs.Orgs) == 0 {
+		return fmt.Errorf("org %s not found", d.opts.Org)
+	}
+
+	bucket := map[string]interface{}{
+		"orgID":          orgs.Orgs[0].ID,
+		"name":           name,
+		"retentionRules": []interface{}{},
+	}
+	if _, err := d.do("POST", "/api/v2/buckets", bucket, nil); err != nil {
+		return fmt.Errorf("create bucket error: %s", err.Error())
+	}
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (d *dbCreator) listDatabasesV3() ([]string, error) {
+	// [{"iox::database":"_internal"},{"iox::database":"benchmark"}]
+	var listing []map[string]string
+	if _, err := d.do("GET", "/api/v3/configure/database?format=json", nil, &listing); err != nil {
+		return nil, fmt.Errorf("listDatabases error: %s", err.Error())
+	}
+	ret := []string{}
+	for _, db := range listing {
+		ret = append(ret, db["iox::database"])
+	}
+	return ret, nil
+}
+
+func (d *dbCreator) removeDatabaseV3(name string) error {
+	v := url.Values{}
+	v.Set("db", name)
+	code, err := d.do("DELETE", "/api/v3/configure/database?"+v.Encode(), nil, nil)
+	if err != nil && code != http.StatusNotFound {
+		return fmt.Errorf("drop db error: %s", err.Error())
+	}
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (d *dbCreator) createDatabaseV3(name string) error {
+	if _, err := d.do("POST", "/api/v3/configure/database", map[string]string{"db": name}, nil); err != nil {
+		return fmt.Errorf("create db error: %s", err.Error())
+	}
+	time.Sleep(time.Second)
+	return nil
+}
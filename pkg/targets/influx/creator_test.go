@@ -0,0 +1,112 @@
+package influx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDBCreatorBuckets(t *testing.T) {
+	buckets := map[string]string{"old": "0a"}
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Token secret" {
+			t.Errorf("incorrect Authorization header: got %s", got)
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v2/buckets":
+			if got := r.URL.Query().Get("org"); got != "my-org" {
+				t.Errorf("incorrect org: got %s", got)
+			}
+			name := r.URL.Query().Get("name")
+			resp := `{"buckets":[]}`
+			if id, ok := buckets[name]; ok {
+				resp = fmt.Sprintf(`{"buckets":[{"id":"%s","name":"%s"}]}`, id, name)
+			}
+			fmt.Fprint(w, resp)
+		case r.Method == "DELETE" && r.URL.Path == "/api/v2/buckets/0a":
+			delete(buckets, "old")
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/api/v2/orgs":
+			fmt.Fprint(w, `{"orgs":[{"id":"1b","name":"my-org"}]}`)
+		case r.Method == "POST" && r.URL.Path == "/api/v2/buckets":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &created)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	d := &dbCreator{opts: &LoadingOptions{APIVersion: 2, Org: "my-org", Token: "secret"}, daemonURL: server.URL}
+	if !d.DBExists("old") {
+		t.Errorf("existing bucket not found")
+	}
+	if d.DBExists("new") {
+		t.Errorf("missing bucket found")
+	}
+	if err := d.RemoveOldDB("old"); err != nil {
+		t.Errorf("unexpected error removing bucket: %v", err)
+	}
+	if _, ok := buckets["old"]; ok {
+		t.Errorf("bucket not removed")
+	}
+	if err := d.CreateDB("new"); err != nil {
+		t.Errorf("unexpected error creating bucket: %v", err)
+	}
+	if created["orgID"] != "1b" || created["name"] != "new" {
+		t.Errorf("incorrect bucket created: %v", created)
+	}
+}
+
+func TestDBCreatorDatabasesV3(t *testing.T) {
+	var dropped, created string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("incorrect Authorization header: got %s", got)
+		}
+		if r.URL.Path != "/api/v3/configure/database" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `[{"iox::database":"_internal"},{"iox::database":"old"}]`)
+		case "DELETE":
+			dropped = r.URL.Query().Get("db")
+			if dropped != "old" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "POST":
+			var db map[string]string
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &db)
+			created = db["db"]
+		}
+	}))
+	defer server.Close()
+
+	d := &dbCreator{opts: &LoadingOptions{APIVersion: 3, Token: "secret"}, daemonURL: server.URL}
+	if !d.DBExists("old") {
+		t.Errorf("existing database not found")
+	}
+	if d.DBExists("new") {
+		t.Errorf("missing database found")
+	}
+	if err := d.RemoveOldDB("old"); err != nil || dropped != "old" {
+		t.Errorf("database not removed: %v", err)
+	}
+	if err := d.RemoveOldDB("missing"); err != nil {
+		t.Errorf("unexpected error removing a missing database: %v", err)
+	}
+	if err := d.CreateDB("new"); err != nil || created != "new" {
+		t.Errorf("database not created: %v", err)
+	}
+}
@@ -1,12 +1,14 @@
-package main
+package influx
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"sync"
 	"time"
 	
@@ -30,6 +32,29 @@ type HTTPClientDoOptions struct {
 	PrettyPrintResponses bool
 	chunkSize            uint64
 	database             string
+	org                  string
+	authorization        string
+}
+
+// Languages of the queries, told apart by their endpoint.
+const (
+	languageInfluxQL = "influxql"
+	languageFlux     = "flux"
+	languageSQL      = "sql"
+)
+
+// queryLanguage returns the language of a query with the given path: InfluxQL
+// for the /query endpoint of 1.x, Flux for the /api/v2/query endpoint of 2.x
+// and SQL for the /api/v3 endpoints of 3.x.
+func queryLanguage(path []byte) string {
+	switch {
+	case bytes.HasPrefix(path, []byte("/api/v2/query")):
+		return languageFlux
+	case bytes.HasPrefix(path, []byte("/api/v3/")):
+		return languageSQL
+	default:
+		return languageInfluxQL
+	}
 }
 
 var httpClientOnce = sync.Once{}
@@ -55,42 +80,62 @@ func NewHTTPClient(host string) *HTTPClient {
 	}
 }
 
-// Do performs the action specified by the given Query. It uses fasthttp, and
-// tries to minimize heap allocations.
-func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, err error) {
+// Do performs the action specified by the given Query and returns its latency
+// and the response body. It uses fasthttp, and tries to minimize heap allocations.
+func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, body []byte, err error) {
 	// populate uri from the reusable byte slice:
 	w.uri = w.uri[:0]
 	w.uri = append(w.uri, w.Host...)
 	// w.uri = append(w.uri, bytesSlash...)
 	w.uri = append(w.uri, q.Path...)
-	w.uri = append(w.uri, []byte("&db="+url.QueryEscape(opts.database))...)
-	if opts.chunkSize > 0 {
+	if bytes.IndexByte(q.Path, '?') < 0 {
+		w.uri = append(w.uri, '?')
+	} else {
+		w.uri = append(w.uri, '&')
+	}
+	language := queryLanguage(q.Path)
+	if language == languageFlux {
+		w.uri = append(w.uri, []byte("org="+url.QueryEscape(opts.org))...)
+	} else {
+		w.uri = append(w.uri, []byte("db="+url.QueryEscape(opts.database))...)
+	}
+	if language == languageInfluxQL && opts.chunkSize > 0 {
 		s := fmt.Sprintf("&chunked=true&chunk_size=%d", opts.chunkSize)
 		w.uri = append(w.uri, []byte(s)...)
 	}
 	
 	// populate a request with data from the Query:
-	req, err := http.NewRequest(string(q.Method), string(w.uri), nil)
+	req, err := http.NewRequest(string(q.Method), string(w.uri), bytes.NewReader(q.Body))
 	if err != nil {
-		panic(err)
+		return 0, nil, err
+	}
+	if language == languageFlux {
+		req.Header.Set("Content-Type", "application/vnd.flux")
+		req.Header.Set("Accept", "application/csv")
+	}
+	if opts.authorization != "" {
+		req.Header.Set("Authorization", opts.authorization)
 	}
 	
 	// Perform the request while tracking latency:
 	start := time.Now()
 	resp, err := w.client.Do(req)
 	if err != nil {
-		panic(err)
+		return 0, nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		panic("http request did not return status 200 OK")
+		respMsg, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return 0, nil, fmt.Errorf("query request returned non-200 code: %d", resp.StatusCode)
+		}
+		return 0, nil, fmt.Errorf("query request returned non-200 code: %d: %s", resp.StatusCode, respMsg)
 	}
 	
-	var body []byte
 	body, err = ioutil.ReadAll(resp.Body)
 	
 	if err != nil {
-		panic(err)
+		return 0, nil, err
 	}
 	
 	lag = float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
@@ -114,15 +159,21 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 		
 		// Pretty print JSON responses, if applicable:
 		if opts.PrettyPrintResponses {
-			// Assumes the response is JSON! This holds for Influx
-			// and Elastic.
+			// The InfluxQL responses are JSON, the Flux and SQL ones CSV
+			// which is printed as its rows.
 			
 			prefix := fmt.Sprintf("ID %d: ", q.GetID())
 			var v interface{}
 			var line []byte
 			full := make(map[string]interface{})
-			full["influxql"] = string(q.RawQuery)
-			json.Unmarshal(body, &v)
+			full[language] = string(q.RawQuery)
+			if language == languageInfluxQL {
+				json.Unmarshal(body, &v)
+			} else if rs, err := parseCSVResponse(body, language == languageFlux); err == nil {
+				v = rs
+			} else {
+				v = strings.TrimSpace(string(body))
+			}
 			full["response"] = v
 			line, err = json.MarshalIndent(full, prefix, "  ")
 			if err != nil {
@@ -132,5 +183,5 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 		}
 	}
 	
-	return lag, err
+	return lag, body, err
 }
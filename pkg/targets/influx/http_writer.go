@@ -1,4 +1,4 @@
-package main
+package influx
 
 // This file lifted wholesale from mountainflux by Mark Rushakoff.
 
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/valyala/fasthttp"
 )
 
@@ -38,28 +39,80 @@ type HTTPWriterConfig struct {
 
 	// Debug label for more informative errors.
 	DebugInfo string
+
+	// Precision of the timestamps of the points, one of s, ms, us or ns.
+	// Empty means the server's default, nanoseconds.
+	Precision string
+
+	// Version of the HTTP API of the server, 1 if zero.
+	APIVersion int
+
+	// Organization of the bucket, used by API version 2.
+	Org string
+
+	// API token, used by API versions 2 and 3.
+	Token string
 }
 
 // HTTPWriter is a Writer that writes to an InfluxDB HTTP server.
 type HTTPWriter struct {
 	client fasthttp.Client
 
-	c   HTTPWriterConfig
-	url []byte
+	c             HTTPWriterConfig
+	url           []byte
+	authorization []byte
 }
 
 // NewHTTPWriter returns a new HTTPWriter from the supplied HTTPWriterConfig.
+// The consistency only applies to API version 1.
 func NewHTTPWriter(c HTTPWriterConfig, consistency string) *HTTPWriter {
+	var writeURL string
+	switch c.APIVersion {
+	case 2:
+		writeURL = c.Host + "/api/v2/write?org=" + url.QueryEscape(c.Org) + "&bucket=" + url.QueryEscape(c.Database)
+		if c.Precision != "" {
+			writeURL += "&precision=" + c.Precision
+		}
+	case 3:
+		writeURL = c.Host + "/api/v3/write_lp?db=" + url.QueryEscape(c.Database)
+		if c.Precision != "" {
+			writeURL += "&precision=" + writePrecisionV3[c.Precision]
+		}
+	default:
+		writeURL = c.Host + "/write?consistency=" + consistency + "&db=" + url.QueryEscape(c.Database)
+		if c.Precision != "" {
+			writeURL += "&precision=" + writePrecision(c.Precision)
+		}
+	}
 	return &HTTPWriter{
 		client: fasthttp.Client{
 			Name: httpClientName,
 		},
 
-		c:   c,
-		url: []byte(c.Host + "/write?consistency=" + consistency + "&db=" + url.QueryEscape(c.Database)),
+		c:             c,
+		url:           []byte(writeURL),
+		authorization: []byte(Authorization(c.APIVersion, c.Token)),
 	}
 }
 
+// writePrecision returns the precision parameter of the InfluxDB 1.x write
+// endpoint, which names microseconds "u".
+func writePrecision(precision string) string {
+	if precision == "us" {
+		return "u"
+	}
+	return precision
+}
+
+// writePrecisionV3 maps the precisions to the names the InfluxDB 3.x write
+// endpoint knows them by.
+var writePrecisionV3 = map[string]string{
+	"s":  "second",
+	"ms": "millisecond",
+	"us": "microsecond",
+	"ns": "nanosecond",
+}
+
 var (
 	methodPost = []byte("POST")
 	textPlain  = []byte("text/plain")
@@ -69,6 +122,9 @@ func (w *HTTPWriter) initializeReq(req *fasthttp.Request, body []byte, isGzip bo
 	req.Header.SetContentTypeBytes(textPlain)
 	req.Header.SetMethodBytes(methodPost)
 	req.Header.SetRequestURIBytes(w.url)
+	if len(w.authorization) > 0 {
+		req.Header.SetBytesV(fasthttp.HeaderAuthorization, w.authorization)
+	}
 	if isGzip {
 		req.Header.Add(headerContentEncoding, headerGzip)
 	}
@@ -79,12 +135,19 @@ func (w *HTTPWriter) executeReq(req *fasthttp.Request, resp *fasthttp.Response)
 	start := time.Now()
 	err := w.client.Do(req, resp)
 	lat := time.Since(start).Nanoseconds()
-	if err == nil {
-		sc := resp.StatusCode()
-		if sc == 500 && backpressurePred(resp.Body()) {
-			err = errBackoff
-		} else if sc != fasthttp.StatusNoContent {
-			err = fmt.Errorf("[DebugInfo: %s] Invalid write response (status %d): %s", w.c.DebugInfo, sc, resp.Body())
+	if err != nil {
+		// the request did not reach the server or got no response
+		return lat, load.Retryable(err)
+	}
+	sc := resp.StatusCode()
+	if sc == 500 && backpressurePred(resp.Body()) {
+		err = errBackoff
+	} else if sc == fasthttp.StatusTooManyRequests || sc == fasthttp.StatusServiceUnavailable {
+		err = errBackoff
+	} else if sc != fasthttp.StatusNoContent {
+		err = fmt.Errorf("[DebugInfo: %s] Invalid write response (status %d): %s", w.c.DebugInfo, sc, resp.Body())
+		if load.IsRetryableHTTPStatus(sc) {
+			err = load.Retryable(err)
 		}
 	}
 	return lat, err
@@ -1,9 +1,10 @@
-package main
+package influx
 
 import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"sync/atomic"
@@ -109,6 +110,86 @@ func TestNewHTTPWriter(t *testing.T) {
 	}
 }
 
+func TestNewHTTPWriterPrecision(t *testing.T) {
+	cases := map[string]string{
+		"":   "",
+		"s":  "&precision=s",
+		"ms": "&precision=ms",
+		"us": "&precision=u",
+		"ns": "&precision=ns",
+	}
+	for precision, want := range cases {
+		conf := testConf
+		conf.Precision = precision
+		w := NewHTTPWriter(conf, testConsistency)
+		got := string(w.url)
+		if want == "" && strings.Contains(got, "precision") {
+			t.Errorf("url contains a precision when none is set: got %s", got)
+		} else if !strings.HasSuffix(got, want) {
+			t.Errorf("url does not contain correct precision for %q: looking for %s in %s", precision, want, got)
+		}
+	}
+}
+
+func TestNewHTTPWriterAPIVersions(t *testing.T) {
+	cases := []struct {
+		desc          string
+		conf          HTTPWriterConfig
+		wantURL       string
+		wantAuthorize string
+	}{
+		{
+			desc:    "version 1",
+			conf:    HTTPWriterConfig{Host: "http://h", Database: "db", Precision: "us", Token: "t"},
+			wantURL: "http://h/write?consistency=one&db=db&precision=u",
+		},
+		{
+			desc:          "version 2",
+			conf:          HTTPWriterConfig{Host: "http://h", Database: "db", Precision: "us", APIVersion: 2, Org: "my org", Token: "t"},
+			wantURL:       "http://h/api/v2/write?org=my+org&bucket=db&precision=us",
+			wantAuthorize: "Token t",
+		},
+		{
+			desc:          "version 3",
+			conf:          HTTPWriterConfig{Host: "http://h", Database: "db", Precision: "us", APIVersion: 3, Token: "t"},
+			wantURL:       "http://h/api/v3/write_lp?db=db&precision=microsecond",
+			wantAuthorize: "Bearer t",
+		},
+		{
+			desc:    "version 3 without token",
+			conf:    HTTPWriterConfig{Host: "http://h", Database: "db", APIVersion: 3},
+			wantURL: "http://h/api/v3/write_lp?db=db",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			w := NewHTTPWriter(c.conf, testConsistency)
+			if got := string(w.url); got != c.wantURL {
+				t.Errorf("incorrect url: got %s want %s", got, c.wantURL)
+			}
+			req := fasthttp.AcquireRequest()
+			defer fasthttp.ReleaseRequest(req)
+			w.initializeReq(req, []byte("body"), false)
+			if got := string(req.Header.Peek(fasthttp.HeaderAuthorization)); got != c.wantAuthorize {
+				t.Errorf("incorrect Authorization header: got '%s' want '%s'", got, c.wantAuthorize)
+			}
+		})
+	}
+}
+
+func TestHTTPWriterBacksOffWhenThrottled(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		w := NewHTTPWriter(HTTPWriterConfig{Host: server.URL, Database: "test", APIVersion: 2, Org: "o"}, testConsistency)
+		if _, err := w.WriteLineProtocol([]byte("body"), false); err != errBackoff {
+			t.Errorf("status %d: incorrect error: got %v want %v", status, err, errBackoff)
+		}
+		server.Close()
+	}
+}
+
 func TestHTTPWriterInitializeReq(t *testing.T) {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
@@ -1,13 +1,16 @@
 package influx
 
 import (
+	"sync"
+	"time"
+
 	"github.com/blagojts/viper"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/spf13/pflag"
-	"time"
 )
 
 func NewTarget() targets.ImplementedTarget {
@@ -23,6 +26,9 @@ func (t *influxTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.Fla
 	flagSet.String(flagPrefix+"consistency", "all", "Write consistency. Must be one of: any, one, quorum, all.")
 	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to sleep between requests when server indicates backpressure is needed.")
 	flagSet.Bool(flagPrefix+"gzip", true, "Whether to gzip encode requests (default true).")
+	flagSet.Int(flagPrefix+"api-version", 1, "HTTP API version of the server: 1 (/write of 1.x), 2 (/api/v2/write of 2.x) or 3 (/api/v3/write_lp of 3.x). The database is the bucket with version 2.")
+	flagSet.String(flagPrefix+"org", "", "Organization of the bucket, required by API version 2.")
+	flagSet.String(flagPrefix+"token", "", "API token to authenticate with API versions 2 and 3.")
 }
 
 func (t *influxTarget) TargetName() string {
@@ -33,6 +39,36 @@ func (t *influxTarget) Serializer() serialize.PointSerializer {
 	return &Serializer{}
 }
 
-func (t *influxTarget) Benchmark(string, *source.DataSourceConfig, *viper.Viper) (targets.Benchmark, error) {
-	panic("not implemented")
+func (t *influxTarget) Benchmark(
+	targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	return NewBenchmark(targetDB, &loadingOptions, dataSourceConfig)
+}
+
+// QueryPool returns the pool of the HTTP queries, whose paths select the
+// InfluxQL, Flux or SQL endpoint.
+func (t *influxTarget) QueryPool() *sync.Pool {
+	return &query.HTTPPool
+}
+
+// QueryProcessor returns the processors sending the queries to the same
+// servers, with the same API version and token, as the writes.
+func (t *influxTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	opts := &QueryOptions{
+		URLs:           loadingOptions.DaemonURLs(),
+		Database:       runner.DatabaseName(),
+		Org:            loadingOptions.Org,
+		Authorization:  Authorization(loadingOptions.APIVersion, loadingOptions.Token),
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
 }
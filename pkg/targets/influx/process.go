@@ -0,0 +1,131 @@
+package influx
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+	
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/valyala/fasthttp"
+)
+
+const backingOffChanCap = 100
+
+// allows for testing
+var printFn = fmt.Printf
+
+type processor struct {
+	dbName         string
+	opts           *LoadingOptions
+	precision      string
+	backingOffChan chan bool
+	backingOffDone chan struct{}
+	httpWriter     *HTTPWriter
+	retryPolicy    *load.RetryPolicy
+}
+
+func (p *processor) Init(numWorker int, _, _ bool) {
+	daemonURLs := p.opts.DaemonURLs()
+	daemonURL := daemonURLs[numWorker%len(daemonURLs)]
+	cfg := HTTPWriterConfig{
+		DebugInfo:  fmt.Sprintf("worker #%d, dest url: %s", numWorker, daemonURL),
+		Host:       daemonURL,
+		Database:   p.dbName,
+		Precision:  p.precision,
+		APIVersion: p.opts.APIVersion,
+		Org:        p.opts.Org,
+		Token:      p.opts.Token,
+	}
+	w := NewHTTPWriter(cfg, p.opts.Consistency)
+	p.initWithHTTPWriter(numWorker, w)
+}
+
+func (p *processor) initWithHTTPWriter(numWorker int, w *HTTPWriter) {
+	p.backingOffChan = make(chan bool, backingOffChanCap)
+	p.backingOffDone = make(chan struct{})
+	p.httpWriter = w
+	go p.processBackoffMessages(numWorker)
+}
+
+func (p *processor) Close(_ bool) {
+	close(p.backingOffChan)
+	<-p.backingOffDone
+}
+
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures writes the batch, retrying failed writes according
+// to the retry policy. A batch that still could not be written is counted as failed.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
+	batch := b.(*batch)
+	metricCnt := batch.metrics
+	rowCnt := uint64(batch.rows)
+	
+	var err error
+	if doLoad {
+		err = p.retryPolicy.Do(func() error {
+			return p.write(batch)
+		})
+	}
+	
+	// Return the batch buffer to the pool.
+	batch.buf.Reset()
+	bufPool.Put(batch.buf)
+	if err != nil {
+		printFn("Error writing: %s\n", err.Error())
+		return 0, 0, metricCnt, rowCnt
+	}
+	return metricCnt, rowCnt, 0, 0
+}
+
+// write writes the batch once. When the server asks to back off, it waits for
+// the backoff and the write is retried like other retryable failures.
+func (p *processor) write(batch *batch) error {
+	var err error
+	if p.opts.UseGzip {
+		compressedBatch := bufPool.Get().(*bytes.Buffer)
+		fasthttp.WriteGzip(compressedBatch, batch.buf.Bytes())
+		_, err = p.httpWriter.WriteLineProtocol(compressedBatch.Bytes(), true)
+		// Return the compressed batch buffer to the pool.
+		compressedBatch.Reset()
+		bufPool.Put(compressedBatch)
+	} else {
+		_, err = p.httpWriter.WriteLineProtocol(batch.buf.Bytes(), false)
+	}
+	
+	if err == errBackoff {
+		p.backingOffChan <- true
+		time.Sleep(p.opts.Backoff)
+		return load.Retryable(err)
+	}
+	p.backingOffChan <- false
+	return err
+}
+
+func (p *processor) processBackoffMessages(workerID int) {
+	var totalBackoffSecs float64
+	var start time.Time
+	last := false
+	for this := range p.backingOffChan {
+		if this && !last {
+			start = time.Now()
+			last = true
+		} else if !this && last {
+			took := time.Now().Sub(start)
+			printFn("[worker %d] backoff took %.02fsec\n", workerID, took.Seconds())
+			totalBackoffSecs += took.Seconds()
+			last = false
+			start = time.Now()
+		}
+	}
+	printFn("[worker %d] backoffs took a total of %fsec of runtime\n", workerID, totalBackoffSecs)
+	p.backingOffDone <- struct{}{}
+}
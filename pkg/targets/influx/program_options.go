@@ -0,0 +1,54 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConsistencyChoices are the write consistencies of API version 1.
+var ConsistencyChoices = []string{"any", "one", "quorum", "all"}
+
+// defaultScannerBufferSize is the initial size of the buffer of the lines of
+// a data file, which grows up to four times this size.
+const defaultScannerBufferSize = 1024 * 1024
+
+// Loading option vars:
+type LoadingOptions struct {
+	URLs              string        `yaml:"urls"`
+	ReplicationFactor int           `yaml:"replication-factor" mapstructure:"replication-factor"`
+	Consistency       string        `yaml:"consistency"`
+	Backoff           time.Duration `yaml:"backoff"`
+	UseGzip           bool          `yaml:"gzip" mapstructure:"gzip"`
+	APIVersion        int           `yaml:"api-version" mapstructure:"api-version"`
+	Org               string        `yaml:"org"`
+	Token             string        `yaml:"token"`
+	ScannerBufferSize int           `yaml:"scanner-buffer-size,omitempty" mapstructure:"scanner-buffer-size"`
+}
+
+// Validate checks the consistency, the API version and the URLs of the options.
+func (o *LoadingOptions) Validate() error {
+	valid := false
+	for _, c := range ConsistencyChoices {
+		valid = valid || c == o.Consistency
+	}
+	if !valid {
+		return fmt.Errorf("invalid consistency '%s', choices: %v", o.Consistency, ConsistencyChoices)
+	}
+	if o.APIVersion < 1 || o.APIVersion > 3 {
+		return fmt.Errorf("invalid api version: %d", o.APIVersion)
+	}
+	if o.APIVersion == 2 && o.Org == "" {
+		return fmt.Errorf("missing 'org' flag, required by api version 2")
+	}
+	if o.URLs == "" {
+		return fmt.Errorf("missing 'urls' flag")
+	}
+	return nil
+}
+
+// DaemonURLs returns the URLs of the servers, which the workers write to in
+// a round-robin fashion.
+func (o *LoadingOptions) DaemonURLs() []string {
+	return strings.Split(o.URLs, ",")
+}
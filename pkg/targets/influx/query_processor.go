@@ -0,0 +1,74 @@
+package influx
+
+import (
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// QueryOptions holds the settings of the query processors.
+type QueryOptions struct {
+	// URLs are the servers the workers send the queries to in a round-robin fashion
+	URLs      []string
+	Database  string
+	ChunkSize uint64
+	// Org is the organization the Flux queries run in
+	Org string
+	// Authorization is the value of the Authorization header, see Authorization
+	Authorization  string
+	Debug          int
+	PrintResponses bool
+}
+
+type queryProcessor struct {
+	w    *HTTPClient
+	opts *QueryOptions
+	do   *HTTPClientDoOptions
+}
+
+// NewQueryProcessor returns a query.Processor that runs the InfluxQL, Flux or
+// SQL queries of InfluxDB with its HTTP API.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(workerNumber int) {
+	p.do = &HTTPClientDoOptions{
+		Debug:                p.opts.Debug,
+		PrettyPrintResponses: p.opts.PrintResponses,
+		chunkSize:            p.opts.ChunkSize,
+		database:             p.opts.Database,
+		org:                  p.opts.Org,
+		authorization:        p.opts.Authorization,
+	}
+	p.w = NewHTTPClient(p.opts.URLs[workerNumber%len(p.opts.URLs)])
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
+	hq := q.(*query.HTTP)
+	lag, _, err := p.w.Do(hq, p.do)
+	if err != nil {
+		return nil, err
+	}
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	return []*query.Stat{stat}, nil
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, _ bool) ([]*query.Stat, *query.ResultSet, error) {
+	hq := q.(*query.HTTP)
+	lag, body, err := p.w.Do(hq, p.do)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rs *query.ResultSet
+	if queryLanguage(hq.Path) == languageInfluxQL {
+		rs, err = parseResponse(body)
+	} else {
+		rs, err = parseCSVResponse(body, queryLanguage(hq.Path) == languageFlux)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	return []*query.Stat{stat}, rs, nil
+}
@@ -0,0 +1,154 @@
+package influx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// influxResponse is the JSON document returned by the InfluxDB /query endpoint.
+// Chunked responses consist of several such documents one after another.
+type influxResponse struct {
+	Results []struct {
+		Error  string `json:"error"`
+		Series []struct {
+			Name    string            `json:"name"`
+			Tags    map[string]string `json:"tags"`
+			Columns []string          `json:"columns"`
+			Values  [][]interface{}   `json:"values"`
+		} `json:"series"`
+	} `json:"results"`
+	Error string `json:"error"`
+}
+
+// parseResponse converts the body of an InfluxDB /query response into a
+// query.ResultSet. The tags of grouped series are prepended to every row.
+func parseResponse(body []byte) (*query.ResultSet, error) {
+	var rs *query.ResultSet
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	for {
+		var resp influxResponse
+		err := decoder.Decode(&resp)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode response: %v", err)
+		}
+		if resp.Error != "" {
+			return nil, fmt.Errorf("query returned error: %s", resp.Error)
+		}
+		for _, result := range resp.Results {
+			if result.Error != "" {
+				return nil, fmt.Errorf("query returned error: %s", result.Error)
+			}
+			for _, series := range result.Series {
+				tagKeys := make([]string, 0, len(series.Tags))
+				for k := range series.Tags {
+					tagKeys = append(tagKeys, k)
+				}
+				sort.Strings(tagKeys)
+				if rs == nil {
+					rs = query.NewResultSet(append(tagKeys, series.Columns...)...)
+				}
+				for _, values := range series.Values {
+					row := make([]interface{}, 0, len(tagKeys)+len(values))
+					for _, k := range tagKeys {
+						row = append(row, series.Tags[k])
+					}
+					rs.AppendRow(append(row, values...)...)
+				}
+			}
+		}
+	}
+	if rs == nil {
+		rs = query.NewResultSet()
+	}
+	return rs, nil
+}
+
+// fluxAnnotationColumns are the columns of the Flux CSV responses that tell
+// the tables apart and the range the query read, rather than holding results.
+var fluxAnnotationColumns = map[string]bool{
+	"":       true,
+	"result": true,
+	"table":  true,
+	"_start": true,
+	"_stop":  true,
+}
+
+// parseCSVResponse converts the body of a Flux (/api/v2/query) or SQL
+// (/api/v3/query_sql) CSV response into a query.ResultSet. Flux responses hold
+// one CSV table after another, separated by empty lines and each with its own
+// header: the rows of all of them are mapped to the columns of the first one.
+func parseCSVResponse(body []byte, flux bool) (*query.ResultSet, error) {
+	var rs *query.ResultSet
+	for _, table := range splitCSVTables(body) {
+		records, err := csv.NewReader(bytes.NewReader(table)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode response: %v", err)
+		}
+		if len(records) == 0 {
+			continue
+		}
+		header := records[0]
+		if flux && len(header) > 0 && header[0] == "error" {
+			if len(records) > 1 {
+				return nil, fmt.Errorf("query returned error: %s", records[1][0])
+			}
+			return nil, fmt.Errorf("query returned error")
+		}
+		index := make(map[string]int, len(header))
+		for i, c := range header {
+			index[c] = i
+		}
+		if rs == nil {
+			columns := []string{}
+			for _, c := range header {
+				if !flux || !fluxAnnotationColumns[c] {
+					columns = append(columns, c)
+				}
+			}
+			rs = query.NewResultSet(columns...)
+		}
+		for _, record := range records[1:] {
+			values := make([]interface{}, len(rs.Columns))
+			for i, c := range rs.Columns {
+				if j, ok := index[c]; ok && j < len(record) {
+					values[i] = record[j]
+				}
+			}
+			rs.AppendRow(values...)
+		}
+	}
+	if rs == nil {
+		rs = query.NewResultSet()
+	}
+	return rs, nil
+}
+
+// splitCSVTables splits a CSV response into its tables at the empty lines.
+func splitCSVTables(body []byte) [][]byte {
+	tables := [][]byte{}
+	var table []byte
+	for _, line := range bytes.SplitAfter(body, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			if len(table) > 0 {
+				tables = append(tables, table)
+			}
+			table = nil
+			continue
+		}
+		table = append(table, line...)
+	}
+	if len(table) > 0 {
+		tables = append(tables, table)
+	}
+	return tables
+}
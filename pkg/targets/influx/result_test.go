@@ -0,0 +1,64 @@
+package influx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCSVResponseFlux(t *testing.T) {
+	body := ",result,table,_start,_stop,name,driver,latitude\r\n" +
+		",_result,0,1970-01-01T00:00:00Z,2022-01-01T00:00:00Z,truck_0,Seth,1.5\r\n" +
+		"\r\n" +
+		",result,table,_start,_stop,latitude,name\r\n" +
+		",_result,1,1970-01-01T00:00:00Z,2022-01-01T00:00:00Z,2.5,truck_1\r\n" +
+		"\r\n"
+	rs, err := parseCSVResponse([]byte(body), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"name", "driver", "latitude"}; !reflect.DeepEqual(rs.Columns, want) {
+		t.Errorf("incorrect columns: got %v want %v", rs.Columns, want)
+	}
+	want := [][]interface{}{
+		{"truck_0", "Seth", "1.5"},
+		{"truck_1", nil, "2.5"},
+	}
+	if !reflect.DeepEqual(rs.Rows, want) {
+		t.Errorf("incorrect rows: got %v want %v", rs.Rows, want)
+	}
+
+	if _, err := parseCSVResponse([]byte("error,reference\r\nbucket not found,\r\n"), true); err == nil {
+		t.Errorf("expected an error for a Flux error response")
+	}
+}
+
+func TestParseCSVResponseSQL(t *testing.T) {
+	rs, err := parseCSVResponse([]byte("name,driver\ntruck_0,Seth\ntruck_1,Trish\n"), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"name", "driver"}; !reflect.DeepEqual(rs.Columns, want) {
+		t.Errorf("incorrect columns: got %v want %v", rs.Columns, want)
+	}
+	if len(rs.Rows) != 2 || rs.Rows[1][1] != "Trish" {
+		t.Errorf("incorrect rows: %v", rs.Rows)
+	}
+
+	rs, err = parseCSVResponse([]byte(""), false)
+	if err != nil || len(rs.Columns) != 0 || len(rs.Rows) != 0 {
+		t.Errorf("incorrect empty response: %v %v", rs, err)
+	}
+}
+
+func TestQueryLanguage(t *testing.T) {
+	cases := map[string]string{
+		"/query?q=SELECT+1":            languageInfluxQL,
+		"/api/v2/query":                languageFlux,
+		"/api/v3/query_sql?q=SELECT+1": languageSQL,
+	}
+	for path, want := range cases {
+		if got := queryLanguage([]byte(path)); got != want {
+			t.Errorf("incorrect language of %s: got %s want %s", path, got, want)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package influx
 
 import (
 	"bufio"
@@ -49,6 +49,17 @@ func TestBatch(t *testing.T) {
 		t.Errorf("batch metric count is not 2 after first append")
 	}
 	
+	p = data.LoadedPoint{
+		Data: []byte(`cpu,host=a\ b,tag2=x state="idle, waiting",col1=1.0 190`),
+	}
+	b.Append(p)
+	if b.rows != 3 {
+		t.Errorf("batch row count is not 3 after append with escapes")
+	}
+	if b.metrics != 6 {
+		t.Errorf("batch metric count is not 6 after append with escapes")
+	}
+	
 	p = data.LoadedPoint{
 		Data: []byte("bad_point"),
 	}
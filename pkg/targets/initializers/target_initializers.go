@@ -5,12 +5,16 @@ import (
 	"strings"
 
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/clickhouse"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/cnosdb"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/influx"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/iotdb"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/prometheus"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/questdb"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/tdengine"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/timescaledb"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/victoriametrics"
 )
 
 func GetTarget(format string) targets.ImplementedTarget {
@@ -26,6 +30,14 @@ func GetTarget(format string) targets.ImplementedTarget {
 
 	case constants.FormatIOTDB:
 		return iotdb.NewTarget()
+	case constants.FormatQuestDB:
+		return questdb.NewTarget()
+	case constants.FormatVictoriaMetrics:
+		return victoriametrics.NewTarget()
+	case constants.FormatClickHouse:
+		return clickhouse.NewTarget()
+	case constants.FormatPrometheus:
+		return prometheus.NewTarget()
 	}
 	supportedFormatsStr := strings.Join(constants.SupportedFormats(), ",")
 	panic(fmt.Sprintf("Unrecognized format %s, supported: %s", format, supportedFormatsStr))
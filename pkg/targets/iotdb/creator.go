@@ -19,6 +19,9 @@ var fatal = log.Fatalf
 
 var tableCols = make(map[string][]string)
 
+// tableColTypes holds the types of the columns of tableCols, see common.FieldType
+var tableColTypes = make(map[string][]string)
+
 type LoadingOptions struct {
 	Host       string `yaml:"host"`
 	User       string
@@ -83,6 +86,7 @@ func (d *dbCreator) PostCreateDB(dbName string) error {
 	for tableName, columns := range headers.FieldKeys {
 		// tableCols is a global map. Globally cache the available columns for the given table
 		tableCols[tableName] = columns
+		tableColTypes[tableName] = headers.FieldTypesOf(tableName)
 
 		path := "root." + dbName + "." + tableName
 		d.session.DeleteTimeseries([]string{path})
@@ -99,12 +103,27 @@ func (d *dbCreator) PostCreateDB(dbName string) error {
 			d.session.CreateTimeseries(path+"."+name, dataType, encoding, compressor, nil, nil)
 		}
 
-		for _, name := range columns {
-			dataType := client.FLOAT
-			encoding := client.GORILLA
+		for i, name := range columns {
+			dataType, encoding := fieldTypeToDataType(tableColTypes[tableName][i])
 			compressor := client.SNAPPY
 			d.session.CreateTimeseries(path+"."+name, dataType, encoding, compressor, nil, nil)
 		}
 	}
 	return nil
 }
+
+// fieldTypeToDataType returns the data type and encoding of the time series of
+// a field type. IoTDB has no unsigned integers, so uint64 values are written
+// as INT64 and have to fit in one.
+func fieldTypeToDataType(fieldType string) (client.TSDataType, client.TSEncoding) {
+	switch fieldType {
+	case "int64", "uint64":
+		return client.INT64, client.TS_2DIFF
+	case "bool":
+		return client.BOOLEAN, client.RLE
+	case "string":
+		return client.TEXT, client.PLAIN
+	default:
+		return client.FLOAT, client.GORILLA
+	}
+}
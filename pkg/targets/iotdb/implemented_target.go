@@ -1,9 +1,13 @@
 package iotdb
 
 import (
+	"sync"
+
+	"github.com/apache/iotdb-client-go/client"
 	"github.com/blagojts/viper"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/spf13/pflag"
@@ -34,6 +38,27 @@ func (t *tdengineTarget) Benchmark(
 	return NewBenchmark(targetDB, &loadingOptions, dataSourceConfig)
 }
 
+func (t *tdengineTarget) QueryPool() *sync.Pool {
+	return &query.IoTDBPool
+}
+
+func (t *tdengineTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	opts := &QueryOptions{
+		Host:           loadingOptions.Host,
+		Port:           loadingOptions.Port,
+		User:           loadingOptions.User,
+		Pass:           loadingOptions.Pass,
+		FetchSize:      client.DefaultFetchSize,
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
+}
+
 func (t *tdengineTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
 	flagSet.String(flagPrefix+"host", "127.0.0.1", "Hostname of TimescaleDB (tdengine) instance")
 	flagSet.String(flagPrefix+"port", "6041", "Which port to connect to on the database host")
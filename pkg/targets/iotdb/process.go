@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/apache/iotdb-client-go/client"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 )
 
@@ -19,7 +21,8 @@ type processor struct {
 	opts   *LoadingOptions
 	dbName string
 
-	session client.Session
+	session     client.Session
+	retryPolicy *load.RetryPolicy
 }
 
 func newProcessor(opts *LoadingOptions, dbName string) *processor {
@@ -45,27 +48,50 @@ func (p *processor) Init(_ int, doLoad, hashWorkers bool) {
 
 func (p *processor) Close(doLoad bool) {}
 
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
 func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures writes the rows of every hypertable of the batch
+// with a batch statement. The rows of a hypertable that could not be written,
+// even after retrying, are counted as failed.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
 	batches := b.(*hypertableArr)
-	rowCnt := 0
+	rowCnt := uint64(0)
 	metricCnt := uint64(0)
+	failedRowCnt := uint64(0)
+	failedMetricCnt := uint64(0)
 	for hypertable, rows := range batches.m {
-		rowCnt += len(rows)
-		if doLoad {
-			start := time.Now()
-			metricCnt += p.processCSI(hypertable, rows)
-
-			if p.opts.LogBatches {
-				now := time.Now()
-				took := now.Sub(start)
-				batchSize := len(rows)
-				fmt.Printf("BATCH: batchsize %d row rate %f/sec (took %v)\n", batchSize, float64(batchSize)/float64(took.Seconds()), took)
-			}
+		if !doLoad {
+			rowCnt += uint64(len(rows))
+			continue
+		}
+		start := time.Now()
+		numMetrics, err := p.processCSI(hypertable, rows)
+		if err != nil {
+			fmt.Printf("Error writing %d rows to %s: %v\n", len(rows), hypertable, err)
+			failedRowCnt += uint64(len(rows))
+			failedMetricCnt += numMetrics
+			continue
+		}
+		rowCnt += uint64(len(rows))
+		metricCnt += numMetrics
+
+		if p.opts.LogBatches {
+			now := time.Now()
+			took := now.Sub(start)
+			batchSize := len(rows)
+			fmt.Printf("BATCH: batchsize %d row rate %f/sec (took %v)\n", batchSize, float64(batchSize)/float64(took.Seconds()), took)
 		}
 	}
 	batches.m = map[string][]*insertData{}
 	batches.cnt = 0
-	return metricCnt, uint64(rowCnt)
+	return metricCnt, rowCnt, failedMetricCnt, failedRowCnt
 }
 
 // tags,name=truck_0,fleet=South,driver=Trish,model=H-2,device_version=v2.3,load_capacity=1500,fuel_capacity=150,nominal_fuel_consumption=12
@@ -74,47 +100,102 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 // ("truck_1", "South", "Albert", "F-150", "v1.5",2000,200,15) VALUES (now, 11.2, 12.19,1);
 
 // insert into root.ln.wf02.wt02(time,s5) values(1,true)
-func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
+func (p *processor) processCSI(hypertable string, rows []*insertData) (uint64, error) {
 	colLen := len(tableCols[hypertable])
 	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, colLen)
 
 	sqls := make([]string, len(rows))
 	for i, tagvals := range tagRows {
+		cols, vals := nonEmptyColumns(tableCols[hypertable], tableColTypes[hypertable], dataRows[i])
+		nodes := make([]string, len(tagvals))
+		for j, v := range tagvals {
+			nodes[j] = pathNode(v)
+		}
 		sql := fmt.Sprintf("insert into root.%s.%s.%s (timestamp, %s) values (%s)",
-			p.dbName, hypertable, strings.Join(tagvals, "."),
-			strings.Join(tableCols[hypertable], ","), dataRows[i])
+			p.dbName, hypertable, strings.Join(nodes, "."),
+			strings.Join(cols, ","), strings.Join(vals, ","))
 
 		sqls[i] = sql
 
 		//fmt.Printf("===%s\n", sql)
 	}
 
-	p.session.ExecuteBatchStatement(sqls)
+	err := p.retryPolicy.Do(func() error {
+		status, err := p.session.ExecuteBatchStatement(sqls)
+		if err != nil {
+			// the statements did not reach the server or got no response
+			return load.Retryable(err)
+		}
+		return client.VerifySuccess(status)
+	})
 
-	return numMetrics
+	return numMetrics, err
 }
 
-func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]string, []string, uint64) {
+func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]string, [][]string, uint64) {
 	tagRows := make([][]string, 0, len(rows))
-	dataRows := make([]string, 0, len(rows))
+	dataRows := make([][]string, 0, len(rows))
 	numMetrics := uint64(0)
 	commonTagsLen := len(tableCols[tagsKey])
 
 	for _, data := range rows {
-		tags := strings.SplitN(data.tags, ",", commonTagsLen+1)
+		tags := serialize.SplitCSV(data.tags, commonTagsLen+1)
 		for i := 0; i < commonTagsLen; i++ {
-			tags[i] = strings.Split(tags[i], "=")[1]
+			tags[i] = strings.SplitN(tags[i], "=", 2)[1]
 		}
 
-		metrics := strings.Split(data.fields, ",")
+		metrics := serialize.SplitCSV(data.fields, -1)
 		numMetrics += uint64(len(metrics) - 1) // 1 field is timestamp
 
 		timeInt, _ := strconv.ParseInt(metrics[0], 10, 64)
 		metrics[0] = strconv.FormatInt(timeInt/1000000, 10)
 
-		dataRows = append(dataRows, strings.Join(metrics, ","))
+		dataRows = append(dataRows, metrics)
 		tagRows = append(tagRows, tags[:commonTagsLen])
 	}
 
 	return tagRows, dataRows, numMetrics
 }
+
+// pathNode returns the tag value v as a node of an IoTDB path. Values that are
+// not plain identifiers, like us-east-1 or Ubuntu16.10 of the devops use case,
+// are quoted with backquotes, doubling the ones they contain, so they are not
+// split into several nodes or rejected.
+func pathNode(v string) string {
+	plain := v != ""
+	digitsOnly := true
+	for _, c := range v {
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+			digitsOnly = false
+		default:
+			plain = false
+		}
+	}
+	if plain && !digitsOnly {
+		return v
+	}
+	return "`" + strings.ReplaceAll(v, "`", "``") + "`"
+}
+
+// nonEmptyColumns drops the empty (NULL) fields of a data row, whose first
+// value is the timestamp, along with their column names: IoTDB only takes the
+// measurements that have a value in an insert statement. The values of string
+// fields are quoted, with their quotes doubled.
+func nonEmptyColumns(cols, types []string, row []string) ([]string, []string) {
+	vals := make([]string, 1, len(row))
+	vals[0] = row[0]
+	nonEmpty := make([]string, 0, len(cols))
+	for i, v := range row[1:] {
+		if v == "" || v == "NULL" || i >= len(cols) {
+			continue
+		}
+		if i < len(types) && types[i] == "string" {
+			v = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+		nonEmpty = append(nonEmpty, cols[i])
+		vals = append(vals, v)
+	}
+	return nonEmpty, vals
+}
@@ -0,0 +1,37 @@
+package iotdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathNode(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "host_0", want: "host_0"},
+		{in: "us-east-1", want: "`us-east-1`"},
+		{in: "Ubuntu16.10", want: "`Ubuntu16.10`"},
+		{in: "v2.3", want: "`v2.3`"},
+		{in: "42", want: "`42`"},
+		{in: "a`b", want: "`a``b`"},
+	}
+	for _, c := range cases {
+		if got := pathNode(c.in); got != c.want {
+			t.Errorf("incorrect node for %s: got %s want %s", c.in, got, c.want)
+		}
+	}
+}
+
+func TestNonEmptyColumns(t *testing.T) {
+	cols := []string{"status", "state", "note"}
+	types := []string{"int64", "string", "string"}
+	gotCols, gotVals := nonEmptyColumns(cols, types, []string{"1640995200000", "1", "a,b'c", ""})
+	if want := []string{"status", "state"}; !reflect.DeepEqual(gotCols, want) {
+		t.Errorf("incorrect columns: got %v want %v", gotCols, want)
+	}
+	if want := []string{"1640995200000", "1", "'a,b''c'"}; !reflect.DeepEqual(gotVals, want) {
+		t.Errorf("incorrect values: got %v want %v", gotVals, want)
+	}
+}
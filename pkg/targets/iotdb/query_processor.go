@@ -0,0 +1,167 @@
+package iotdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/iotdb-client-go/client"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// QueryOptions holds the settings of the query processors, which use one
+// IoTDB session per worker.
+type QueryOptions struct {
+	Host           string
+	Port           string
+	User           string
+	Pass           string
+	FetchSize      int32
+	Timeout        time.Duration
+	Debug          bool
+	PrintResponses bool
+}
+
+type queryProcessor struct {
+	opts      *QueryOptions
+	session   client.Session
+	timeoutMs *int64
+}
+
+// NewQueryProcessor returns a query.Processor that runs IoTDB queries.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(workerNumber int) {
+	config := &client.Config{
+		Host:      p.opts.Host,
+		Port:      p.opts.Port,
+		UserName:  p.opts.User,
+		Password:  p.opts.Pass,
+		FetchSize: p.opts.FetchSize,
+	}
+	p.session = client.NewSession(config)
+	if err := p.session.Open(false, 0); err != nil {
+		panic(fmt.Sprintf("worker %d: connect to iotdb %s:%s failed: %v", workerNumber, p.opts.Host, p.opts.Port, err))
+	}
+	if p.opts.Timeout > 0 {
+		ms := p.opts.Timeout.Milliseconds()
+		p.timeoutMs = &ms
+	}
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	stats, _, err := p.execute(q, false)
+	return stats, err
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, isWarm bool) ([]*query.Stat, *query.ResultSet, error) {
+	return p.execute(q, true)
+}
+
+// execute runs the query and, if collect is set, returns all the fetched rows.
+func (p *queryProcessor) execute(q query.Query, collect bool) ([]*query.Stat, *query.ResultSet, error) {
+	iq := q.(*query.IoTDB)
+
+	start := time.Now()
+	qry := string(iq.SqlQuery)
+	if p.opts.Debug {
+		fmt.Println(qry)
+	}
+	ds, err := p.session.ExecuteQueryStatement(qry, p.timeoutMs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rs *query.ResultSet
+	if collect || p.opts.PrintResponses {
+		rs = newResultSet(ds)
+	}
+	// Fetching all the rows to confirm that the query is fully completed.
+	for {
+		ok, err := ds.Next()
+		if err != nil {
+			ds.Close()
+			return nil, nil, err
+		}
+		if !ok {
+			break
+		}
+		if rs != nil {
+			if err := appendRow(rs, ds); err != nil {
+				ds.Close()
+				return nil, nil, err
+			}
+		}
+	}
+	if err := ds.Close(); err != nil {
+		return nil, nil, err
+	}
+	took := float64(time.Since(start).Nanoseconds()) / 1e6
+
+	if p.opts.PrintResponses {
+		prettyPrintResponse(rs, iq)
+	}
+
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), took)
+
+	return []*query.Stat{stat}, rs, nil
+}
+
+// prettyPrintResponse prints a Query and its response in JSON format with two
+// keys: 'query' which has a value of the SQL used to generate the second key
+// 'results' which is an array of each row in the return set.
+func prettyPrintResponse(rs *query.ResultSet, q *query.IoTDB) {
+	results := make([]map[string]interface{}, 0, len(rs.Rows))
+	for _, values := range rs.Rows {
+		row := make(map[string]interface{})
+		for i, column := range rs.Columns {
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	resp := make(map[string]interface{})
+	resp["query"] = string(q.SqlQuery)
+	resp["results"] = results
+
+	line, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(line) + "\n")
+}
+
+// newResultSet returns an empty query.ResultSet with the columns of ds. Unless
+// the data set ignores timestamps, the first column is the row time.
+func newResultSet(ds *client.SessionDataSet) *query.ResultSet {
+	var columns []string
+	if !ds.IsIgnoreTimeStamp() {
+		columns = append(columns, client.TimestampColumnName)
+	}
+	columns = append(columns, ds.GetColumnNames()...)
+	return query.NewResultSet(columns...)
+}
+
+// appendRow adds the current row of ds to rs.
+func appendRow(rs *query.ResultSet, ds *client.SessionDataSet) error {
+	record, err := ds.GetRowRecord()
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, 0, len(rs.Columns))
+	if !ds.IsIgnoreTimeStamp() {
+		values = append(values, time.Unix(0, record.GetTimestamp()*int64(time.Millisecond)).UTC())
+	}
+	for _, field := range record.GetFields() {
+		if field.IsNull() {
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, field.GetValue())
+	}
+	rs.AppendRow(values...)
+	return nil
+}
@@ -28,7 +28,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 		buf = append(buf, ',')
 		buf = append(buf, tagKeys[i]...)
 		buf = append(buf, '=')
-		buf = serialize.FastFormatAppend(v, buf)
+		buf = serialize.CSVFormatAppend(v, buf)
 	}
 	buf = append(buf, '\n')
 	_, err := w.Write(buf)
@@ -44,7 +44,7 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	fieldValues := p.FieldValues()
 	for _, v := range fieldValues {
 		buf = append(buf, ',')
-		buf = serialize.FastFormatAppend(v, buf)
+		buf = serialize.CSVFormatAppend(v, buf)
 	}
 	buf = append(buf, '\n')
 	_, err = w.Write(buf)
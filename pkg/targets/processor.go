@@ -14,3 +14,12 @@ type ProcessorCloser interface {
 	// Close cleans up after a Processor
 	Close(doLoad bool)
 }
+
+// ProcessorWithFailures is a Processor that tells apart the data of a batch
+// that was written from the data that could not be written
+type ProcessorWithFailures interface {
+	Processor
+	// ProcessBatchWithFailures handles a single batch of data, returning the
+	// number of metrics and rows written and the number that failed
+	ProcessBatchWithFailures(b Batch, doLoad bool) (metricCount, rowCount, failedMetricCount, failedRowCount uint64)
+}
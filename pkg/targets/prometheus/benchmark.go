@@ -0,0 +1,57 @@
+package prometheus
+
+import (
+	"github.com/cnosdb/tsdb-comparisons/internal/inputs"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+type benchmark struct {
+	opts *LoadingOptions
+	ds   targets.DataSource
+}
+
+func NewBenchmark(opts *LoadingOptions, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		var err error
+		if ds, err = newFileDataSource(dataSourceConfig.File.Location); err != nil {
+			return nil, err
+		}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	return &benchmark{
+		opts: opts,
+		ds:   ds,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return &seriesIndexer{partitions: maxPartitions}
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return newProcessor(b.opts)
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{}
+}
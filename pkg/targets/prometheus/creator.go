@@ -0,0 +1,30 @@
+package prometheus
+
+import "log"
+
+// allows for testing
+var fatal = log.Fatalf
+
+// dbCreator stands in for the creation of the benchmark database:
+// remote_write has no notion of databases, the series are created by the
+// first writes. A receiver that stores the series in a database takes it from
+// the write URL, e.g. a db query argument, and must have it created
+// beforehand.
+type dbCreator struct{}
+
+func (d *dbCreator) Init() {}
+
+// DBExists returns false, the series cannot be listed over remote_write.
+func (d *dbCreator) DBExists(_ string) bool {
+	return false
+}
+
+// RemoveOldDB does nothing, remote_write cannot delete series.
+func (d *dbCreator) RemoveOldDB(_ string) error {
+	return nil
+}
+
+// CreateDB does nothing, the series are created by the first writes.
+func (d *dbCreator) CreateDB(_ string) error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package prometheus
+
+import (
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/spf13/pflag"
+)
+
+// NewTarget returns the target loading data over the Prometheus remote_write
+// protocol, into any receiver of it. It has no queries.
+func NewTarget() targets.ImplementedTarget {
+	return &prometheusTarget{}
+}
+
+type prometheusTarget struct {
+}
+
+func (t *prometheusTarget) TargetName() string {
+	return constants.FormatPrometheus
+}
+
+func (t *prometheusTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *prometheusTarget) Benchmark(
+	_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	return NewBenchmark(&loadingOptions, dataSourceConfig)
+}
+
+func (t *prometheusTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"write-url", "http://localhost:9090/api/v1/write", "URL of the remote_write endpoint the batches are written to")
+}
@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+)
+
+const (
+	errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
+	errNotNanosecondsFmt = "timestamp precision '%s' of the data file is not supported, generate it with nanosecond timestamps"
+)
+
+// parseLine parses a line of line protocol, as written by the Serializer,
+// into p. Integer, unsigned and boolean fields keep their type, string fields
+// are strings.
+func parseLine(line []byte, p *data.Point) error {
+	args := serialize.SplitLineProtocol(line, ' ')
+	if len(args) != 3 {
+		return fmt.Errorf(errNotThreeTuplesFmt, len(args))
+	}
+	ns, err := strconv.ParseInt(string(args[2]), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse error: invalid timestamp '%s'", args[2])
+	}
+	ts := time.Unix(0, ns).UTC()
+	p.SetTimestamp(&ts)
+
+	tags := serialize.SplitLineProtocol(args[0], ',')
+	p.SetMeasurementName([]byte(serialize.UnescapeLineProtocol(tags[0])))
+	for _, tag := range tags[1:] {
+		key, value, err := serialize.SplitLineProtocolKeyValue(tag)
+		if err != nil {
+			return fmt.Errorf("parse error: %v", err)
+		}
+		p.AppendTag([]byte(key), serialize.UnescapeLineProtocol(value))
+	}
+
+	for _, field := range serialize.SplitLineProtocol(args[1], ',') {
+		key, raw, err := serialize.SplitLineProtocolKeyValue(field)
+		if err != nil {
+			return fmt.Errorf("parse error: %v", err)
+		}
+		value, err := serialize.ParseLineProtocolFieldValue(raw)
+		if err != nil {
+			return fmt.Errorf("parse error: field '%s': %v", key, err)
+		}
+		p.AppendField([]byte(key), value)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package prometheus
+
+import (
+	"fmt"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/klauspost/compress/snappy"
+	"github.com/valyala/fasthttp"
+)
+
+const httpClientName = "load_prometheus"
+
+// remoteWriteVersion is the version of the remote_write protocol the requests
+// follow.
+const remoteWriteVersion = "0.1.0"
+
+// allows for testing
+var printFn = fmt.Printf
+
+var (
+	methodPost      = []byte("POST")
+	contentType     = []byte("application/x-protobuf")
+	contentEncoding = []byte("snappy")
+)
+
+// httpWriter posts the snappy-compressed WriteRequests to the remote_write
+// endpoint. Receivers answer 204 No Content or 200 OK once a request is
+// accepted.
+type httpWriter struct {
+	client fasthttp.Client
+	url    []byte
+}
+
+func newHTTPWriter(url string) *httpWriter {
+	return &httpWriter{
+		client: fasthttp.Client{Name: httpClientName},
+		url:    []byte(url),
+	}
+}
+
+func (w *httpWriter) Write(body []byte) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetContentTypeBytes(contentType)
+	req.Header.SetBytesV("Content-Encoding", contentEncoding)
+	req.Header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+	req.Header.SetMethodBytes(methodPost)
+	req.Header.SetRequestURIBytes(w.url)
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := w.client.Do(req, resp); err != nil {
+		return load.Retryable(err)
+	}
+	if sc := resp.StatusCode(); sc < 200 || sc > 299 {
+		err := fmt.Errorf("invalid write response (status %d): %s", sc, resp.Body())
+		if load.IsRetryableHTTPStatus(sc) {
+			return load.Retryable(err)
+		}
+		return err
+	}
+	return nil
+}
+
+type processor struct {
+	opts        *LoadingOptions
+	writer      *httpWriter
+	retryPolicy *load.RetryPolicy
+	// buf and compressed hold the WriteRequest of a batch, before and after
+	// its compression; they are reused by the next batches.
+	buf        []byte
+	compressed []byte
+}
+
+func newProcessor(opts *LoadingOptions) targets.Processor {
+	return &processor{opts: opts}
+}
+
+func (p *processor) Init(_ int, _, _ bool) {
+	p.writer = newHTTPWriter(p.opts.WriteURL)
+}
+
+func (p *processor) Close(_ bool) {
+}
+
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures writes the batch as a single WriteRequest, retrying
+// failed writes according to the retry policy. A batch that still could not be
+// written is counted as failed. Every sample is counted as a metric.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
+	batch := b.(*batch)
+	metricCnt := batch.samples
+	rowCnt := uint64(batch.rows)
+	if !doLoad || len(batch.order) == 0 {
+		return metricCnt, rowCnt, 0, 0
+	}
+
+	body := p.body(batch)
+	err := p.retryPolicy.Do(func() error {
+		return p.writer.Write(body)
+	})
+	if err != nil {
+		printFn("Error writing: %s\n", err.Error())
+		return 0, 0, metricCnt, rowCnt
+	}
+	return metricCnt, rowCnt, 0, 0
+}
+
+// body returns the snappy-compressed WriteRequest of the batch.
+func (p *processor) body(batch *batch) []byte {
+	p.buf = appendWriteRequest(p.buf[:0], batch.order)
+	p.compressed = snappy.Encode(p.compressed[:cap(p.compressed)], p.buf)
+	return p.compressed
+}
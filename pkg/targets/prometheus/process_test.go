@@ -0,0 +1,113 @@
+package prometheus
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/klauspost/compress/snappy"
+)
+
+func testBatch(t *testing.T) *batch {
+	b := (&factory{}).New().(*batch)
+	b.Append(data.NewLoadedPoint(parsePoint(t, "readings,name=truck_0 latitude=1,longitude=2 1640995200000000000")))
+	return b
+}
+
+func TestProcessorWritesSnappyWriteRequests(t *testing.T) {
+	var header http.Header
+	var path string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		path = r.URL.Path
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := newProcessor(&LoadingOptions{WriteURL: server.URL + "/api/v1/write"}).(*processor)
+	p.Init(0, true, false)
+	b := testBatch(t)
+	want := appendWriteRequest(nil, b.order)
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 2 || rows != 1 {
+		t.Errorf("incorrect counts: got %d metrics %d rows want 2 and 1", metrics, rows)
+	}
+	if path != "/api/v1/write" {
+		t.Errorf("incorrect path: got %s", path)
+	}
+	for k, v := range map[string]string{
+		"Content-Type":                      "application/x-protobuf",
+		"Content-Encoding":                  "snappy",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	} {
+		if got := header.Get(k); got != v {
+			t.Errorf("incorrect %s header: got %s want %s", k, got, v)
+		}
+	}
+	got, err := snappy.Decode(nil, body)
+	if err != nil {
+		t.Fatalf("cannot decode body: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("incorrect request:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestProcessorDoesNotWriteWithoutLoad(t *testing.T) {
+	p := newProcessor(&LoadingOptions{WriteURL: "http://localhost:1/api/v1/write"}).(*processor)
+	p.Init(0, false, false)
+	metrics, rows, failedMetrics, failedRows := p.ProcessBatchWithFailures(testBatch(t), false)
+	if metrics != 2 || rows != 1 || failedMetrics != 0 || failedRows != 0 {
+		t.Errorf("incorrect counts: got %d %d %d %d want 2 1 0 0", metrics, rows, failedMetrics, failedRows)
+	}
+}
+
+func TestProcessorRetriesFailedWrites(t *testing.T) {
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printed := 0
+	printFn = func(string, ...interface{}) (int, error) {
+		printed++
+		return 0, nil
+	}
+
+	cases := []struct {
+		desc     string
+		status   int
+		attempts int
+	}{
+		{desc: "server error is retried", status: http.StatusServiceUnavailable, attempts: 3},
+		{desc: "bad request is not retried", status: http.StatusBadRequest, attempts: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			printed = 0
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(c.status)
+			}))
+			defer server.Close()
+
+			p := newProcessor(&LoadingOptions{WriteURL: server.URL}).(*processor)
+			p.Init(0, true, false)
+			p.SetRetryPolicy(load.NewRetryPolicy(load.RetryConfig{MaxAttempts: 3}))
+			metrics, rows, failedMetrics, failedRows := p.ProcessBatchWithFailures(testBatch(t), true)
+			if attempts != c.attempts {
+				t.Errorf("incorrect number of attempts: got %d want %d", attempts, c.attempts)
+			}
+			if metrics != 0 || rows != 0 || failedMetrics != 2 || failedRows != 1 {
+				t.Errorf("incorrect counts: got %d %d %d %d want 0 0 2 1", metrics, rows, failedMetrics, failedRows)
+			}
+			if printed != 1 {
+				t.Errorf("printFn called incorrect # of times: got %d want 1", printed)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package prometheus
+
+// Loading option vars:
+type LoadingOptions struct {
+	// WriteURL is the remote_write endpoint of the receiver, e.g.
+	// http://localhost:9090/api/v1/write for Prometheus.
+	WriteURL string `yaml:"write-url" mapstructure:"write-url"`
+}
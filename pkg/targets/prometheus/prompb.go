@@ -0,0 +1,154 @@
+package prometheus
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// The messages of a remote_write request, as defined by the prompb package of
+// Prometheus:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+//
+// They are encoded by hand, in the protobuf wire format, rather than with
+// generated code: the loader only ever writes them.
+
+// Wire types of the protobuf fields
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// label is a label of a series, a pair of name and value.
+type label struct {
+	name  string
+	value string
+}
+
+// sample is a value of a series at a timestamp in milliseconds.
+type sample struct {
+	value     float64
+	timestamp int64
+}
+
+// timeSeries is a series identified by its labels, sorted by name, and its
+// samples.
+type timeSeries struct {
+	labels  []label
+	samples []sample
+}
+
+// appendWriteRequest appends the WriteRequest of the given series to buf.
+// Fields with the default value of their type are omitted, as proto3 does.
+func appendWriteRequest(buf []byte, series []*timeSeries) []byte {
+	for _, ts := range series {
+		buf = appendTag(buf, 1, wireBytes)
+		buf = appendUvarint(buf, uint64(ts.size()))
+		buf = ts.append(buf)
+	}
+	return buf
+}
+
+func (ts *timeSeries) size() int {
+	n := 0
+	for _, l := range ts.labels {
+		s := l.size()
+		n += 1 + uvarintSize(uint64(s)) + s
+	}
+	for _, s := range ts.samples {
+		size := s.size()
+		n += 1 + uvarintSize(uint64(size)) + size
+	}
+	return n
+}
+
+func (ts *timeSeries) append(buf []byte) []byte {
+	for _, l := range ts.labels {
+		buf = appendTag(buf, 1, wireBytes)
+		buf = appendUvarint(buf, uint64(l.size()))
+		buf = appendString(buf, 1, l.name)
+		buf = appendString(buf, 2, l.value)
+	}
+	for _, s := range ts.samples {
+		buf = appendTag(buf, 2, wireBytes)
+		buf = appendUvarint(buf, uint64(s.size()))
+		if s.value != 0 || math.Signbit(s.value) {
+			buf = appendTag(buf, 1, wireFixed64)
+			buf = appendFixed64(buf, math.Float64bits(s.value))
+		}
+		if s.timestamp != 0 {
+			buf = appendTag(buf, 2, wireVarint)
+			buf = appendUvarint(buf, uint64(s.timestamp))
+		}
+	}
+	return buf
+}
+
+func (l label) size() int {
+	return stringSize(l.name) + stringSize(l.value)
+}
+
+func (s sample) size() int {
+	n := 0
+	if s.value != 0 || math.Signbit(s.value) {
+		n += 1 + 8
+	}
+	if s.timestamp != 0 {
+		n += 1 + uvarintSize(uint64(s.timestamp))
+	}
+	return n
+}
+
+// appendTag appends the key of the field with the given number and wire type.
+// All the field numbers used fit in a single byte.
+func appendTag(buf []byte, field int, wireType int) []byte {
+	return append(buf, byte(field<<3|wireType))
+}
+
+// appendString appends a string field, omitted if empty.
+func appendString(buf []byte, field int, s string) []byte {
+	if len(s) == 0 {
+		return buf
+	}
+	buf = appendTag(buf, field, wireBytes)
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// stringSize returns the encoded size of a string field, omitted if empty.
+func stringSize(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	return 1 + uvarintSize(uint64(len(s))) + len(s)
+}
+
+// appendUvarint appends the varint encoding of x.
+func appendUvarint(buf []byte, x uint64) []byte {
+	for x >= 0x80 {
+		buf = append(buf, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(buf, byte(x))
+}
+
+// appendFixed64 appends x in little-endian order.
+func appendFixed64(buf []byte, x uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	return append(buf, b[:]...)
+}
+
+// uvarintSize returns the number of bytes of the varint encoding of x.
+func uvarintSize(x uint64) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
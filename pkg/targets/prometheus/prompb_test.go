@@ -0,0 +1,67 @@
+package prometheus
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendWriteRequest(t *testing.T) {
+	cases := []struct {
+		desc   string
+		series []*timeSeries
+		want   []byte
+	}{
+		{
+			desc: "no series",
+			want: []byte{},
+		},
+		{
+			desc: "labels and samples",
+			series: []*timeSeries{{
+				labels:  []label{{name: "__name__", value: "a"}, {name: "x", value: "b"}},
+				samples: []sample{{value: 1, timestamp: 2}},
+			}},
+			want: []byte("\x0a\x24" +
+				"\x0a\x0d\x0a\x08__name__\x12\x01a" +
+				"\x0a\x06\x0a\x01x\x12\x01b" +
+				"\x12\x0b\x09\x00\x00\x00\x00\x00\x00\xf0\x3f\x10\x02"),
+		},
+		{
+			desc: "default values omitted",
+			series: []*timeSeries{{
+				labels:  []label{{name: "__name__", value: "a"}, {name: "x"}},
+				samples: []sample{{value: 0, timestamp: 0}, {value: 0, timestamp: 300}},
+			}},
+			want: []byte("\x0a\x1b" +
+				"\x0a\x0d\x0a\x08__name__\x12\x01a" +
+				"\x0a\x03\x0a\x01x" +
+				"\x12\x00" +
+				"\x12\x03\x10\xac\x02"),
+		},
+		{
+			desc: "several series",
+			series: []*timeSeries{
+				{labels: []label{{name: "__name__", value: "a"}}},
+				{labels: []label{{name: "__name__", value: "b"}}},
+			},
+			want: []byte("\x0a\x0f\x0a\x0d\x0a\x08__name__\x12\x01a" +
+				"\x0a\x0f\x0a\x0d\x0a\x08__name__\x12\x01b"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			got := appendWriteRequest([]byte{}, c.series)
+			if !bytes.Equal(got, c.want) {
+				t.Errorf("incorrect request:\ngot  %q\nwant %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestUvarintSize(t *testing.T) {
+	for _, x := range []uint64{0, 1, 127, 128, 300, 16383, 16384, 1640995200000, 1<<63 + 1} {
+		if got, want := uvarintSize(x), len(appendUvarint(nil, x)); got != want {
+			t.Errorf("incorrect size of %d: got %d want %d", x, got, want)
+		}
+	}
+}
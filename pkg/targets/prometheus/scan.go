@@ -0,0 +1,214 @@
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// metricNameLabel is the name of the label holding the metric name.
+const metricNameLabel = "__name__"
+
+// fileDataSource reads the points of a data file generated for Prometheus.
+type fileDataSource struct {
+	scanner *bufio.Scanner
+}
+
+// newFileDataSource returns the data source of the file, whose timestamps
+// must be in nanoseconds: the ones of a file with a precision header in
+// another unit are not converted.
+func newFileDataSource(fileName string) (targets.DataSource, error) {
+	br := load.GetBufferedReader(fileName)
+	precision, err := common.ReadPrecisionHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if precision != common.PrecisionNanoseconds {
+		return nil, fmt.Errorf(errNotNanosecondsFmt, precision)
+	}
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 4*1024*1024)
+	return &fileDataSource{scanner: scanner}, nil
+}
+
+func (d *fileDataSource) NextItem() data.LoadedPoint {
+	ok := d.scanner.Scan()
+	if !ok && d.scanner.Err() == nil { // nothing scanned & no error = EOF
+		return data.LoadedPoint{}
+	} else if !ok {
+		fatal("scan error: %v", d.scanner.Err())
+		return data.LoadedPoint{}
+	}
+	p := data.NewPoint()
+	if err := parseLine(d.scanner.Bytes(), p); err != nil {
+		fatal("%v", err)
+		return data.LoadedPoint{}
+	}
+	return data.NewLoadedPoint(p)
+}
+
+// Headers returns nil, line protocol files have no header.
+func (d *fileDataSource) Headers() *common.GeneratedDataHeaders { return nil }
+
+// simulationDataSource passes the points of the simulator as they are, without
+// serializing them.
+type simulationDataSource struct {
+	simulator common.Simulator
+}
+
+func newSimulationDataSource(sim common.Simulator) targets.DataSource {
+	return &simulationDataSource{simulator: sim}
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	p := data.NewPoint()
+	for !d.simulator.Finished() {
+		if d.simulator.Next(p) {
+			return data.NewLoadedPoint(p)
+		}
+		p.Reset()
+	}
+	return data.LoadedPoint{}
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	return d.simulator.Headers()
+}
+
+// seriesIndexer is used to consistently send the points of the same tags, and
+// so the samples of the same series, to the same worker.
+type seriesIndexer struct {
+	partitions uint
+}
+
+func (i *seriesIndexer) GetIndex(item data.LoadedPoint) uint {
+	p := item.Data.(*data.Point)
+	h := fnv.New32a()
+	h.Write(appendTagsKey(nil, p))
+	return uint(h.Sum32()) % i.partitions
+}
+
+// appendTagsKey appends the measurement and the tags of p, which identify its
+// series but for the field.
+func appendTagsKey(buf []byte, p *data.Point) []byte {
+	buf = append(buf, p.MeasurementName()...)
+	tagValues := p.TagValues()
+	for i, key := range p.TagKeys() {
+		buf = append(buf, ',')
+		buf = append(buf, key...)
+		buf = append(buf, '=')
+		buf = serialize.FastFormatAppend(tagValues[i], buf)
+	}
+	return buf
+}
+
+// batch accumulates the samples of the points by series.
+type batch struct {
+	series map[string]*timeSeries
+	// order holds the series in the order of their first sample, the order
+	// they are written in.
+	order   []*timeSeries
+	rows    uint
+	samples uint64
+	key     []byte
+}
+
+func (b *batch) Len() uint {
+	return b.rows
+}
+
+// Append adds a sample to the series of every numeric or boolean field of the
+// point, named <measurement>_<field> and labeled with the tags. String fields
+// and missing values are skipped.
+func (b *batch) Append(item data.LoadedPoint) {
+	p := item.Data.(*data.Point)
+	b.rows++
+	timestamp := p.Timestamp().UnixNano() / int64(time.Millisecond)
+
+	b.key = appendTagsKey(b.key[:0], p)
+	b.key = append(b.key, ' ')
+	tagsKeyLen := len(b.key)
+	fieldValues := p.FieldValues()
+	for i, field := range p.FieldKeys() {
+		value, ok := sampleValue(fieldValues[i])
+		if !ok {
+			continue
+		}
+		b.key = append(b.key[:tagsKeyLen], field...)
+		ts, ok := b.series[string(b.key)]
+		if !ok {
+			ts = &timeSeries{labels: seriesLabels(p, field)}
+			b.series[string(b.key)] = ts
+			b.order = append(b.order, ts)
+		}
+		ts.samples = append(ts.samples, sample{value: value, timestamp: timestamp})
+		b.samples++
+	}
+}
+
+// seriesLabels returns the labels, sorted by name, of the series of the given
+// field of p. Tags without a value are left out, as Prometheus does with
+// empty labels.
+func seriesLabels(p *data.Point, field []byte) []label {
+	name := make([]byte, 0, len(p.MeasurementName())+1+len(field))
+	name = append(name, p.MeasurementName()...)
+	name = append(name, '_')
+	name = append(name, field...)
+	labels := []label{{name: metricNameLabel, value: string(name)}}
+
+	tagValues := p.TagValues()
+	for i, key := range p.TagKeys() {
+		if tagValues[i] == nil {
+			continue
+		}
+		value := string(serialize.FastFormatAppend(tagValues[i], nil))
+		if value == "" {
+			continue
+		}
+		labels = append(labels, label{name: string(key), value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+	return labels
+}
+
+// sampleValue returns the value of a field as a float64, with booleans as 1
+// and 0, or false for a string or missing value.
+func sampleValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+type factory struct{}
+
+func (f *factory) New() targets.Batch {
+	return &batch{series: map[string]*timeSeries{}}
+}
@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+func parsePoint(t *testing.T, line string) *data.Point {
+	p := data.NewPoint()
+	if err := parseLine([]byte(line), p); err != nil {
+		t.Fatalf("cannot parse '%s': %v", line, err)
+	}
+	return p
+}
+
+func TestParseLine(t *testing.T) {
+	p := parsePoint(t, `cpu\ load,host\ name=host\,0\=a,region=eu usage\=guest=1.5,status=3i,count=4u,online=t,state="a b,c" 1640995200123456789`)
+	if got := string(p.MeasurementName()); got != "cpu load" {
+		t.Errorf("incorrect measurement: got %s", got)
+	}
+	if got, want := p.TagValues(), []interface{}{"host,0=a", "eu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect tag values: got %v want %v", got, want)
+	}
+	if got := string(p.TagKeys()[0]); got != "host name" {
+		t.Errorf("incorrect tag key: got %s", got)
+	}
+	if got := string(p.FieldKeys()[0]); got != "usage=guest" {
+		t.Errorf("incorrect field key: got %s", got)
+	}
+	want := []interface{}{1.5, int64(3), uint64(4), true, "a b,c"}
+	if got := p.FieldValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect field values: got %#v want %#v", got, want)
+	}
+	if got := p.Timestamp().UnixNano(); got != 1640995200123456789 {
+		t.Errorf("incorrect timestamp: got %d", got)
+	}
+
+	for _, line := range []string{
+		"cpu,host=h usage=1",
+		"cpu,host=h usage=x 0",
+		"cpu,host usage=1 0",
+		"cpu,host=h usage=1 x",
+	} {
+		if err := parseLine([]byte(line), data.NewPoint()); err == nil {
+			t.Errorf("expected an error for '%s'", line)
+		}
+	}
+}
+
+func TestBatchAppend(t *testing.T) {
+	b := (&factory{}).New().(*batch)
+	for _, line := range []string{
+		`readings,name=truck_0,fleet=East latitude=1,status=0i,state="idle" 1640995200000000000`,
+		`readings,name=truck_1,fleet=East latitude=2,status=1i 1640995200000000000`,
+		`readings,name=truck_0,fleet=East latitude=3,status=1i,state="on" 1640995210000000000`,
+		`diagnostics,name=truck_0,fleet=East ok=true 1640995210000000000`,
+	} {
+		b.Append(data.NewLoadedPoint(parsePoint(t, line)))
+	}
+	if b.Len() != 4 || b.samples != 7 {
+		t.Fatalf("incorrect counts: got %d rows %d samples want 4 and 7", b.Len(), b.samples)
+	}
+
+	labels := func(name, truck string) []label {
+		return []label{{"__name__", name}, {"fleet", "East"}, {"name", truck}}
+	}
+	want := []*timeSeries{
+		{labels: labels("readings_latitude", "truck_0"), samples: []sample{{1, 1640995200000}, {3, 1640995210000}}},
+		{labels: labels("readings_status", "truck_0"), samples: []sample{{0, 1640995200000}, {1, 1640995210000}}},
+		{labels: labels("readings_latitude", "truck_1"), samples: []sample{{2, 1640995200000}}},
+		{labels: labels("readings_status", "truck_1"), samples: []sample{{1, 1640995200000}}},
+		{labels: labels("diagnostics_ok", "truck_0"), samples: []sample{{1, 1640995210000}}},
+	}
+	if !reflect.DeepEqual(b.order, want) {
+		t.Errorf("incorrect series:\ngot  %v\nwant %v", b.order, want)
+	}
+}
+
+func TestSeriesLabelsSkipsMissingTags(t *testing.T) {
+	p := data.NewPoint()
+	ts := time.Unix(0, 0)
+	p.SetTimestamp(&ts)
+	p.SetMeasurementName([]byte("readings"))
+	p.AppendTag([]byte("name"), "truck_0")
+	p.AppendTag([]byte("driver"), nil)
+	p.AppendTag([]byte("model"), "")
+	p.AppendTag([]byte("fleet"), []byte("East"))
+
+	want := []label{{"__name__", "readings_velocity"}, {"fleet", "East"}, {"name", "truck_0"}}
+	if got := seriesLabels(p, []byte("velocity")); !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect labels: got %v want %v", got, want)
+	}
+}
+
+func TestSeriesIndexer(t *testing.T) {
+	i := &seriesIndexer{partitions: 8}
+	index := func(line string) uint {
+		return i.GetIndex(data.NewLoadedPoint(parsePoint(t, line)))
+	}
+	a := index("readings,name=truck_0 latitude=1 0")
+	if b := index("readings,name=truck_0 latitude=2,longitude=3 1000000000"); a != b {
+		t.Errorf("points of the same series on different partitions: %d and %d", a, b)
+	}
+	if a >= 8 {
+		t.Errorf("partition out of range: %d", a)
+	}
+}
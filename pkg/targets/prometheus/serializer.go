@@ -0,0 +1,21 @@
+package prometheus
+
+import (
+	"io"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/influx"
+)
+
+// Serializer writes a Point in InfluxDB line protocol, which load_prometheus
+// reads back into points: remote_write requests are binary and batched, so
+// data files keep the line protocol and the loader builds the requests.
+type Serializer struct {
+	lineProtocol influx.Serializer
+}
+
+// Serialize writes Point data to the given writer, conforming to the
+// InfluxDB line protocol.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	return s.lineProtocol.Serialize(p, w)
+}
@@ -0,0 +1,55 @@
+package questdb
+
+import (
+	"github.com/cnosdb/tsdb-comparisons/internal/inputs"
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+type benchmark struct {
+	opts *LoadingOptions
+	ds   targets.DataSource
+}
+
+func NewBenchmark(opts *LoadingOptions, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		ds = newFileDataSource(dataSourceConfig.File.Location)
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = load.NewSerializedDataSource(simulator, &Serializer{})
+	}
+
+	return &benchmark{
+		opts: opts,
+		ds:   ds,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{}
+}
+
+func (b *benchmark) GetPointIndexer(_ uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return newProcessor(b.opts)
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{
+		ds:   b.ds,
+		opts: b.opts,
+	}
+}
@@ -0,0 +1,103 @@
+package questdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// allows for testing
+var fatal = log.Fatalf
+
+// dbCreator stands in for the creation of the benchmark database: QuestDB has
+// a single database and line protocol writes create the tables they write to,
+// so the benchmark database is the set of tables of the loaded data. The
+// tables are only known for simulated data, a data file has no header.
+type dbCreator struct {
+	ds   targets.DataSource
+	opts *LoadingOptions
+
+	tables []string
+}
+
+func (d *dbCreator) Init() {
+	headers := d.ds.Headers()
+	if headers == nil {
+		return
+	}
+	for table := range headers.FieldKeys {
+		d.tables = append(d.tables, table)
+	}
+}
+
+// DBExists tells whether any of the tables of the loaded data exists.
+func (d *dbCreator) DBExists(_ string) bool {
+	if len(d.tables) == 0 {
+		return false
+	}
+	rows, err := d.exec("SHOW TABLES")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		for _, table := range d.tables {
+			if row[0] == table {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoveOldDB drops the tables of the loaded data.
+func (d *dbCreator) RemoveOldDB(_ string) error {
+	for _, table := range d.tables {
+		if _, err := d.exec(fmt.Sprintf("DROP TABLE IF EXISTS '%s'", table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateDB does nothing, the tables are created by the first writes.
+func (d *dbCreator) CreateDB(_ string) error {
+	return nil
+}
+
+// exec runs a SQL statement with the /exec endpoint of the REST API and
+// returns the rows of its result.
+func (d *dbCreator) exec(sql string) ([][]interface{}, error) {
+	u := fmt.Sprintf("%s/exec?query=%s", d.opts.HTTPURL(), url.QueryEscape(sql))
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("exec error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// {"query":"SHOW TABLES","columns":[{"name":"table_name","type":"STRING"}],"dataset":[["readings"]],"count":1}
+	// or {"query":"...","error":"...","position":0}
+	var result struct {
+		Dataset [][]interface{}
+		Error   string
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("exec '%s' returned code %d: %s", sql, resp.StatusCode, body)
+	}
+	if resp.StatusCode != http.StatusOK || result.Error != "" {
+		return nil, fmt.Errorf("exec '%s' returned code %d: %s", sql, resp.StatusCode, result.Error)
+	}
+	return result.Dataset, nil
+}
@@ -0,0 +1,71 @@
+package questdb
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+type testDataSource struct {
+	headers *common.GeneratedDataHeaders
+}
+
+func (d *testDataSource) NextItem() data.LoadedPoint            { return data.LoadedPoint{} }
+func (d *testDataSource) Headers() *common.GeneratedDataHeaders { return d.headers }
+
+func TestDBCreator(t *testing.T) {
+	var statements []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("query")
+		statements = append(statements, q)
+		if q == "SHOW TABLES" {
+			w.Write([]byte(`{"query":"SHOW TABLES","columns":[{"name":"table_name","type":"STRING"}],"dataset":[["other"],["readings"]],"count":2}`))
+			return
+		}
+		w.Write([]byte(`{"ddl":"OK"}`))
+	}))
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	d := &dbCreator{
+		ds: &testDataSource{headers: &common.GeneratedDataHeaders{
+			FieldKeys: map[string][]string{"readings": {"latitude"}},
+		}},
+		opts: &LoadingOptions{Host: host, HTTPPort: port},
+	}
+	d.Init()
+	if !d.DBExists("benchmark") {
+		t.Errorf("existing table not found")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := statements[len(statements)-1]; got != "DROP TABLE IF EXISTS 'readings'" {
+		t.Errorf("incorrect statement: got %s", got)
+	}
+
+	// the tables of a data file are not known
+	d = &dbCreator{ds: &testDataSource{}, opts: d.opts}
+	d.Init()
+	if d.DBExists("benchmark") {
+		t.Errorf("tables found without headers")
+	}
+}
+
+func TestDBCreatorExecError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"query":"DROP","error":"table does not exist","position":0}`))
+	}))
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	d := &dbCreator{opts: &LoadingOptions{Host: host, HTTPPort: port}}
+	if _, err := d.exec("DROP TABLE x"); err == nil {
+		t.Errorf("no error for a failed statement")
+	}
+}
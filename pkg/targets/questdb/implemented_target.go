@@ -0,0 +1,78 @@
+package questdb
+
+import (
+	"sync"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/timescaledb"
+	"github.com/spf13/pflag"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+// pgxDriver is the database/sql driver of the PostgreSQL wire protocol
+// endpoint the queries are run against.
+const pgxDriver = "pgx"
+
+func NewTarget() targets.ImplementedTarget {
+	return &questdbTarget{}
+}
+
+type questdbTarget struct {
+}
+
+func (t *questdbTarget) TargetName() string {
+	return constants.FormatQuestDB
+}
+
+func (t *questdbTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *questdbTarget) Benchmark(
+	_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	return NewBenchmark(&loadingOptions, dataSourceConfig)
+}
+
+// QueryPool returns the pool of the SQL queries, which are generated as
+// TimescaleDB queries.
+func (t *questdbTarget) QueryPool() *sync.Pool {
+	return &query.TimescaleDBPool
+}
+
+// QueryProcessor returns the processors of TimescaleDB, connected to the
+// PostgreSQL wire protocol endpoint of QuestDB.
+func (t *questdbTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	connectString := loadingOptions.GetConnectString()
+	opts := &timescaledb.QueryOptions{
+		Driver:         pgxDriver,
+		ConnectString:  func(int) string { return connectString },
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return timescaledb.NewQueryProcessor(opts) }, nil
+}
+
+func (t *questdbTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"host", "localhost", "Hostname of the QuestDB instance")
+	flagSet.String(flagPrefix+"protocol", ProtocolHTTP, "Protocol the line protocol is written over: http (acknowledged writes) or tcp (streamed writes)")
+	flagSet.String(flagPrefix+"http-port", "9000", "Port of the REST API, which takes the writes over http")
+	flagSet.String(flagPrefix+"ilp-port", "9009", "Port of the line protocol TCP listener, which takes the writes over tcp")
+	flagSet.String(flagPrefix+"pg-port", "8812", "Port of the PostgreSQL wire protocol endpoint, which runs the queries")
+	flagSet.String(flagPrefix+"user", "admin", "User to connect to the PostgreSQL wire protocol endpoint as")
+	flagSet.String(flagPrefix+"pass", "quest", "Password for the user connecting to the PostgreSQL wire protocol endpoint")
+}
@@ -0,0 +1,196 @@
+package questdb
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/valyala/fasthttp"
+)
+
+const httpClientName = "load_questdb"
+
+// allows for testing
+var printFn = fmt.Printf
+
+// ilpWriter writes batches of line protocol to QuestDB.
+type ilpWriter interface {
+	Write(body []byte) error
+	Close() error
+}
+
+// newILPWriter returns the writer of the protocol of the options.
+func newILPWriter(opts *LoadingOptions) (ilpWriter, error) {
+	switch opts.Protocol {
+	case ProtocolHTTP:
+		return newHTTPWriter(opts.HTTPURL()), nil
+	case ProtocolTCP:
+		return newTCPWriter(opts.ILPAddress())
+	}
+	return nil, fmt.Errorf("invalid protocol '%s', choices: %v", opts.Protocol, ProtocolChoices)
+}
+
+// httpWriter posts the batches to the /write endpoint. QuestDB answers
+// 204 No Content once all the lines of a batch are committed, or an error
+// naming the first line it could not parse.
+type httpWriter struct {
+	client fasthttp.Client
+	url    []byte
+}
+
+func newHTTPWriter(host string) *httpWriter {
+	return &httpWriter{
+		client: fasthttp.Client{Name: httpClientName},
+		url:    []byte(host + "/write"),
+	}
+}
+
+var (
+	methodPost = []byte("POST")
+	textPlain  = []byte("text/plain")
+)
+
+func (w *httpWriter) Write(body []byte) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetContentTypeBytes(textPlain)
+	req.Header.SetMethodBytes(methodPost)
+	req.Header.SetRequestURIBytes(w.url)
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := w.client.Do(req, resp); err != nil {
+		return load.Retryable(err)
+	}
+	if sc := resp.StatusCode(); sc != fasthttp.StatusNoContent {
+		err := fmt.Errorf("invalid write response (status %d): %s", sc, resp.Body())
+		if load.IsRetryableHTTPStatus(sc) {
+			return load.Retryable(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (w *httpWriter) Close() error {
+	return nil
+}
+
+// tcpWriter streams the batches over a connection to the line protocol TCP
+// listener. The listener does not acknowledge writes, it closes the
+// connection on a line it cannot parse, which fails a later write. A failed
+// write drops the connection. A retry writes the batch over a new one, unless
+// part of it was already sent: resending it would duplicate those rows, so
+// the batch fails.
+type tcpWriter struct {
+	address string
+	conn    net.Conn
+}
+
+func newTCPWriter(address string) (*tcpWriter, error) {
+	w := &tcpWriter{address: address}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *tcpWriter) connect() error {
+	conn, err := net.DialTimeout("tcp", w.address, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+func (w *tcpWriter) Write(body []byte) error {
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return load.Retryable(err)
+		}
+	}
+	if n, err := w.conn.Write(body); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		if n > 0 {
+			return fmt.Errorf("wrote %d of %d bytes: %v", n, len(body), err)
+		}
+		return load.Retryable(err)
+	}
+	return nil
+}
+
+func (w *tcpWriter) Close() error {
+	if w.conn == nil {
+		return nil
+	}
+	return w.conn.Close()
+}
+
+type processor struct {
+	opts        *LoadingOptions
+	writer      ilpWriter
+	retryPolicy *load.RetryPolicy
+}
+
+func newProcessor(opts *LoadingOptions) targets.Processor {
+	return &processor{opts: opts}
+}
+
+func (p *processor) Init(_ int, doLoad, _ bool) {
+	if !doLoad {
+		return
+	}
+	w, err := newILPWriter(p.opts)
+	if err != nil {
+		fatal("cannot connect to QuestDB: %v", err)
+		return
+	}
+	p.writer = w
+}
+
+func (p *processor) Close(doLoad bool) {
+	if doLoad && p.writer != nil {
+		if err := p.writer.Close(); err != nil {
+			fatal("cannot close connection to QuestDB: %v", err)
+		}
+	}
+}
+
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures writes the batch, retrying failed writes according
+// to the retry policy. A batch that still could not be written is counted as failed.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
+	batch := b.(*batch)
+	metricCnt := batch.metrics
+	rowCnt := uint64(batch.rows)
+
+	var err error
+	if doLoad {
+		err = p.retryPolicy.Do(func() error {
+			return p.writer.Write(batch.buf.Bytes())
+		})
+	}
+
+	// Return the batch buffer to the pool.
+	batch.buf.Reset()
+	bufPool.Put(batch.buf)
+	if err != nil {
+		printFn("Error writing: %s\n", err.Error())
+		return 0, 0, metricCnt, rowCnt
+	}
+	return metricCnt, rowCnt, 0, 0
+}
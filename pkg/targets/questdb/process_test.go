@@ -0,0 +1,169 @@
+package questdb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+const testLine = "readings,name=truck_0 latitude=1,longitude=2 1640995200000000000"
+
+func testBatch() *batch {
+	b := (&factory{}).New().(*batch)
+	b.Append(data.NewLoadedPoint([]byte(testLine)))
+	return b
+}
+
+func TestProcessorHTTP(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/write" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := &processor{writer: newHTTPWriter(server.URL)}
+	metrics, rows := p.ProcessBatch(testBatch(), true)
+	if metrics != 2 || rows != 1 {
+		t.Errorf("incorrect counts: got %d metrics %d rows want 2 and 1", metrics, rows)
+	}
+	if got := string(body); got != testLine+"\n" {
+		t.Errorf("incorrect body: got %q", got)
+	}
+}
+
+func TestHTTPWriterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":"invalid","message":"failed to parse line protocol"}`))
+	}))
+	defer server.Close()
+
+	err := newHTTPWriter(server.URL).Write([]byte(testLine))
+	if err == nil {
+		t.Fatalf("no error for a bad request")
+	}
+	if !strings.Contains(err.Error(), "failed to parse line protocol") {
+		t.Errorf("error does not include the response: %v", err)
+	}
+}
+
+func TestProcessorRetriesFailedWrites(t *testing.T) {
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printed := 0
+	printFn = func(string, ...interface{}) (int, error) {
+		printed++
+		return 0, nil
+	}
+
+	cases := []struct {
+		desc     string
+		status   int
+		attempts int
+	}{
+		{desc: "server error is retried", status: http.StatusServiceUnavailable, attempts: 3},
+		{desc: "bad request is not retried", status: http.StatusBadRequest, attempts: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			printed = 0
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(c.status)
+			}))
+			defer server.Close()
+
+			p := &processor{writer: newHTTPWriter(server.URL)}
+			p.SetRetryPolicy(load.NewRetryPolicy(load.RetryConfig{MaxAttempts: 3}))
+			metrics, rows, failedMetrics, failedRows := p.ProcessBatchWithFailures(testBatch(), true)
+			if attempts != c.attempts {
+				t.Errorf("incorrect number of attempts: got %d want %d", attempts, c.attempts)
+			}
+			if metrics != 0 || rows != 0 || failedMetrics != 2 || failedRows != 1 {
+				t.Errorf("incorrect counts: got %d %d %d %d want 0 0 2 1", metrics, rows, failedMetrics, failedRows)
+			}
+			if printed != 1 {
+				t.Errorf("printFn called incorrect # of times: got %d want 1", printed)
+			}
+		})
+	}
+}
+
+func TestProcessorTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+	defer l.Close()
+	received := make(chan []byte)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		body, _ := ioutil.ReadAll(conn)
+		received <- body
+	}()
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	p := newProcessor(&LoadingOptions{Host: "127.0.0.1", Protocol: ProtocolTCP, ILPPort: port}).(*processor)
+	p.Init(0, true, false)
+	p.ProcessBatch(testBatch(), true)
+	p.Close(true)
+
+	if got := string(<-received); got != testLine+"\n" {
+		t.Errorf("incorrect data: got %q", got)
+	}
+}
+
+// partialConn is a connection that sends half of every write and fails.
+type partialConn struct {
+	net.Conn
+	writes int
+}
+
+func (c *partialConn) Write(b []byte) (int, error) {
+	c.writes++
+	return len(b) / 2, errors.New("connection reset")
+}
+
+func (c *partialConn) Close() error {
+	return nil
+}
+
+func TestProcessorTCPPartialWrite(t *testing.T) {
+	conn := &partialConn{}
+	p := &processor{writer: &tcpWriter{address: "127.0.0.1:0", conn: conn}}
+	p.SetRetryPolicy(load.NewRetryPolicy(load.RetryConfig{MaxAttempts: 3}))
+	printFn = func(string, ...interface{}) (int, error) { return 0, nil }
+	defer func() { printFn = fmt.Printf }()
+
+	metrics, rows, failedMetrics, failedRows := p.ProcessBatchWithFailures(testBatch(), true)
+	if metrics != 0 || rows != 0 || failedMetrics != 2 || failedRows != 1 {
+		t.Errorf("incorrect counts: got %d, %d, %d, %d", metrics, rows, failedMetrics, failedRows)
+	}
+	if conn.writes != 1 {
+		t.Errorf("partially written batch was resent: got %d writes want 1", conn.writes)
+	}
+}
+
+func TestNewILPWriterInvalidProtocol(t *testing.T) {
+	if _, err := newILPWriter(&LoadingOptions{Protocol: "udp"}); err == nil {
+		t.Errorf("no error for an invalid protocol")
+	}
+}
@@ -0,0 +1,47 @@
+package questdb
+
+import "fmt"
+
+// Protocols the line protocol can be written over
+const (
+	ProtocolHTTP = "http"
+	ProtocolTCP  = "tcp"
+)
+
+// ProtocolChoices are the protocols QuestDB can be loaded over.
+var ProtocolChoices = []string{ProtocolHTTP, ProtocolTCP}
+
+// queryDB is the only database of QuestDB, whatever the name of the benchmark database.
+const queryDB = "qdb"
+
+// Loading option vars:
+type LoadingOptions struct {
+	Host     string `yaml:"host"`
+	Protocol string `yaml:"protocol"`
+	HTTPPort string `yaml:"http-port" mapstructure:"http-port"`
+	ILPPort  string `yaml:"ilp-port" mapstructure:"ilp-port"`
+	PGPort   string `yaml:"pg-port" mapstructure:"pg-port"`
+	User     string
+	Pass     string
+}
+
+// HTTPURL returns the URL of the REST API of QuestDB, which also takes line
+// protocol writes.
+func (o *LoadingOptions) HTTPURL() string {
+	return fmt.Sprintf("http://%s:%s", o.Host, o.HTTPPort)
+}
+
+// ILPAddress returns the address of the line protocol TCP listener.
+func (o *LoadingOptions) ILPAddress() string {
+	return o.Host + ":" + o.ILPPort
+}
+
+// GetConnectString returns the connection string of the PostgreSQL wire
+// protocol endpoint queries are run against.
+func (o *LoadingOptions) GetConnectString() string {
+	connectString := fmt.Sprintf("host=%s port=%s dbname=%s user=%s sslmode=disable", o.Host, o.PGPort, queryDB, o.User)
+	if len(o.Pass) > 0 {
+		connectString = fmt.Sprintf("%s password=%s", connectString, o.Pass)
+	}
+	return connectString
+}
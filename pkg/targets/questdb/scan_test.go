@@ -0,0 +1,41 @@
+package questdb
+
+import (
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+func TestBatch(t *testing.T) {
+	f := &factory{}
+	b := f.New().(*batch)
+	if b.Len() != 0 {
+		t.Errorf("batch not initialized with count 0")
+	}
+	b.Append(data.NewLoadedPoint([]byte("readings,name=truck_0 latitude=1,longitude=2 1640995200000000000")))
+	if b.Len() != 1 {
+		t.Errorf("batch count is not 1 after first append")
+	}
+	if b.metrics != 2 {
+		t.Errorf("batch metric count is not 2 after first append")
+	}
+	// escaped separators and string values are not split
+	b.Append(data.NewLoadedPoint([]byte(`readings,name=truck\ 1 state="a b,c",velocity=3 1640995200000000000`)))
+	if b.Len() != 2 {
+		t.Errorf("batch count is not 2 after second append")
+	}
+	if b.metrics != 4 {
+		t.Errorf("batch metric count is not 4 after second append: got %d", b.metrics)
+	}
+
+	fatalCalled := false
+	oldFatal := fatal
+	defer func() { fatal = oldFatal }()
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+	b.Append(data.NewLoadedPoint([]byte("readings,name=truck_0 latitude=1")))
+	if !fatalCalled {
+		t.Errorf("fatal was not called for a line without a timestamp")
+	}
+}
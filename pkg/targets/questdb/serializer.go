@@ -0,0 +1,25 @@
+package questdb
+
+import (
+	"io"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/influx"
+)
+
+// Serializer writes a Point in InfluxDB line protocol, which QuestDB ingests
+// as is: the tags become SYMBOL columns and the fields typed columns of a table
+// named after the measurement.
+//
+// The timestamps are always written in nanoseconds, the unit of the TCP
+// listener, so it does not take a timestamp precision: timestamps truncated by
+// the simulator are still written in nanoseconds.
+type Serializer struct {
+	lineProtocol influx.Serializer
+}
+
+// Serialize writes Point data to the given writer, conforming to the
+// InfluxDB line protocol.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	return s.lineProtocol.Serialize(p, w)
+}
@@ -0,0 +1,47 @@
+package questdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+)
+
+func TestQuestDBSerializerSerialize(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+		{
+			Desc:       "a Point with unsigned, boolean and string fields",
+			InputPoint: serialize.TestPointTyped(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b bytes_sent=18446744073709551615u,online=true,state=\"running\",usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+		{
+			Desc:       "a Point with special characters",
+			InputPoint: serialize.TestPointEscaped(),
+			Output:     "cpu\\ load,host\\ name=host\\,0\\=a usage\\=guest=38.24311829 1451606400000000000\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "cpu usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
+
+func TestQuestDBSerializerIgnoresPrecision(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point in nanoseconds",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, serialize.WithTimestampPrecision(&Serializer{}, time.Millisecond))
+}
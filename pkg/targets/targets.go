@@ -1,12 +1,15 @@
 package targets
 
 import (
+	"sync"
+	
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 )
 
 type ImplementedTarget interface {
@@ -22,6 +25,17 @@ type ImplementedTarget interface {
 	TargetName() string
 }
 
+// QueryTarget is implemented by an ImplementedTarget that can also run the
+// queries generated for it, so that it can be queried while it is being loaded.
+type QueryTarget interface {
+	// QueryPool returns the pool of the query type generated for this target
+	QueryPool() *sync.Pool
+	// QueryProcessor returns a function creating the Processors that execute
+	// the queries of runner. v holds the same target-specific configuration
+	// that is passed to Benchmark.
+	QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error)
+}
+
 // Batch is an aggregate of points for a particular data system.
 // It needs to have a way to measure it's size to make sure
 // it does not get too large and it needs a way to append a point
@@ -1,11 +1,14 @@
 package tdengine
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/cnosdb/tsdb-comparisons/load"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 
 	_ "github.com/jackc/pgx/v4/stdlib"
@@ -20,6 +23,9 @@ var fatal = log.Fatalf
 
 var tableCols = make(map[string][]string)
 
+// tableColTypes holds the types of the columns of tableCols, see common.FieldType
+var tableColTypes = make(map[string][]string)
+
 type LoadingOptions struct {
 	Host       string `yaml:"host"`
 	User       string
@@ -82,11 +88,12 @@ func (d *dbCreator) PostCreateDB(dbName string) error {
 	for tableName, columns := range headers.FieldKeys {
 		// tableCols is a global map. Globally cache the available columns for the given table
 		tableCols[tableName] = columns
+		tableColTypes[tableName] = headers.FieldTypesOf(tableName)
 
 		httpClientExecSQL(client, d.httpurl, "DROP TABLE IF EXISTS "+tableName, d.opts.User, d.opts.Pass)
 
 		createSql := fmt.Sprintf("CREATE STABLE %s (ts TIMESTAMP, %s) TAGS (%s)",
-			tableName, generateFieldsStr(columns), generateTagsStr(tagNames, tagTypes))
+			tableName, generateFieldsStr(columns, tableColTypes[tableName]), generateTagsStr(tagNames, tagTypes))
 		httpClientExecSQL(client, d.httpurl, createSql, d.opts.User, d.opts.Pass)
 
 	}
@@ -105,15 +112,32 @@ func generateTagsStr(tagNames, tagTypes []string) string {
 	return strings.Join(tagColumnDefinitions, ", ")
 }
 
-func generateFieldsStr(filedNames []string) string {
+func generateFieldsStr(filedNames, fieldTypes []string) string {
 	cols := make([]string, len(filedNames))
 	for i, tagName := range filedNames {
-		cols[i] = tagName + " FLOAT"
+		cols[i] = tagName + " " + fieldTypeToColumnType(fieldTypes[i])
 	}
 
 	return strings.Join(cols, ", ")
 }
 
+// fieldTypeToColumnType returns the column type for a field type. Floats are
+// kept as FLOAT, whatever their precision.
+func fieldTypeToColumnType(fieldType string) string {
+	switch fieldType {
+	case "int64":
+		return "BIGINT"
+	case "uint64":
+		return "BIGINT UNSIGNED"
+	case "bool":
+		return "BOOL"
+	case "string":
+		return "NCHAR(128)"
+	default:
+		return "FLOAT"
+	}
+}
+
 func serializedTypeToPgType(serializedType string) string {
 	switch serializedType {
 	case "string":
@@ -125,6 +149,18 @@ func serializedTypeToPgType(serializedType string) string {
 	}
 }
 
+// execResponse is the part of a response of the TDengine REST API telling
+// whether the statement succeeded. TDengine 2.x reports errors with the status
+// 'error', 3.x with a non zero code.
+type execResponse struct {
+	Status string `json:"status"`
+	Code   int    `json:"code"`
+	Desc   string `json:"desc"`
+}
+
+// httpClientExecSQL executes a statement with the REST API. Errors of requests
+// that did not reach the server, or that it rejected because it was overloaded,
+// are marked as retryable.
 func httpClientExecSQL(client *http.Client, url, sqlcmd, usr, pw string) error {
 	body := strings.NewReader(sqlcmd)
 	req, _ := http.NewRequest("POST", url, body)
@@ -133,12 +169,30 @@ func httpClientExecSQL(client *http.Client, url, sqlcmd, usr, pw string) error {
 
 	if err != nil {
 		fmt.Println(err)
-		return err
+		return load.Retryable(err)
 	}
 
 	// fmt.Printf("URL: %s ### SQL: %s\n\n", url, sqlcmd)
 
 	defer resp.Body.Close()
 
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return load.Retryable(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("invalid response (status %d): %s", resp.StatusCode, respBody)
+		if load.IsRetryableHTTPStatus(resp.StatusCode) {
+			return load.Retryable(err)
+		}
+		return err
+	}
+	var r execResponse
+	if err := json.Unmarshal(respBody, &r); err != nil {
+		return fmt.Errorf("invalid response: %s", respBody)
+	}
+	if r.Status == "error" || r.Code != 0 {
+		return fmt.Errorf("error %d: %s", r.Code, r.Desc)
+	}
 	return nil
 }
@@ -57,20 +57,21 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 
 	tagsarr := strings.Split(tags, ",")
 	if tagsarr[0] != tagsKey {
-		fatal("input header in wrong format. got '%s', expected 'tags'", tags[0])
+		fatal("input header in wrong format. got '%s', expected 'tags'", tagsarr[0])
 	}
 	tagNames, tagTypes := extractTagNamesAndTypes(tagsarr[1:])
 	fieldKeys := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	for _, tableDef := range cols {
 		columns := strings.Split(tableDef, ",")
 		tableName := columns[0]
-		colNames := columns[1:]
-		fieldKeys[tableName] = colNames
+		fieldKeys[tableName], fieldTypes[tableName] = extractFieldNamesAndTypes(columns[1:])
 	}
 	d.headers = &common.GeneratedDataHeaders{
-		TagTypes:  tagTypes,
-		TagKeys:   tagNames,
-		FieldKeys: fieldKeys,
+		TagTypes:   tagTypes,
+		TagKeys:    tagNames,
+		FieldKeys:  fieldKeys,
+		FieldTypes: fieldTypes,
 	}
 	return d.headers
 }
@@ -128,3 +129,24 @@ func extractTagNamesAndTypes(tags []string) ([]string, []string) {
 
 	return tagNames, tagTypes
 }
+
+// extractFieldNamesAndTypes splits the columns of a table header into field
+// names and types. Fields without a type are float64.
+func extractFieldNamesAndTypes(fields []string) ([]string, []string) {
+	fieldNames := make([]string, len(fields))
+	fieldTypes := make([]string, len(fields))
+	for i, fieldWithType := range fields {
+		fieldAndType := strings.Split(fieldWithType, " ")
+		switch len(fieldAndType) {
+		case 1:
+			fieldTypes[i] = common.FieldTypeFloat64
+		case 2:
+			fieldTypes[i] = fieldAndType[1]
+		default:
+			panic("field header has invalid format")
+		}
+		fieldNames[i] = fieldAndType[0]
+	}
+
+	return fieldNames, fieldTypes
+}
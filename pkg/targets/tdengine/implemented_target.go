@@ -1,9 +1,13 @@
 package tdengine
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/blagojts/viper"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 	"github.com/spf13/pflag"
@@ -34,6 +38,28 @@ func (t *tdengineTarget) Benchmark(
 	return NewBenchmark(targetDB, &loadingOptions, dataSourceConfig)
 }
 
+// QueryPool returns the pool of the TDengine SQL queries.
+func (t *tdengineTarget) QueryPool() *sync.Pool {
+	return &query.TDenginePool
+}
+
+// QueryProcessor returns the processors running the queries with the REST API
+// of the server written to, in the database of runner.
+func (t *tdengineTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	opts := &QueryOptions{
+		URL:            fmt.Sprintf("http://%s:%s/rest/sql/%s", loadingOptions.Host, loadingOptions.Port, runner.DatabaseName()),
+		User:           loadingOptions.User,
+		Pass:           loadingOptions.Pass,
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
+}
+
 func (t *tdengineTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
 	flagSet.String(flagPrefix+"host", "127.0.0.1", "Hostname of TimescaleDB (tdengine) instance")
 	flagSet.String(flagPrefix+"port", "6041", "Which port to connect to on the database host")
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 )
 
@@ -21,8 +23,9 @@ type processor struct {
 	opts   *LoadingOptions
 	dbName string
 
-	client  *http.Client
-	httpurl string
+	client      *http.Client
+	httpurl     string
+	retryPolicy *load.RetryPolicy
 }
 
 func newProcessor(opts *LoadingOptions, dbName string) *processor {
@@ -48,36 +51,59 @@ func (p *processor) Init(_ int, doLoad, hashWorkers bool) {
 
 func (p *processor) Close(doLoad bool) {}
 
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
 func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures writes the rows of every hypertable of the batch
+// with an INSERT statement. The rows of a hypertable that could not be
+// written, even after retrying, are counted as failed.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
 	batches := b.(*hypertableArr)
-	rowCnt := 0
+	rowCnt := uint64(0)
 	metricCnt := uint64(0)
+	failedRowCnt := uint64(0)
+	failedMetricCnt := uint64(0)
 	for hypertable, rows := range batches.m {
-		rowCnt += len(rows)
-		if doLoad {
-			start := time.Now()
-			metricCnt += p.processCSI(hypertable, rows)
-
-			if p.opts.LogBatches {
-				now := time.Now()
-				took := now.Sub(start)
-				batchSize := len(rows)
-				fmt.Printf("BATCH: batchsize %d row rate %f/sec (took %v)\n", batchSize, float64(batchSize)/float64(took.Seconds()), took)
-			}
+		if !doLoad {
+			rowCnt += uint64(len(rows))
+			continue
+		}
+		start := time.Now()
+		numMetrics, err := p.processCSI(hypertable, rows)
+		if err != nil {
+			fmt.Printf("Error writing %d rows to %s: %v\n", len(rows), hypertable, err)
+			failedRowCnt += uint64(len(rows))
+			failedMetricCnt += numMetrics
+			continue
+		}
+		rowCnt += uint64(len(rows))
+		metricCnt += numMetrics
+
+		if p.opts.LogBatches {
+			now := time.Now()
+			took := now.Sub(start)
+			batchSize := len(rows)
+			fmt.Printf("BATCH: batchsize %d row rate %f/sec (took %v)\n", batchSize, float64(batchSize)/float64(took.Seconds()), took)
 		}
 	}
 	batches.m = map[string][]*insertData{}
 	batches.cnt = 0
-	return metricCnt, uint64(rowCnt)
+	return metricCnt, rowCnt, failedMetricCnt, failedRowCnt
 }
 
 // tags,name=truck_0,fleet=South,driver=Trish,model=H-2,device_version=v2.3,load_capacity=1500,fuel_capacity=150,nominal_fuel_consumption=12
 // diagnostics,1640995200000 000000,1,,0
 // INSERT INTO diagnostics USING diagnostics_super TAGS
 // ("truck_1", "South", "Albert", "F-150", "v1.5",2000,200,15) VALUES (now, 11.2, 12.19,1);
-func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
+func (p *processor) processCSI(hypertable string, rows []*insertData) (uint64, error) {
 	colLen := len(tableCols[hypertable])
-	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, colLen)
+	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(hypertable, rows, colLen)
 
 	httpbody := "INSERT INTO "
 	tagVals := p.insertTags(tagRows)
@@ -85,9 +111,11 @@ func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 		tablname := "t_" + md5Str(str)[0:10]
 		httpbody += fmt.Sprintf("%s USING %s TAGS (%s) VALUES (%s) ", tablname, hypertable, str, dataRows[i])
 	}
-	httpClientExecSQL(p.client, p.httpurl, httpbody, p.opts.User, p.opts.Pass)
+	err := p.retryPolicy.Do(func() error {
+		return httpClientExecSQL(p.client, p.httpurl, httpbody, p.opts.User, p.opts.Pass)
+	})
 
-	return numMetrics
+	return numMetrics, err
 }
 
 func (p *processor) insertTags(tagRows [][]string) []string {
@@ -104,23 +132,28 @@ func (p *processor) insertTags(tagRows [][]string) []string {
 	return values
 }
 
-func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]string, []string, uint64) {
+func (p *processor) splitTagsAndMetrics(hypertable string, rows []*insertData, dataCols int) ([][]string, []string, uint64) {
+	fieldTypes := tableColTypes[hypertable]
 	tagRows := make([][]string, 0, len(rows))
 	dataRows := make([]string, 0, len(rows))
 	numMetrics := uint64(0)
 	commonTagsLen := len(tableCols[tagsKey])
 
 	for _, data := range rows {
-		tags := strings.SplitN(data.tags, ",", commonTagsLen+1)
+		tags := serialize.SplitCSV(data.tags, commonTagsLen+1)
 		for i := 0; i < commonTagsLen; i++ {
-			tags[i] = strings.Split(tags[i], "=")[1]
+			tags[i] = strings.SplitN(tags[i], "=", 2)[1]
 		}
 
-		metrics := strings.Split(data.fields, ",")
+		metrics := serialize.SplitCSV(data.fields, -1)
 		numMetrics += uint64(len(metrics) - 1) // 1 field is timestamp
 
 		timeInt, _ := strconv.ParseInt(metrics[0], 10, 64)
 		metrics[0] = strconv.FormatInt(timeInt/1000000, 10)
+		// quote the string fields and write the empty ones as NULL
+		if len(fieldTypes) == len(metrics)-1 {
+			copy(metrics[1:], convertValsToBasedOnType(metrics[1:], fieldTypes, "'", "NULL"))
+		}
 
 		dataRows = append(dataRows, strings.Join(metrics, ","))
 		tagRows = append(tagRows, tags[:commonTagsLen])
@@ -135,17 +168,23 @@ func md5Str(str string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// escapeString escapes the backslashes, the quote marks and the newlines of a
+// string value, which TDengine reads back from the quoted value.
+func escapeString(val, quotemark string) string {
+	return strings.NewReplacer(`\`, `\\`, quotemark, `\`+quotemark, "\n", `\n`).Replace(val)
+}
+
 func convertValsToBasedOnType(values []string, types []string, quotemark string, null string) []string {
 	sqlVals := make([]string, len(values))
 	for i, val := range values {
-		if val == "" {
+		if val == "" || val == "NULL" {
 			sqlVals[i] = null
 			continue
 		}
 
 		switch types[i] {
 		case "string":
-			sqlVals[i] = quotemark + val + quotemark
+			sqlVals[i] = quotemark + escapeString(val, quotemark) + quotemark
 		default:
 			sqlVals[i] = val
 		}
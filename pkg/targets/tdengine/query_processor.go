@@ -0,0 +1,144 @@
+package tdengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// QueryOptions holds the settings of the query processors.
+type QueryOptions struct {
+	// URL is the REST API endpoint the queries are sent to, ending with the database
+	URL            string
+	User           string
+	Pass           string
+	Debug          bool
+	PrintResponses bool
+}
+
+// queryResponse is the response of the TDengine REST API to a query. TDengine
+// 2.x names the columns in head, 3.x only in column_meta.
+type queryResponse struct {
+	execResponse
+	Head       []string        `json:"head"`
+	ColumnMeta [][]interface{} `json:"column_meta"`
+	Data       [][]interface{} `json:"data"`
+}
+
+// columns returns the names of the columns of the response.
+func (r *queryResponse) columns() []string {
+	if len(r.Head) > 0 {
+		return r.Head
+	}
+	cols := make([]string, len(r.ColumnMeta))
+	for i, meta := range r.ColumnMeta {
+		if len(meta) > 0 {
+			cols[i] = fmt.Sprint(meta[0])
+		}
+	}
+	return cols
+}
+
+type queryProcessor struct {
+	client *http.Client
+	opts   *QueryOptions
+}
+
+// NewQueryProcessor returns a query.Processor that runs TDengine queries with
+// the REST API, the one the loader writes with. run_queries_tdengine uses the
+// native client instead, which needs cgo.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(_ int) {
+	p.client = &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: 1024,
+			IdleConnTimeout:     time.Second * 60,
+		},
+	}
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
+	stats, _, err := p.execute(q, false)
+	return stats, err
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, _ bool) ([]*query.Stat, *query.ResultSet, error) {
+	return p.execute(q, true)
+}
+
+// execute runs the query and, if collect is set, returns all the fetched rows.
+func (p *queryProcessor) execute(q query.Query, collect bool) ([]*query.Stat, *query.ResultSet, error) {
+	tq := q.(*query.TDengine)
+	qry := string(tq.SqlQuery)
+	if p.opts.Debug {
+		fmt.Println(qry)
+	}
+	req, err := http.NewRequest("POST", p.opts.URL, strings.NewReader(qry))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.SetBasicAuth(p.opts.User, p.opts.Pass)
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	took := float64(time.Since(start).Nanoseconds()) / 1e6
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("query request returned non-200 code: %d: %s", resp.StatusCode, body)
+	}
+
+	var r queryResponse
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&r); err != nil {
+		return nil, nil, fmt.Errorf("invalid response: %s", body)
+	}
+	if r.Status == "error" || r.Code != 0 {
+		return nil, nil, fmt.Errorf("error %d: %s", r.Code, r.Desc)
+	}
+
+	var rs *query.ResultSet
+	if collect || p.opts.PrintResponses {
+		rs = query.NewResultSet(r.columns()...)
+		for _, row := range r.Data {
+			rs.AppendRow(row...)
+		}
+		if p.opts.PrintResponses {
+			prettyPrintResultSet(rs, tq)
+		}
+	}
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), took)
+
+	return []*query.Stat{stat}, rs, nil
+}
+
+// prettyPrintResultSet prints a query and its rows in JSON format.
+func prettyPrintResultSet(rs *query.ResultSet, q *query.TDengine) {
+	resp := map[string]interface{}{
+		"query":   string(q.SqlQuery),
+		"columns": rs.Columns,
+		"rows":    rs.Rows,
+	}
+	line, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(line) + "\n")
+}
@@ -0,0 +1,89 @@
+package tdengine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+func TestQueryProcessor(t *testing.T) {
+	cases := []struct {
+		desc        string
+		response    string
+		wantColumns []string
+		wantRows    [][]interface{}
+		wantErr     string
+	}{
+		{
+			desc:        "2.x response",
+			response:    `{"status":"succ","head":["ts","v"],"column_meta":[["ts",9,8],["v",6,4]],"data":[["2022-01-01 00:00:00.000",1.5]],"rows":1}`,
+			wantColumns: []string{"ts", "v"},
+			wantRows:    [][]interface{}{{"2022-01-01 00:00:00.000", json.Number("1.5")}},
+		},
+		{
+			desc:        "3.x response",
+			response:    `{"code":0,"column_meta":[["ts","TIMESTAMP",8],["v","FLOAT",4]],"data":[["2022-01-01T00:00:00.000Z",2]],"rows":1}`,
+			wantColumns: []string{"ts", "v"},
+			wantRows:    [][]interface{}{{"2022-01-01T00:00:00.000Z", json.Number("2")}},
+		},
+		{
+			desc:     "2.x error",
+			response: `{"status":"error","code":866,"desc":"Table does not exist"}`,
+			wantErr:  "error 866: Table does not exist",
+		},
+		{
+			desc:     "3.x error",
+			response: `{"code":9750,"desc":"Table does not exist"}`,
+			wantErr:  "error 9750: Table does not exist",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var gotSQL, gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				gotSQL, gotPath = string(body), r.URL.Path
+				if user, pass, _ := r.BasicAuth(); user != "root" || pass != "taosdata" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(c.response))
+			}))
+			defer server.Close()
+
+			p := NewQueryProcessor(&QueryOptions{URL: server.URL + "/rest/sql/benchmark", User: "root", Pass: "taosdata"})
+			p.Init(0)
+			q := query.NewTDengine()
+			q.HumanLabel = []byte("label")
+			q.SqlQuery = []byte("SELECT ts, v FROM t")
+			stats, rs, err := p.(query.ResultProcessor).ProcessQueryWithResult(q, false)
+			if gotSQL != "SELECT ts, v FROM t" || gotPath != "/rest/sql/benchmark" {
+				t.Errorf("incorrect request: got %s to %s", gotSQL, gotPath)
+			}
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Errorf("incorrect error: got %v want %s", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(stats) != 1 {
+				t.Errorf("incorrect number of stats: got %d want 1", len(stats))
+			}
+			if !reflect.DeepEqual(rs.Columns, c.wantColumns) {
+				t.Errorf("incorrect columns: got %v want %v", rs.Columns, c.wantColumns)
+			}
+			if !reflect.DeepEqual(rs.Rows, c.wantRows) {
+				t.Errorf("incorrect rows: got %v want %v", rs.Rows, c.wantRows)
+			}
+		})
+	}
+}
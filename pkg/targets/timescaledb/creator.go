@@ -24,6 +24,9 @@ var fatal = log.Fatalf
 
 var tableCols = make(map[string][]string)
 
+// tableColTypes holds the types of the columns of tableCols, see common.FieldType
+var tableColTypes = make(map[string][]string)
+
 type dbCreator struct {
 	driver  string
 	ds      targets.DataSource
@@ -100,6 +103,7 @@ func (d *dbCreator) PostCreateDB(dbName string) error {
 	for tableName, columns := range headers.FieldKeys {
 		// tableCols is a global map. Globally cache the available columns for the given table
 		tableCols[tableName] = columns
+		tableColTypes[tableName] = headers.FieldTypesOf(tableName)
 		fieldDefs, indexDefs := d.getFieldAndIndexDefinitions(tableName, columns)
 		if d.opts.CreateMetricsTable {
 			d.createTableAndIndexes(dbBench, tableName, fieldDefs, indexDefs)
@@ -137,12 +141,19 @@ func (d *dbCreator) getFieldAndIndexDefinitions(tableName string, columns []stri
 	}
 	
 	allCols = append(allCols, columns...)
+	allTypes := tableColTypes[tableName]
 	extraCols := 0 // set to 1 when hostname is kept in-table
+	if d.opts.InTableTag {
+		extraCols = 1
+	}
 	for idx, field := range allCols {
 		if len(field) == 0 {
 			continue
 		}
 		fieldType := "DOUBLE PRECISION"
+		if typeIdx := idx - extraCols; typeIdx >= 0 && typeIdx < len(allTypes) {
+			fieldType = serializedTypeToPgType(allTypes[typeIdx])
+		}
 		idxType := d.opts.FieldIndex
 		// This condition handles the case where we keep the primary tag key in the table
 		// and partition on it. Since under the current implementation this tag is always
@@ -150,7 +161,6 @@ func (d *dbCreator) getFieldAndIndexDefinitions(tableName string, columns []stri
 		if d.opts.InTableTag && idx == 0 {
 			fieldType = "TEXT"
 			idxType = ""
-			extraCols = 1
 		}
 		
 		fieldDefs = append(fieldDefs, fmt.Sprintf("%s %s", field, fieldType))
@@ -329,6 +339,11 @@ func serializedTypeToPgType(serializedType string) string {
 		return "BIGINT"
 	case "int32":
 		return "INTEGER"
+	case "uint64":
+		// there are no unsigned types, NUMERIC fits the whole range
+		return "NUMERIC(20)"
+	case "bool":
+		return "BOOLEAN"
 	default:
 		panic(fmt.Sprintf("unrecognized type %s", serializedType))
 	}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"reflect"
 	"testing"
 )
 
@@ -179,8 +180,18 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 			wantFieldDefs:   []string{"usage_user DOUBLE PRECISION", "usage_system DOUBLE PRECISION", "usage_idle DOUBLE PRECISION", "usage_nice DOUBLE PRECISION"},
 			wantIndexDefs:   []string{"CREATE INDEX ON cpu (usage_user, time DESC)", "CREATE INDEX ON cpu (usage_system, time DESC)"},
 		},
+		{
+			desc:            "typed fields, in table tag",
+			tableName:       "status",
+			columns:         []string{"load", "code", "bytes", "online", "mode"},
+			fieldIndexCount: 0,
+			inTableTag:      true,
+			wantFieldDefs:   []string{"hostname TEXT", "load DOUBLE PRECISION", "code BIGINT", "bytes NUMERIC(20)", "online BOOLEAN", "mode TEXT"},
+			wantIndexDefs:   []string{},
+		},
 	}
 
+	tableColTypes["status"] = []string{"float64", "int64", "uint64", "bool", "string"}
 	for _, c := range cases {
 		// Set the global in-table-tag flag based on the test case
 		// Initialize global cache
@@ -214,6 +225,16 @@ func TestExtractTagNamesAndTypes(t *testing.T) {
 
 	}
 }
+
+func TestExtractFieldNamesAndTypes(t *testing.T) {
+	names, types := extractFieldNamesAndTypes([]string{"usage", "status int64", "mode string"})
+	if !reflect.DeepEqual(names, []string{"usage", "status", "mode"}) {
+		t.Errorf("unexpected field names, got: %v", names)
+	}
+	if !reflect.DeepEqual(types, []string{"float64", "int64", "string"}) {
+		t.Errorf("unexpected field types, got: %v", types)
+	}
+}
 func TestGenerateTagsTableQuery(t *testing.T) {
 	testCases := []struct {
 		in  []string
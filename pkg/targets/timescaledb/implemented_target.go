@@ -1,12 +1,14 @@
 package timescaledb
 
 import (
+	"sync"
 	"time"
 	
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
 )
@@ -36,6 +38,25 @@ func (t *timescaleTarget) Benchmark(
 	return NewBenchmark(targetDB, &loadingOptions, dataSourceConfig)
 }
 
+func (t *timescaleTarget) QueryPool() *sync.Pool {
+	return &query.TimescaleDBPool
+}
+
+func (t *timescaleTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	connectString := loadingOptions.GetConnectString(runner.DatabaseName())
+	opts := &QueryOptions{
+		Driver:         getDriver(loadingOptions.ForceTextFormat),
+		ConnectString:  func(int) string { return connectString },
+		Debug:          runner.DebugLevel() > 0,
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
+}
+
 func (t *timescaleTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
 	flagSet.String(flagPrefix+"postgres", "sslmode=disable", "PostgreSQL connection string")
 	flagSet.String(flagPrefix+"host", "localhost", "Hostname of TimescaleDB (PostgreSQL) instance")
@@ -13,6 +13,8 @@ import (
 	"sync"
 	"time"
 	
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
 	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
 	
 	"github.com/jackc/pgx/v4"
@@ -50,7 +52,7 @@ var globalSyncCSI = newSyncCSI()
 func subsystemTagsToJSON(tags []string) map[string]interface{} {
 	jsonToReturn := map[string]interface{}{}
 	for _, t := range tags {
-		args := strings.Split(t, "=")
+		args := strings.SplitN(t, "=", 2)
 		jsonToReturn[args[0]] = args[1]
 	}
 	return jsonToReturn
@@ -126,7 +128,8 @@ func (p *processor) sqlTagsToCacheLine(res *sql.Rows, err error, tagCols []strin
 // divides the tags from data into appropriate slices that can then be used in
 // SQL queries to insert into their respective tables. Additionally, it also
 // returns the number of metrics (i.e., non-tag fields) for the data processed.
-func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]string, [][]interface{}, uint64) {
+func (p *processor) splitTagsAndMetrics(hypertable string, rows []*insertData, dataCols int) ([][]string, [][]interface{}, uint64) {
+	fieldTypes := tableColTypes[hypertable]
 	tagRows := make([][]string, 0, len(rows))
 	dataRows := make([][]interface{}, 0, len(rows))
 	numMetrics := uint64(0)
@@ -137,17 +140,17 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 		// for non-common tags that need to be added separately. For each of
 		// the common tags, remove everything before = in the form <label>=<val>
 		// since we won't need it.
-		tags := strings.SplitN(data.tags, ",", commonTagsLen+1)
+		tags := serialize.SplitCSV(data.tags, commonTagsLen+1)
 		for i := 0; i < commonTagsLen; i++ {
-			tags[i] = strings.Split(tags[i], "=")[1]
+			tags[i] = strings.SplitN(tags[i], "=", 2)[1]
 		}
 		
 		var json interface{}
 		if len(tags) > commonTagsLen {
-			json = subsystemTagsToJSON(strings.Split(tags[commonTagsLen], ","))
+			json = subsystemTagsToJSON(serialize.SplitCSV(tags[commonTagsLen], -1))
 		}
 		
-		metrics := strings.Split(data.fields, ",")
+		metrics := serialize.SplitCSV(data.fields, -1)
 		numMetrics += uint64(len(metrics) - 1) // 1 field is timestamp
 		
 		timeInt, err := strconv.ParseInt(metrics[0], 10, 64)
@@ -162,18 +165,22 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 		if p.opts.InTableTag {
 			r = append(r, tags[0])
 		}
-		for _, v := range metrics[1:] {
-			if v == "" {
+		for i, v := range metrics[1:] {
+			if v == "" || v == "NULL" {
 				r = append(r, nil)
 				continue
 			}
 			
-			num, err := strconv.ParseFloat(v, 64)
+			fieldType := common.FieldTypeFloat64
+			if i < len(fieldTypes) {
+				fieldType = fieldTypes[i]
+			}
+			val, err := parseFieldValue(v, fieldType)
 			if err != nil {
 				panic(err)
 			}
 			
-			r = append(r, num)
+			r = append(r, val)
 		}
 		
 		dataRows = append(dataRows, r)
@@ -183,12 +190,33 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 	return tagRows, dataRows, numMetrics
 }
 
+// parseFieldValue converts a serialized field value to the Go type matching
+// the column type of the given field type. Unsigned integers are kept as text,
+// since they may not fit in an int64 parameter, and parsed by the NUMERIC column.
+func parseFieldValue(v, fieldType string) (interface{}, error) {
+	switch fieldType {
+	case common.FieldTypeInt64:
+		return strconv.ParseInt(v, 10, 64)
+	case common.FieldTypeUint64:
+		if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case common.FieldTypeBool:
+		return strconv.ParseBool(v)
+	case common.FieldTypeString:
+		return v, nil
+	default:
+		return strconv.ParseFloat(v, 64)
+	}
+}
+
 func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 	colLen := len(tableCols[hypertable]) + numExtraCols
 	if p.opts.InTableTag {
 		colLen++
 	}
-	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, colLen)
+	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(hypertable, rows, colLen)
 	
 	// Check if any of these tags has yet to be inserted
 	newTags := make([][]string, 0, len(rows))
@@ -404,14 +432,26 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 	return metricCnt, uint64(rowCnt)
 }
 func convertValsToSQLBasedOnType(values []string, types []string) []string {
-	return convertValsToBasedOnType(values, types, "'", "NULL")
+	return convertValsToBasedOnType(values, types, quoteSQL, "NULL")
 }
 
 func convertValsToJSONBasedOnType(values []string, types []string) []string {
-	return convertValsToBasedOnType(values, types, `"`, "null")
+	return convertValsToBasedOnType(values, types, quoteJSON, "null")
+}
+
+// quoteSQL returns s as an SQL string literal.
+func quoteSQL(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteJSON returns s as a JSON string of the tagset, which is itself written
+// in an SQL string literal.
+func quoteJSON(s string) string {
+	b, _ := json.Marshal(s)
+	return strings.ReplaceAll(string(b), "'", "''")
 }
 
-func convertValsToBasedOnType(values []string, types []string, quotemark string, null string) []string {
+func convertValsToBasedOnType(values []string, types []string, quote func(string) string, null string) []string {
 	sqlVals := make([]string, len(values))
 	for i, val := range values {
 		if val == "" {
@@ -420,7 +460,7 @@ func convertValsToBasedOnType(values []string, types []string, quotemark string,
 		}
 		switch types[i] {
 		case "string":
-			sqlVals[i] = quotemark + val + quotemark
+			sqlVals[i] = quote(val)
 		default:
 			sqlVals[i] = val
 		}
@@ -49,6 +49,37 @@ func TestSubsystemTagsToJSON(t *testing.T) {
 	}
 }
 
+func TestParseFieldValue(t *testing.T) {
+	cases := []struct {
+		value     string
+		fieldType string
+		want      interface{}
+		wantErr   bool
+	}{
+		{value: "1.5", fieldType: "float64", want: 1.5},
+		{value: "-7", fieldType: "int64", want: int64(-7)},
+		{value: "1.5", fieldType: "int64", wantErr: true},
+		{value: "18446744073709551615", fieldType: "uint64", want: "18446744073709551615"},
+		{value: "-1", fieldType: "uint64", wantErr: true},
+		{value: "false", fieldType: "bool", want: false},
+		{value: "idle", fieldType: "string", want: "idle"},
+	}
+	for _, c := range cases {
+		got, err := parseFieldValue(c.value, c.fieldType)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s %s: expected an error", c.fieldType, c.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %v", c.fieldType, c.value, err)
+		} else if got != c.want {
+			t.Errorf("%s %s: got %#v want %#v", c.fieldType, c.value, got, c.want)
+		}
+	}
+}
+
 func TestSplitTagsAndMetrics(t *testing.T) {
 	numCols := 3
 	tableCols[tagsKey] = []string{"tag1", "tag2"}
@@ -82,8 +113,11 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 		},
 	}
 
+	tableColTypes["typed"] = []string{"int64", "uint64", "bool", "string"}
+
 	cases := []struct {
 		desc        string
+		hypertable  string
 		rows        []*insertData
 		inTableTag  bool
 		wantMetrics uint64
@@ -133,6 +167,36 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 				{toTS("200"), nil, map[string]interface{}{"tag3": "BAZ"}, "foofoo", 1.0, 5.0, 45.0},
 			},
 		},
+		{
+			desc:       "typed field values",
+			hypertable: "typed",
+			rows: []*insertData{
+				{
+					tags:   "tag1=foo,tag2=bar",
+					fields: "100,-7,18446744073709551615,true,idle",
+				},
+			},
+			wantTags: [][]string{{"foo", "bar"}},
+			wantData: [][]interface{}{
+				[]interface{}{toTS("100"), nil, nil, int64(-7), "18446744073709551615", true, "idle"},
+			},
+			wantMetrics: 4,
+		},
+		{
+			desc:       "escaped commas and quotes",
+			hypertable: "typed",
+			rows: []*insertData{
+				{
+					tags:   `tag1=a\,b'c,tag2=bar,tag3=d\,e`,
+					fields: `100,-7,1,true,a\,b'c\\d\ne`,
+				},
+			},
+			wantTags: [][]string{{"a,b'c", "bar"}},
+			wantData: [][]interface{}{
+				[]interface{}{toTS("100"), nil, map[string]interface{}{"tag3": "d,e"}, int64(-7), "1", true, "a,b'c\\d\ne"},
+			},
+			wantMetrics: 4,
+		},
 		{
 			desc: "invalid timestamp",
 			rows: []*insertData{
@@ -182,6 +246,20 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 				[]interface{}{toTS("100"), nil, nil, nil, 5.0, 42.0},
 			},
 		},
+		{
+			desc: "NULL field value",
+			rows: []*insertData{
+				{
+					tags:   "tag1=foo,tag2=bar",
+					fields: "100,1,NULL,42",
+				},
+			},
+			wantTags: [][]string{{"foo", "bar"}},
+			wantData: [][]interface{}{
+				[]interface{}{toTS("100"), nil, nil, 1.0, nil, 42.0},
+			},
+			wantMetrics: 3,
+		},
 	}
 
 	for _, c := range cases {
@@ -194,13 +272,13 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 					t.Errorf("%s: did not panic when should", c.desc)
 				}
 			}()
-			p.splitTagsAndMetrics(c.rows, numCols+numExtraCols)
+			p.splitTagsAndMetrics(c.hypertable, c.rows, numCols+numExtraCols)
 		}
 
 		oldInTableTag := p.opts.InTableTag
 		p.opts.InTableTag = c.inTableTag
 
-		gotTags, gotData, numMetrics := p.splitTagsAndMetrics(c.rows, numCols+numExtraCols)
+		gotTags, gotData, numMetrics := p.splitTagsAndMetrics(c.hypertable, c.rows, numCols+numExtraCols)
 		if numMetrics != c.wantMetrics {
 			t.Errorf("%s: number of metrics incorrect: got %d want %d", c.desc, numMetrics, c.wantMetrics)
 		}
@@ -299,3 +377,18 @@ func TestConvertValsToSQLBasedOnType(t *testing.T) {
 		t.Errorf("error converting to sql values\nexpected: %v\ngot: %v", expected, converted)
 	}
 }
+
+func TestConvertValsQuotesStrings(t *testing.T) {
+	types := []string{"string", "int64", "string"}
+	values := []string{"a,b'c", "1", ""}
+	got := convertValsToSQLBasedOnType(values, types)
+	want := []string{"'a,b''c'", "1", "NULL"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect SQL values: got %v want %v", got, want)
+	}
+	got = convertValsToJSONBasedOnType([]string{`a,b'c"d`, "1", ""}, types)
+	want = []string{`"a,b''c\"d"`, "1", "null"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect JSON values: got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package timescaledb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/pkg/errors"
+)
+
+// QueryOptions holds the settings of the query processors.
+type QueryOptions struct {
+	// Driver is the database/sql driver used to connect, pgx unless text format is forced
+	Driver string
+	// ConnectString returns the connection string for the worker with the given number
+	ConnectString  func(workerNumber int) string
+	ShowExplain    bool
+	Debug          bool
+	PrintResponses bool
+}
+
+type queryProcessor struct {
+	db   *sql.DB
+	opts *QueryOptions
+}
+
+// NewQueryProcessor returns a query.Processor that runs TimescaleDB queries.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(workerNumber int) {
+	db, err := sql.Open(p.opts.Driver, p.opts.ConnectString(workerNumber))
+	if err != nil {
+		panic(err)
+	}
+	p.db = db
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	stats, _, err := p.execute(q, isWarm, false)
+	return stats, err
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, isWarm bool) ([]*query.Stat, *query.ResultSet, error) {
+	return p.execute(q, isWarm, true)
+}
+
+// execute runs the query and, if collect is set, returns all the fetched rows.
+func (p *queryProcessor) execute(q query.Query, isWarm, collect bool) ([]*query.Stat, *query.ResultSet, error) {
+	// No need to run again for EXPLAIN
+	if isWarm && p.opts.ShowExplain {
+		return nil, nil, nil
+	}
+	tq := q.(*query.TimescaleDB)
+
+	start := time.Now()
+	qry := string(tq.SqlQuery)
+	if p.opts.ShowExplain {
+		qry = "EXPLAIN ANALYZE " + qry
+	}
+	rows, err := p.db.Query(qry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.opts.Debug {
+		fmt.Println(qry)
+	}
+	var rs *query.ResultSet
+	if p.opts.ShowExplain {
+		text := ""
+		for rows.Next() {
+			var s string
+			if err2 := rows.Scan(&s); err2 != nil {
+				panic(err2)
+			}
+			text += s + "\n"
+		}
+		fmt.Printf("%s\n\n%s\n-----\n\n", qry, text)
+	} else if collect {
+		rs = collectRows(rows)
+		if p.opts.PrintResponses {
+			prettyPrintResultSet(rs, tq)
+		}
+	} else if p.opts.PrintResponses {
+		prettyPrintResponse(rows, tq)
+	}
+	// Fetching all the rows to confirm that the query is fully completed.
+	for rows.Next() {
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	took := float64(time.Since(start).Nanoseconds()) / 1e6
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), took)
+
+	return []*query.Stat{stat}, rs, err
+}
+
+// prettyPrintResponse prints a Query and its response in JSON format with two
+// keys: 'query' which has a value of the SQL used to generate the second key
+// 'results' which is an array of each row in the return set.
+func prettyPrintResponse(rows *sql.Rows, q *query.TimescaleDB) {
+	resp := make(map[string]interface{})
+	resp["query"] = string(q.SqlQuery)
+	resp["results"] = mapRows(rows)
+
+	line, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(line) + "\n")
+}
+
+func mapRows(r *sql.Rows) []map[string]interface{} {
+	rows := []map[string]interface{}{}
+	cols, _ := r.Columns()
+	for r.Next() {
+		row := make(map[string]interface{})
+		values := make([]interface{}, len(cols))
+		for i := range values {
+			values[i] = new(interface{})
+		}
+
+		err := r.Scan(values...)
+		if err != nil {
+			panic(errors.Wrap(err, "error while reading values"))
+		}
+
+		for i, column := range cols {
+			row[column] = *values[i].(*interface{})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// collectRows reads all the rows of r into a query.ResultSet.
+func collectRows(r *sql.Rows) *query.ResultSet {
+	cols, _ := r.Columns()
+	rs := query.NewResultSet(cols...)
+	values := make([]interface{}, len(cols))
+	for r.Next() {
+		for i := range values {
+			values[i] = new(interface{})
+		}
+
+		err := r.Scan(values...)
+		if err != nil {
+			panic(errors.Wrap(err, "error while reading values"))
+		}
+
+		row := make([]interface{}, len(cols))
+		for i := range values {
+			row[i] = *values[i].(*interface{})
+		}
+		rs.AppendRow(row...)
+	}
+	return rs
+}
+
+// prettyPrintResultSet prints a Query and its already fetched response in the
+// same format as prettyPrintResponse.
+func prettyPrintResultSet(rs *query.ResultSet, q *query.TimescaleDB) {
+	results := make([]map[string]interface{}, 0, len(rs.Rows))
+	for _, values := range rs.Rows {
+		row := make(map[string]interface{})
+		for i, column := range rs.Columns {
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	resp := make(map[string]interface{})
+	resp["query"] = string(q.SqlQuery)
+	resp["results"] = results
+
+	line, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(line) + "\n")
+}
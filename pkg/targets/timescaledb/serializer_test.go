@@ -1,6 +1,7 @@
 package timescaledb
 
 import (
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
 	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
 	"testing"
 )
@@ -22,6 +23,16 @@ func TestTimescaleDBSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointMultiField(),
 			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,5000000000,38,38.24311829\n",
 		},
+		{
+			Desc:       "a Point with a comma in a tag value",
+			InputPoint: serialize.TestPointEscaped(),
+			Output:     "tags,host name=host\\,0=a\ncpu load,1451606400000000000,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with a string field containing a comma and a quote",
+			InputPoint: stringFieldPoint("a,b'c"),
+			Output:     "tags,hostname=host_0\ncpu,1451606400000000000,a\\,b'c\n",
+		},
 		{
 			Desc:       "a Point with no tags",
 			InputPoint: serialize.TestPointNoTags(),
@@ -32,6 +43,15 @@ func TestTimescaleDBSerializerSerialize(t *testing.T) {
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
 
+func stringFieldPoint(v string) *data.Point {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("cpu"))
+	p.AppendTag([]byte("hostname"), "host_0")
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendField([]byte("state"), v)
+	return p
+}
+
 func TestTimescaleDBSerializerSerializeErr(t *testing.T) {
 	p := serialize.TestPointMultiField()
 	s := &Serializer{}
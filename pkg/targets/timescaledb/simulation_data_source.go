@@ -57,7 +57,7 @@ func (d *simulationDataSource) NextItem() data.LoadedPoint {
 		}
 		buf = append(buf, tagKeys[i]...)
 		buf = append(buf, '=')
-		buf = serialize.FastFormatAppend(v, buf)
+		buf = serialize.CSVFormatAppend(v, buf)
 	}
 	newLoadPoint.tags = string(buf)
 	buf = buf[:0]
@@ -66,7 +66,7 @@ func (d *simulationDataSource) NextItem() data.LoadedPoint {
 	fieldValues := newSimulatorPoint.FieldValues()
 	for _, v := range fieldValues {
 		buf = append(buf, ',')
-		buf = serialize.FastFormatAppend(v, buf)
+		buf = serialize.CSVFormatAppend(v, buf)
 	}
 	
 	newLoadPoint.fields = string(buf)
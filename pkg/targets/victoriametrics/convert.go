@@ -0,0 +1,87 @@
+package victoriametrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+)
+
+// jsonLine is a line of the JSON lines import format of VictoriaMetrics: the
+// samples of a single series.
+type jsonLine struct {
+	Metric     map[string]string `json:"metric"`
+	Values     []float64         `json:"values"`
+	Timestamps []int64           `json:"timestamps"`
+}
+
+// appendJSONLines converts a line of line protocol into the JSON lines of its
+// fields and appends them to buf. The fields become series the same way as
+// with a line protocol import: named <measurement>_<field>, labeled with the
+// tags and with a millisecond timestamp. String fields are skipped.
+func appendJSONLines(buf *bytes.Buffer, line []byte) error {
+	args := serialize.SplitLineProtocol(line, ' ')
+	if len(args) != 3 {
+		return fmt.Errorf(errNotThreeTuplesFmt, len(args))
+	}
+	ns, err := strconv.ParseInt(string(args[2]), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse error: invalid timestamp '%s'", args[2])
+	}
+	timestamps := []int64{ns / int64(time.Millisecond)}
+
+	tags := serialize.SplitLineProtocol(args[0], ',')
+	measurement := serialize.UnescapeLineProtocol(tags[0])
+	metric := make(map[string]string, len(tags))
+	for _, tag := range tags[1:] {
+		key, value, err := serialize.SplitLineProtocolKeyValue(tag)
+		if err != nil {
+			return fmt.Errorf("parse error: %v", err)
+		}
+		metric[key] = serialize.UnescapeLineProtocol(value)
+	}
+
+	enc := json.NewEncoder(buf)
+	for _, field := range serialize.SplitLineProtocol(args[1], ',') {
+		key, raw, err := serialize.SplitLineProtocolKeyValue(field)
+		if err != nil {
+			return fmt.Errorf("parse error: %v", err)
+		}
+		v, err := serialize.ParseLineProtocolFieldValue(raw)
+		if err != nil {
+			return fmt.Errorf("parse error: field '%s': %v", key, err)
+		}
+		value, ok := numericValue(v)
+		if !ok {
+			continue
+		}
+		metric["__name__"] = measurement + "_" + key
+		// Encode terminates every line with a newline.
+		if err := enc.Encode(&jsonLine{Metric: metric, Values: []float64{value}, Timestamps: timestamps}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// numericValue returns a field value as a float, with booleans as 1 and 0, or
+// false for a string.
+func numericValue(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
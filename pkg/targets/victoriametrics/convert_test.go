@@ -0,0 +1,65 @@
+package victoriametrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendJSONLines(t *testing.T) {
+	cases := []struct {
+		desc    string
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{
+			desc: "numeric fields",
+			line: "readings,name=truck_0,fleet=East latitude=82.7,status=0i,count=3u 1640995200123456789",
+			want: `{"metric":{"__name__":"readings_latitude","fleet":"East","name":"truck_0"},"values":[82.7],"timestamps":[1640995200123]}
+{"metric":{"__name__":"readings_status","fleet":"East","name":"truck_0"},"values":[0],"timestamps":[1640995200123]}
+{"metric":{"__name__":"readings_count","fleet":"East","name":"truck_0"},"values":[3],"timestamps":[1640995200123]}
+`,
+		},
+		{
+			desc: "boolean and string fields",
+			line: `cpu,host=h online=true,state="a b,c",down=f 1000000`,
+			want: `{"metric":{"__name__":"cpu_online","host":"h"},"values":[1],"timestamps":[1]}
+{"metric":{"__name__":"cpu_down","host":"h"},"values":[0],"timestamps":[1]}
+`,
+		},
+		{
+			desc: "escaped characters",
+			line: `cpu\ load,host\ name=host\,0\=a usage\=guest=1.5 0`,
+			want: `{"metric":{"__name__":"cpu load_usage=guest","host name":"host,0=a"},"values":[1.5],"timestamps":[0]}
+`,
+		},
+		{
+			desc:    "no timestamp",
+			line:    "cpu,host=h usage=1",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid field value",
+			line:    "cpu,host=h usage=abc 0",
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := appendJSONLines(buf, []byte(c.line))
+			if c.wantErr {
+				if err == nil {
+					t.Errorf("no error for %q", c.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != c.want {
+				t.Errorf("incorrect JSON lines:\ngot\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
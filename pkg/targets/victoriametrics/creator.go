@@ -0,0 +1,106 @@
+package victoriametrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+// allows for testing
+var fatal = log.Fatalf
+
+// dbCreator stands in for the creation of the benchmark database:
+// VictoriaMetrics has no databases, so the benchmark database is the set of
+// series imported from the measurements of the loaded data. The measurements
+// are only known for simulated data, a data file has no header.
+type dbCreator struct {
+	ds   targets.DataSource
+	opts *LoadingOptions
+
+	measurements []string
+}
+
+func (d *dbCreator) Init() {
+	headers := d.ds.Headers()
+	if headers == nil {
+		return
+	}
+	for measurement := range headers.FieldKeys {
+		d.measurements = append(d.measurements, measurement)
+	}
+	sort.Strings(d.measurements)
+}
+
+// seriesSelector returns the series selector matching the series of the
+// measurements of the loaded data.
+func (d *dbCreator) seriesSelector() string {
+	return fmt.Sprintf(`{__name__=~"(%s)_.+"}`, strings.Join(d.measurements, "|"))
+}
+
+// DBExists tells whether any series of the loaded data exists.
+func (d *dbCreator) DBExists(_ string) bool {
+	if len(d.measurements) == 0 {
+		return false
+	}
+	v := url.Values{}
+	v.Set("match[]", d.seriesSelector())
+	v.Set("start", "0")
+	body, err := d.do(http.MethodGet, "/api/v1/series", v)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var result struct {
+		Data []map[string]string
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		log.Fatalf("cannot decode series: %v", err)
+	}
+	return len(result.Data) > 0
+}
+
+// RemoveOldDB deletes the series of the loaded data.
+func (d *dbCreator) RemoveOldDB(_ string) error {
+	if len(d.measurements) == 0 {
+		return nil
+	}
+	v := url.Values{}
+	v.Set("match[]", d.seriesSelector())
+	_, err := d.do(http.MethodPost, "/api/v1/admin/tsdb/delete_series", v)
+	return err
+}
+
+// CreateDB does nothing, the series are created by the first writes.
+func (d *dbCreator) CreateDB(_ string) error {
+	return nil
+}
+
+// do sends a request with the given query arguments to the first URL and
+// returns the body of its response.
+func (d *dbCreator) do(method, path string, v url.Values) ([]byte, error) {
+	u := fmt.Sprintf("%s%s?%s", d.opts.URLList()[0], path, v.Encode())
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %s", path, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned code %d: %s", path, resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,86 @@
+package victoriametrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+)
+
+type testDataSource struct {
+	headers *common.GeneratedDataHeaders
+}
+
+func (d *testDataSource) NextItem() data.LoadedPoint            { return data.LoadedPoint{} }
+func (d *testDataSource) Headers() *common.GeneratedDataHeaders { return d.headers }
+
+func TestDBCreator(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("match[]"))
+		if r.URL.Path == "/api/v1/series" {
+			w.Write([]byte(`{"status":"success","data":[{"__name__":"readings_latitude","name":"truck_0"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := &dbCreator{
+		ds: &testDataSource{headers: &common.GeneratedDataHeaders{
+			FieldKeys: map[string][]string{"readings": {"latitude"}, "diagnostics": {"status"}},
+		}},
+		opts: &LoadingOptions{URLs: server.URL},
+	}
+	d.Init()
+	if !d.DBExists("benchmark") {
+		t.Errorf("series of the loaded data not found")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`GET /api/v1/series {__name__=~"(diagnostics|readings)_.+"}`,
+		`POST /api/v1/admin/tsdb/delete_series {__name__=~"(diagnostics|readings)_.+"}`,
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("incorrect requests: got %v want %v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("incorrect request %d: got %s want %s", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestDBCreatorWithoutHeaders(t *testing.T) {
+	d := &dbCreator{ds: &testDataSource{}, opts: &LoadingOptions{URLs: "http://localhost:0"}}
+	d.Init()
+	if d.DBExists("benchmark") {
+		t.Errorf("series found without headers")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDBCreatorError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("delete_series is not allowed"))
+	}))
+	defer server.Close()
+
+	d := &dbCreator{
+		ds: &testDataSource{headers: &common.GeneratedDataHeaders{
+			FieldKeys: map[string][]string{"readings": {"latitude"}},
+		}},
+		opts: &LoadingOptions{URLs: server.URL},
+	}
+	d.Init()
+	if err := d.RemoveOldDB("benchmark"); err == nil {
+		t.Errorf("no error for a rejected delete")
+	}
+}
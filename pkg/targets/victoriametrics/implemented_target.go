@@ -0,0 +1,61 @@
+package victoriametrics
+
+import (
+	"sync"
+
+	"github.com/blagojts/viper"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/source"
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/constants"
+	"github.com/spf13/pflag"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &victoriaMetricsTarget{}
+}
+
+type victoriaMetricsTarget struct {
+}
+
+func (t *victoriaMetricsTarget) TargetName() string {
+	return constants.FormatVictoriaMetrics
+}
+
+func (t *victoriaMetricsTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *victoriaMetricsTarget) Benchmark(
+	_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper,
+) (targets.Benchmark, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	return NewBenchmark(&loadingOptions, dataSourceConfig)
+}
+
+// QueryPool returns the pool of the /api/v1/query_range requests.
+func (t *victoriaMetricsTarget) QueryPool() *sync.Pool {
+	return &query.HTTPPool
+}
+
+func (t *victoriaMetricsTarget) QueryProcessor(runner *query.BenchmarkRunner, v *viper.Viper) (query.ProcessorCreate, error) {
+	var loadingOptions LoadingOptions
+	if err := v.Unmarshal(&loadingOptions); err != nil {
+		return nil, err
+	}
+	opts := &QueryOptions{
+		URLs:           loadingOptions.URLList(),
+		Debug:          runner.DebugLevel(),
+		PrintResponses: runner.DoPrintResponses(),
+	}
+	return func() query.Processor { return NewQueryProcessor(opts) }, nil
+}
+
+func (t *victoriaMetricsTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"urls", "http://localhost:8428", "VictoriaMetrics URLs, comma-separated. Will be used in a round-robin fashion.")
+	flagSet.String(flagPrefix+"import-format", ImportFormatInflux, "Import API the batches are written to: influx (line protocol to /influx/write) or json (JSON lines to /api/v1/import)")
+}
@@ -0,0 +1,147 @@
+package victoriametrics
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+	"github.com/valyala/fasthttp"
+)
+
+const httpClientName = "load_victoriametrics"
+
+// Paths of the import APIs
+const (
+	influxWritePath = "/influx/write"
+	jsonImportPath  = "/api/v1/import"
+)
+
+// allows for testing
+var printFn = fmt.Printf
+
+var (
+	methodPost = []byte("POST")
+	textPlain  = []byte("text/plain")
+)
+
+// httpWriter posts the batches to an import API. VictoriaMetrics answers
+// 204 No Content once a batch is accepted.
+type httpWriter struct {
+	client fasthttp.Client
+	url    []byte
+}
+
+func newHTTPWriter(url, path string) *httpWriter {
+	return &httpWriter{
+		client: fasthttp.Client{Name: httpClientName},
+		url:    []byte(url + path),
+	}
+}
+
+func (w *httpWriter) Write(body []byte) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetContentTypeBytes(textPlain)
+	req.Header.SetMethodBytes(methodPost)
+	req.Header.SetRequestURIBytes(w.url)
+	req.SetBody(body)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := w.client.Do(req, resp); err != nil {
+		return load.Retryable(err)
+	}
+	if sc := resp.StatusCode(); sc < 200 || sc > 299 {
+		err := fmt.Errorf("invalid write response (status %d): %s", sc, resp.Body())
+		if load.IsRetryableHTTPStatus(sc) {
+			return load.Retryable(err)
+		}
+		return err
+	}
+	return nil
+}
+
+type processor struct {
+	opts        *LoadingOptions
+	writer      *httpWriter
+	retryPolicy *load.RetryPolicy
+	// jsonBuf holds the JSON lines of a batch, if imported in that format.
+	jsonBuf *bytes.Buffer
+}
+
+func newProcessor(opts *LoadingOptions) targets.Processor {
+	return &processor{opts: opts}
+}
+
+func (p *processor) Init(workerNum int, _, _ bool) {
+	urls := p.opts.URLList()
+	url := urls[workerNum%len(urls)]
+	switch p.opts.ImportFormat {
+	case ImportFormatJSON:
+		p.writer = newHTTPWriter(url, jsonImportPath)
+		p.jsonBuf = new(bytes.Buffer)
+	case ImportFormatInflux:
+		p.writer = newHTTPWriter(url, influxWritePath)
+	default:
+		fatal("invalid import format '%s', choices: %v", p.opts.ImportFormat, ImportFormatChoices)
+	}
+}
+
+func (p *processor) Close(_ bool) {
+}
+
+func (p *processor) SetRetryPolicy(policy *load.RetryPolicy) {
+	p.retryPolicy = policy
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	metricCnt, rowCnt, _, _ := p.ProcessBatchWithFailures(b, doLoad)
+	return metricCnt, rowCnt
+}
+
+// ProcessBatchWithFailures writes the batch, retrying failed writes according
+// to the retry policy. A batch that still could not be written is counted as failed.
+func (p *processor) ProcessBatchWithFailures(b targets.Batch, doLoad bool) (uint64, uint64, uint64, uint64) {
+	batch := b.(*batch)
+	metricCnt := batch.metrics
+	rowCnt := uint64(batch.rows)
+
+	var err error
+	if doLoad {
+		var body []byte
+		body, err = p.body(batch)
+		if err == nil {
+			err = p.retryPolicy.Do(func() error {
+				return p.writer.Write(body)
+			})
+		}
+	}
+
+	// Return the batch buffer to the pool.
+	batch.buf.Reset()
+	bufPool.Put(batch.buf)
+	if err != nil {
+		printFn("Error writing: %s\n", err.Error())
+		return 0, 0, metricCnt, rowCnt
+	}
+	return metricCnt, rowCnt, 0, 0
+}
+
+// body returns the batch in the import format of the processor.
+func (p *processor) body(batch *batch) ([]byte, error) {
+	if p.jsonBuf == nil {
+		return batch.buf.Bytes(), nil
+	}
+	p.jsonBuf.Reset()
+	for _, line := range bytes.Split(bytes.TrimSuffix(batch.buf.Bytes(), newLine), newLine) {
+		if len(line) == 0 {
+			continue
+		}
+		if err := appendJSONLines(p.jsonBuf, line); err != nil {
+			return nil, err
+		}
+	}
+	return p.jsonBuf.Bytes(), nil
+}
@@ -0,0 +1,124 @@
+package victoriametrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+const testLine = "readings,name=truck_0 latitude=1,longitude=2 1640995200000000000"
+
+func testBatch() *batch {
+	b := (&factory{}).New().(*batch)
+	b.Append(data.NewLoadedPoint([]byte(testLine)))
+	return b
+}
+
+func TestProcessorImportFormats(t *testing.T) {
+	cases := []struct {
+		format   string
+		wantPath string
+		wantBody string
+	}{
+		{
+			format:   ImportFormatInflux,
+			wantPath: "/influx/write",
+			wantBody: testLine + "\n",
+		},
+		{
+			format:   ImportFormatJSON,
+			wantPath: "/api/v1/import",
+			wantBody: `{"metric":{"__name__":"readings_latitude","name":"truck_0"},"values":[1],"timestamps":[1640995200000]}
+{"metric":{"__name__":"readings_longitude","name":"truck_0"},"values":[2],"timestamps":[1640995200000]}
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			var path, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				b, _ := ioutil.ReadAll(r.Body)
+				body = string(b)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			p := newProcessor(&LoadingOptions{URLs: server.URL, ImportFormat: c.format}).(*processor)
+			p.Init(0, true, false)
+			metrics, rows := p.ProcessBatch(testBatch(), true)
+			if metrics != 2 || rows != 1 {
+				t.Errorf("incorrect counts: got %d metrics %d rows want 2 and 1", metrics, rows)
+			}
+			if path != c.wantPath {
+				t.Errorf("incorrect path: got %s want %s", path, c.wantPath)
+			}
+			if body != c.wantBody {
+				t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", body, c.wantBody)
+			}
+		})
+	}
+}
+
+func TestProcessorRetriesFailedWrites(t *testing.T) {
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printed := 0
+	printFn = func(string, ...interface{}) (int, error) {
+		printed++
+		return 0, nil
+	}
+
+	cases := []struct {
+		desc     string
+		status   int
+		attempts int
+	}{
+		{desc: "server error is retried", status: http.StatusServiceUnavailable, attempts: 3},
+		{desc: "bad request is not retried", status: http.StatusBadRequest, attempts: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			printed = 0
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(c.status)
+			}))
+			defer server.Close()
+
+			p := newProcessor(&LoadingOptions{URLs: server.URL, ImportFormat: ImportFormatInflux}).(*processor)
+			p.Init(0, true, false)
+			p.SetRetryPolicy(load.NewRetryPolicy(load.RetryConfig{MaxAttempts: 3}))
+			metrics, rows, failedMetrics, failedRows := p.ProcessBatchWithFailures(testBatch(), true)
+			if attempts != c.attempts {
+				t.Errorf("incorrect number of attempts: got %d want %d", attempts, c.attempts)
+			}
+			if metrics != 0 || rows != 0 || failedMetrics != 2 || failedRows != 1 {
+				t.Errorf("incorrect counts: got %d %d %d %d want 0 0 2 1", metrics, rows, failedMetrics, failedRows)
+			}
+			if printed != 1 {
+				t.Errorf("printFn called incorrect # of times: got %d want 1", printed)
+			}
+		})
+	}
+}
+
+func TestProcessorInvalidImportFormat(t *testing.T) {
+	fatalCalled := false
+	oldFatal := fatal
+	defer func() { fatal = oldFatal }()
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+
+	p := newProcessor(&LoadingOptions{URLs: "http://localhost:8428", ImportFormat: "csv"}).(*processor)
+	p.Init(0, true, false)
+	if !fatalCalled {
+		t.Errorf("fatal was not called for an invalid import format")
+	}
+}
@@ -0,0 +1,23 @@
+package victoriametrics
+
+import "strings"
+
+// Import formats the batches can be written in
+const (
+	ImportFormatInflux = "influx"
+	ImportFormatJSON   = "json"
+)
+
+// ImportFormatChoices are the import APIs VictoriaMetrics can be loaded over.
+var ImportFormatChoices = []string{ImportFormatInflux, ImportFormatJSON}
+
+// Loading option vars:
+type LoadingOptions struct {
+	URLs         string `yaml:"urls"`
+	ImportFormat string `yaml:"import-format" mapstructure:"import-format"`
+}
+
+// URLList returns the comma-separated URLs of the options.
+func (o *LoadingOptions) URLList() []string {
+	return strings.Split(o.URLs, ",")
+}
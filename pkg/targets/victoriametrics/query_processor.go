@@ -0,0 +1,206 @@
+package victoriametrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// QueryOptions holds the settings of the query processors, which send the
+// queries to the URLs in a round-robin fashion.
+type QueryOptions struct {
+	URLs           []string
+	Debug          int
+	PrintResponses bool
+}
+
+var httpClientOnce = sync.Once{}
+var httpClient *http.Client
+
+func getHttpClient() *http.Client {
+	httpClientOnce.Do(func() {
+		tr := &http.Transport{
+			MaxIdleConnsPerHost: 1024,
+		}
+		httpClient = &http.Client{Transport: tr}
+	})
+	return httpClient
+}
+
+type queryProcessor struct {
+	opts   *QueryOptions
+	url    string
+	client *http.Client
+}
+
+// NewQueryProcessor returns a query.Processor that runs the /api/v1/query_range
+// requests of query.HTTP queries.
+func NewQueryProcessor(opts *QueryOptions) query.Processor {
+	return &queryProcessor{opts: opts}
+}
+
+func (p *queryProcessor) Init(workerNumber int) {
+	p.url = p.opts.URLs[workerNumber%len(p.opts.URLs)]
+	p.client = getHttpClient()
+}
+
+func (p *queryProcessor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	stats, _, err := p.execute(q, false)
+	return stats, err
+}
+
+func (p *queryProcessor) ProcessQueryWithResult(q query.Query, isWarm bool) ([]*query.Stat, *query.ResultSet, error) {
+	return p.execute(q, true)
+}
+
+// execute runs the query and, if collect is set, returns its samples.
+func (p *queryProcessor) execute(q query.Query, collect bool) ([]*query.Stat, *query.ResultSet, error) {
+	hq := q.(*query.HTTP)
+	req, err := http.NewRequest(string(hq.Method), p.url+string(hq.Path), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	lag := float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("query returned code %d: %s", resp.StatusCode, body)
+	}
+
+	switch p.opts.Debug {
+	case 0:
+	case 1:
+		fmt.Printf("debug: %s in %7.2fms\n", hq.HumanLabel, lag)
+	case 2:
+		fmt.Printf("debug: %s in %7.2fms -- %s\n", hq.HumanLabel, lag, hq.HumanDescription)
+	default:
+		fmt.Printf("debug: %s in %7.2fms -- %s\n", hq.HumanLabel, lag, hq.HumanDescription)
+		fmt.Printf("debug:   request: %s\n", hq.RawQuery)
+		fmt.Printf("debug:   response: %s\n", body)
+	}
+
+	var rs *query.ResultSet
+	if collect || p.opts.PrintResponses {
+		rs, err = parseResponse(body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if p.opts.PrintResponses {
+		prettyPrintResponse(rs, hq)
+	}
+
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	return []*query.Stat{stat}, rs, nil
+}
+
+// queryRangeResponse is the JSON document returned by /api/v1/query_range.
+type queryRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parseResponse converts the body of a /api/v1/query_range response into a
+// query.ResultSet with a row per sample: the labels of its series, sorted by
+// name, followed by its time and value.
+func parseResponse(body []byte) (*query.ResultSet, error) {
+	var resp queryRangeResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("query returned error: %s", resp.Error)
+	}
+
+	labelSet := make(map[string]bool)
+	for _, series := range resp.Data.Result {
+		for k := range series.Metric {
+			labelSet[k] = true
+		}
+	}
+	labels := make([]string, 0, len(labelSet))
+	for k := range labelSet {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+
+	rs := query.NewResultSet(append(labels, "time", "value")...)
+	for _, series := range resp.Data.Result {
+		for _, sample := range series.Values {
+			t, ok := sample[0].(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid sample time: %v", sample[0])
+			}
+			s, ok := sample[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid sample value: %v", sample[1])
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sample value: %v", err)
+			}
+			row := make([]interface{}, 0, len(labels)+2)
+			for _, k := range labels {
+				if value, ok := series.Metric[k]; ok {
+					row = append(row, value)
+				} else {
+					row = append(row, nil)
+				}
+			}
+			sec, frac := math.Modf(t)
+			row = append(row, time.Unix(int64(sec), int64(math.Round(frac*1e3))*int64(time.Millisecond)).UTC(), v)
+			rs.AppendRow(row...)
+		}
+	}
+	return rs, nil
+}
+
+// prettyPrintResponse prints a Query and its response in JSON format with two
+// keys: 'query' which has a value of the MetricsQL used to generate the second
+// key 'results' which is an array of each sample in the response.
+func prettyPrintResponse(rs *query.ResultSet, q *query.HTTP) {
+	results := make([]map[string]interface{}, 0, len(rs.Rows))
+	for _, values := range rs.Rows {
+		row := make(map[string]interface{})
+		for i, column := range rs.Columns {
+			row[column] = values[i]
+		}
+		results = append(results, row)
+	}
+	resp := make(map[string]interface{})
+	resp["query"] = string(q.RawQuery)
+	resp["results"] = results
+
+	line, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(line) + "\n")
+}
@@ -0,0 +1,101 @@
+package victoriametrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/query"
+)
+
+// recordedResponse is a /api/v1/query_range response of VictoriaMetrics to a
+// last-loc query.
+const recordedResponse = `{"status":"success","isPartial":false,"data":{"resultType":"matrix","result":[` +
+	`{"metric":{"__name__":"readings_latitude","driver":"Seth","fleet":"East","name":"truck_0"},"values":[[1641002400,"82.70029"]]},` +
+	`{"metric":{"__name__":"readings_longitude","driver":"Seth","fleet":"East","name":"truck_0"},"values":[[1641002400.5,"41.67129"]]}` +
+	`]}}`
+
+func testQuery() *query.HTTP {
+	q := query.NewHTTP()
+	q.HumanLabel = []byte("VictoriaMetrics last location per truck")
+	q.Method = []byte("GET")
+	q.Path = []byte("/api/v1/query_range?query=up&start=0&end=0&step=1s")
+	return q
+}
+
+func TestQueryProcessor(t *testing.T) {
+	var uri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uri = r.URL.RequestURI()
+		w.Write([]byte(recordedResponse))
+	}))
+	defer server.Close()
+
+	p := NewQueryProcessor(&QueryOptions{URLs: []string{"http://unused", server.URL}}).(*queryProcessor)
+	p.Init(1)
+	stats, rs, err := p.ProcessQueryWithResult(testQuery(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uri != "/api/v1/query_range?query=up&start=0&end=0&step=1s" {
+		t.Errorf("incorrect request: %s", uri)
+	}
+	if len(stats) != 1 {
+		t.Errorf("incorrect stats: %v", stats)
+	}
+
+	wantColumns := []string{"__name__", "driver", "fleet", "name", "time", "value"}
+	if len(rs.Columns) != len(wantColumns) {
+		t.Fatalf("incorrect columns: got %v want %v", rs.Columns, wantColumns)
+	}
+	for i := range wantColumns {
+		if rs.Columns[i] != wantColumns[i] {
+			t.Errorf("incorrect column %d: got %s want %s", i, rs.Columns[i], wantColumns[i])
+		}
+	}
+	if len(rs.Rows) != 2 {
+		t.Fatalf("incorrect number of rows: got %d want 2", len(rs.Rows))
+	}
+	row := rs.Rows[1]
+	if row[0] != "readings_longitude" || row[3] != "truck_0" || row[5] != 41.67129 {
+		t.Errorf("incorrect row: %v", row)
+	}
+	if want := time.Unix(1641002400, 500*int64(time.Millisecond)).UTC(); row[4] != want {
+		t.Errorf("incorrect time: got %v want %v", row[4], want)
+	}
+}
+
+func TestQueryProcessorErrors(t *testing.T) {
+	cases := []struct {
+		desc   string
+		status int
+		body   string
+	}{
+		{
+			desc:   "bad request",
+			status: http.StatusBadRequest,
+			body:   `{"status":"error","errorType":"422","error":"cannot parse query"}`,
+		},
+		{
+			desc:   "error status",
+			status: http.StatusOK,
+			body:   `{"status":"error","error":"cannot parse query"}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body))
+			}))
+			defer server.Close()
+
+			p := NewQueryProcessor(&QueryOptions{URLs: []string{server.URL}}).(*queryProcessor)
+			p.Init(0)
+			if _, _, err := p.ProcessQueryWithResult(testQuery(), false); err == nil {
+				t.Errorf("no error for %s", c.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package victoriametrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/cnosdb/tsdb-comparisons/load"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/serialize"
+	"github.com/cnosdb/tsdb-comparisons/pkg/data/usecases/common"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets"
+)
+
+const (
+	errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
+	errNotNanosecondsFmt = "timestamp precision '%s' of the data file is not supported, generate it with nanosecond timestamps"
+)
+
+var newLine = []byte("\n")
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+	},
+}
+
+// fileDataSource reads the lines of a data file generated for VictoriaMetrics.
+type fileDataSource struct {
+	scanner *bufio.Scanner
+}
+
+// newFileDataSource returns the data source of the file, whose timestamps
+// must be in nanoseconds: the ones of a file with a precision header in
+// another unit are not converted.
+func newFileDataSource(fileName string) (targets.DataSource, error) {
+	br := load.GetBufferedReader(fileName)
+	precision, err := common.ReadPrecisionHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if precision != common.PrecisionNanoseconds {
+		return nil, fmt.Errorf(errNotNanosecondsFmt, precision)
+	}
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 4*1024*1024)
+	return &fileDataSource{scanner: scanner}, nil
+}
+
+func (d *fileDataSource) NextItem() data.LoadedPoint {
+	ok := d.scanner.Scan()
+	if !ok && d.scanner.Err() == nil { // nothing scanned & no error = EOF
+		return data.LoadedPoint{}
+	} else if !ok {
+		fatal("scan error: %v", d.scanner.Err())
+		return data.LoadedPoint{}
+	}
+	return data.NewLoadedPoint(d.scanner.Bytes())
+}
+
+// Headers returns nil, line protocol files have no header.
+func (d *fileDataSource) Headers() *common.GeneratedDataHeaders { return nil }
+
+// batch is a buffer of lines of line protocol.
+type batch struct {
+	buf     *bytes.Buffer
+	rows    uint
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return b.rows
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	that := item.Data.([]byte)
+	b.rows++
+	// Each line is "measurement,tags fields timestamp": the fields are the
+	// comma separated items of the second space separated section.
+	args := serialize.SplitLineProtocol(that, ' ')
+	if len(args) != 3 {
+		fatal(errNotThreeTuplesFmt, len(args))
+		return
+	}
+	b.metrics += uint64(len(serialize.SplitLineProtocol(args[1], ',')))
+
+	b.buf.Write(that)
+	b.buf.Write(newLine)
+}
+
+type factory struct{}
+
+func (f *factory) New() targets.Batch {
+	return &batch{buf: bufPool.Get().(*bytes.Buffer)}
+}
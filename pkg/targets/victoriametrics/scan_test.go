@@ -0,0 +1,78 @@
+package victoriametrics
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+)
+
+func TestBatch(t *testing.T) {
+	f := &factory{}
+	b := f.New().(*batch)
+	if b.Len() != 0 {
+		t.Errorf("batch not initialized with count 0")
+	}
+	b.Append(data.NewLoadedPoint([]byte("readings,name=truck_0 latitude=1,longitude=2 1640995200000000000")))
+	if b.Len() != 1 {
+		t.Errorf("batch count is not 1 after first append")
+	}
+	if b.metrics != 2 {
+		t.Errorf("batch metric count is not 2 after first append")
+	}
+	// escaped separators and string values are not split
+	b.Append(data.NewLoadedPoint([]byte(`readings,name=truck\ 1 state="a b,c",velocity=3 1640995200000000000`)))
+	if b.Len() != 2 {
+		t.Errorf("batch count is not 2 after second append")
+	}
+	if b.metrics != 4 {
+		t.Errorf("batch metric count is not 4 after second append: got %d", b.metrics)
+	}
+
+	fatalCalled := false
+	oldFatal := fatal
+	defer func() { fatal = oldFatal }()
+	fatal = func(format string, args ...interface{}) {
+		fatalCalled = true
+	}
+	b.Append(data.NewLoadedPoint([]byte("readings,name=truck_0 latitude=1")))
+	if !fatalCalled {
+		t.Errorf("fatal was not called for a line without a timestamp")
+	}
+}
+
+func TestNewFileDataSourcePrecision(t *testing.T) {
+	dir, err := ioutil.TempDir("", "victoriametrics")
+	if err != nil {
+		t.Fatalf("cannot create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	line := "readings,name=truck_0 latitude=1 1640995200000000000\n"
+	cases := map[string]bool{
+		"":                 true,
+		"# precision=ns\n": true,
+		"# precision=ms\n": false,
+	}
+	for header, ok := range cases {
+		fileName := filepath.Join(dir, "data.txt")
+		if err := ioutil.WriteFile(fileName, []byte(header+line), 0644); err != nil {
+			t.Fatalf("cannot write data file: %v", err)
+		}
+		ds, err := newFileDataSource(fileName)
+		if !ok {
+			if err == nil {
+				t.Errorf("no error for header %q", header)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for header %q: %v", header, err)
+		}
+		if got := string(ds.NextItem().Data.([]byte)); got+"\n" != line {
+			t.Errorf("incorrect line for header %q: got %q", header, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package victoriametrics
+
+import (
+	"io"
+
+	"github.com/cnosdb/tsdb-comparisons/pkg/data"
+	"github.com/cnosdb/tsdb-comparisons/pkg/targets/influx"
+)
+
+// Serializer writes a Point in InfluxDB line protocol. VictoriaMetrics
+// imports every numeric or boolean field as a series named
+// <measurement>_<field>, labeled with the tags; string fields are not
+// imported.
+//
+// The timestamps are always written in nanoseconds, so it does not take a
+// timestamp precision: timestamps truncated by the simulator are still
+// written in nanoseconds.
+type Serializer struct {
+	lineProtocol influx.Serializer
+}
+
+// Serialize writes Point data to the given writer, conforming to the
+// InfluxDB line protocol.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	return s.lineProtocol.Serialize(p, w)
+}